@@ -5,14 +5,45 @@ import "time"
 // Account represents account data.
 type Account struct {
 	ID        int64
+	TenantID  int64
 	Name      string
 	CreatedAt time.Time
 	UpdatedAt time.Time
+	Status    Status
+
+	// VerifiedAt is when the account name was verified to belong to the account holder.
+	// It is zero while the account is pending verification.
+	VerifiedAt time.Time
+
+	// Login lockout.
+	FailedLoginAttempts int
+	LockedUntil         time.Time
 }
 
-// NewAccount returns a new Account.
+// NewAccount returns a new active Account.
 func NewAccount(id int64, name string, createdAt, updatedAt time.Time) *Account {
 	createdAt = createdAt.UTC().Truncate(time.Second)
 	updatedAt = updatedAt.UTC().Truncate(time.Second)
-	return &Account{id, name, createdAt, updatedAt}
+	return &Account{
+		ID:        id,
+		Name:      name,
+		CreatedAt: createdAt,
+		UpdatedAt: updatedAt,
+		Status:    StatusActive,
+	}
+}
+
+// IsActive reports whether the account can be used.
+func (a Account) IsActive() bool {
+	return a.Status == StatusActive
+}
+
+// IsVerified reports whether the account name is verified.
+func (a Account) IsVerified() bool {
+	return !a.VerifiedAt.IsZero()
+}
+
+// IsLocked reports whether the account is locked for logins at the moment t.
+func (a Account) IsLocked(t time.Time) bool {
+	return a.LockedUntil.After(t)
 }
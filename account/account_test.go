@@ -47,6 +47,15 @@ func TestNewAccount(t *testing.T) {
 			)
 		}
 
+		if actual.Status != StatusActive {
+			t.Errorf(
+				"testcase %d: Expected Status to be %v but got %v\n",
+				i,
+				StatusActive,
+				actual.Status,
+			)
+		}
+
 		if actual.CreatedAt != c.expectedCreatedAt {
 			t.Errorf(
 				"testcase %d: Expected CreatedAt to be %v but got %v\n",
@@ -66,3 +75,69 @@ func TestNewAccount(t *testing.T) {
 		}
 	}
 }
+
+func TestAccount_IsLocked(t *testing.T) {
+	now := time.Now()
+
+	cases := []struct {
+		caseName    string
+		lockedUntil time.Time
+		expected    bool
+	}{
+		{
+			caseName:    "Never locked",
+			lockedUntil: time.Time{},
+			expected:    false,
+		},
+		{
+			caseName:    "Lock has expired",
+			lockedUntil: now.Add(-time.Minute),
+			expected:    false,
+		},
+		{
+			caseName:    "Locked",
+			lockedUntil: now.Add(time.Minute),
+			expected:    true,
+		},
+	}
+
+	for i, c := range cases {
+		acc := Account{LockedUntil: c.lockedUntil}
+
+		if actual := acc.IsLocked(now); actual != c.expected {
+			t.Errorf(
+				"testcase %d %s: Expected IsLocked to be %v but got %v\n",
+				i,
+				c.caseName,
+				c.expected,
+				actual,
+			)
+		}
+	}
+}
+
+func TestAccount_IsActive(t *testing.T) {
+	cases := []struct {
+		status   Status
+		expected bool
+	}{
+		{StatusActive, true},
+		{StatusDisabled, false},
+		{StatusSuspended, false},
+		{StatusPending, false},
+	}
+
+	for i, c := range cases {
+		acc := Account{Status: c.status}
+
+		if actual := acc.IsActive(); actual != c.expected {
+			t.Errorf(
+				"testcase %d: Expected IsActive of %q account to be %v but got %v\n",
+				i,
+				c.status,
+				c.expected,
+				actual,
+			)
+		}
+	}
+}
@@ -5,13 +5,20 @@ import "time"
 // Application represents a new account proposal.
 // It can be used as intention to create a new Account.
 type Application struct {
+	TenantID     int64
 	Name         string
 	PasswordHash []byte
 	CreatedAt    time.Time
+
+	// VerifiedAt is when the account name is verified.
+	// Zero means the account is pending verification.
+	VerifiedAt time.Time
 }
 
 // NewApplication returns a new Application.
-func NewApplication(name string, passwordHash []byte, createdAt time.Time) Application {
+// The name of the account is considered verified at the time of creation,
+// unless VerifiedAt is reset.
+func NewApplication(tenantID int64, name string, passwordHash []byte, createdAt time.Time) Application {
 	createdAt = createdAt.UTC().Truncate(time.Second)
-	return Application{name, passwordHash, createdAt}
+	return Application{tenantID, name, passwordHash, createdAt, createdAt}
 }
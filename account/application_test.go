@@ -11,12 +11,14 @@ func TestNewApplication(t *testing.T) {
 	now := time.Now()
 
 	cases := []struct {
+		tenantID          int64
 		name              string
 		passwordHash      []byte
 		createdAt         time.Time
 		expectedCreatedAt time.Time
 	}{
 		{
+			tenantID:          1,
 			name:              "email@email.com",
 			passwordHash:      []byte("some_secure_hash"),
 			createdAt:         now,
@@ -25,7 +27,16 @@ func TestNewApplication(t *testing.T) {
 	}
 
 	for i, c := range cases {
-		actual := NewApplication(c.name, c.passwordHash, c.createdAt)
+		actual := NewApplication(c.tenantID, c.name, c.passwordHash, c.createdAt)
+
+		if actual.TenantID != c.tenantID {
+			t.Errorf(
+				"testcase %d: Expected TenantID to be %v but got %v\n",
+				i,
+				c.tenantID,
+				actual.TenantID,
+			)
+		}
 
 		if actual.Name != c.name {
 			t.Errorf(
@@ -53,5 +64,14 @@ func TestNewApplication(t *testing.T) {
 				actual.CreatedAt,
 			)
 		}
+
+		if !reflect.DeepEqual(actual.VerifiedAt, c.expectedCreatedAt) {
+			t.Errorf(
+				"testcase %d: Expected VerifiedAt to be %v, but got %v\n",
+				i,
+				c.expectedCreatedAt,
+				actual.VerifiedAt,
+			)
+		}
 	}
 }
@@ -1,5 +1,11 @@
 package account
 
+import (
+	"time"
+
+	"github.com/hypnoglow/pascont/outbox"
+)
+
 type fakeRepository struct {
 	acceptResults                               []FakeRepositoryAcceptResult
 	acceptResultCounter                         int
@@ -9,6 +15,24 @@ type fakeRepository struct {
 	existsResultCounter                         int
 	findWithPasswordHashByUsernameResults       []FakeRepositoryFindWithPasswordHashByUsernameResult
 	findWithPasswordHashByUsernameResultCounter int
+	findWithPasswordHashByIDResults             []FakeRepositoryFindWithPasswordHashByIDResult
+	findWithPasswordHashByIDResultCounter       int
+	deleteResults                               []FakeRepositoryDeleteResult
+	deleteResultCounter                         int
+	findByIDResults                             []FakeRepositoryFindByIDResult
+	findByIDResultCounter                       int
+	registerFailedLoginResults                  []FakeRepositoryRegisterFailedLoginResult
+	registerFailedLoginResultCounter            int
+	resetFailedLoginsResults                    []FakeRepositoryResetFailedLoginsResult
+	resetFailedLoginsResultCounter              int
+	updateStatusResults                         []FakeRepositoryUpdateStatusResult
+	updateStatusResultCounter                   int
+	listResults                                 []FakeRepositoryListResult
+	listResultCounter                           int
+	verifyResults                               []FakeRepositoryVerifyResult
+	verifyResultCounter                         int
+	changePasswordResults                       []FakeRepositoryChangePasswordResult
+	changePasswordResultCounter                 int
 }
 
 type FakeRepositoryAcceptResult struct {
@@ -31,44 +55,167 @@ type FakeRepositoryFindWithPasswordHashByUsernameResult struct {
 	Error        error
 }
 
+type FakeRepositoryFindWithPasswordHashByIDResult struct {
+	Account      *Account
+	PasswordHash []byte
+	Error        error
+}
+
+type FakeRepositoryDeleteResult struct {
+	Error error
+}
+
+type FakeRepositoryFindByIDResult struct {
+	Account *Account
+	Error   error
+}
+
+type FakeRepositoryRegisterFailedLoginResult struct {
+	Locked bool
+	Error  error
+}
+
+type FakeRepositoryResetFailedLoginsResult struct {
+	Error error
+}
+
+type FakeRepositoryUpdateStatusResult struct {
+	Error error
+}
+
+type FakeRepositoryListResult struct {
+	Accounts []*Account
+	Total    int
+	Error    error
+}
+
+type FakeRepositoryVerifyResult struct {
+	Error error
+}
+
+type FakeRepositoryChangePasswordResult struct {
+	Error error
+}
+
 // NewFakeRepository returns a new fake Repository.
 func NewFakeRepository(
 	acceptResults []FakeRepositoryAcceptResult,
 	saveResults []FakeRepositorySaveResult,
 	existsResults []FakeRepositoryExistsResult,
 	findWithPasswordHashByUsernameResults []FakeRepositoryFindWithPasswordHashByUsernameResult,
+	findWithPasswordHashByIDResults []FakeRepositoryFindWithPasswordHashByIDResult,
+	deleteResults []FakeRepositoryDeleteResult,
+	findByIDResults []FakeRepositoryFindByIDResult,
+	registerFailedLoginResults []FakeRepositoryRegisterFailedLoginResult,
+	resetFailedLoginsResults []FakeRepositoryResetFailedLoginsResult,
+	updateStatusResults []FakeRepositoryUpdateStatusResult,
+	listResults []FakeRepositoryListResult,
+	verifyResults []FakeRepositoryVerifyResult,
+	changePasswordResults []FakeRepositoryChangePasswordResult,
 ) Repository {
 	return &fakeRepository{
-		acceptResults:                               acceptResults,
-		acceptResultCounter:                         0,
-		saveResults:                                 saveResults,
-		saveResultCounter:                           0,
-		existsResults:                               existsResults,
-		existsResultCounter:                         0,
-		findWithPasswordHashByUsernameResults:       findWithPasswordHashByUsernameResults,
+		acceptResults:                         acceptResults,
+		acceptResultCounter:                   0,
+		saveResults:                           saveResults,
+		saveResultCounter:                     0,
+		existsResults:                         existsResults,
+		existsResultCounter:                   0,
+		findWithPasswordHashByUsernameResults: findWithPasswordHashByUsernameResults,
 		findWithPasswordHashByUsernameResultCounter: 0,
+		findWithPasswordHashByIDResults:             findWithPasswordHashByIDResults,
+		findWithPasswordHashByIDResultCounter:       0,
+		deleteResults:                               deleteResults,
+		deleteResultCounter:                         0,
+		findByIDResults:                             findByIDResults,
+		findByIDResultCounter:                       0,
+		registerFailedLoginResults:                  registerFailedLoginResults,
+		registerFailedLoginResultCounter:            0,
+		resetFailedLoginsResults:                    resetFailedLoginsResults,
+		resetFailedLoginsResultCounter:              0,
+		updateStatusResults:                         updateStatusResults,
+		updateStatusResultCounter:                   0,
+		listResults:                                 listResults,
+		listResultCounter:                           0,
+		verifyResults:                               verifyResults,
+		verifyResultCounter:                         0,
+		changePasswordResults:                       changePasswordResults,
+		changePasswordResultCounter:                 0,
 	}
 }
 
-func (r *fakeRepository) Accept(app Application) (acc *Account, err error) {
+func (r *fakeRepository) Accept(app Application, messages ...outbox.Message) (acc *Account, err error) {
 	res := r.acceptResults[r.acceptResultCounter]
 	r.acceptResultCounter++
 	return res.Account, res.Error
 }
 
-func (r *fakeRepository) Save(acc Account, passwordHash []byte) error {
+func (r *fakeRepository) Save(acc Account, passwordHash []byte, messages ...outbox.Message) error {
 	res := r.saveResults[r.saveResultCounter]
 	r.saveResultCounter++
 	return res.Error
 }
 
-func (r *fakeRepository) FindWithPasswordHashByUsername(name string) (*Account, []byte, error) {
+func (r *fakeRepository) ChangePassword(change PasswordChange) error {
+	res := r.changePasswordResults[r.changePasswordResultCounter]
+	r.changePasswordResultCounter++
+	return res.Error
+}
+
+func (r *fakeRepository) FindByID(id int64) (*Account, error) {
+	res := r.findByIDResults[r.findByIDResultCounter]
+	r.findByIDResultCounter++
+	return res.Account, res.Error
+}
+
+func (r *fakeRepository) List(query string, offset, limit int) ([]*Account, int, error) {
+	res := r.listResults[r.listResultCounter]
+	r.listResultCounter++
+	return res.Accounts, res.Total, res.Error
+}
+
+func (r *fakeRepository) FindWithPasswordHashByUsername(tenantID int64, name string) (*Account, []byte, error) {
 	res := r.findWithPasswordHashByUsernameResults[r.findWithPasswordHashByUsernameResultCounter]
 	r.findWithPasswordHashByUsernameResultCounter++
 	return res.Account, res.PasswordHash, res.Error
 }
 
-func (r *fakeRepository) Exists(username string) (bool, error) {
+func (r *fakeRepository) FindWithPasswordHashByID(id int64) (*Account, []byte, error) {
+	res := r.findWithPasswordHashByIDResults[r.findWithPasswordHashByIDResultCounter]
+	r.findWithPasswordHashByIDResultCounter++
+	return res.Account, res.PasswordHash, res.Error
+}
+
+func (r *fakeRepository) UpdateStatus(id int64, status Status, messages ...outbox.Message) error {
+	res := r.updateStatusResults[r.updateStatusResultCounter]
+	r.updateStatusResultCounter++
+	return res.Error
+}
+
+func (r *fakeRepository) Verify(id int64, verifiedAt time.Time) error {
+	res := r.verifyResults[r.verifyResultCounter]
+	r.verifyResultCounter++
+	return res.Error
+}
+
+func (r *fakeRepository) RegisterFailedLogin(id int64, threshold int, lockedUntil time.Time) (bool, error) {
+	res := r.registerFailedLoginResults[r.registerFailedLoginResultCounter]
+	r.registerFailedLoginResultCounter++
+	return res.Locked, res.Error
+}
+
+func (r *fakeRepository) ResetFailedLogins(id int64) error {
+	res := r.resetFailedLoginsResults[r.resetFailedLoginsResultCounter]
+	r.resetFailedLoginsResultCounter++
+	return res.Error
+}
+
+func (r *fakeRepository) Delete(id int64, messages ...outbox.Message) error {
+	res := r.deleteResults[r.deleteResultCounter]
+	r.deleteResultCounter++
+	return res.Error
+}
+
+func (r *fakeRepository) Exists(tenantID int64, username string) (bool, error) {
 	res := r.existsResults[r.existsResultCounter]
 	r.existsResultCounter++
 	return res.Exists, res.Error
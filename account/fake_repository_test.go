@@ -8,11 +8,11 @@ import (
 )
 
 func TestNewFakeRepository(t *testing.T) {
-	NewFakeRepository(nil, nil, nil, nil)
+	NewFakeRepository(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 }
 
 func TestFakeRepository_Accept(t *testing.T) {
-	fakeAcc := &Account{123, "email@email.com", time.Now(), time.Now()}
+	fakeAcc := &Account{ID: 123, Name: "email@email.com", CreatedAt: time.Now(), UpdatedAt: time.Now()}
 
 	cases := []struct {
 		acceptResults    []FakeRepositoryAcceptResult
@@ -1,22 +1,94 @@
 package account
 
+import (
+	"time"
+
+	"github.com/hypnoglow/pascont/outbox"
+	"github.com/hypnoglow/pascont/session"
+)
+
+// PasswordChange is a change of the account password.
+// All sessions of the account are revoked and its failed logins are reset,
+// so the new password takes effect at once.
+type PasswordChange struct {
+	Account      Account
+	PasswordHash []byte
+
+	// Session, if not nil, is issued after the other sessions are revoked.
+	Session *session.Session
+
+	// Messages are added to the outbox along with the change.
+	Messages []outbox.Message
+}
+
 // Repository is a repository for an Account.
 type Repository interface {
 	// Accept accepts an Application and adds a new Account to the Repository.
-	Accept(app Application) (acc *Account, err error)
+	// The outbox messages are added along with the account, in the same transaction,
+	// with the ID of the new account as the AccountID of their notifications.
+	// If account with such name already exists in the tenant, returns ErrAlreadyExists.
+	// Other errors may occur.
+	Accept(app Application, messages ...outbox.Message) (acc *Account, err error)
 
 	// Save saves an Account to the repository.
+	// The outbox messages are added along with the account, in the same transaction.
+	// If Account has no ID, returns ErrNoIdentity.
+	// Other errors may occur.
+	Save(acc Account, passwordHash []byte, messages ...outbox.Message) error
+
+	// ChangePassword applies the password change in a single transaction.
 	// If Account has no ID, returns ErrNoIdentity.
 	// Other errors may occur.
-	Save(acc Account, passwordHash []byte) error
+	ChangePassword(change PasswordChange) error
+
+	// FindByID retrieves an Account for matching id.
+	// If account with such id not found, returns ErrNotFound.
+	// Other errors may occur.
+	FindByID(id int64) (*Account, error)
+
+	// List retrieves a page of accounts ordered by ID, along with the total
+	// number of accounts. If query is not empty, only accounts which name
+	// contains query are retrieved.
+	// Accounts of all tenants are retrieved, so it is only meant for operators.
+	List(query string, offset, limit int) (accounts []*Account, total int, err error)
 
-	// FindWithPasswordHashByUsername retrieves an Account for matching name with it's password.
+	// FindWithPasswordHashByUsername retrieves an Account for matching name in the tenant with it's password.
 	// If account with such username not found, returns ErrNotFound.
 	// Other errors may occur.
-	FindWithPasswordHashByUsername(name string) (account *Account, password []byte, err error)
+	FindWithPasswordHashByUsername(tenantID int64, name string) (account *Account, password []byte, err error)
+
+	// FindWithPasswordHashByID retrieves an Account for matching id with it's password.
+	// If account with such id not found, returns ErrNotFound.
+	// Other errors may occur.
+	FindWithPasswordHashByID(id int64) (account *Account, password []byte, err error)
+
+	// UpdateStatus sets the status of the account.
+	// When the account becomes not active, all its sessions are revoked,
+	// and the outbox messages are added along with it, in the same transaction.
+	// If account with such id not found, returns ErrNotFound.
+	// Other errors may occur.
+	UpdateStatus(id int64, status Status, messages ...outbox.Message) error
 
-	// Exists checks whether account with such username exists.
-	Exists(username string) (bool, error)
+	// Verify marks the account name as verified at verifiedAt.
+	// Accounts which are verified already keep the time of the first verification.
+	// If account with such id not found, returns ErrNotFound.
+	// Other errors may occur.
+	Verify(id int64, verifiedAt time.Time) error
+
+	// RegisterFailedLogin increments the failed login attempts counter of the account.
+	// When the counter reaches threshold, the account gets locked until lockedUntil
+	// and the counter starts over. Returns whether the account got locked.
+	RegisterFailedLogin(id int64, threshold int, lockedUntil time.Time) (locked bool, err error)
+
+	// ResetFailedLogins resets the failed login attempts counter of the account.
+	ResetFailedLogins(id int64) error
+
+	// Delete removes the account with all its sessions.
+	// The outbox messages are added along with the removal, in the same transaction.
+	Delete(id int64, messages ...outbox.Message) error
+
+	// Exists checks whether account with such username exists in the tenant.
+	Exists(tenantID int64, username string) (bool, error)
 }
 
 // repositoryError is an error occurred in Repository.
@@ -32,4 +104,7 @@ const (
 
 	// ErrNotFound occurs when account not found.
 	ErrNotFound = repositoryError("Account not found")
+
+	// ErrAlreadyExists occurs when account with the same name already exists in the tenant.
+	ErrAlreadyExists = repositoryError("Account already exists")
 )
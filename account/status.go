@@ -0,0 +1,27 @@
+package account
+
+// Status is a lifecycle status of an Account.
+type Status string
+
+const (
+	// StatusActive is a status of an account that can be used normally.
+	StatusActive = Status("active")
+
+	// StatusDisabled is a status of an account that was disabled and can not be used.
+	StatusDisabled = Status("disabled")
+
+	// StatusSuspended is a status of an account that was temporarily suspended and can not be used.
+	StatusSuspended = Status("suspended")
+
+	// StatusPending is a status of an account that is not yet confirmed and can not be used.
+	StatusPending = Status("pending")
+)
+
+// IsValid reports whether s is one of the known statuses.
+func (s Status) IsValid() bool {
+	switch s {
+	case StatusActive, StatusDisabled, StatusSuspended, StatusPending:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,31 @@
+package account
+
+import (
+	"testing"
+)
+
+func TestStatus_IsValid(t *testing.T) {
+	cases := []struct {
+		status   Status
+		expected bool
+	}{
+		{StatusActive, true},
+		{StatusDisabled, true},
+		{StatusSuspended, true},
+		{StatusPending, true},
+		{Status(""), false},
+		{Status("deleted"), false},
+	}
+
+	for i, c := range cases {
+		if actual := c.status.IsValid(); actual != c.expected {
+			t.Errorf(
+				"testcase %d: Expected IsValid of %q to be %v but got %v\n",
+				i,
+				c.status,
+				c.expected,
+				actual,
+			)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package accounts
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/hypnoglow/pascont/kit/middleware"
+	"github.com/hypnoglow/pascont/session"
+)
+
+// authorizedAccountID returns the ID of the account the request is authorized with,
+// either by an API key or by a session. The account MUST be the one from the request path,
+// where session.PathIDMe stands for the authorized account.
+// On failure, returns a status code to respond with.
+func (c RestController) authorizedAccountID(req *http.Request) (id int64, status int) {
+	keyAccountID, ok := req.Context().Value(middleware.ContextKeyAccountID{}).(int64)
+	if !ok {
+		sess, status := session.AccountSession(req, c.sessionRepo, c.errorLogger)
+		if sess == nil {
+			return 0, status
+		}
+
+		return sess.AccountID, http.StatusOK
+	}
+
+	pathID, _ := middleware.PathParam(req, "id")
+	if pathID == session.PathIDMe {
+		return keyAccountID, http.StatusOK
+	}
+
+	accountID, err := strconv.ParseInt(pathID, 10, 64)
+	if err != nil {
+		return 0, http.StatusNotFound
+	}
+
+	// Account ID from path and from the API key MUST match in order to proceed.
+	if accountID != keyAccountID {
+		return 0, http.StatusUnauthorized
+	}
+
+	return keyAccountID, http.StatusOK
+}
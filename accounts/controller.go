@@ -2,34 +2,90 @@ package accounts
 
 import (
 	"log"
+	"time"
 
 	"github.com/hypnoglow/pascont/account"
 	"github.com/hypnoglow/pascont/hasher"
+	"github.com/hypnoglow/pascont/identity"
+	"github.com/hypnoglow/pascont/notary"
+	"github.com/hypnoglow/pascont/outbox"
+	"github.com/hypnoglow/pascont/packer"
+	"github.com/hypnoglow/pascont/password"
+	"github.com/hypnoglow/pascont/session"
+	"github.com/hypnoglow/pascont/verification"
+	"github.com/hypnoglow/pascont/webhook"
 )
 
 const (
-	PathAccounts = "/accounts"
+	PathAccounts        = "/accounts"
+	PathAccount         = "/accounts/"
+	PathAccountByID     = "/accounts/:id"
+	PathAccountPassword = "/accounts/:id/password"
 )
 
 // RestController is a REST controller for accounts.
 type RestController struct {
-	errorLogger *log.Logger
-	accountRepo account.Repository
-	hasher      hasher.Hasher
-	options     Options
+	errorLogger  *log.Logger
+	accountRepo  account.Repository
+	sessionRepo  session.Repository
+	notary       notary.Notary
+	packer       packer.Packer
+	hasher       hasher.Hasher
+	uuidProducer identity.UUIDProducer
+	options      Options
 }
 
 // Options is a structure holding accounts RestController specific options.
 type Options struct {
-	// no options currently
+	// SessionSecretKey is a key to sign tokens of sessions issued by the controller.
+	SessionSecretKey []byte
+
+	// SessionDuration is a duration of sessions issued by the controller.
+	SessionDuration time.Duration
+
+	// LockoutThreshold is a number of consecutive failed logins
+	// after which the account gets locked. Wrong passwords confirming
+	// account changes count as failed logins. Zero disables the lockout.
+	LockoutThreshold int
+
+	// LockoutDuration is how long the account stays locked.
+	LockoutDuration time.Duration
+
+	// PasswordPolicy is a policy for new account passwords.
+	PasswordPolicy password.Policy
+
+	// Verification sends verification tokens to holders of new accounts.
+	// If nil, new accounts are verified on creation.
+	Verification verification.Sender
+
+	// Outbox routes password change alerts, which are saved along with the account.
+	// The zero Router sends no alerts.
+	Outbox outbox.Router
+
+	// Events emits account events to webhook subscriptions.
+	// If nil, no events are emitted.
+	Events webhook.Emitter
 }
 
 // NewRestController returns a new RestController.
 func NewRestController(
 	logger *log.Logger,
 	accountRepo account.Repository,
-	hasher hasher.Hasher,
+	sessionRepo session.Repository,
+	n notary.Notary,
+	p packer.Packer,
+	h hasher.Hasher,
+	u identity.UUIDProducer,
 	opts Options,
 ) RestController {
-	return RestController{logger, accountRepo, hasher, opts}
+	return RestController{
+		logger,
+		accountRepo,
+		sessionRepo,
+		n,
+		p,
+		h,
+		u,
+		opts,
+	}
 }
@@ -0,0 +1,73 @@
+package accounts
+
+import (
+	"net/http"
+
+	"github.com/hypnoglow/pascont/account"
+	"github.com/hypnoglow/pascont/kit"
+	"github.com/hypnoglow/pascont/kit/form"
+	"github.com/hypnoglow/pascont/kit/schema"
+	"github.com/hypnoglow/pascont/session"
+	"github.com/hypnoglow/pascont/webhook"
+)
+
+// DeleteAccount is a handler for:
+// DELETE /accounts/:id
+//
+// The account is removed with all its sessions.
+func (c RestController) DeleteAccount(w http.ResponseWriter, req *http.Request) {
+	current, status := session.AccountSession(req, c.sessionRepo, c.errorLogger)
+	if current == nil {
+		w.WriteHeader(status)
+		return
+	}
+
+	var accForm deleteAccountForm
+	form.PopulateFormFromJSON(req.Body, &accForm)
+	if !accForm.Validate() {
+		kit.RespondWithFormErrors(w, http.StatusBadRequest, accForm.ValidationErrors())
+		return
+	}
+
+	acc, passwordHash, err := c.accountRepo.FindWithPasswordHashByID(current.AccountID)
+	if err != nil {
+		if err == account.ErrNotFound {
+			w.WriteHeader(http.StatusNotFound)
+		} else {
+			c.errorLogger.Println(err)
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		return
+	}
+
+	// Deletion MUST be confirmed with the password.
+	if !c.confirmPassword(w, acc, passwordHash, accForm.Password, schema.NewError(
+		"Password is incorrect",
+		"password",
+		nil,
+	)) {
+		return
+	}
+
+	revoked := webhook.Messages(c.options.Events, c.errorLogger, webhook.SessionRevokedEvent(acc.TenantID, acc.ID, ""))
+	if err := c.accountRepo.Delete(acc.ID, revoked...); err != nil {
+		c.errorLogger.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type deleteAccountForm struct {
+	form.BaseForm
+	Password string `json:"password"`
+}
+
+func (f *deleteAccountForm) Validate() bool {
+	if len(f.Password) == 0 {
+		f.AddError("Password must not be empty", "password", nil)
+	}
+
+	return len(f.ValidationErrors()) == 0
+}
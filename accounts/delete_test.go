@@ -0,0 +1,355 @@
+package accounts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/pascont/account"
+	"github.com/hypnoglow/pascont/hasher"
+	"github.com/hypnoglow/pascont/kit/middleware"
+	"github.com/hypnoglow/pascont/session"
+)
+
+func TestRestController_DeleteAccount(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	fakeLogger := log.New(ioutil.Discard, "", log.LstdFlags)
+
+	currentSession := &session.Session{
+		ID:        "12345678-90ab-cdef-0123-4567890abcde",
+		AccountID: 123,
+		CreatedAt: now,
+		ExpiresAt: now.Add(time.Hour),
+	}
+	acc := &account.Account{
+		ID:        123,
+		Name:      "email@email.com",
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	lockedAcc := &account.Account{
+		ID:          123,
+		Name:        "email@email.com",
+		CreatedAt:   now,
+		UpdatedAt:   now,
+		LockedUntil: time.Now().Add(time.Hour),
+	}
+
+	cases := []struct {
+		caseName string
+		// in
+		accRepo                  account.Repository
+		hasher                   hasher.Hasher
+		reqContextSessionIDValue interface{}
+		reqBody                  io.Reader
+		// out
+		expectedCode int
+		expectedBody *bytes.Buffer
+	}{
+		{
+			caseName: "There is no session ID from Authorization Header",
+			reqBody:  bytes.NewBufferString(`{"password":"password"}`),
+			// out
+			expectedCode: http.StatusUnauthorized,
+			expectedBody: bytes.NewBuffer(nil),
+		},
+		{
+			caseName:                 "Empty password should result in 400",
+			reqContextSessionIDValue: currentSession.ID,
+			reqBody:                  bytes.NewBufferString(`{"password":""}`),
+			// out
+			expectedCode: http.StatusBadRequest,
+			expectedBody: bytes.NewBufferString(`{
+				"errors":[
+					{
+						"message":"Password must not be empty",
+						"field":"password"
+					}
+				]
+			}`),
+		},
+		{
+			caseName: "Incorrect password should result in 403",
+			accRepo: account.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				nil,
+				[]account.FakeRepositoryFindWithPasswordHashByIDResult{
+					{
+						Account:      acc,
+						PasswordHash: []byte("password_hash"),
+					},
+				},
+				nil,
+				nil,
+				[]account.FakeRepositoryRegisterFailedLoginResult{
+					{
+						Locked: false,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			hasher: hasher.NewFakeHasher(
+				nil,
+				[]hasher.FakeCompareHashWithPasswordResult{
+					{
+						Error: fmt.Errorf("No match"),
+					},
+				},
+			),
+			reqContextSessionIDValue: currentSession.ID,
+			reqBody:                  bytes.NewBufferString(`{"password":"wrong_password"}`),
+			// out
+			expectedCode: http.StatusForbidden,
+			expectedBody: bytes.NewBufferString(`{
+				"errors":[
+					{
+						"message":"Password is incorrect",
+						"field":"password"
+					}
+				]
+			}`),
+		},
+		{
+			caseName: "Incorrect password locking the account should result in 429",
+			accRepo: account.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				nil,
+				[]account.FakeRepositoryFindWithPasswordHashByIDResult{
+					{
+						Account:      acc,
+						PasswordHash: []byte("password_hash"),
+					},
+				},
+				nil,
+				nil,
+				[]account.FakeRepositoryRegisterFailedLoginResult{
+					{
+						Locked: true,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			hasher: hasher.NewFakeHasher(
+				nil,
+				[]hasher.FakeCompareHashWithPasswordResult{
+					{
+						Error: fmt.Errorf("No match"),
+					},
+				},
+			),
+			reqContextSessionIDValue: currentSession.ID,
+			reqBody:                  bytes.NewBufferString(`{"password":"wrong_password"}`),
+			// out
+			expectedCode: http.StatusTooManyRequests,
+			expectedBody: bytes.NewBufferString(`{
+				"errors":[
+					{
+						"message":"Account is temporarily locked due to too many failed login attempts"
+					}
+				]
+			}`),
+		},
+		{
+			caseName: "Locked account should result in 429",
+			accRepo: account.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				nil,
+				[]account.FakeRepositoryFindWithPasswordHashByIDResult{
+					{
+						Account:      lockedAcc,
+						PasswordHash: []byte("password_hash"),
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			hasher:                   hasher.NewFakeHasher(nil, nil),
+			reqContextSessionIDValue: currentSession.ID,
+			reqBody:                  bytes.NewBufferString(`{"password":"wrong_password"}`),
+			// out
+			expectedCode: http.StatusTooManyRequests,
+			expectedBody: bytes.NewBufferString(`{
+				"errors":[
+					{
+						"message":"Account is temporarily locked due to too many failed login attempts"
+					}
+				]
+			}`),
+		},
+		{
+			caseName: "Error on accountRepo.Delete should result in 500",
+			accRepo: account.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				nil,
+				[]account.FakeRepositoryFindWithPasswordHashByIDResult{
+					{
+						Account:      acc,
+						PasswordHash: []byte("password_hash"),
+					},
+				},
+				[]account.FakeRepositoryDeleteResult{
+					{
+						Error: fmt.Errorf("Delete failed"),
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			hasher: hasher.NewFakeHasher(
+				nil,
+				[]hasher.FakeCompareHashWithPasswordResult{
+					{
+						Error: nil,
+					},
+				},
+			),
+			reqContextSessionIDValue: currentSession.ID,
+			reqBody:                  bytes.NewBufferString(`{"password":"password"}`),
+			// out
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: bytes.NewBuffer(nil),
+		},
+		{
+			caseName: "Successful",
+			accRepo: account.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				nil,
+				[]account.FakeRepositoryFindWithPasswordHashByIDResult{
+					{
+						Account:      acc,
+						PasswordHash: []byte("password_hash"),
+					},
+				},
+				[]account.FakeRepositoryDeleteResult{
+					{
+						Error: nil,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			hasher: hasher.NewFakeHasher(
+				nil,
+				[]hasher.FakeCompareHashWithPasswordResult{
+					{
+						Error: nil,
+					},
+				},
+			),
+			reqContextSessionIDValue: currentSession.ID,
+			reqBody:                  bytes.NewBufferString(`{"password":"password"}`),
+			// out
+			expectedCode: http.StatusNoContent,
+			expectedBody: bytes.NewBuffer(nil),
+		},
+	}
+
+	for i, c := range cases {
+		ctrl := NewRestController(
+			fakeLogger,
+			c.accRepo,
+			session.NewFakeRepository(
+				nil,
+				[]session.FakeRepositoryFindByIDResult{
+					{
+						Session: currentSession,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			nil,
+			nil,
+			c.hasher,
+			nil,
+			Options{
+				LockoutThreshold: 3,
+				LockoutDuration:  10 * time.Minute,
+			},
+		)
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodDelete, "/accounts/123", c.reqBody)
+		req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyPathParams{}, map[string]string{"id": "123"}))
+		req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeySessionID{}, c.reqContextSessionIDValue))
+		ctrl.DeleteAccount(w, req)
+
+		if w.Code != c.expectedCode {
+			t.Errorf(
+				"testcase %d %s:\nExpected status code to be %v, but got %v\n",
+				i,
+				c.caseName,
+				c.expectedCode,
+				w.Code,
+			)
+		}
+
+		// To make possible pretty formatting in test cases, we use json.Compact:
+		b := c.expectedBody.Bytes()
+		if b != nil {
+			c.expectedBody.Reset()
+			err := json.Compact(c.expectedBody, b)
+			if err != nil {
+				t.Errorf(
+					"testcase %d %s:\nFailed to compact JSON body: %s",
+					i,
+					c.caseName,
+					err,
+				)
+			}
+		}
+
+		if !bytes.Equal(w.Body.Bytes(), c.expectedBody.Bytes()) {
+			t.Errorf(
+				"testcase %d %s:\nExpected body to be\n%v\nbut got\n%v\n",
+				i,
+				c.caseName,
+				c.expectedBody,
+				w.Body,
+			)
+		}
+	}
+}
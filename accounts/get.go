@@ -0,0 +1,53 @@
+package accounts
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/hypnoglow/pascont/account"
+	"github.com/hypnoglow/pascont/kit"
+	"github.com/hypnoglow/pascont/kit/schema"
+)
+
+// GetAccount is a handler for:
+// GET /accounts/:id
+// GET /accounts/me
+func (c RestController) GetAccount(w http.ResponseWriter, req *http.Request) {
+	accountID, status := c.authorizedAccountID(req)
+	if accountID == 0 {
+		w.WriteHeader(status)
+		return
+	}
+
+	acc, err := c.accountRepo.FindByID(accountID)
+	if err != nil {
+		if err == account.ErrNotFound {
+			w.WriteHeader(http.StatusNotFound)
+		} else {
+			c.errorLogger.Println(err)
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		return
+	}
+
+	kit.RespondJSON(w, http.StatusOK, schema.NewResultBody(
+		getAccountSchema{
+			ID:        acc.ID,
+			TenantID:  acc.TenantID,
+			Name:      acc.Name,
+			CreatedAt: acc.CreatedAt,
+			UpdatedAt: acc.UpdatedAt,
+			Verified:  acc.IsVerified(),
+		},
+		nil,
+	))
+}
+
+type getAccountSchema struct {
+	ID        int64     `json:"id"`
+	TenantID  int64     `json:"tenantID"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	Verified  bool      `json:"verified"`
+}
@@ -0,0 +1,368 @@
+package accounts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/pascont/account"
+	"github.com/hypnoglow/pascont/kit/middleware"
+	"github.com/hypnoglow/pascont/session"
+)
+
+func TestRestController_GetAccount(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	fakeLogger := log.New(ioutil.Discard, "", log.LstdFlags)
+
+	currentSession := &session.Session{
+		ID:        "12345678-90ab-cdef-0123-4567890abcde",
+		AccountID: 123,
+		CreatedAt: now,
+		ExpiresAt: now.Add(time.Hour),
+	}
+
+	cases := []struct {
+		caseName string
+		// in
+		accRepo                  account.Repository
+		sessRepo                 session.Repository
+		reqContextSessionIDValue interface{}
+		reqContextAccountIDValue interface{}
+		reqPathID                string
+		// out
+		expectedCode int
+		expectedBody *bytes.Buffer
+	}{
+		{
+			caseName:  "The account id from path is neither a number nor me",
+			reqPathID: "abc",
+			// out
+			expectedCode: http.StatusNotFound,
+			expectedBody: bytes.NewBuffer(nil),
+		},
+		{
+			caseName:  "There is no session ID from Authorization Header",
+			reqPathID: "me",
+			// out
+			expectedCode: http.StatusUnauthorized,
+			expectedBody: bytes.NewBuffer(nil),
+		},
+		{
+			caseName: "Session belongs to another account",
+			sessRepo: session.NewFakeRepository(
+				nil,
+				[]session.FakeRepositoryFindByIDResult{
+					{
+						Session: currentSession,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			reqContextSessionIDValue: currentSession.ID,
+			reqPathID:                "456",
+			// out
+			expectedCode: http.StatusUnauthorized,
+			expectedBody: bytes.NewBuffer(nil),
+		},
+		{
+			caseName: "accountRepo.FindByID returns ErrNotFound",
+			accRepo: account.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				[]account.FakeRepositoryFindByIDResult{
+					{
+						Error: account.ErrNotFound,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			sessRepo: session.NewFakeRepository(
+				nil,
+				[]session.FakeRepositoryFindByIDResult{
+					{
+						Session: currentSession,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			reqContextSessionIDValue: currentSession.ID,
+			reqPathID:                "me",
+			// out
+			expectedCode: http.StatusNotFound,
+			expectedBody: bytes.NewBuffer(nil),
+		},
+		{
+			caseName: "accountRepo.FindByID failed",
+			accRepo: account.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				[]account.FakeRepositoryFindByIDResult{
+					{
+						Error: fmt.Errorf("FindByID failed"),
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			sessRepo: session.NewFakeRepository(
+				nil,
+				[]session.FakeRepositoryFindByIDResult{
+					{
+						Session: currentSession,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			reqContextSessionIDValue: currentSession.ID,
+			reqPathID:                "123",
+			// out
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: bytes.NewBuffer(nil),
+		},
+		{
+			caseName: "Successful with me",
+			accRepo: account.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				[]account.FakeRepositoryFindByIDResult{
+					{
+						Account: &account.Account{
+							ID:        123,
+							TenantID:  1,
+							Name:      "email@email.com",
+							CreatedAt: now,
+							UpdatedAt: now,
+						},
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			sessRepo: session.NewFakeRepository(
+				nil,
+				[]session.FakeRepositoryFindByIDResult{
+					{
+						Session: currentSession,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			reqContextSessionIDValue: currentSession.ID,
+			reqPathID:                "me",
+			// out
+			expectedCode: http.StatusOK,
+			expectedBody: bytes.NewBufferString(fmt.Sprintf(`{
+				"result":{
+					"id":123,
+					"tenantID":1,
+					"name":"email@email.com",
+					"createdAt":"%s",
+					"updatedAt":"%s",
+					"verified":false
+				}
+			}`, now.Format(time.RFC3339), now.Format(time.RFC3339))),
+		},
+		{
+			caseName: "Successful with account id",
+			accRepo: account.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				[]account.FakeRepositoryFindByIDResult{
+					{
+						Account: &account.Account{
+							ID:        123,
+							TenantID:  1,
+							Name:      "email@email.com",
+							CreatedAt: now,
+							UpdatedAt: now,
+						},
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			sessRepo: session.NewFakeRepository(
+				nil,
+				[]session.FakeRepositoryFindByIDResult{
+					{
+						Session: currentSession,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			reqContextSessionIDValue: currentSession.ID,
+			reqPathID:                "123",
+			// out
+			expectedCode: http.StatusOK,
+			expectedBody: bytes.NewBufferString(fmt.Sprintf(`{
+				"result":{
+					"id":123,
+					"tenantID":1,
+					"name":"email@email.com",
+					"createdAt":"%s",
+					"updatedAt":"%s",
+					"verified":false
+				}
+			}`, now.Format(time.RFC3339), now.Format(time.RFC3339))),
+		},
+		{
+			caseName:                 "API key belongs to another account",
+			reqContextAccountIDValue: int64(123),
+			reqPathID:                "456",
+			// out
+			expectedCode: http.StatusUnauthorized,
+			expectedBody: bytes.NewBuffer(nil),
+		},
+		{
+			caseName: "Successful with API key",
+			accRepo: account.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				[]account.FakeRepositoryFindByIDResult{
+					{
+						Account: &account.Account{
+							ID:        123,
+							TenantID:  1,
+							Name:      "email@email.com",
+							CreatedAt: now,
+							UpdatedAt: now,
+						},
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			reqContextAccountIDValue: int64(123),
+			reqPathID:                "me",
+			// out
+			expectedCode: http.StatusOK,
+			expectedBody: bytes.NewBufferString(fmt.Sprintf(`{
+				"result":{
+					"id":123,
+					"tenantID":1,
+					"name":"email@email.com",
+					"createdAt":"%s",
+					"updatedAt":"%s",
+					"verified":false
+				}
+			}`, now.Format(time.RFC3339), now.Format(time.RFC3339))),
+		},
+	}
+
+	for i, c := range cases {
+		ctrl := NewRestController(
+			fakeLogger,
+			c.accRepo,
+			c.sessRepo,
+			nil,
+			nil,
+			nil,
+			nil,
+			Options{},
+		)
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/accounts/"+c.reqPathID, nil)
+		req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyPathParams{}, map[string]string{"id": c.reqPathID}))
+		req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeySessionID{}, c.reqContextSessionIDValue))
+		req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyAccountID{}, c.reqContextAccountIDValue))
+		ctrl.GetAccount(w, req)
+
+		if w.Code != c.expectedCode {
+			t.Errorf(
+				"testcase %d %s:\nExpected status code to be %v, but got %v\n",
+				i,
+				c.caseName,
+				c.expectedCode,
+				w.Code,
+			)
+		}
+
+		// To make possible pretty formatting in test cases, we use json.Compact:
+		b := c.expectedBody.Bytes()
+		if b != nil {
+			c.expectedBody.Reset()
+			err := json.Compact(c.expectedBody, b)
+			if err != nil {
+				t.Errorf(
+					"testcase %d %s:\nFailed to compact JSON body: %s",
+					i,
+					c.caseName,
+					err,
+				)
+			}
+		}
+
+		if !bytes.Equal(w.Body.Bytes(), c.expectedBody.Bytes()) {
+			t.Errorf(
+				"testcase %d %s:\nExpected body to be\n%v\nbut got\n%v\n",
+				i,
+				c.caseName,
+				c.expectedBody,
+				w.Body,
+			)
+		}
+	}
+}
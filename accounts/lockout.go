@@ -0,0 +1,67 @@
+package accounts
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/hypnoglow/pascont/account"
+	"github.com/hypnoglow/pascont/kit"
+	"github.com/hypnoglow/pascont/kit/schema"
+	"github.com/hypnoglow/pascont/sessions"
+)
+
+// confirmPassword checks the password confirming an action on the account.
+// Wrong passwords count as failed logins, so guessing them is subject to the lockout,
+// and passwords of locked accounts are not checked until the lock expires.
+// It responds with incorrect if the password is wrong and returns whether the password is confirmed.
+func (c RestController) confirmPassword(
+	w http.ResponseWriter,
+	acc *account.Account,
+	passwordHash []byte,
+	password string,
+	incorrect schema.Error,
+) bool {
+	now := time.Now().UTC()
+	if acc.IsLocked(now) {
+		sessions.RespondLocked(w, acc.LockedUntil.Sub(now))
+		return false
+	}
+
+	if err := c.hasher.CompareHashWithPassword(passwordHash, []byte(password)); err != nil {
+		c.respondIncorrectPassword(w, acc.ID, now, incorrect)
+		return false
+	}
+
+	if acc.FailedLoginAttempts > 0 {
+		if err := c.accountRepo.ResetFailedLogins(acc.ID); err != nil {
+			c.errorLogger.Println(err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return false
+		}
+	}
+
+	return true
+}
+
+// respondIncorrectPassword registers a failed login of the account at the moment now
+// and responds whether the account got locked.
+func (c RestController) respondIncorrectPassword(w http.ResponseWriter, accountID int64, now time.Time, incorrect schema.Error) {
+	if c.options.LockoutThreshold > 0 {
+		locked, err := c.accountRepo.RegisterFailedLogin(
+			accountID,
+			c.options.LockoutThreshold,
+			now.Add(c.options.LockoutDuration),
+		)
+		if err != nil {
+			c.errorLogger.Println(err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if locked {
+			sessions.RespondLocked(w, c.options.LockoutDuration)
+			return
+		}
+	}
+
+	kit.RespondWithError(w, http.StatusForbidden, incorrect)
+}
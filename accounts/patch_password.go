@@ -0,0 +1,167 @@
+package accounts
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/hypnoglow/pascont/account"
+	"github.com/hypnoglow/pascont/kit"
+	"github.com/hypnoglow/pascont/kit/form"
+	"github.com/hypnoglow/pascont/kit/schema"
+	"github.com/hypnoglow/pascont/notifier"
+	"github.com/hypnoglow/pascont/outbox"
+	"github.com/hypnoglow/pascont/password"
+	"github.com/hypnoglow/pascont/session"
+	"github.com/hypnoglow/pascont/webhook"
+)
+
+// PatchAccountPassword is a handler for:
+// PATCH /accounts/:id/password
+//
+// On success, all account sessions are revoked
+// and a new session is issued for the caller.
+func (c RestController) PatchAccountPassword(w http.ResponseWriter, req *http.Request) {
+	current, status := session.AccountSession(req, c.sessionRepo, c.errorLogger)
+	if current == nil {
+		w.WriteHeader(status)
+		return
+	}
+
+	acc, passwordHash, err := c.accountRepo.FindWithPasswordHashByID(current.AccountID)
+	if err != nil {
+		if err == account.ErrNotFound {
+			w.WriteHeader(http.StatusNotFound)
+		} else {
+			c.errorLogger.Println(err)
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		return
+	}
+
+	// The account is needed to validate the new password against the policy.
+	passwordForm := patchPasswordForm{policy: c.options.PasswordPolicy, name: acc.Name}
+	form.PopulateFormFromJSON(req.Body, &passwordForm)
+	if !passwordForm.Validate() {
+		if passwordForm.policyErr != nil {
+			c.errorLogger.Println(passwordForm.policyErr)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		kit.RespondWithFormErrors(w, http.StatusBadRequest, passwordForm.ValidationErrors())
+		return
+	}
+
+	if !c.confirmPassword(w, acc, passwordHash, passwordForm.CurrentPassword, schema.NewError(
+		"Current password is incorrect",
+		"currentPassword",
+		nil,
+	)) {
+		return
+	}
+
+	newPasswordHash, err := c.hasher.GenerateHashFromPassword([]byte(passwordForm.NewPassword))
+	if err != nil {
+		c.errorLogger.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	acc.UpdatedAt = time.Now().UTC().Truncate(time.Second)
+	sess := session.CreateSession(c.uuidProducer, acc.ID, c.options.SessionDuration)
+	sess.TenantID = acc.TenantID
+	sess.IP = kit.ClientIP(req)
+	sess.UserAgent = session.TruncateUserAgent(req.UserAgent())
+
+	// Other sessions are revoked and the caller's one is issued along with the new password,
+	// so a failure leaves both the password and the sessions as they were.
+	err = c.accountRepo.ChangePassword(account.PasswordChange{
+		Account:      *acc,
+		PasswordHash: newPasswordHash,
+		Session:      sess,
+		Messages: append(
+			c.options.Outbox.Messages(PasswordChangedNotification(*acc)),
+			PasswordChangedEvents(c.options.Events, c.errorLogger, *acc)...,
+		),
+	})
+	if err != nil {
+		c.errorLogger.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	token, err := sess.Token(c.notary, c.packer, c.options.SessionSecretKey)
+	if err != nil {
+		c.errorLogger.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	kit.RespondJSON(w, http.StatusOK, schema.NewResultBody(
+		patchPasswordSchema{
+			Token:     token,
+			ID:        sess.ID,
+			AccountID: sess.AccountID,
+			CreatedAt: sess.CreatedAt,
+			ExpiresAt: sess.ExpiresAt,
+		},
+		nil,
+	))
+}
+
+// PasswordChangedNotification returns an alert about the password change of the account.
+func PasswordChangedNotification(acc account.Account) notifier.Notification {
+	return notifier.Notification{
+		Kind:      notifier.KindPasswordChanged,
+		TenantID:  acc.TenantID,
+		AccountID: acc.ID,
+		Recipient: acc.Name,
+		Params: map[string]string{
+			"changedAt": acc.UpdatedAt.Format(time.RFC3339),
+		},
+	}
+}
+
+// PasswordChangedEvents returns the outbox messages telling the subscribers to events with em
+// about the password change of the account, which revokes all its sessions.
+func PasswordChangedEvents(em webhook.Emitter, logger *log.Logger, acc account.Account) []outbox.Message {
+	return webhook.Messages(
+		em,
+		logger,
+		webhook.Event{
+			Type:      webhook.EventPasswordChanged,
+			TenantID:  acc.TenantID,
+			AccountID: acc.ID,
+		},
+		webhook.SessionRevokedEvent(acc.TenantID, acc.ID, ""),
+	)
+}
+
+type patchPasswordForm struct {
+	form.BaseForm
+	CurrentPassword string `json:"currentPassword"`
+	NewPassword     string `json:"newPassword"`
+
+	policy    password.Policy
+	policyErr error
+	name      string
+}
+
+func (f *patchPasswordForm) Validate() bool {
+	if len(f.CurrentPassword) == 0 {
+		f.AddError("Current password must not be empty", "currentPassword", nil)
+	}
+
+	f.policyErr = f.policy.Validate(f, "newPassword", f.name, f.NewPassword)
+
+	return f.policyErr == nil && len(f.ValidationErrors()) == 0
+}
+
+// patchPasswordSchema represents the session issued on password change.
+type patchPasswordSchema struct {
+	Token     string    `json:"token"`
+	ID        string    `json:"id"`
+	AccountID int64     `json:"accountID"`
+	CreatedAt time.Time `json:"createdAt"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
@@ -0,0 +1,628 @@
+package accounts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/pascont/account"
+	"github.com/hypnoglow/pascont/hasher"
+	"github.com/hypnoglow/pascont/kit/form"
+	"github.com/hypnoglow/pascont/kit/middleware"
+	"github.com/hypnoglow/pascont/notary"
+	"github.com/hypnoglow/pascont/notifier"
+	"github.com/hypnoglow/pascont/packer"
+	"github.com/hypnoglow/pascont/password"
+	"github.com/hypnoglow/pascont/session"
+)
+
+func TestPatchPasswordForm_Validate(t *testing.T) {
+	cases := []struct {
+		caseName        string
+		CurrentPassword string
+		NewPassword     string
+		policy          password.Policy
+		expectedErrors  []form.FormError
+	}{
+		{
+			caseName:        "Current password is empty",
+			CurrentPassword: "",
+			NewPassword:     "new_password",
+			policy:          password.DefaultPolicy,
+			expectedErrors: []form.FormError{
+				{
+					Message: "Current password must not be empty",
+					Field:   "currentPassword",
+					Value:   nil,
+				},
+			},
+		},
+		{
+			caseName:        "New password is too short",
+			CurrentPassword: "password",
+			NewPassword:     "short",
+			policy:          password.DefaultPolicy,
+			expectedErrors: []form.FormError{
+				{
+					Message: "Password must be at least 8 characters",
+					Field:   "newPassword",
+					Value:   nil,
+					Code:    password.CodeTooShort,
+				},
+			},
+		},
+		{
+			caseName:        "New password contains the account name",
+			CurrentPassword: "password",
+			NewPassword:     "EMAIL@email.com!",
+			policy:          password.Policy{ForbidName: true},
+			expectedErrors: []form.FormError{
+				{
+					Message: "Password must not contain the account name",
+					Field:   "newPassword",
+					Value:   nil,
+					Code:    password.CodeContainsName,
+				},
+			},
+		},
+		{
+			caseName:        "Valid",
+			CurrentPassword: "password",
+			NewPassword:     "new_password",
+			policy:          password.DefaultPolicy,
+			expectedErrors:  nil,
+		},
+	}
+
+	for i, c := range cases {
+		f := &patchPasswordForm{
+			CurrentPassword: c.CurrentPassword,
+			NewPassword:     c.NewPassword,
+			policy:          c.policy,
+			name:            "email@email.com",
+		}
+
+		f.Validate()
+
+		if !reflect.DeepEqual(f.ValidationErrors(), c.expectedErrors) {
+			t.Errorf(
+				"testcase %d %s\nExpected validation errors to equal\n%#v\n, but got\n%#v\n",
+				i,
+				c.caseName,
+				c.expectedErrors,
+				f.ValidationErrors(),
+			)
+		}
+	}
+}
+
+func TestRestController_PatchAccountPassword(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	fakeLogger := log.New(ioutil.Discard, "", log.LstdFlags)
+	uuidProducer := func() string {
+		return "99999999-90ab-cdef-0123-4567890abcde"
+	}
+	sessionDuration := time.Hour
+
+	currentSession := &session.Session{
+		ID:        "12345678-90ab-cdef-0123-4567890abcde",
+		AccountID: 123,
+		CreatedAt: now,
+		ExpiresAt: now.Add(sessionDuration),
+	}
+	acc := &account.Account{
+		ID:        123,
+		Name:      "email@email.com",
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	lockedAcc := &account.Account{
+		ID:          123,
+		Name:        "email@email.com",
+		CreatedAt:   now,
+		UpdatedAt:   now,
+		LockedUntil: time.Now().Add(time.Hour),
+	}
+	validBody := `{"currentPassword":"password","newPassword":"new_password"}`
+
+	cases := []struct {
+		caseName string
+		// in
+		accRepo                  account.Repository
+		sessRepo                 session.Repository
+		notary                   notary.Notary
+		packer                   packer.Packer
+		hasher                   hasher.Hasher
+		reqContextSessionIDValue interface{}
+		reqBody                  io.Reader
+		// out
+		expectedCode int
+		expectedBody *bytes.Buffer
+	}{
+		{
+			caseName: "There is no session ID from Authorization Header",
+			reqBody:  bytes.NewBufferString(validBody),
+			// out
+			expectedCode: http.StatusUnauthorized,
+			expectedBody: bytes.NewBuffer(nil),
+		},
+		{
+			caseName: "Invalid input form",
+			accRepo: account.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				nil,
+				[]account.FakeRepositoryFindWithPasswordHashByIDResult{
+					{
+						Account:      acc,
+						PasswordHash: []byte("password_hash"),
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			sessRepo: session.NewFakeRepository(
+				nil,
+				[]session.FakeRepositoryFindByIDResult{
+					{
+						Session: currentSession,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			reqContextSessionIDValue: currentSession.ID,
+			reqBody:                  bytes.NewBufferString(`{"currentPassword":"password","newPassword":"short"}`),
+			// out
+			expectedCode: http.StatusBadRequest,
+			expectedBody: bytes.NewBufferString(`{
+				"errors":[
+					{
+						"message":"Password must be at least 8 characters",
+						"field":"newPassword",
+						"code":"password_too_short"
+					}
+				]
+			}`),
+		},
+		{
+			caseName: "Error on FindWithPasswordHashByID should result in 500",
+			accRepo: account.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				nil,
+				[]account.FakeRepositoryFindWithPasswordHashByIDResult{
+					{
+						Error: fmt.Errorf("FindWithPasswordHashByID failed"),
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			sessRepo: session.NewFakeRepository(
+				nil,
+				[]session.FakeRepositoryFindByIDResult{
+					{
+						Session: currentSession,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			reqContextSessionIDValue: currentSession.ID,
+			reqBody:                  bytes.NewBufferString(validBody),
+			// out
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: bytes.NewBuffer(nil),
+		},
+		{
+			caseName: "Incorrect current password should result in 403",
+			accRepo: account.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				nil,
+				[]account.FakeRepositoryFindWithPasswordHashByIDResult{
+					{
+						Account:      acc,
+						PasswordHash: []byte("password_hash"),
+					},
+				},
+				nil,
+				nil,
+				[]account.FakeRepositoryRegisterFailedLoginResult{
+					{
+						Locked: false,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			sessRepo: session.NewFakeRepository(
+				nil,
+				[]session.FakeRepositoryFindByIDResult{
+					{
+						Session: currentSession,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			hasher: hasher.NewFakeHasher(
+				nil,
+				[]hasher.FakeCompareHashWithPasswordResult{
+					{
+						Error: fmt.Errorf("No match"),
+					},
+				},
+			),
+			reqContextSessionIDValue: currentSession.ID,
+			reqBody:                  bytes.NewBufferString(validBody),
+			// out
+			expectedCode: http.StatusForbidden,
+			expectedBody: bytes.NewBufferString(`{
+				"errors":[
+					{
+						"message":"Current password is incorrect",
+						"field":"currentPassword"
+					}
+				]
+			}`),
+		},
+		{
+			caseName: "Incorrect current password locking the account should result in 429",
+			accRepo: account.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				nil,
+				[]account.FakeRepositoryFindWithPasswordHashByIDResult{
+					{
+						Account:      acc,
+						PasswordHash: []byte("password_hash"),
+					},
+				},
+				nil,
+				nil,
+				[]account.FakeRepositoryRegisterFailedLoginResult{
+					{
+						Locked: true,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			sessRepo: session.NewFakeRepository(
+				nil,
+				[]session.FakeRepositoryFindByIDResult{
+					{
+						Session: currentSession,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			hasher: hasher.NewFakeHasher(
+				nil,
+				[]hasher.FakeCompareHashWithPasswordResult{
+					{
+						Error: fmt.Errorf("No match"),
+					},
+				},
+			),
+			reqContextSessionIDValue: currentSession.ID,
+			reqBody:                  bytes.NewBufferString(validBody),
+			// out
+			expectedCode: http.StatusTooManyRequests,
+			expectedBody: bytes.NewBufferString(`{
+				"errors":[
+					{
+						"message":"Account is temporarily locked due to too many failed login attempts"
+					}
+				]
+			}`),
+		},
+		{
+			caseName: "Locked account should result in 429",
+			accRepo: account.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				nil,
+				[]account.FakeRepositoryFindWithPasswordHashByIDResult{
+					{
+						Account:      lockedAcc,
+						PasswordHash: []byte("password_hash"),
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			sessRepo: session.NewFakeRepository(
+				nil,
+				[]session.FakeRepositoryFindByIDResult{
+					{
+						Session: currentSession,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			hasher:                   hasher.NewFakeHasher(nil, nil),
+			reqContextSessionIDValue: currentSession.ID,
+			reqBody:                  bytes.NewBufferString(validBody),
+			// out
+			expectedCode: http.StatusTooManyRequests,
+			expectedBody: bytes.NewBufferString(`{
+				"errors":[
+					{
+						"message":"Account is temporarily locked due to too many failed login attempts"
+					}
+				]
+			}`),
+		},
+		{
+			caseName: "Error on accountRepo.ChangePassword should result in 500",
+			accRepo: account.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				nil,
+				[]account.FakeRepositoryFindWithPasswordHashByIDResult{
+					{
+						Account:      acc,
+						PasswordHash: []byte("password_hash"),
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				[]account.FakeRepositoryChangePasswordResult{
+					{
+						Error: fmt.Errorf("ChangePassword failed"),
+					},
+				},
+			),
+			sessRepo: session.NewFakeRepository(
+				nil,
+				[]session.FakeRepositoryFindByIDResult{
+					{
+						Session: currentSession,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			hasher: hasher.NewFakeHasher(
+				[]hasher.FakeGenerateHashFromPasswordResult{
+					{
+						Hash: []byte("new_password_hash"),
+					},
+				},
+				[]hasher.FakeCompareHashWithPasswordResult{
+					{
+						Error: nil,
+					},
+				},
+			),
+			reqContextSessionIDValue: currentSession.ID,
+			reqBody:                  bytes.NewBufferString(validBody),
+			// out
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: bytes.NewBuffer(nil),
+		},
+		{
+			caseName: "Successful",
+			accRepo: account.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				nil,
+				[]account.FakeRepositoryFindWithPasswordHashByIDResult{
+					{
+						Account:      acc,
+						PasswordHash: []byte("password_hash"),
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				[]account.FakeRepositoryChangePasswordResult{
+					{
+						Error: nil,
+					},
+				},
+			),
+			sessRepo: session.NewFakeRepository(
+				nil,
+				[]session.FakeRepositoryFindByIDResult{
+					{
+						Session: currentSession,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			notary: notary.NewFakeNotary(
+				[]notary.FakeNotarySignResult{
+					{
+						Signature: []byte("signature"),
+					},
+				},
+				nil,
+			),
+			packer: packer.NewFakePacker(
+				[]packer.FakePackerPackResult{
+					{
+						Pack: []byte("pack"),
+					},
+				},
+				nil,
+			),
+			hasher: hasher.NewFakeHasher(
+				[]hasher.FakeGenerateHashFromPasswordResult{
+					{
+						Hash: []byte("new_password_hash"),
+					},
+				},
+				[]hasher.FakeCompareHashWithPasswordResult{
+					{
+						Error: nil,
+					},
+				},
+			),
+			reqContextSessionIDValue: currentSession.ID,
+			reqBody:                  bytes.NewBufferString(validBody),
+			// out
+			expectedCode: http.StatusOK,
+			expectedBody: bytes.NewBufferString(fmt.Sprintf(`{
+				"result": {
+					"token":"pack",
+					"id":"99999999-90ab-cdef-0123-4567890abcde",
+					"accountID":123,
+					"createdAt":"%s",
+					"expiresAt":"%s"
+				}
+			}`, now.Format(time.RFC3339), now.Add(sessionDuration).Format(time.RFC3339))),
+		},
+	}
+
+	for i, c := range cases {
+		ctrl := NewRestController(
+			fakeLogger,
+			c.accRepo,
+			c.sessRepo,
+			c.notary,
+			c.packer,
+			c.hasher,
+			uuidProducer,
+			Options{
+				SessionDuration:  sessionDuration,
+				LockoutThreshold: 3,
+				LockoutDuration:  10 * time.Minute,
+				PasswordPolicy:   password.DefaultPolicy,
+			},
+		)
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPatch, "/accounts/123/password", c.reqBody)
+		req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyPathParams{}, map[string]string{"id": "123"}))
+		req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeySessionID{}, c.reqContextSessionIDValue))
+		ctrl.PatchAccountPassword(w, req)
+
+		if w.Code != c.expectedCode {
+			t.Errorf(
+				"testcase %d %s:\nExpected status code to be %v, but got %v\n",
+				i,
+				c.caseName,
+				c.expectedCode,
+				w.Code,
+			)
+		}
+
+		// To make possible pretty formatting in test cases, we use json.Compact:
+		b := c.expectedBody.Bytes()
+		if b != nil {
+			c.expectedBody.Reset()
+			err := json.Compact(c.expectedBody, b)
+			if err != nil {
+				t.Errorf(
+					"testcase %d %s:\nFailed to compact JSON body: %s",
+					i,
+					c.caseName,
+					err,
+				)
+			}
+		}
+
+		if !bytes.Equal(w.Body.Bytes(), c.expectedBody.Bytes()) {
+			t.Errorf(
+				"testcase %d %s:\nExpected body to be\n%v\nbut got\n%v\n",
+				i,
+				c.caseName,
+				c.expectedBody,
+				w.Body,
+			)
+		}
+	}
+}
+
+func TestPasswordChangedNotification(t *testing.T) {
+	changedAt := time.Date(2017, 7, 1, 21, 10, 29, 0, time.UTC)
+	acc := account.Account{
+		ID:        123,
+		TenantID:  1,
+		Name:      "email@email.com",
+		UpdatedAt: changedAt,
+	}
+
+	expected := notifier.Notification{
+		Kind:      notifier.KindPasswordChanged,
+		TenantID:  1,
+		AccountID: 123,
+		Recipient: "email@email.com",
+		Params: map[string]string{
+			"changedAt": "2017-07-01T21:10:29Z",
+		},
+	}
+
+	if n := PasswordChangedNotification(acc); !reflect.DeepEqual(n, expected) {
+		t.Errorf("Expected notification to be\n%#v\nbut got\n%#v\n", expected, n)
+	}
+}
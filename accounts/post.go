@@ -8,27 +8,54 @@ import (
 	"github.com/hypnoglow/pascont/account"
 	"github.com/hypnoglow/pascont/kit"
 	"github.com/hypnoglow/pascont/kit/form"
+	"github.com/hypnoglow/pascont/kit/middleware"
 	"github.com/hypnoglow/pascont/kit/schema"
+	"github.com/hypnoglow/pascont/password"
+	"github.com/hypnoglow/pascont/webhook"
 )
 
 // PostAccounts is a handler for:
 // POST /accounts
 func (c RestController) PostAccounts(w http.ResponseWriter, req *http.Request) {
-	var accForm postAccountForm
+	// Accounts are created in the tenant the request is made to.
+	tenantID, ok := req.Context().Value(middleware.ContextKeyTenantID{}).(int64)
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	accForm := postAccountForm{policy: c.options.PasswordPolicy}
 	form.PopulateFormFromJSON(req.Body, &accForm)
 	if !accForm.Validate() {
+		if accForm.policyErr != nil {
+			c.errorLogger.Println(accForm.policyErr)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
 		kit.RespondWithFormErrors(w, http.StatusBadRequest, accForm.ValidationErrors())
 		return
 	}
 
-	// It must be not possible to create multiple accounts with same name.
-	exists, err := c.accountRepo.Exists(accForm.Name)
+	passwordHash, err := c.hasher.GenerateHashFromPassword([]byte(accForm.Password))
 	if err != nil {
 		c.errorLogger.Println(err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
-	if exists {
+
+	// The verification token and the event are sent along with the new account.
+	app := account.NewApplication(tenantID, accForm.Name, passwordHash, time.Now())
+	messages := webhook.Messages(c.options.Events, c.errorLogger, webhook.Event{
+		Type:     webhook.EventAccountCreated,
+		TenantID: tenantID,
+	})
+	if c.options.Verification != nil {
+		app.VerifiedAt = time.Time{}
+		messages = append(messages, c.options.Verification.Messages(account.Account{TenantID: tenantID, Name: accForm.Name})...)
+	}
+	acc, err := c.accountRepo.Accept(app, messages...)
+	if err == account.ErrAlreadyExists {
+		// It must be not possible to create multiple accounts with same name.
 		kit.RespondWithError(w, http.StatusConflict, schema.NewError(
 			fmt.Sprintf("Account with name %s already exists", accForm.Name),
 			"name",
@@ -36,16 +63,6 @@ func (c RestController) PostAccounts(w http.ResponseWriter, req *http.Request) {
 		))
 		return
 	}
-
-	passwordHash, err := c.hasher.GenerateHashFromPassword([]byte(accForm.Password))
-	if err != nil {
-		c.errorLogger.Println(err)
-		w.WriteHeader(http.StatusInternalServerError)
-		return
-	}
-
-	app := account.NewApplication(accForm.Name, passwordHash, time.Now())
-	acc, err := c.accountRepo.Accept(app)
 	if err != nil {
 		c.errorLogger.Println(err)
 		w.WriteHeader(http.StatusInternalServerError)
@@ -55,9 +72,11 @@ func (c RestController) PostAccounts(w http.ResponseWriter, req *http.Request) {
 	kit.RespondJSON(w, http.StatusCreated, schema.NewResultBody(
 		postAccountSchema{
 			ID:        acc.ID,
+			TenantID:  acc.TenantID,
 			Name:      acc.Name,
 			CreatedAt: acc.CreatedAt,
 			UpdatedAt: acc.UpdatedAt,
+			Verified:  acc.IsVerified(),
 		},
 		nil,
 	))
@@ -69,15 +88,15 @@ const (
 
 	// nameMaxLen is max len of the account name in bytes.
 	nameMaxLen = 64
-
-	// passwordMinLen is min len of the account password in bytes.
-	passwordMinLen = 8
 )
 
 type postAccountForm struct {
 	form.BaseForm
 	Name     string `json:"name"`
 	Password string `json:"password"`
+
+	policy    password.Policy
+	policyErr error
 }
 
 func (f *postAccountForm) Validate() bool {
@@ -88,16 +107,16 @@ func (f *postAccountForm) Validate() bool {
 		f.AddError(fmt.Sprintf("Name must be at most %d bytes", nameMaxLen), "name", f.Name)
 	}
 
-	if len(f.Password) < passwordMinLen {
-		f.AddError(fmt.Sprintf("Password must be at least %d bytes", passwordMinLen), "password", nil)
-	}
+	f.policyErr = f.policy.Validate(f, "password", f.Name, f.Password)
 
-	return len(f.ValidationErrors()) == 0
+	return f.policyErr == nil && len(f.ValidationErrors()) == 0
 }
 
 type postAccountSchema struct {
 	ID        int64     `json:"id"`
+	TenantID  int64     `json:"tenantID"`
 	Name      string    `json:"name"`
 	CreatedAt time.Time `json:"createdAt"`
 	UpdatedAt time.Time `json:"updatedAt"`
+	Verified  bool      `json:"verified"`
 }
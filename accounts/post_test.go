@@ -2,6 +2,7 @@ package accounts
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -14,8 +15,13 @@ import (
 	"time"
 
 	"github.com/hypnoglow/pascont/account"
+	"github.com/hypnoglow/pascont/breach"
 	"github.com/hypnoglow/pascont/hasher"
 	"github.com/hypnoglow/pascont/kit/form"
+	"github.com/hypnoglow/pascont/kit/middleware"
+	"github.com/hypnoglow/pascont/password"
+	"github.com/hypnoglow/pascont/verification"
+	"github.com/hypnoglow/pascont/webhook"
 )
 
 func TestPostAccountForm_Validate(t *testing.T) {
@@ -23,12 +29,14 @@ func TestPostAccountForm_Validate(t *testing.T) {
 		caseName       string
 		Name           string
 		Password       string
+		policy         password.Policy
 		expectedErrors []form.FormError
 	}{
 		{
 			caseName: "Name is too short",
 			Name:     "i",
 			Password: "password",
+			policy:   password.DefaultPolicy,
 			expectedErrors: []form.FormError{
 				{
 					Message: fmt.Sprintf("Name must be at least %d bytes", nameMinLen),
@@ -41,6 +49,7 @@ func TestPostAccountForm_Validate(t *testing.T) {
 			caseName: "Name is too long",
 			Name:     "1234567890123456789012345678901234567890123456789012345678901234567890",
 			Password: "password",
+			policy:   password.DefaultPolicy,
 			expectedErrors: []form.FormError{
 				{
 					Message: fmt.Sprintf("Name must be at most %d bytes", nameMaxLen),
@@ -53,11 +62,33 @@ func TestPostAccountForm_Validate(t *testing.T) {
 			caseName: "Password is too short",
 			Name:     "email@email.com",
 			Password: "pass",
+			policy:   password.DefaultPolicy,
 			expectedErrors: []form.FormError{
 				{
-					Message: fmt.Sprintf("Password must be at least %d bytes", passwordMinLen),
+					Message: "Password must be at least 8 characters",
 					Field:   "password",
 					Value:   nil,
+					Code:    password.CodeTooShort,
+				},
+			},
+		},
+		{
+			caseName: "Password violates several rules",
+			Name:     "email@email.com",
+			Password: "email@email.com",
+			policy:   password.Policy{MinLength: 8, RequireDigit: true, ForbidName: true},
+			expectedErrors: []form.FormError{
+				{
+					Message: "Password must contain a digit",
+					Field:   "password",
+					Value:   nil,
+					Code:    password.CodeNoDigit,
+				},
+				{
+					Message: "Password must not contain the account name",
+					Field:   "password",
+					Value:   nil,
+					Code:    password.CodeContainsName,
 				},
 			},
 		},
@@ -67,6 +98,7 @@ func TestPostAccountForm_Validate(t *testing.T) {
 		f := &postAccountForm{
 			Name:     c.Name,
 			Password: c.Password,
+			policy:   c.policy,
 		}
 
 		f.Validate()
@@ -94,6 +126,7 @@ func TestRestController_PostAccounts(t *testing.T) {
 		accRepo           account.Repository
 		hasher            hasher.Hasher
 		opts              Options
+		reqTenantID       int64
 		form              postAccountForm
 		reqBody           io.Reader
 		expectedCode      int
@@ -101,8 +134,19 @@ func TestRestController_PostAccounts(t *testing.T) {
 		expectedBody      *bytes.Buffer
 	}{
 		{
-			caseName: "Too short account name should result in 400",
-			accRepo:  account.NewFakeRepository(nil, nil, nil, nil),
+			caseName: "Request without a tenant should result in 404",
+			reqBody: bytes.NewBufferString(`{
+				"name":"email@email.com",
+				"password":"password"
+			}`),
+			expectedCode:      http.StatusNotFound,
+			expectedHeaderMap: http.Header{},
+			expectedBody:      bytes.NewBuffer(nil),
+		},
+		{
+			caseName:    "Too short account name should result in 400",
+			reqTenantID: 1,
+			accRepo:     account.NewFakeRepository(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil),
 			reqBody: bytes.NewBufferString(`{
 				"name":"i",
 				"password":"password"
@@ -120,18 +164,113 @@ func TestRestController_PostAccounts(t *testing.T) {
 			}`),
 		},
 		{
-			caseName: "Error on repository Exists",
-			accRepo: account.NewFakeRepository(
-				nil,
-				nil,
-				[]account.FakeRepositoryExistsResult{
+			caseName:    "Password violating the policy should result in 400",
+			reqTenantID: 1,
+			accRepo:     account.NewFakeRepository(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil),
+			opts: Options{
+				PasswordPolicy: password.Policy{
+					MinLength:       8,
+					CommonPasswords: map[string]struct{}{"password": {}},
+				},
+			},
+			reqBody: bytes.NewBufferString(`{
+				"name":"email@email.com",
+				"password":"password"
+			}`),
+			expectedCode:      http.StatusBadRequest,
+			expectedHeaderMap: http.Header{"Content-Type": []string{"application/json; charset=utf-8"}},
+			expectedBody: bytes.NewBufferString(`{
+				"errors":[
+					{
+						"message":"Password is too common",
+						"field":"password",
+						"code":"password_common"
+					}
+				]
+			}`),
+		},
+		{
+			caseName:    "Breached password should result in 400",
+			reqTenantID: 1,
+			accRepo:     account.NewFakeRepository(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil),
+			opts: Options{
+				PasswordPolicy: password.Policy{
+					Breaches: breach.NewFakeChecker([]breach.FakeCheckerIsBreachedResult{
+						{
+							Breached: true,
+						},
+					}),
+				},
+			},
+			reqBody: bytes.NewBufferString(`{
+				"name":"email@email.com",
+				"password":"password"
+			}`),
+			expectedCode:      http.StatusBadRequest,
+			expectedHeaderMap: http.Header{"Content-Type": []string{"application/json; charset=utf-8"}},
+			expectedBody: bytes.NewBufferString(`{
+				"errors":[
+					{
+						"message":"Password appears in a known data breach",
+						"field":"password",
+						"code":"password_breached"
+					}
+				]
+			}`),
+		},
+		{
+			caseName:    "Breached password is reported along with other errors",
+			reqTenantID: 1,
+			accRepo:     account.NewFakeRepository(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil),
+			opts: Options{
+				PasswordPolicy: password.Policy{
+					MinLength: 10,
+					Breaches: breach.NewFakeChecker([]breach.FakeCheckerIsBreachedResult{
+						{
+							Breached: true,
+						},
+					}),
+				},
+			},
+			reqBody: bytes.NewBufferString(`{
+				"name":"e",
+				"password":"password"
+			}`),
+			expectedCode:      http.StatusBadRequest,
+			expectedHeaderMap: http.Header{"Content-Type": []string{"application/json; charset=utf-8"}},
+			expectedBody: bytes.NewBufferString(`{
+				"errors":[
 					{
-						Exists: false,
-						Error:  fmt.Errorf("Exists failed"),
+						"message":"Name must be at least 4 bytes",
+						"field":"name",
+						"value":"e"
+					},
+					{
+						"message":"Password must be at least 10 characters",
+						"field":"password",
+						"code":"password_too_short"
 					},
+					{
+						"message":"Password appears in a known data breach",
+						"field":"password",
+						"code":"password_breached"
+					}
+				]
+			}`),
+		},
+		{
+			caseName:    "Error on breach check should result in 500",
+			reqTenantID: 1,
+			accRepo:     account.NewFakeRepository(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil),
+			opts: Options{
+				PasswordPolicy: password.Policy{
+					Breaches: breach.NewFakeChecker([]breach.FakeCheckerIsBreachedResult{
+						{
+							Error: fmt.Errorf("IsBreached failed"),
+						},
+					}),
 				},
-				nil,
-			),
+			},
 			reqBody: bytes.NewBufferString(`{
 				"name":"email@email.com",
 				"password":"password"
@@ -141,14 +280,33 @@ func TestRestController_PostAccounts(t *testing.T) {
 			expectedBody:      bytes.NewBuffer(nil),
 		},
 		{
-			caseName: "Account with such name already exists",
+			caseName:    "Account with such name already exists",
+			reqTenantID: 1,
 			accRepo: account.NewFakeRepository(
+				[]account.FakeRepositoryAcceptResult{
+					{
+						Account: nil,
+						Error:   account.ErrAlreadyExists,
+					},
+				},
+				nil,
+				nil,
+				nil,
 				nil,
 				nil,
-				[]account.FakeRepositoryExistsResult{
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			hasher: hasher.NewFakeHasher(
+				[]hasher.FakeGenerateHashFromPasswordResult{
 					{
-						Exists: true,
-						Error:  nil,
+						Hash:  []byte("password_hash"),
+						Error: nil,
 					},
 				},
 				nil,
@@ -170,16 +328,21 @@ func TestRestController_PostAccounts(t *testing.T) {
 			}`),
 		},
 		{
-			caseName: "Error on hasher.GenerateHashFromPassword should result in 500",
+			caseName:    "Error on hasher.GenerateHashFromPassword should result in 500",
+			reqTenantID: 1,
 			accRepo: account.NewFakeRepository(
 				nil,
 				nil,
-				[]account.FakeRepositoryExistsResult{
-					{
-						Exists: false,
-						Error:  nil,
-					},
-				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
 				nil,
 			),
 			hasher: hasher.NewFakeHasher(
@@ -200,7 +363,8 @@ func TestRestController_PostAccounts(t *testing.T) {
 			expectedBody:      bytes.NewBuffer(nil),
 		},
 		{
-			caseName: "Error on repository Save",
+			caseName:    "Error on repository Accept",
+			reqTenantID: 1,
 			accRepo: account.NewFakeRepository(
 				[]account.FakeRepositoryAcceptResult{
 					{
@@ -209,12 +373,16 @@ func TestRestController_PostAccounts(t *testing.T) {
 					},
 				},
 				nil,
-				[]account.FakeRepositoryExistsResult{
-					{
-						Exists: false,
-						Error:  nil,
-					},
-				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
 				nil,
 			),
 			hasher: hasher.NewFakeHasher(
@@ -235,12 +403,139 @@ func TestRestController_PostAccounts(t *testing.T) {
 			expectedBody:      bytes.NewBuffer(nil),
 		},
 		{
-			caseName: "Successful",
+			caseName:    "Successful",
+			reqTenantID: 1,
+			accRepo: account.NewFakeRepository(
+				[]account.FakeRepositoryAcceptResult{
+					{
+						Account: &account.Account{
+							ID:         123,
+							TenantID:   1,
+							Name:       "email@email.com",
+							CreatedAt:  now,
+							UpdatedAt:  now,
+							VerifiedAt: now,
+						},
+						Error: nil,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			hasher: hasher.NewFakeHasher(
+				[]hasher.FakeGenerateHashFromPasswordResult{
+					{
+						Hash:  []byte("password_hash"),
+						Error: nil,
+					},
+				},
+				nil,
+			),
+			reqBody: bytes.NewBufferString(`{
+				"name":"email@email.com",
+				"password":"password"
+			}`),
+			expectedCode:      http.StatusCreated,
+			expectedHeaderMap: http.Header{"Content-Type": []string{"application/json; charset=utf-8"}},
+			expectedBody: bytes.NewBufferString(fmt.Sprintf(`{
+				"result":{
+					"id":123,
+					"tenantID":1,
+					"name":"email@email.com",
+					"createdAt":"%s",
+					"updatedAt":"%s",
+					"verified":true
+				}
+			}`, now.Format(time.RFC3339), now.Format(time.RFC3339))),
+		},
+		{
+			caseName: "Error on event emitting should result in 201",
+			opts: Options{
+				Events: webhook.NewFakeEmitter([]webhook.FakeEmitterMessagesResult{{Error: fmt.Errorf("Messages failed")}}, nil),
+			},
+			reqTenantID: 1,
+			accRepo: account.NewFakeRepository(
+				[]account.FakeRepositoryAcceptResult{
+					{
+						Account: &account.Account{
+							ID:         123,
+							TenantID:   1,
+							Name:       "email@email.com",
+							CreatedAt:  now,
+							UpdatedAt:  now,
+							VerifiedAt: now,
+						},
+						Error: nil,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			hasher: hasher.NewFakeHasher(
+				[]hasher.FakeGenerateHashFromPasswordResult{
+					{
+						Hash:  []byte("password_hash"),
+						Error: nil,
+					},
+				},
+				nil,
+			),
+			reqBody: bytes.NewBufferString(`{
+				"name":"email@email.com",
+				"password":"password"
+			}`),
+			expectedCode:      http.StatusCreated,
+			expectedHeaderMap: http.Header{"Content-Type": []string{"application/json; charset=utf-8"}},
+			expectedBody: bytes.NewBufferString(fmt.Sprintf(`{
+				"result":{
+					"id":123,
+					"tenantID":1,
+					"name":"email@email.com",
+					"createdAt":"%s",
+					"updatedAt":"%s",
+					"verified":true
+				}
+			}`, now.Format(time.RFC3339), now.Format(time.RFC3339))),
+		},
+		{
+			caseName:    "Successful with verification",
+			reqTenantID: 1,
+			opts: Options{
+				Verification: verification.NewFakeSender(
+					nil,
+					[]verification.FakeSenderMessagesResult{
+						{
+							Messages: nil,
+						},
+					},
+				),
+			},
 			accRepo: account.NewFakeRepository(
 				[]account.FakeRepositoryAcceptResult{
 					{
 						Account: &account.Account{
 							ID:        123,
+							TenantID:  1,
 							Name:      "email@email.com",
 							CreatedAt: now,
 							UpdatedAt: now,
@@ -249,12 +544,16 @@ func TestRestController_PostAccounts(t *testing.T) {
 					},
 				},
 				nil,
-				[]account.FakeRepositoryExistsResult{
-					{
-						Exists: false,
-						Error:  nil,
-					},
-				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
 				nil,
 			),
 			hasher: hasher.NewFakeHasher(
@@ -275,9 +574,11 @@ func TestRestController_PostAccounts(t *testing.T) {
 			expectedBody: bytes.NewBufferString(fmt.Sprintf(`{
 				"result":{
 					"id":123,
+					"tenantID":1,
 					"name":"email@email.com",
 					"createdAt":"%s",
-					"updatedAt":"%s"
+					"updatedAt":"%s",
+					"verified":false
 				}
 			}`, now.Format(time.RFC3339), now.Format(time.RFC3339))),
 		},
@@ -287,11 +588,18 @@ func TestRestController_PostAccounts(t *testing.T) {
 		ctrl := NewRestController(
 			fakeLogger,
 			c.accRepo,
+			nil,
+			nil,
+			nil,
 			c.hasher,
+			nil,
 			c.opts,
 		)
 		w := httptest.NewRecorder()
 		req := httptest.NewRequest(http.MethodPost, PathAccounts, c.reqBody)
+		if c.reqTenantID != 0 {
+			req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyTenantID{}, c.reqTenantID))
+		}
 
 		ctrl.PostAccounts(w, req)
 
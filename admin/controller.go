@@ -0,0 +1,156 @@
+// Package admin provides a REST controller for operators to manage accounts.
+package admin
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/hypnoglow/pascont/account"
+	"github.com/hypnoglow/pascont/kit/middleware"
+	"github.com/hypnoglow/pascont/outbox"
+	"github.com/hypnoglow/pascont/reset"
+	"github.com/hypnoglow/pascont/role"
+	"github.com/hypnoglow/pascont/session"
+	"github.com/hypnoglow/pascont/webhook"
+)
+
+const (
+	PathAdmin                = "/admin/"
+	PathAccounts             = "/admin/accounts"
+	PathAccount              = "/admin/accounts/:id"
+	PathAccountStatus        = "/admin/accounts/:id/status"
+	PathAccountPasswordReset = "/admin/accounts/:id/password-reset"
+	PathAccountSessions      = "/admin/accounts/:id/sessions"
+	PathAccountRoles         = "/admin/accounts/:id/roles"
+	PathAccountRole          = "/admin/accounts/:id/roles/:role"
+	PathRoles                = "/admin/roles"
+	PathRole                 = "/admin/roles/:role"
+)
+
+// RestController is a REST controller for account management.
+type RestController struct {
+	logger      *log.Logger
+	accountRepo account.Repository
+	sessionRepo session.Repository
+	roleRepo    role.Repository
+	resetSender reset.Sender
+	events      webhook.Emitter
+}
+
+// NewRestController returns a new RestController.
+func NewRestController(
+	logger *log.Logger,
+	accountRepo account.Repository,
+	sessionRepo session.Repository,
+	roleRepo role.Repository,
+	s reset.Sender,
+	em webhook.Emitter,
+) RestController {
+	return RestController{
+		logger,
+		accountRepo,
+		sessionRepo,
+		roleRepo,
+		s,
+		em,
+	}
+}
+
+// revokedMessages returns the outbox messages telling the subscribers to events
+// that all sessions of the account with the id are revoked.
+// The account is looked up only to tell the subscribers its tenant.
+func (c RestController) revokedMessages(id int64) []outbox.Message {
+	if c.events == nil {
+		return nil
+	}
+
+	acc, err := c.accountRepo.FindByID(id)
+	if err != nil {
+		if err != account.ErrNotFound {
+			c.logger.Println(err)
+		}
+		return nil
+	}
+
+	return webhook.Messages(c.events, c.logger, webhook.SessionRevokedEvent(acc.TenantID, acc.ID, ""))
+}
+
+// pathAccount retrieves the account with the ID from the request path.
+// Requests scoped to a tenant, i.e. authorized with a session, only find
+// accounts of the tenant, while the admin token finds accounts of any tenant.
+// On failure, returns a status code to respond with.
+func (c RestController) pathAccount(req *http.Request) (acc *account.Account, status int) {
+	id, ok := pathAccountID(req)
+	if !ok {
+		return nil, http.StatusNotFound
+	}
+
+	acc, err := c.accountRepo.FindByID(id)
+	if err != nil {
+		if err == account.ErrNotFound {
+			return nil, http.StatusNotFound
+		}
+
+		c.logger.Println(err)
+		return nil, http.StatusInternalServerError
+	}
+
+	if tenantID, ok := req.Context().Value(middleware.ContextKeyTenantID{}).(int64); ok && acc.TenantID != tenantID {
+		return nil, http.StatusNotFound
+	}
+
+	return acc, http.StatusOK
+}
+
+// pathAccountID returns the account ID from the request path.
+func pathAccountID(req *http.Request) (id int64, ok bool) {
+	pathID, _ := middleware.PathParam(req, "id")
+	id, err := strconv.ParseInt(pathID, 10, 64)
+	return id, err == nil
+}
+
+type accountSchema struct {
+	ID                  int64          `json:"id"`
+	TenantID            int64          `json:"tenantID"`
+	Name                string         `json:"name"`
+	Status              account.Status `json:"status"`
+	CreatedAt           time.Time      `json:"createdAt"`
+	UpdatedAt           time.Time      `json:"updatedAt"`
+	FailedLoginAttempts int            `json:"failedLoginAttempts"`
+	LockedUntil         *time.Time     `json:"lockedUntil,omitempty"`
+}
+
+func newAccountSchema(acc *account.Account) accountSchema {
+	s := accountSchema{
+		ID:                  acc.ID,
+		TenantID:            acc.TenantID,
+		Name:                acc.Name,
+		Status:              acc.Status,
+		CreatedAt:           acc.CreatedAt,
+		UpdatedAt:           acc.UpdatedAt,
+		FailedLoginAttempts: acc.FailedLoginAttempts,
+	}
+	if !acc.LockedUntil.IsZero() {
+		lockedUntil := acc.LockedUntil
+		s.LockedUntil = &lockedUntil
+	}
+	return s
+}
+
+type roleSchema struct {
+	Name        string            `json:"name"`
+	Permissions []role.Permission `json:"permissions"`
+}
+
+func newRoleSchemas(roles []*role.Role) []roleSchema {
+	schemas := make([]roleSchema, len(roles))
+	for i, r := range roles {
+		schemas[i] = roleSchema{
+			Name:        r.Name,
+			Permissions: append([]role.Permission{}, r.Permissions...),
+		}
+	}
+	return schemas
+}
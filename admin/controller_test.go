@@ -0,0 +1,22 @@
+package admin
+
+import (
+	"io/ioutil"
+	"log"
+	"testing"
+
+	"github.com/hypnoglow/pascont/account"
+	"github.com/hypnoglow/pascont/reset"
+	"github.com/hypnoglow/pascont/session"
+)
+
+func TestNewRestController(t *testing.T) {
+	NewRestController(
+		log.New(ioutil.Discard, "", log.LstdFlags),
+		account.NewFakeRepository(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil),
+		session.NewFakeRepository(nil, nil, nil, nil, nil, nil),
+		nil,
+		reset.NewFakeSender(nil, nil),
+		nil,
+	)
+}
@@ -0,0 +1,21 @@
+package admin
+
+import "net/http"
+
+// DeleteAccountSessions is a handler for:
+// DELETE /admin/accounts/:id/sessions
+func (c RestController) DeleteAccountSessions(w http.ResponseWriter, req *http.Request) {
+	id, ok := pathAccountID(req)
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	if err := c.sessionRepo.DeleteAllByAccount(id, c.revokedMessages(id)...); err != nil {
+		c.logger.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,88 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hypnoglow/pascont/kit/middleware"
+	"github.com/hypnoglow/pascont/session"
+)
+
+func TestRestController_DeleteAccountSessions(t *testing.T) {
+	fakeLogger := log.New(ioutil.Discard, "", log.LstdFlags)
+
+	cases := []struct {
+		caseName string
+		// in
+		sessRepo  session.Repository
+		reqPathID string
+		// out
+		expectedCode int
+	}{
+		{
+			caseName:  "The account id from path is not a number",
+			reqPathID: "abc",
+			// out
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			caseName: "sessionRepo.DeleteAllByAccount failed",
+			sessRepo: session.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				[]session.FakeRepositoryDeleteAllByAccountResult{
+					{
+						Error: fmt.Errorf("DeleteAllByAccount failed"),
+					},
+				},
+			),
+			reqPathID: "123",
+			// out
+			expectedCode: http.StatusInternalServerError,
+		},
+		{
+			caseName: "Successful",
+			sessRepo: session.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				[]session.FakeRepositoryDeleteAllByAccountResult{
+					{
+						Error: nil,
+					},
+				},
+			),
+			reqPathID: "123",
+			// out
+			expectedCode: http.StatusNoContent,
+		},
+	}
+
+	for i, c := range cases {
+		ctrl := NewRestController(fakeLogger, nil, c.sessRepo, nil, nil, nil)
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodDelete, "/admin/accounts/"+c.reqPathID+"/sessions", nil)
+		req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyPathParams{}, map[string]string{"id": c.reqPathID}))
+		ctrl.DeleteAccountSessions(w, req)
+
+		if w.Code != c.expectedCode {
+			t.Errorf(
+				"testcase %d %s:\nExpected status code to be %v, but got %v\n",
+				i,
+				c.caseName,
+				c.expectedCode,
+				w.Code,
+			)
+		}
+	}
+}
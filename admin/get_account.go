@@ -0,0 +1,35 @@
+package admin
+
+import (
+	"net/http"
+
+	"github.com/hypnoglow/pascont/account"
+	"github.com/hypnoglow/pascont/kit"
+	"github.com/hypnoglow/pascont/kit/schema"
+)
+
+// GetAccount is a handler for:
+// GET /admin/accounts/:id
+func (c RestController) GetAccount(w http.ResponseWriter, req *http.Request) {
+	id, ok := pathAccountID(req)
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	acc, err := c.accountRepo.FindByID(id)
+	if err != nil {
+		if err == account.ErrNotFound {
+			w.WriteHeader(http.StatusNotFound)
+		} else {
+			c.logger.Println(err)
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		return
+	}
+
+	kit.RespondJSON(w, http.StatusOK, schema.NewResultBody(
+		newAccountSchema(acc),
+		nil,
+	))
+}
@@ -0,0 +1,30 @@
+package admin
+
+import (
+	"net/http"
+
+	"github.com/hypnoglow/pascont/kit"
+	"github.com/hypnoglow/pascont/kit/schema"
+)
+
+// GetAccountRoles is a handler for:
+// GET /admin/accounts/:id/roles
+func (c RestController) GetAccountRoles(w http.ResponseWriter, req *http.Request) {
+	acc, status := c.pathAccount(req)
+	if acc == nil {
+		w.WriteHeader(status)
+		return
+	}
+
+	roles, err := c.roleRepo.FindByAccount(acc.ID)
+	if err != nil {
+		c.logger.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	kit.RespondJSON(w, http.StatusOK, schema.NewResultsBody(
+		newRoleSchemas(roles),
+		nil,
+	))
+}
@@ -0,0 +1,149 @@
+package admin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hypnoglow/pascont/account"
+	"github.com/hypnoglow/pascont/kit/middleware"
+	"github.com/hypnoglow/pascont/role"
+)
+
+func TestRestController_GetAccountRoles(t *testing.T) {
+	fakeLogger := log.New(ioutil.Discard, "", log.LstdFlags)
+	acc := &account.Account{ID: 123, TenantID: 1, Name: "email@email.com"}
+
+	cases := []struct {
+		caseName string
+		// in
+		accRepo                 account.Repository
+		roleRepo                role.Repository
+		reqPathID               string
+		reqContextTenantIDValue interface{}
+		// out
+		expectedCode int
+		expectedBody *bytes.Buffer
+	}{
+		{
+			caseName:  "The account id from path is not a number",
+			reqPathID: "abc",
+			// out
+			expectedCode: http.StatusNotFound,
+			expectedBody: bytes.NewBuffer(nil),
+		},
+		{
+			caseName:                "Account of another tenant than the session should result in 404",
+			accRepo:                 accountFound(acc, nil),
+			reqPathID:               "123",
+			reqContextTenantIDValue: int64(2),
+			// out
+			expectedCode: http.StatusNotFound,
+			expectedBody: bytes.NewBuffer(nil),
+		},
+		{
+			caseName: "roleRepo.FindByAccount failed",
+			accRepo:  accountFound(acc, nil),
+			roleRepo: role.NewFakeRepository(
+				nil,
+				nil,
+				[]role.FakeRepositoryFindByAccountResult{
+					{
+						Error: fmt.Errorf("FindByAccount failed"),
+					},
+				},
+				nil,
+				nil,
+			),
+			reqPathID: "123",
+			// out
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: bytes.NewBuffer(nil),
+		},
+		{
+			caseName: "Successful",
+			accRepo:  accountFound(acc, nil),
+			roleRepo: role.NewFakeRepository(
+				nil,
+				nil,
+				[]role.FakeRepositoryFindByAccountResult{
+					{
+						Roles: []*role.Role{
+							role.NewRole("admin", []role.Permission{"accounts:manage", "roles:manage"}),
+							role.NewRole("support", nil),
+						},
+					},
+				},
+				nil,
+				nil,
+			),
+			reqPathID: "123",
+			// out
+			expectedCode: http.StatusOK,
+			expectedBody: bytes.NewBufferString(`{
+				"results":[
+					{
+						"name":"admin",
+						"permissions":["accounts:manage","roles:manage"]
+					},
+					{
+						"name":"support",
+						"permissions":[]
+					}
+				]
+			}`),
+		},
+	}
+
+	for i, c := range cases {
+		ctrl := NewRestController(fakeLogger, c.accRepo, nil, c.roleRepo, nil, nil)
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/admin/accounts/"+c.reqPathID+"/roles", nil)
+		req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyPathParams{}, map[string]string{"id": c.reqPathID}))
+		if c.reqContextTenantIDValue != nil {
+			req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyTenantID{}, c.reqContextTenantIDValue))
+		}
+		ctrl.GetAccountRoles(w, req)
+
+		if w.Code != c.expectedCode {
+			t.Errorf(
+				"testcase %d %s:\nExpected status code to be %v, but got %v\n",
+				i,
+				c.caseName,
+				c.expectedCode,
+				w.Code,
+			)
+		}
+
+		// To make possible pretty formatting in test cases, we use json.Compact:
+		b := c.expectedBody.Bytes()
+		if b != nil {
+			c.expectedBody.Reset()
+			err := json.Compact(c.expectedBody, b)
+			if err != nil {
+				t.Errorf(
+					"testcase %d %s:\nFailed to compact JSON body: %s",
+					i,
+					c.caseName,
+					err,
+				)
+			}
+		}
+
+		if !bytes.Equal(w.Body.Bytes(), c.expectedBody.Bytes()) {
+			t.Errorf(
+				"testcase %d %s:\nExpected body to be\n%v\nbut got\n%v\n",
+				i,
+				c.caseName,
+				c.expectedBody,
+				w.Body,
+			)
+		}
+	}
+}
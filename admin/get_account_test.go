@@ -0,0 +1,178 @@
+package admin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/pascont/account"
+	"github.com/hypnoglow/pascont/kit/middleware"
+)
+
+func TestRestController_GetAccount(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	fakeLogger := log.New(ioutil.Discard, "", log.LstdFlags)
+
+	cases := []struct {
+		caseName string
+		// in
+		accRepo   account.Repository
+		reqPathID string
+		// out
+		expectedCode int
+		expectedBody *bytes.Buffer
+	}{
+		{
+			caseName:  "The account id from path is not a number",
+			reqPathID: "abc",
+			// out
+			expectedCode: http.StatusNotFound,
+			expectedBody: bytes.NewBuffer(nil),
+		},
+		{
+			caseName: "accountRepo.FindByID returns ErrNotFound",
+			accRepo: account.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				[]account.FakeRepositoryFindByIDResult{
+					{
+						Error: account.ErrNotFound,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			reqPathID: "123",
+			// out
+			expectedCode: http.StatusNotFound,
+			expectedBody: bytes.NewBuffer(nil),
+		},
+		{
+			caseName: "accountRepo.FindByID failed",
+			accRepo: account.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				[]account.FakeRepositoryFindByIDResult{
+					{
+						Error: fmt.Errorf("FindByID failed"),
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			reqPathID: "123",
+			// out
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: bytes.NewBuffer(nil),
+		},
+		{
+			caseName: "Successful",
+			accRepo: account.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				[]account.FakeRepositoryFindByIDResult{
+					{
+						Account: &account.Account{
+							ID:        123,
+							TenantID:  1,
+							Name:      "email@email.com",
+							Status:    account.StatusActive,
+							CreatedAt: now,
+							UpdatedAt: now,
+						},
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			reqPathID: "123",
+			// out
+			expectedCode: http.StatusOK,
+			expectedBody: bytes.NewBufferString(fmt.Sprintf(`{
+				"result":{
+					"id":123,
+					"tenantID":1,
+					"name":"email@email.com",
+					"status":"active",
+					"createdAt":"%[1]s",
+					"updatedAt":"%[1]s",
+					"failedLoginAttempts":0
+				}
+			}`, now.Format(time.RFC3339))),
+		},
+	}
+
+	for i, c := range cases {
+		ctrl := NewRestController(fakeLogger, c.accRepo, nil, nil, nil, nil)
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/admin/accounts/"+c.reqPathID, nil)
+		req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyPathParams{}, map[string]string{"id": c.reqPathID}))
+		ctrl.GetAccount(w, req)
+
+		if w.Code != c.expectedCode {
+			t.Errorf(
+				"testcase %d %s:\nExpected status code to be %v, but got %v\n",
+				i,
+				c.caseName,
+				c.expectedCode,
+				w.Code,
+			)
+		}
+
+		// To make possible pretty formatting in test cases, we use json.Compact:
+		b := c.expectedBody.Bytes()
+		if b != nil {
+			c.expectedBody.Reset()
+			err := json.Compact(c.expectedBody, b)
+			if err != nil {
+				t.Errorf(
+					"testcase %d %s:\nFailed to compact JSON body: %s",
+					i,
+					c.caseName,
+					err,
+				)
+			}
+		}
+
+		if !bytes.Equal(w.Body.Bytes(), c.expectedBody.Bytes()) {
+			t.Errorf(
+				"testcase %d %s:\nExpected body to be\n%v\nbut got\n%v\n",
+				i,
+				c.caseName,
+				c.expectedBody,
+				w.Body,
+			)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package admin
+
+import (
+	"net/http"
+
+	"github.com/hypnoglow/pascont/kit"
+	"github.com/hypnoglow/pascont/kit/form"
+	"github.com/hypnoglow/pascont/kit/schema"
+)
+
+// GetAccounts is a handler for:
+// GET /admin/accounts
+//
+// Accounts can be searched by name with the "query" parameter.
+func (c RestController) GetAccounts(w http.ResponseWriter, req *http.Request) {
+	var pageForm form.PaginationForm
+	form.PopulatePaginationFormFromQuery(req.URL.Query(), &pageForm)
+	if !pageForm.Validate() {
+		kit.RespondWithFormErrors(w, http.StatusBadRequest, pageForm.ValidationErrors())
+		return
+	}
+
+	accounts, total, err := c.accountRepo.List(req.URL.Query().Get("query"), pageForm.Offset, pageForm.Limit)
+	if err != nil {
+		c.logger.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	results := make([]accountSchema, len(accounts))
+	for i, acc := range accounts {
+		results[i] = newAccountSchema(acc)
+	}
+
+	kit.RespondJSON(w, http.StatusOK, schema.NewResultsBody(
+		results,
+		schema.NewPaginationMeta(pageForm.Limit, pageForm.Offset, total),
+	))
+}
@@ -0,0 +1,188 @@
+package admin
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/pascont/account"
+)
+
+func TestRestController_GetAccounts(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	later := now.Add(time.Minute * 15)
+	fakeLogger := log.New(ioutil.Discard, "", log.LstdFlags)
+
+	cases := []struct {
+		caseName string
+		// in
+		accRepo account.Repository
+		reqURL  string
+		// out
+		expectedCode int
+		expectedBody *bytes.Buffer
+	}{
+		{
+			caseName: "Invalid pagination parameters should result in 400",
+			reqURL:   "/admin/accounts?limit=abc",
+			// out
+			expectedCode: http.StatusBadRequest,
+			expectedBody: bytes.NewBufferString(`{
+				"errors":[
+					{
+						"message":"Limit must be an integer",
+						"field":"limit",
+						"value":"abc"
+					}
+				]
+			}`),
+		},
+		{
+			caseName: "accountRepo.List failed",
+			accRepo: account.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				[]account.FakeRepositoryListResult{
+					{
+						Error: fmt.Errorf("List failed"),
+					},
+				},
+				nil,
+				nil,
+			),
+			reqURL: "/admin/accounts?query=email",
+			// out
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: bytes.NewBuffer(nil),
+		},
+		{
+			caseName: "Successful",
+			accRepo: account.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				[]account.FakeRepositoryListResult{
+					{
+						Accounts: []*account.Account{
+							{
+								ID:        123,
+								TenantID:  1,
+								Name:      "email@email.com",
+								Status:    account.StatusActive,
+								CreatedAt: now,
+								UpdatedAt: now,
+							},
+							{
+								ID:                  124,
+								TenantID:            1,
+								Name:                "other@email.com",
+								Status:              account.StatusDisabled,
+								CreatedAt:           now,
+								UpdatedAt:           now,
+								FailedLoginAttempts: 2,
+								LockedUntil:         later,
+							},
+						},
+						Total: 12,
+					},
+				},
+				nil,
+				nil,
+			),
+			reqURL: "/admin/accounts?query=email&limit=2&offset=10",
+			// out
+			expectedCode: http.StatusOK,
+			expectedBody: bytes.NewBufferString(fmt.Sprintf(`{
+				"results":[
+					{
+						"id":123,
+						"tenantID":1,
+						"name":"email@email.com",
+						"status":"active",
+						"createdAt":"%[1]s",
+						"updatedAt":"%[1]s",
+						"failedLoginAttempts":0
+					},
+					{
+						"id":124,
+						"tenantID":1,
+						"name":"other@email.com",
+						"status":"disabled",
+						"createdAt":"%[1]s",
+						"updatedAt":"%[1]s",
+						"failedLoginAttempts":2,
+						"lockedUntil":"%[2]s"
+					}
+				],
+				"meta":{
+					"limit":2,
+					"offset":10,
+					"total":12
+				}
+			}`, now.Format(time.RFC3339), later.Format(time.RFC3339))),
+		},
+	}
+
+	for i, c := range cases {
+		ctrl := NewRestController(fakeLogger, c.accRepo, nil, nil, nil, nil)
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, c.reqURL, nil)
+		ctrl.GetAccounts(w, req)
+
+		if w.Code != c.expectedCode {
+			t.Errorf(
+				"testcase %d %s:\nExpected status code to be %v, but got %v\n",
+				i,
+				c.caseName,
+				c.expectedCode,
+				w.Code,
+			)
+		}
+
+		// To make possible pretty formatting in test cases, we use json.Compact:
+		b := c.expectedBody.Bytes()
+		if b != nil {
+			c.expectedBody.Reset()
+			err := json.Compact(c.expectedBody, b)
+			if err != nil {
+				t.Errorf(
+					"testcase %d %s:\nFailed to compact JSON body: %s",
+					i,
+					c.caseName,
+					err,
+				)
+			}
+		}
+
+		if !bytes.Equal(w.Body.Bytes(), c.expectedBody.Bytes()) {
+			t.Errorf(
+				"testcase %d %s:\nExpected body to be\n%v\nbut got\n%v\n",
+				i,
+				c.caseName,
+				c.expectedBody,
+				w.Body,
+			)
+		}
+	}
+}
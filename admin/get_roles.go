@@ -0,0 +1,24 @@
+package admin
+
+import (
+	"net/http"
+
+	"github.com/hypnoglow/pascont/kit"
+	"github.com/hypnoglow/pascont/kit/schema"
+)
+
+// GetRoles is a handler for:
+// GET /admin/roles
+func (c RestController) GetRoles(w http.ResponseWriter, req *http.Request) {
+	roles, err := c.roleRepo.List()
+	if err != nil {
+		c.logger.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	kit.RespondJSON(w, http.StatusOK, schema.NewResultsBody(
+		newRoleSchemas(roles),
+		nil,
+	))
+}
@@ -0,0 +1,118 @@
+package admin
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hypnoglow/pascont/role"
+)
+
+func TestRestController_GetRoles(t *testing.T) {
+	fakeLogger := log.New(ioutil.Discard, "", log.LstdFlags)
+
+	cases := []struct {
+		caseName string
+		// in
+		roleRepo role.Repository
+		// out
+		expectedCode int
+		expectedBody *bytes.Buffer
+	}{
+		{
+			caseName: "roleRepo.List failed",
+			roleRepo: role.NewFakeRepository(
+				nil,
+				[]role.FakeRepositoryListResult{
+					{
+						Error: fmt.Errorf("List failed"),
+					},
+				},
+				nil,
+				nil,
+				nil,
+			),
+			// out
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: bytes.NewBuffer(nil),
+		},
+		{
+			caseName: "Successful",
+			roleRepo: role.NewFakeRepository(
+				nil,
+				[]role.FakeRepositoryListResult{
+					{
+						Roles: []*role.Role{
+							role.NewRole("admin", []role.Permission{"accounts:manage", "roles:manage"}),
+							role.NewRole("support", nil),
+						},
+					},
+				},
+				nil,
+				nil,
+				nil,
+			),
+			// out
+			expectedCode: http.StatusOK,
+			expectedBody: bytes.NewBufferString(`{
+				"results":[
+					{
+						"name":"admin",
+						"permissions":["accounts:manage","roles:manage"]
+					},
+					{
+						"name":"support",
+						"permissions":[]
+					}
+				]
+			}`),
+		},
+	}
+
+	for i, c := range cases {
+		ctrl := NewRestController(fakeLogger, nil, nil, c.roleRepo, nil, nil)
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, PathRoles, nil)
+		ctrl.GetRoles(w, req)
+
+		if w.Code != c.expectedCode {
+			t.Errorf(
+				"testcase %d %s:\nExpected status code to be %v, but got %v\n",
+				i,
+				c.caseName,
+				c.expectedCode,
+				w.Code,
+			)
+		}
+
+		// To make possible pretty formatting in test cases, we use json.Compact:
+		b := c.expectedBody.Bytes()
+		if b != nil {
+			c.expectedBody.Reset()
+			err := json.Compact(c.expectedBody, b)
+			if err != nil {
+				t.Errorf(
+					"testcase %d %s:\nFailed to compact JSON body: %s",
+					i,
+					c.caseName,
+					err,
+				)
+			}
+		}
+
+		if !bytes.Equal(w.Body.Bytes(), c.expectedBody.Bytes()) {
+			t.Errorf(
+				"testcase %d %s:\nExpected body to be\n%v\nbut got\n%v\n",
+				i,
+				c.caseName,
+				c.expectedBody,
+				w.Body,
+			)
+		}
+	}
+}
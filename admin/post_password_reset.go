@@ -0,0 +1,53 @@
+package admin
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/hypnoglow/pascont/account"
+	"github.com/hypnoglow/pascont/accounts"
+)
+
+// PostAccountPasswordReset is a handler for:
+// POST /admin/accounts/:id/password-reset
+//
+// The current password stops working and all sessions of the account are revoked.
+// A reset token is sent to the account holder, who chooses the new password.
+func (c RestController) PostAccountPasswordReset(w http.ResponseWriter, req *http.Request) {
+	id, ok := pathAccountID(req)
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	acc, err := c.accountRepo.FindByID(id)
+	if err != nil {
+		if err == account.ErrNotFound {
+			w.WriteHeader(http.StatusNotFound)
+		} else {
+			c.logger.Println(err)
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		return
+	}
+
+	// No password matches an empty hash. The token is sent along with the change,
+	// so the account is never left without a way to set the password.
+	acc.UpdatedAt = time.Now().UTC().Truncate(time.Second)
+	err = c.resetSender.SendWithPasswordChange(account.PasswordChange{
+		Account:      *acc,
+		PasswordHash: []byte{},
+		Messages:     accounts.PasswordChangedEvents(c.events, c.logger, *acc),
+	})
+	if err != nil {
+		if err == account.ErrNotFound {
+			w.WriteHeader(http.StatusNotFound)
+		} else {
+			c.logger.Println(err)
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
@@ -0,0 +1,260 @@
+package admin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/pascont/account"
+	"github.com/hypnoglow/pascont/kit/middleware"
+	"github.com/hypnoglow/pascont/reset"
+)
+
+func TestRestController_PostAccountPasswordReset(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	fakeLogger := log.New(ioutil.Discard, "", log.LstdFlags)
+
+	cases := []struct {
+		caseName string
+		// in
+		accRepo     account.Repository
+		resetSender reset.Sender
+		reqPathID   string
+		// out
+		expectedCode int
+		expectedBody *bytes.Buffer
+	}{
+		{
+			caseName:  "The account id from path is not a number",
+			reqPathID: "abc",
+			// out
+			expectedCode: http.StatusNotFound,
+			expectedBody: bytes.NewBuffer(nil),
+		},
+		{
+			caseName: "Account not found should result in 404",
+			accRepo: account.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				[]account.FakeRepositoryFindByIDResult{
+					{
+						Error: account.ErrNotFound,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			reqPathID: "123",
+			// out
+			expectedCode: http.StatusNotFound,
+			expectedBody: bytes.NewBuffer(nil),
+		},
+		{
+			caseName: "Error on accountRepo.FindByID should result in 500",
+			accRepo: account.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				[]account.FakeRepositoryFindByIDResult{
+					{
+						Error: fmt.Errorf("FindByID failed"),
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			reqPathID: "123",
+			// out
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: bytes.NewBuffer(nil),
+		},
+		{
+			caseName: "Account deleted meanwhile should result in 404",
+			accRepo: account.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				[]account.FakeRepositoryFindByIDResult{
+					{
+						Account: &account.Account{
+							ID:        123,
+							TenantID:  1,
+							Name:      "email@email.com",
+							Status:    account.StatusActive,
+							CreatedAt: now,
+							UpdatedAt: now,
+						},
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			resetSender: reset.NewFakeSender(
+				nil,
+				[]reset.FakeSenderSendWithPasswordChangeResult{
+					{
+						Error: account.ErrNotFound,
+					},
+				},
+			),
+			reqPathID: "123",
+			// out
+			expectedCode: http.StatusNotFound,
+			expectedBody: bytes.NewBuffer(nil),
+		},
+		{
+			caseName: "Error on resetSender.SendWithPasswordChange should result in 500",
+			accRepo: account.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				[]account.FakeRepositoryFindByIDResult{
+					{
+						Account: &account.Account{
+							ID:        123,
+							TenantID:  1,
+							Name:      "email@email.com",
+							Status:    account.StatusActive,
+							CreatedAt: now,
+							UpdatedAt: now,
+						},
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			resetSender: reset.NewFakeSender(
+				nil,
+				[]reset.FakeSenderSendWithPasswordChangeResult{
+					{
+						Error: fmt.Errorf("SendWithPasswordChange failed"),
+					},
+				},
+			),
+			reqPathID: "123",
+			// out
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: bytes.NewBuffer(nil),
+		},
+		{
+			caseName: "Successful",
+			accRepo: account.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				[]account.FakeRepositoryFindByIDResult{
+					{
+						Account: &account.Account{
+							ID:        123,
+							TenantID:  1,
+							Name:      "email@email.com",
+							Status:    account.StatusActive,
+							CreatedAt: now,
+							UpdatedAt: now,
+						},
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			resetSender: reset.NewFakeSender(
+				nil,
+				[]reset.FakeSenderSendWithPasswordChangeResult{
+					{
+						Error: nil,
+					},
+				},
+			),
+			reqPathID: "123",
+			// out
+			expectedCode: http.StatusAccepted,
+			expectedBody: bytes.NewBuffer(nil),
+		},
+	}
+
+	for i, c := range cases {
+		ctrl := NewRestController(fakeLogger, c.accRepo, nil, nil, c.resetSender, nil)
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/admin/accounts/"+c.reqPathID+"/password-reset", nil)
+		req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyPathParams{}, map[string]string{"id": c.reqPathID}))
+		ctrl.PostAccountPasswordReset(w, req)
+
+		if w.Code != c.expectedCode {
+			t.Errorf(
+				"testcase %d %s:\nExpected status code to be %v, but got %v\n",
+				i,
+				c.caseName,
+				c.expectedCode,
+				w.Code,
+			)
+		}
+
+		// To make possible pretty formatting in test cases, we use json.Compact:
+		b := c.expectedBody.Bytes()
+		if b != nil {
+			c.expectedBody.Reset()
+			err := json.Compact(c.expectedBody, b)
+			if err != nil {
+				t.Errorf(
+					"testcase %d %s:\nFailed to compact JSON body: %s",
+					i,
+					c.caseName,
+					err,
+				)
+			}
+		}
+
+		if !bytes.Equal(w.Body.Bytes(), c.expectedBody.Bytes()) {
+			t.Errorf(
+				"testcase %d %s:\nExpected body to be\n%v\nbut got\n%v\n",
+				i,
+				c.caseName,
+				c.expectedBody,
+				w.Body,
+			)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package admin
+
+import (
+	"net/http"
+
+	"github.com/hypnoglow/pascont/kit/middleware"
+	"github.com/hypnoglow/pascont/role"
+)
+
+// PutAccountRole is a handler for:
+// PUT /admin/accounts/:id/roles/:role
+func (c RestController) PutAccountRole(w http.ResponseWriter, req *http.Request) {
+	acc, status := c.pathAccount(req)
+	if acc == nil {
+		w.WriteHeader(status)
+		return
+	}
+	roleName, _ := middleware.PathParam(req, "role")
+
+	if err := c.roleRepo.Assign(acc.ID, roleName); err != nil {
+		if err == role.ErrNotFound {
+			w.WriteHeader(http.StatusNotFound)
+		} else {
+			c.logger.Println(err)
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DeleteAccountRole is a handler for:
+// DELETE /admin/accounts/:id/roles/:role
+func (c RestController) DeleteAccountRole(w http.ResponseWriter, req *http.Request) {
+	acc, status := c.pathAccount(req)
+	if acc == nil {
+		w.WriteHeader(status)
+		return
+	}
+	roleName, _ := middleware.PathParam(req, "role")
+
+	if err := c.roleRepo.Unassign(acc.ID, roleName); err != nil {
+		if err == role.ErrNotFound {
+			w.WriteHeader(http.StatusNotFound)
+		} else {
+			c.logger.Println(err)
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,317 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hypnoglow/pascont/account"
+	"github.com/hypnoglow/pascont/kit/middleware"
+	"github.com/hypnoglow/pascont/role"
+)
+
+// accountFound returns a fake account.Repository which finds the account.
+func accountFound(acc *account.Account, err error) account.Repository {
+	return account.NewFakeRepository(
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		[]account.FakeRepositoryFindByIDResult{
+			{
+				Account: acc,
+				Error:   err,
+			},
+		},
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+}
+
+func TestRestController_PutAccountRole(t *testing.T) {
+	fakeLogger := log.New(ioutil.Discard, "", log.LstdFlags)
+	acc := &account.Account{ID: 123, TenantID: 1, Name: "email@email.com"}
+
+	cases := []struct {
+		caseName string
+		// in
+		accRepo                 account.Repository
+		roleRepo                role.Repository
+		reqPathID               string
+		reqContextTenantIDValue interface{}
+		// out
+		expectedCode int
+	}{
+		{
+			caseName:  "The account id from path is not a number",
+			reqPathID: "abc",
+			// out
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			caseName:  "Account not found should result in 404",
+			accRepo:   accountFound(nil, account.ErrNotFound),
+			reqPathID: "123",
+			// out
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			caseName:  "Error on accountRepo.FindByID should result in 500",
+			accRepo:   accountFound(nil, fmt.Errorf("FindByID failed")),
+			reqPathID: "123",
+			// out
+			expectedCode: http.StatusInternalServerError,
+		},
+		{
+			caseName:                "Account of another tenant than the session should result in 404",
+			accRepo:                 accountFound(acc, nil),
+			reqPathID:               "123",
+			reqContextTenantIDValue: int64(2),
+			// out
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			caseName: "roleRepo.Assign returns ErrNotFound",
+			accRepo:  accountFound(acc, nil),
+			roleRepo: role.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				[]role.FakeRepositoryAssignResult{
+					{
+						Error: role.ErrNotFound,
+					},
+				},
+				nil,
+			),
+			reqPathID: "123",
+			// out
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			caseName: "roleRepo.Assign failed",
+			accRepo:  accountFound(acc, nil),
+			roleRepo: role.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				[]role.FakeRepositoryAssignResult{
+					{
+						Error: fmt.Errorf("Assign failed"),
+					},
+				},
+				nil,
+			),
+			reqPathID: "123",
+			// out
+			expectedCode: http.StatusInternalServerError,
+		},
+		{
+			caseName: "Successful for the session of the account tenant",
+			accRepo:  accountFound(acc, nil),
+			roleRepo: role.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				[]role.FakeRepositoryAssignResult{
+					{
+						Error: nil,
+					},
+				},
+				nil,
+			),
+			reqPathID:               "123",
+			reqContextTenantIDValue: int64(1),
+			// out
+			expectedCode: http.StatusNoContent,
+		},
+		{
+			caseName: "Successful",
+			accRepo:  accountFound(acc, nil),
+			roleRepo: role.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				[]role.FakeRepositoryAssignResult{
+					{
+						Error: nil,
+					},
+				},
+				nil,
+			),
+			reqPathID: "123",
+			// out
+			expectedCode: http.StatusNoContent,
+		},
+	}
+
+	for i, c := range cases {
+		ctrl := NewRestController(fakeLogger, c.accRepo, nil, c.roleRepo, nil, nil)
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPut, "/admin/accounts/"+c.reqPathID+"/roles/admin", nil)
+		req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyPathParams{}, map[string]string{"id": c.reqPathID, "role": "admin"}))
+		if c.reqContextTenantIDValue != nil {
+			req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyTenantID{}, c.reqContextTenantIDValue))
+		}
+		ctrl.PutAccountRole(w, req)
+
+		if w.Code != c.expectedCode {
+			t.Errorf(
+				"testcase %d %s:\nExpected status code to be %v, but got %v\n",
+				i,
+				c.caseName,
+				c.expectedCode,
+				w.Code,
+			)
+		}
+	}
+}
+
+func TestRestController_DeleteAccountRole(t *testing.T) {
+	fakeLogger := log.New(ioutil.Discard, "", log.LstdFlags)
+	acc := &account.Account{ID: 123, TenantID: 1, Name: "email@email.com"}
+
+	cases := []struct {
+		caseName string
+		// in
+		accRepo                 account.Repository
+		roleRepo                role.Repository
+		reqPathID               string
+		reqContextTenantIDValue interface{}
+		// out
+		expectedCode int
+	}{
+		{
+			caseName:  "The account id from path is not a number",
+			reqPathID: "abc",
+			// out
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			caseName:  "Account not found should result in 404",
+			accRepo:   accountFound(nil, account.ErrNotFound),
+			reqPathID: "123",
+			// out
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			caseName:  "Error on accountRepo.FindByID should result in 500",
+			accRepo:   accountFound(nil, fmt.Errorf("FindByID failed")),
+			reqPathID: "123",
+			// out
+			expectedCode: http.StatusInternalServerError,
+		},
+		{
+			caseName:                "Account of another tenant than the session should result in 404",
+			accRepo:                 accountFound(acc, nil),
+			reqPathID:               "123",
+			reqContextTenantIDValue: int64(2),
+			// out
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			caseName: "roleRepo.Unassign returns ErrNotFound",
+			accRepo:  accountFound(acc, nil),
+			roleRepo: role.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				nil,
+				[]role.FakeRepositoryUnassignResult{
+					{
+						Error: role.ErrNotFound,
+					},
+				},
+			),
+			reqPathID: "123",
+			// out
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			caseName: "roleRepo.Unassign failed",
+			accRepo:  accountFound(acc, nil),
+			roleRepo: role.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				nil,
+				[]role.FakeRepositoryUnassignResult{
+					{
+						Error: fmt.Errorf("Unassign failed"),
+					},
+				},
+			),
+			reqPathID: "123",
+			// out
+			expectedCode: http.StatusInternalServerError,
+		},
+		{
+			caseName: "Successful for the session of the account tenant",
+			accRepo:  accountFound(acc, nil),
+			roleRepo: role.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				nil,
+				[]role.FakeRepositoryUnassignResult{
+					{
+						Error: nil,
+					},
+				},
+			),
+			reqPathID:               "123",
+			reqContextTenantIDValue: int64(1),
+			// out
+			expectedCode: http.StatusNoContent,
+		},
+		{
+			caseName: "Successful",
+			accRepo:  accountFound(acc, nil),
+			roleRepo: role.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				nil,
+				[]role.FakeRepositoryUnassignResult{
+					{
+						Error: nil,
+					},
+				},
+			),
+			reqPathID: "123",
+			// out
+			expectedCode: http.StatusNoContent,
+		},
+	}
+
+	for i, c := range cases {
+		ctrl := NewRestController(fakeLogger, c.accRepo, nil, c.roleRepo, nil, nil)
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodDelete, "/admin/accounts/"+c.reqPathID+"/roles/admin", nil)
+		req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyPathParams{}, map[string]string{"id": c.reqPathID, "role": "admin"}))
+		if c.reqContextTenantIDValue != nil {
+			req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyTenantID{}, c.reqContextTenantIDValue))
+		}
+		ctrl.DeleteAccountRole(w, req)
+
+		if w.Code != c.expectedCode {
+			t.Errorf(
+				"testcase %d %s:\nExpected status code to be %v, but got %v\n",
+				i,
+				c.caseName,
+				c.expectedCode,
+				w.Code,
+			)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package admin
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/hypnoglow/pascont/kit"
+	"github.com/hypnoglow/pascont/kit/form"
+	"github.com/hypnoglow/pascont/kit/middleware"
+	"github.com/hypnoglow/pascont/role"
+)
+
+// PutRole is a handler for:
+// PUT /admin/roles/:role
+//
+// The role is created, or its permissions are replaced.
+func (c RestController) PutRole(w http.ResponseWriter, req *http.Request) {
+	var roleForm putRoleForm
+	form.PopulateFormFromJSON(req.Body, &roleForm)
+	roleForm.Name, _ = middleware.PathParam(req, "role")
+	if !roleForm.Validate() {
+		kit.RespondWithFormErrors(w, http.StatusBadRequest, roleForm.ValidationErrors())
+		return
+	}
+
+	if err := c.roleRepo.Save(*role.NewRole(roleForm.Name, roleForm.Permissions)); err != nil {
+		c.logger.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+const (
+	// roleNameMaxLen is max len of the role name in bytes.
+	roleNameMaxLen = 64
+
+	// permissionMaxLen is max len of the permission in bytes.
+	permissionMaxLen = 64
+)
+
+type putRoleForm struct {
+	form.BaseForm
+	Name        string            `json:"-"`
+	Permissions []role.Permission `json:"permissions"`
+}
+
+func (f *putRoleForm) Validate() bool {
+	if len(f.Name) > roleNameMaxLen {
+		f.AddError(fmt.Sprintf("Role name must be at most %d bytes", roleNameMaxLen), "name", f.Name)
+	}
+
+	for _, p := range f.Permissions {
+		if len(p) == 0 || len(p) > permissionMaxLen {
+			f.AddError(fmt.Sprintf("Permission must be from 1 to %d bytes", permissionMaxLen), "permissions", p)
+		}
+	}
+
+	return len(f.ValidationErrors()) == 0
+}
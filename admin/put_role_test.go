@@ -0,0 +1,136 @@
+package admin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hypnoglow/pascont/kit/middleware"
+	"github.com/hypnoglow/pascont/role"
+)
+
+func TestRestController_PutRole(t *testing.T) {
+	fakeLogger := log.New(ioutil.Discard, "", log.LstdFlags)
+
+	cases := []struct {
+		caseName string
+		// in
+		roleRepo    role.Repository
+		reqRoleName string
+		reqBody     io.Reader
+		// out
+		expectedCode int
+		expectedBody *bytes.Buffer
+	}{
+		{
+			caseName:    "Invalid permission should result in 400",
+			reqRoleName: "admin",
+			reqBody: bytes.NewBufferString(`{
+				"permissions":[""]
+			}`),
+			// out
+			expectedCode: http.StatusBadRequest,
+			expectedBody: bytes.NewBufferString(`{
+				"errors":[
+					{
+						"message":"Permission must be from 1 to 64 bytes",
+						"field":"permissions",
+						"value":""
+					}
+				]
+			}`),
+		},
+		{
+			caseName: "roleRepo.Save failed",
+			roleRepo: role.NewFakeRepository(
+				[]role.FakeRepositorySaveResult{
+					{
+						Error: fmt.Errorf("Save failed"),
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			reqRoleName: "admin",
+			reqBody: bytes.NewBufferString(`{
+				"permissions":["accounts:manage"]
+			}`),
+			// out
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: bytes.NewBuffer(nil),
+		},
+		{
+			caseName: "Successful",
+			roleRepo: role.NewFakeRepository(
+				[]role.FakeRepositorySaveResult{
+					{
+						Error: nil,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			reqRoleName: "admin",
+			reqBody: bytes.NewBufferString(`{
+				"permissions":["accounts:manage"]
+			}`),
+			// out
+			expectedCode: http.StatusNoContent,
+			expectedBody: bytes.NewBuffer(nil),
+		},
+	}
+
+	for i, c := range cases {
+		ctrl := NewRestController(fakeLogger, nil, nil, c.roleRepo, nil, nil)
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPut, "/admin/roles/"+c.reqRoleName, c.reqBody)
+		req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyPathParams{}, map[string]string{"role": c.reqRoleName}))
+		ctrl.PutRole(w, req)
+
+		if w.Code != c.expectedCode {
+			t.Errorf(
+				"testcase %d %s:\nExpected status code to be %v, but got %v\n",
+				i,
+				c.caseName,
+				c.expectedCode,
+				w.Code,
+			)
+		}
+
+		// To make possible pretty formatting in test cases, we use json.Compact:
+		b := c.expectedBody.Bytes()
+		if b != nil {
+			c.expectedBody.Reset()
+			err := json.Compact(c.expectedBody, b)
+			if err != nil {
+				t.Errorf(
+					"testcase %d %s:\nFailed to compact JSON body: %s",
+					i,
+					c.caseName,
+					err,
+				)
+			}
+		}
+
+		if !bytes.Equal(w.Body.Bytes(), c.expectedBody.Bytes()) {
+			t.Errorf(
+				"testcase %d %s:\nExpected body to be\n%v\nbut got\n%v\n",
+				i,
+				c.caseName,
+				c.expectedBody,
+				w.Body,
+			)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package admin
+
+import (
+	"net/http"
+
+	"github.com/hypnoglow/pascont/account"
+	"github.com/hypnoglow/pascont/kit"
+	"github.com/hypnoglow/pascont/kit/form"
+	"github.com/hypnoglow/pascont/outbox"
+)
+
+// PutAccountStatus is a handler for:
+// PUT /admin/accounts/:id/status
+//
+// Sessions of the account are revoked when it becomes not active.
+func (c RestController) PutAccountStatus(w http.ResponseWriter, req *http.Request) {
+	id, ok := pathAccountID(req)
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	var statusForm putStatusForm
+	form.PopulateFormFromJSON(req.Body, &statusForm)
+	if !statusForm.Validate() {
+		kit.RespondWithFormErrors(w, http.StatusBadRequest, statusForm.ValidationErrors())
+		return
+	}
+
+	var revoked []outbox.Message
+	if statusForm.Status != account.StatusActive {
+		revoked = c.revokedMessages(id)
+	}
+	if err := c.accountRepo.UpdateStatus(id, statusForm.Status, revoked...); err != nil {
+		if err == account.ErrNotFound {
+			w.WriteHeader(http.StatusNotFound)
+		} else {
+			c.logger.Println(err)
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type putStatusForm struct {
+	form.BaseForm
+	Status account.Status `json:"status"`
+}
+
+func (f *putStatusForm) Validate() bool {
+	if !f.Status.IsValid() {
+		f.AddError("Status must be one of: active, disabled, suspended, pending", "status", f.Status)
+	}
+
+	return len(f.ValidationErrors()) == 0
+}
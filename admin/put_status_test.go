@@ -0,0 +1,254 @@
+package admin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hypnoglow/pascont/account"
+	"github.com/hypnoglow/pascont/kit/middleware"
+	"github.com/hypnoglow/pascont/outbox"
+	"github.com/hypnoglow/pascont/webhook"
+)
+
+func TestRestController_PutAccountStatus(t *testing.T) {
+	fakeLogger := log.New(ioutil.Discard, "", log.LstdFlags)
+
+	cases := []struct {
+		caseName string
+		// in
+		accRepo   account.Repository
+		reqPathID string
+		reqBody   io.Reader
+		// out
+		expectedCode int
+		expectedBody *bytes.Buffer
+	}{
+		{
+			caseName:  "The account id from path is not a number",
+			reqPathID: "abc",
+			reqBody: bytes.NewBufferString(`{
+				"status":"disabled"
+			}`),
+			// out
+			expectedCode: http.StatusNotFound,
+			expectedBody: bytes.NewBuffer(nil),
+		},
+		{
+			caseName:  "Unknown status should result in 400",
+			reqPathID: "123",
+			reqBody: bytes.NewBufferString(`{
+				"status":"deleted"
+			}`),
+			// out
+			expectedCode: http.StatusBadRequest,
+			expectedBody: bytes.NewBufferString(`{
+				"errors":[
+					{
+						"message":"Status must be one of: active, disabled, suspended, pending",
+						"field":"status",
+						"value":"deleted"
+					}
+				]
+			}`),
+		},
+		{
+			caseName: "accountRepo.UpdateStatus returns ErrNotFound",
+			accRepo: account.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				[]account.FakeRepositoryUpdateStatusResult{
+					{
+						Error: account.ErrNotFound,
+					},
+				},
+				nil,
+				nil,
+				nil,
+			),
+			reqPathID: "123",
+			reqBody: bytes.NewBufferString(`{
+				"status":"disabled"
+			}`),
+			// out
+			expectedCode: http.StatusNotFound,
+			expectedBody: bytes.NewBuffer(nil),
+		},
+		{
+			caseName: "accountRepo.UpdateStatus failed",
+			accRepo: account.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				[]account.FakeRepositoryUpdateStatusResult{
+					{
+						Error: fmt.Errorf("UpdateStatus failed"),
+					},
+				},
+				nil,
+				nil,
+				nil,
+			),
+			reqPathID: "123",
+			reqBody: bytes.NewBufferString(`{
+				"status":"disabled"
+			}`),
+			// out
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: bytes.NewBuffer(nil),
+		},
+		{
+			caseName: "Successful",
+			accRepo: account.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				[]account.FakeRepositoryUpdateStatusResult{
+					{
+						Error: nil,
+					},
+				},
+				nil,
+				nil,
+				nil,
+			),
+			reqPathID: "123",
+			reqBody: bytes.NewBufferString(`{
+				"status":"disabled"
+			}`),
+			// out
+			expectedCode: http.StatusNoContent,
+			expectedBody: bytes.NewBuffer(nil),
+		},
+	}
+
+	for i, c := range cases {
+		ctrl := NewRestController(fakeLogger, c.accRepo, nil, nil, nil, nil)
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPut, "/admin/accounts/"+c.reqPathID+"/status", c.reqBody)
+		req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyPathParams{}, map[string]string{"id": c.reqPathID}))
+		ctrl.PutAccountStatus(w, req)
+
+		if w.Code != c.expectedCode {
+			t.Errorf(
+				"testcase %d %s:\nExpected status code to be %v, but got %v\n",
+				i,
+				c.caseName,
+				c.expectedCode,
+				w.Code,
+			)
+		}
+
+		// To make possible pretty formatting in test cases, we use json.Compact:
+		b := c.expectedBody.Bytes()
+		if b != nil {
+			c.expectedBody.Reset()
+			err := json.Compact(c.expectedBody, b)
+			if err != nil {
+				t.Errorf(
+					"testcase %d %s:\nFailed to compact JSON body: %s",
+					i,
+					c.caseName,
+					err,
+				)
+			}
+		}
+
+		if !bytes.Equal(w.Body.Bytes(), c.expectedBody.Bytes()) {
+			t.Errorf(
+				"testcase %d %s:\nExpected body to be\n%v\nbut got\n%v\n",
+				i,
+				c.caseName,
+				c.expectedBody,
+				w.Body,
+			)
+		}
+	}
+}
+
+// statusAccountRepository is an account.Repository which keeps the outbox messages
+// added along with the status change.
+type statusAccountRepository struct {
+	account.Repository
+	messages []outbox.Message
+}
+
+func (r *statusAccountRepository) UpdateStatus(id int64, status account.Status, messages ...outbox.Message) error {
+	r.messages = append(r.messages, messages...)
+	return nil
+}
+
+func TestRestController_PutAccountStatus_Events(t *testing.T) {
+	fakeLogger := log.New(ioutil.Discard, "", log.LstdFlags)
+	revoked := outbox.Message{ID: "1", Channel: webhook.Channel}
+
+	accRepo := &statusAccountRepository{
+		Repository: account.NewFakeRepository(
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			[]account.FakeRepositoryFindByIDResult{
+				{
+					Account: &account.Account{ID: 123, TenantID: 1},
+					Error:   nil,
+				},
+			},
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+		),
+	}
+	events := webhook.NewFakeEmitter(
+		[]webhook.FakeEmitterMessagesResult{
+			{
+				Messages: []outbox.Message{revoked},
+			},
+		},
+		nil,
+	)
+
+	ctrl := NewRestController(fakeLogger, accRepo, nil, nil, nil, events)
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPut, "/admin/accounts/123/status", bytes.NewBufferString(`{"status":"disabled"}`))
+	req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyPathParams{}, map[string]string{"id": "123"}))
+	ctrl.PutAccountStatus(w, req)
+
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("Expected status code to be %v, but got %v\n", http.StatusNoContent, w.Code)
+	}
+	if len(accRepo.messages) != 1 || accRepo.messages[0].ID != revoked.ID {
+		t.Errorf("Expected the revocation event to be added along with it, but got %#v\n", accRepo.messages)
+	}
+}
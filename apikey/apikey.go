@@ -0,0 +1,102 @@
+package apikey
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"strings"
+	"time"
+
+	"github.com/pborman/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/hypnoglow/pascont/identity"
+)
+
+const (
+	// TokenPrefix is a prefix of API key tokens, which distinguishes them
+	// from session tokens.
+	TokenPrefix = "pak_"
+
+	// idLength is the length of an API key ID in bytes.
+	idLength = 36
+
+	// secretLength is the length of an API key secret in bytes, before encoding.
+	secretLength = 32
+)
+
+// APIKey represents a long-lived credential of an account.
+// Only a hash of the key secret is kept.
+type APIKey struct {
+	ID        string
+	AccountID int64
+	Name      string
+	Hash      []byte
+	CreatedAt time.Time
+
+	// ExpiresAt is zero if the key never expires.
+	ExpiresAt time.Time
+
+	// LastUsedAt is zero if the key was never used.
+	LastUsedAt time.Time
+}
+
+// Generate creates a new APIKey for the account.
+// The APIKey ID is a UUID produced by identity.UUIDProducer func.
+// Returns the key along with its token, which is the only place
+// the key secret is revealed.
+func Generate(uuidProducer identity.UUIDProducer, accountID int64, name string, createdAt, expiresAt time.Time) (key *APIKey, token string, err error) {
+	b := make([]byte, secretLength)
+	if _, err = rand.Read(b); err != nil {
+		return nil, "", errors.Wrap(err, "Failed to generate an API key secret")
+	}
+	secret := base64.RawURLEncoding.EncodeToString(b)
+
+	key = &APIKey{
+		ID:        uuidProducer(),
+		AccountID: accountID,
+		Name:      name,
+		Hash:      hashSecret(secret),
+		CreatedAt: createdAt.UTC().Truncate(time.Second),
+	}
+	if !expiresAt.IsZero() {
+		key.ExpiresAt = expiresAt.UTC().Truncate(time.Second)
+	}
+
+	return key, TokenPrefix + key.ID + "_" + secret, nil
+}
+
+// ParseToken splits an API key token into the key ID and secret.
+func ParseToken(token string) (id, secret string, ok bool) {
+	if !strings.HasPrefix(token, TokenPrefix) {
+		return "", "", false
+	}
+	token = strings.TrimPrefix(token, TokenPrefix)
+
+	if len(token) <= idLength+1 || token[idLength] != '_' {
+		return "", "", false
+	}
+	if uuid.Parse(token[:idLength]) == nil {
+		return "", "", false
+	}
+
+	return token[:idLength], token[idLength+1:], true
+}
+
+// IsExpired reports whether the key is expired at the moment t.
+func (k APIKey) IsExpired(t time.Time) bool {
+	return !k.ExpiresAt.IsZero() && !t.Before(k.ExpiresAt)
+}
+
+// Verify reports whether the secret belongs to the key.
+func (k APIKey) Verify(secret string) bool {
+	return subtle.ConstantTimeCompare(k.Hash, hashSecret(secret)) == 1
+}
+
+// hashSecret hashes a key secret. Secrets are random and long enough,
+// so a fast hash is sufficient unlike for passwords.
+func hashSecret(secret string) []byte {
+	sum := sha256.Sum256([]byte(secret))
+	return sum[:]
+}
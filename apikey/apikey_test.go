@@ -0,0 +1,142 @@
+package apikey
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGenerate(t *testing.T) {
+	now := time.Now()
+	uuidProducer := func() string {
+		return "12345678-90ab-cdef-0123-4567890abcde"
+	}
+
+	key, token, err := Generate(uuidProducer, 123, "ci", now, time.Time{})
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+
+	if key.ID != "12345678-90ab-cdef-0123-4567890abcde" || key.AccountID != 123 || key.Name != "ci" {
+		t.Errorf("Unexpected key %#v\n", key)
+	}
+
+	if !key.CreatedAt.Equal(now.UTC().Truncate(time.Second)) {
+		t.Errorf("Expected CreatedAt to be %v, but got %v\n", now.UTC().Truncate(time.Second), key.CreatedAt)
+	}
+
+	if !key.ExpiresAt.IsZero() {
+		t.Errorf("Expected ExpiresAt to be zero, but got %v\n", key.ExpiresAt)
+	}
+
+	if !strings.HasPrefix(token, TokenPrefix+key.ID+"_") {
+		t.Errorf("Expected token to start with the key ID, but got %s\n", token)
+	}
+
+	id, secret, ok := ParseToken(token)
+	if !ok || id != key.ID {
+		t.Fatalf("Expected token to be parsed to the key ID %s, but got %s\n", key.ID, id)
+	}
+
+	if strings.Contains(string(key.Hash), secret) {
+		t.Errorf("Expected key hash not to contain the secret\n")
+	}
+
+	if !key.Verify(secret) {
+		t.Errorf("Expected the secret to be verified\n")
+	}
+
+	if key.Verify(secret + "x") {
+		t.Errorf("Expected a wrong secret not to be verified\n")
+	}
+
+	_, other, err := Generate(uuidProducer, 123, "ci", now, time.Time{})
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	if other == token {
+		t.Errorf("Expected tokens of different keys to differ\n")
+	}
+}
+
+func TestParseToken(t *testing.T) {
+	cases := []struct {
+		token          string
+		expectedID     string
+		expectedSecret string
+		expectedOK     bool
+	}{
+		{
+			token:          "pak_12345678-90ab-cdef-0123-4567890abcde_secret",
+			expectedID:     "12345678-90ab-cdef-0123-4567890abcde",
+			expectedSecret: "secret",
+			expectedOK:     true,
+		},
+		{
+			token:      "MzkwMGNkZjMtMTY4Yi00M2JlLTkwMzQtNDhiZjQ0YmI3YzljMTQ5ODk0MjIy",
+			expectedOK: false,
+		},
+		{
+			token:      "pak_12345678-90ab-cdef-0123-4567890abcde",
+			expectedOK: false,
+		},
+		{
+			token:      "pak_12345678-90ab-cdef-0123-4567890abcde-secret",
+			expectedOK: false,
+		},
+		{
+			token:      "pak_12345678-90ab-cdef-0123-4567890abcdX_secret",
+			expectedOK: false,
+		},
+		{
+			token:      "pak_1234567890abcdef0123456789abcdef1234_secret",
+			expectedOK: false,
+		},
+	}
+
+	for i, c := range cases {
+		id, secret, ok := ParseToken(c.token)
+
+		if id != c.expectedID || secret != c.expectedSecret || ok != c.expectedOK {
+			t.Errorf(
+				"testcase %d: Expected (%v, %v, %v), but got (%v, %v, %v)\n",
+				i,
+				c.expectedID,
+				c.expectedSecret,
+				c.expectedOK,
+				id,
+				secret,
+				ok,
+			)
+		}
+	}
+}
+
+func TestAPIKey_IsExpired(t *testing.T) {
+	now := time.Now()
+
+	cases := []struct {
+		expiresAt time.Time
+		expected  bool
+	}{
+		{
+			expiresAt: time.Time{},
+			expected:  false,
+		},
+		{
+			expiresAt: now.Add(time.Minute),
+			expected:  false,
+		},
+		{
+			expiresAt: now,
+			expected:  true,
+		},
+	}
+
+	for i, c := range cases {
+		actual := APIKey{ExpiresAt: c.expiresAt}.IsExpired(now)
+		if actual != c.expected {
+			t.Errorf("testcase %d: Expected %v but got %v\n", i, c.expected, actual)
+		}
+	}
+}
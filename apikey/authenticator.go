@@ -0,0 +1,56 @@
+package apikey
+
+import (
+	"log"
+	"time"
+
+	"github.com/hypnoglow/pascont/account"
+)
+
+// lastUsedResolution is how often a key LastUsedAt is updated on requests.
+const lastUsedResolution = time.Minute
+
+// Authenticator returns a func which can be used to authenticate an account
+// by an API key token. Tokens that are not API keys, wrong secrets, expired keys
+// and keys of accounts that are not active are not found.
+func Authenticator(keyRepo Repository, accountRepo account.Repository, logger *log.Logger) func(token string) (accountID int64, found bool, err error) {
+	return func(token string) (int64, bool, error) {
+		id, secret, ok := ParseToken(token)
+		if !ok {
+			return 0, false, nil
+		}
+
+		key, err := keyRepo.FindByID(id)
+		if err == ErrNotFound {
+			return 0, false, nil
+		}
+		if err != nil {
+			return 0, false, err
+		}
+
+		now := time.Now().UTC().Truncate(time.Second)
+		if !key.Verify(secret) || key.IsExpired(now) {
+			return 0, false, nil
+		}
+
+		acc, err := accountRepo.FindByID(key.AccountID)
+		if err == account.ErrNotFound {
+			return 0, false, nil
+		}
+		if err != nil {
+			return 0, false, err
+		}
+		if !acc.IsActive() {
+			return 0, false, nil
+		}
+
+		// Failing to track the key usage must not fail the request.
+		if now.Sub(key.LastUsedAt) >= lastUsedResolution {
+			if err := keyRepo.Touch(key.ID, now); err != nil {
+				logger.Println(err)
+			}
+		}
+
+		return key.AccountID, true, nil
+	}
+}
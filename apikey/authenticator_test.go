@@ -0,0 +1,277 @@
+package apikey
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/pascont/account"
+)
+
+func TestAuthenticator(t *testing.T) {
+	now := time.Now()
+	fakeLogger := log.New(ioutil.Discard, "", log.LstdFlags)
+	uuidProducer := func() string {
+		return "12345678-90ab-cdef-0123-4567890abcde"
+	}
+
+	key, token, err := Generate(uuidProducer, 123, "ci", now.Add(-time.Hour), time.Time{})
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	expiredKey := *key
+	expiredKey.ExpiresAt = now.Add(-time.Minute)
+	recentlyUsedKey := *key
+	recentlyUsedKey.LastUsedAt = now
+
+	cases := []struct {
+		caseName string
+		// in
+		keyRepo Repository
+		accRepo account.Repository
+		token   string
+		// out
+		expectedAccountID int64
+		expectedFound     bool
+		expectedError     error
+	}{
+		{
+			caseName:          "Token is not an API key",
+			token:             "MzkwMGNkZjMtMTY4Yi00M2JlLTkwMzQtNDhiZjQ0YmI3YzljMTQ5ODk0MjIy",
+			expectedAccountID: 0,
+			expectedFound:     false,
+			expectedError:     nil,
+		},
+		{
+			caseName:          "Token key ID is not a UUID",
+			token:             "pak_not-a-uuid-not-a-uuid-not-a-uuid-abc_secret",
+			expectedAccountID: 0,
+			expectedFound:     false,
+			expectedError:     nil,
+		},
+		{
+			caseName: "Key not found",
+			keyRepo: NewFakeRepository(
+				nil,
+				[]FakeRepositoryFindByIDResult{
+					{
+						Error: ErrNotFound,
+					},
+				},
+				nil,
+				nil,
+				nil,
+			),
+			token:             token,
+			expectedAccountID: 0,
+			expectedFound:     false,
+			expectedError:     nil,
+		},
+		{
+			caseName: "keyRepo.FindByID failed",
+			keyRepo: NewFakeRepository(
+				nil,
+				[]FakeRepositoryFindByIDResult{
+					{
+						Error: fmt.Errorf("FindByID failed"),
+					},
+				},
+				nil,
+				nil,
+				nil,
+			),
+			token:             token,
+			expectedAccountID: 0,
+			expectedFound:     false,
+			expectedError:     fmt.Errorf("FindByID failed"),
+		},
+		{
+			caseName: "Wrong secret",
+			keyRepo: NewFakeRepository(
+				nil,
+				[]FakeRepositoryFindByIDResult{
+					{
+						Key: key,
+					},
+				},
+				nil,
+				nil,
+				nil,
+			),
+			token:             token + "x",
+			expectedAccountID: 0,
+			expectedFound:     false,
+			expectedError:     nil,
+		},
+		{
+			caseName: "Key is expired",
+			keyRepo: NewFakeRepository(
+				nil,
+				[]FakeRepositoryFindByIDResult{
+					{
+						Key: &expiredKey,
+					},
+				},
+				nil,
+				nil,
+				nil,
+			),
+			token:             token,
+			expectedAccountID: 0,
+			expectedFound:     false,
+			expectedError:     nil,
+		},
+		{
+			caseName: "Account is not active",
+			keyRepo: NewFakeRepository(
+				nil,
+				[]FakeRepositoryFindByIDResult{
+					{
+						Key: key,
+					},
+				},
+				nil,
+				nil,
+				nil,
+			),
+			accRepo: account.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				[]account.FakeRepositoryFindByIDResult{
+					{
+						Account: &account.Account{ID: 123, Status: account.StatusDisabled},
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			token:             token,
+			expectedAccountID: 0,
+			expectedFound:     false,
+			expectedError:     nil,
+		},
+		{
+			caseName: "Successful with LastUsedAt updated",
+			keyRepo: NewFakeRepository(
+				nil,
+				[]FakeRepositoryFindByIDResult{
+					{
+						Key: key,
+					},
+				},
+				nil,
+				[]FakeRepositoryTouchResult{
+					{
+						Error: nil,
+					},
+				},
+				nil,
+			),
+			accRepo: account.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				[]account.FakeRepositoryFindByIDResult{
+					{
+						Account: &account.Account{ID: 123, Status: account.StatusActive},
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			token:             token,
+			expectedAccountID: 123,
+			expectedFound:     true,
+			expectedError:     nil,
+		},
+		{
+			caseName: "Successful with LastUsedAt recently updated",
+			keyRepo: NewFakeRepository(
+				nil,
+				[]FakeRepositoryFindByIDResult{
+					{
+						Key: &recentlyUsedKey,
+					},
+				},
+				nil,
+				nil,
+				nil,
+			),
+			accRepo: account.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				[]account.FakeRepositoryFindByIDResult{
+					{
+						Account: &account.Account{ID: 123, Status: account.StatusActive},
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			token:             token,
+			expectedAccountID: 123,
+			expectedFound:     true,
+			expectedError:     nil,
+		},
+	}
+
+	for i, c := range cases {
+		accountID, found, err := Authenticator(c.keyRepo, c.accRepo, fakeLogger)(c.token)
+
+		if accountID != c.expectedAccountID {
+			t.Errorf(
+				"testcase %d %s:\nExpected account ID to be %v, but got %v\n",
+				i,
+				c.caseName,
+				c.expectedAccountID,
+				accountID,
+			)
+		}
+
+		if found != c.expectedFound {
+			t.Errorf(
+				"testcase %d %s:\nExpected found to be %v, but got %v\n",
+				i,
+				c.caseName,
+				c.expectedFound,
+				found,
+			)
+		}
+
+		if !reflect.DeepEqual(err, c.expectedError) {
+			t.Errorf(
+				"testcase %d %s:\nExpected error to be %v, but got %v\n",
+				i,
+				c.caseName,
+				c.expectedError,
+				err,
+			)
+		}
+	}
+}
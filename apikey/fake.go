@@ -0,0 +1,90 @@
+package apikey
+
+import "time"
+
+type fakeRepository struct {
+	saveResults                []FakeRepositorySaveResult
+	saveResultCounter          int
+	findByIDResults            []FakeRepositoryFindByIDResult
+	findByIDResultCounter      int
+	listByAccountResults       []FakeRepositoryListByAccountResult
+	listByAccountResultCounter int
+	touchResults               []FakeRepositoryTouchResult
+	touchResultCounter         int
+	deleteResults              []FakeRepositoryDeleteResult
+	deleteResultCounter        int
+}
+
+type FakeRepositorySaveResult struct {
+	Error error
+}
+
+type FakeRepositoryFindByIDResult struct {
+	Key   *APIKey
+	Error error
+}
+
+type FakeRepositoryListByAccountResult struct {
+	Keys  []*APIKey
+	Error error
+}
+
+type FakeRepositoryTouchResult struct {
+	Error error
+}
+
+type FakeRepositoryDeleteResult struct {
+	Error error
+}
+
+// NewFakeRepository returns a new fake Repository.
+func NewFakeRepository(
+	saveResults []FakeRepositorySaveResult,
+	findByIDResults []FakeRepositoryFindByIDResult,
+	listByAccountResults []FakeRepositoryListByAccountResult,
+	touchResults []FakeRepositoryTouchResult,
+	deleteResults []FakeRepositoryDeleteResult,
+) Repository {
+	return &fakeRepository{
+		saveResults:                saveResults,
+		saveResultCounter:          0,
+		findByIDResults:            findByIDResults,
+		findByIDResultCounter:      0,
+		listByAccountResults:       listByAccountResults,
+		listByAccountResultCounter: 0,
+		touchResults:               touchResults,
+		touchResultCounter:         0,
+		deleteResults:              deleteResults,
+		deleteResultCounter:        0,
+	}
+}
+
+func (r *fakeRepository) Save(key APIKey) error {
+	res := r.saveResults[r.saveResultCounter]
+	r.saveResultCounter++
+	return res.Error
+}
+
+func (r *fakeRepository) FindByID(id string) (*APIKey, error) {
+	res := r.findByIDResults[r.findByIDResultCounter]
+	r.findByIDResultCounter++
+	return res.Key, res.Error
+}
+
+func (r *fakeRepository) ListByAccount(accountID int64) ([]*APIKey, error) {
+	res := r.listByAccountResults[r.listByAccountResultCounter]
+	r.listByAccountResultCounter++
+	return res.Keys, res.Error
+}
+
+func (r *fakeRepository) Touch(id string, lastUsedAt time.Time) error {
+	res := r.touchResults[r.touchResultCounter]
+	r.touchResultCounter++
+	return res.Error
+}
+
+func (r *fakeRepository) Delete(accountID int64, id string) error {
+	res := r.deleteResults[r.deleteResultCounter]
+	r.deleteResultCounter++
+	return res.Error
+}
@@ -0,0 +1,42 @@
+package apikey
+
+import "time"
+
+// Repository is a repository for an APIKey.
+type Repository interface {
+	// Save saves an APIKey to the repository.
+	// If the account already has a key with the same name, returns ErrAlreadyExists.
+	// Other errors may occur.
+	Save(key APIKey) error
+
+	// FindByID retrieves an APIKey for matching id.
+	// If key with such id not found, returns ErrNotFound.
+	// Other errors may occur.
+	FindByID(id string) (*APIKey, error)
+
+	// ListByAccount retrieves all keys of the account ordered by creation time.
+	ListByAccount(accountID int64) ([]*APIKey, error)
+
+	// Touch sets the LastUsedAt of the key.
+	Touch(id string, lastUsedAt time.Time) error
+
+	// Delete removes the key of the account.
+	// If the account has no key with such id, returns ErrNotFound.
+	// Other errors may occur.
+	Delete(accountID int64, id string) error
+}
+
+// repositoryError is an error occurred in Repository.
+type repositoryError string
+
+func (e repositoryError) Error() string {
+	return string(e)
+}
+
+const (
+	// ErrNotFound occurs when API key not found.
+	ErrNotFound = repositoryError("API key not found")
+
+	// ErrAlreadyExists occurs when API key with the same name already exists.
+	ErrAlreadyExists = repositoryError("API key already exists")
+)
@@ -0,0 +1,63 @@
+package apikeys
+
+import (
+	"log"
+	"time"
+
+	"github.com/hypnoglow/pascont/apikey"
+	"github.com/hypnoglow/pascont/identity"
+	"github.com/hypnoglow/pascont/session"
+)
+
+const (
+	PathAccountAPIKeys = "/accounts/:id/api-keys"
+	PathAccountAPIKey  = "/accounts/:id/api-keys/:keyID"
+)
+
+// RestController is a REST controller for API keys.
+type RestController struct {
+	logger       *log.Logger
+	sessionRepo  session.Repository
+	apiKeyRepo   apikey.Repository
+	uuidProducer identity.UUIDProducer
+}
+
+// NewRestController returns a new RestController.
+func NewRestController(
+	logger *log.Logger,
+	sessionRepo session.Repository,
+	apiKeyRepo apikey.Repository,
+	u identity.UUIDProducer,
+) RestController {
+	return RestController{
+		logger,
+		sessionRepo,
+		apiKeyRepo,
+		u,
+	}
+}
+
+type apiKeySchema struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+}
+
+func newAPIKeySchema(k *apikey.APIKey) apiKeySchema {
+	s := apiKeySchema{
+		ID:        k.ID,
+		Name:      k.Name,
+		CreatedAt: k.CreatedAt,
+	}
+	if !k.ExpiresAt.IsZero() {
+		expiresAt := k.ExpiresAt
+		s.ExpiresAt = &expiresAt
+	}
+	if !k.LastUsedAt.IsZero() {
+		lastUsedAt := k.LastUsedAt
+		s.LastUsedAt = &lastUsedAt
+	}
+	return s
+}
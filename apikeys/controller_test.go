@@ -0,0 +1,21 @@
+package apikeys
+
+import (
+	"io/ioutil"
+	"log"
+	"testing"
+
+	"github.com/hypnoglow/pascont/apikey"
+	"github.com/hypnoglow/pascont/session"
+)
+
+func TestNewRestController(t *testing.T) {
+	NewRestController(
+		log.New(ioutil.Discard, "", log.LstdFlags),
+		session.NewFakeRepository(nil, nil, nil, nil, nil, nil),
+		apikey.NewFakeRepository(nil, nil, nil, nil, nil),
+		func() string {
+			return "12345678-90ab-cdef-0123-4567890abcde"
+		},
+	)
+}
@@ -0,0 +1,40 @@
+package apikeys
+
+import (
+	"net/http"
+
+	"github.com/pborman/uuid"
+
+	"github.com/hypnoglow/pascont/apikey"
+	"github.com/hypnoglow/pascont/kit/middleware"
+	"github.com/hypnoglow/pascont/session"
+)
+
+// DeleteAPIKey is a handler for:
+// DELETE /accounts/:id/api-keys/:keyID
+func (c RestController) DeleteAPIKey(w http.ResponseWriter, req *http.Request) {
+	current, status := session.AccountSession(req, c.sessionRepo, c.logger)
+	if current == nil {
+		w.WriteHeader(status)
+		return
+	}
+
+	keyID, _ := middleware.PathParam(req, "keyID")
+	if uuid.Parse(keyID) == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	err := c.apiKeyRepo.Delete(current.AccountID, keyID)
+	if err == apikey.ErrNotFound {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		c.logger.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,164 @@
+package apikeys
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/pascont/apikey"
+	"github.com/hypnoglow/pascont/kit/middleware"
+	"github.com/hypnoglow/pascont/session"
+)
+
+func TestRestController_DeleteAPIKey(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	fakeLogger := log.New(ioutil.Discard, "", log.LstdFlags)
+
+	currentSession := &session.Session{
+		ID:        "12345678-90ab-cdef-0123-4567890abcde",
+		AccountID: 123,
+		CreatedAt: now,
+		ExpiresAt: now.Add(time.Hour),
+	}
+
+	cases := []struct {
+		caseName string
+		// in
+		sessRepo                 session.Repository
+		keyRepo                  apikey.Repository
+		reqContextSessionIDValue interface{}
+		reqPathKeyID             string
+		// out
+		expectedCode int
+	}{
+		{
+			caseName:     "There is no session ID from Authorization Header",
+			reqPathKeyID: "fedcba09-8765-4321-0fed-cba987654321",
+			// out
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			caseName: "keyRepo.Delete returns ErrNotFound",
+			sessRepo: session.NewFakeRepository(
+				nil,
+				[]session.FakeRepositoryFindByIDResult{
+					{
+						Session: currentSession,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			keyRepo: apikey.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				nil,
+				[]apikey.FakeRepositoryDeleteResult{
+					{
+						Error: apikey.ErrNotFound,
+					},
+				},
+			),
+			reqContextSessionIDValue: currentSession.ID,
+			reqPathKeyID:             "fedcba09-8765-4321-0fed-cba987654321",
+			// out
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			caseName: "keyRepo.Delete failed",
+			sessRepo: session.NewFakeRepository(
+				nil,
+				[]session.FakeRepositoryFindByIDResult{
+					{
+						Session: currentSession,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			keyRepo: apikey.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				nil,
+				[]apikey.FakeRepositoryDeleteResult{
+					{
+						Error: fmt.Errorf("Delete failed"),
+					},
+				},
+			),
+			reqContextSessionIDValue: currentSession.ID,
+			reqPathKeyID:             "fedcba09-8765-4321-0fed-cba987654321",
+			// out
+			expectedCode: http.StatusInternalServerError,
+		},
+		{
+			caseName: "Successful",
+			sessRepo: session.NewFakeRepository(
+				nil,
+				[]session.FakeRepositoryFindByIDResult{
+					{
+						Session: currentSession,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			keyRepo: apikey.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				nil,
+				[]apikey.FakeRepositoryDeleteResult{
+					{
+						Error: nil,
+					},
+				},
+			),
+			reqContextSessionIDValue: currentSession.ID,
+			reqPathKeyID:             "fedcba09-8765-4321-0fed-cba987654321",
+			// out
+			expectedCode: http.StatusNoContent,
+		},
+	}
+
+	for i, c := range cases {
+		ctrl := NewRestController(fakeLogger, c.sessRepo, c.keyRepo, nil)
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodDelete, "/accounts/me/api-keys/"+c.reqPathKeyID, nil)
+		req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyPathParams{}, map[string]string{"id": "me", "keyID": c.reqPathKeyID}))
+		req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeySessionID{}, c.reqContextSessionIDValue))
+		ctrl.DeleteAPIKey(w, req)
+
+		if w.Code != c.expectedCode {
+			t.Errorf(
+				"testcase %d %s:\nExpected status code to be %v, but got %v\n",
+				i,
+				c.caseName,
+				c.expectedCode,
+				w.Code,
+			)
+		}
+
+		if w.Body.Len() != 0 {
+			t.Errorf(
+				"testcase %d %s:\nExpected body to be empty, but got\n%v\n",
+				i,
+				c.caseName,
+				w.Body,
+			)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package apikeys
+
+import (
+	"net/http"
+
+	"github.com/pborman/uuid"
+
+	"github.com/hypnoglow/pascont/apikey"
+	"github.com/hypnoglow/pascont/kit"
+	"github.com/hypnoglow/pascont/kit/middleware"
+	"github.com/hypnoglow/pascont/kit/schema"
+	"github.com/hypnoglow/pascont/session"
+)
+
+// GetAPIKeys is a handler for:
+// GET /accounts/:id/api-keys
+func (c RestController) GetAPIKeys(w http.ResponseWriter, req *http.Request) {
+	current, status := session.AccountSession(req, c.sessionRepo, c.logger)
+	if current == nil {
+		w.WriteHeader(status)
+		return
+	}
+
+	keys, err := c.apiKeyRepo.ListByAccount(current.AccountID)
+	if err != nil {
+		c.logger.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	results := make([]apiKeySchema, len(keys))
+	for i, key := range keys {
+		results[i] = newAPIKeySchema(key)
+	}
+
+	kit.RespondJSON(w, http.StatusOK, schema.NewResultsBody(results, nil))
+}
+
+// GetAPIKey is a handler for:
+// GET /accounts/:id/api-keys/:keyID
+func (c RestController) GetAPIKey(w http.ResponseWriter, req *http.Request) {
+	current, status := session.AccountSession(req, c.sessionRepo, c.logger)
+	if current == nil {
+		w.WriteHeader(status)
+		return
+	}
+
+	keyID, _ := middleware.PathParam(req, "keyID")
+	if uuid.Parse(keyID) == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	key, err := c.apiKeyRepo.FindByID(keyID)
+	if err == apikey.ErrNotFound {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		c.logger.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	// Keys of other accounts are not visible.
+	if key.AccountID != current.AccountID {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	kit.RespondJSON(w, http.StatusOK, schema.NewResultBody(newAPIKeySchema(key), nil))
+}
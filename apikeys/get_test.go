@@ -0,0 +1,392 @@
+package apikeys
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/pascont/apikey"
+	"github.com/hypnoglow/pascont/kit/middleware"
+	"github.com/hypnoglow/pascont/session"
+)
+
+func TestRestController_GetAPIKeys(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	later := now.Add(time.Hour * 24 * 30)
+	fakeLogger := log.New(ioutil.Discard, "", log.LstdFlags)
+
+	currentSession := &session.Session{
+		ID:        "12345678-90ab-cdef-0123-4567890abcde",
+		AccountID: 123,
+		CreatedAt: now,
+		ExpiresAt: now.Add(time.Hour),
+	}
+
+	cases := []struct {
+		caseName string
+		// in
+		sessRepo                 session.Repository
+		keyRepo                  apikey.Repository
+		reqContextSessionIDValue interface{}
+		reqPathID                string
+		// out
+		expectedCode int
+		expectedBody *bytes.Buffer
+	}{
+		{
+			caseName:  "The account id from path is neither a number nor me",
+			reqPathID: "abc",
+			// out
+			expectedCode: http.StatusNotFound,
+			expectedBody: bytes.NewBuffer(nil),
+		},
+		{
+			caseName: "keyRepo.ListByAccount failed",
+			sessRepo: session.NewFakeRepository(
+				nil,
+				[]session.FakeRepositoryFindByIDResult{
+					{
+						Session: currentSession,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			keyRepo: apikey.NewFakeRepository(
+				nil,
+				nil,
+				[]apikey.FakeRepositoryListByAccountResult{
+					{
+						Error: fmt.Errorf("ListByAccount failed"),
+					},
+				},
+				nil,
+				nil,
+			),
+			reqContextSessionIDValue: currentSession.ID,
+			reqPathID:                "me",
+			// out
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: bytes.NewBuffer(nil),
+		},
+		{
+			caseName: "Successful",
+			sessRepo: session.NewFakeRepository(
+				nil,
+				[]session.FakeRepositoryFindByIDResult{
+					{
+						Session: currentSession,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			keyRepo: apikey.NewFakeRepository(
+				nil,
+				nil,
+				[]apikey.FakeRepositoryListByAccountResult{
+					{
+						Keys: []*apikey.APIKey{
+							{
+								ID:        "fedcba09-8765-4321-0fed-cba987654321",
+								AccountID: 123,
+								Name:      "ci",
+								Hash:      []byte("hash"),
+								CreatedAt: now,
+							},
+							{
+								ID:         "fedcba09-8765-4321-0fed-cba987654322",
+								AccountID:  123,
+								Name:       "deploy",
+								Hash:       []byte("hash"),
+								CreatedAt:  now,
+								ExpiresAt:  later,
+								LastUsedAt: now,
+							},
+						},
+					},
+				},
+				nil,
+				nil,
+			),
+			reqContextSessionIDValue: currentSession.ID,
+			reqPathID:                "123",
+			// out
+			expectedCode: http.StatusOK,
+			expectedBody: bytes.NewBufferString(fmt.Sprintf(`{
+				"results":[
+					{
+						"id":"fedcba09-8765-4321-0fed-cba987654321",
+						"name":"ci",
+						"createdAt":"%s"
+					},
+					{
+						"id":"fedcba09-8765-4321-0fed-cba987654322",
+						"name":"deploy",
+						"createdAt":"%s",
+						"expiresAt":"%s",
+						"lastUsedAt":"%s"
+					}
+				]
+			}`,
+				now.Format(time.RFC3339),
+				now.Format(time.RFC3339),
+				later.Format(time.RFC3339),
+				now.Format(time.RFC3339),
+			)),
+		},
+	}
+
+	for i, c := range cases {
+		ctrl := NewRestController(fakeLogger, c.sessRepo, c.keyRepo, nil)
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/accounts/"+c.reqPathID+"/api-keys", nil)
+		req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyPathParams{}, map[string]string{"id": c.reqPathID}))
+		req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeySessionID{}, c.reqContextSessionIDValue))
+		ctrl.GetAPIKeys(w, req)
+
+		if w.Code != c.expectedCode {
+			t.Errorf(
+				"testcase %d %s:\nExpected status code to be %v, but got %v\n",
+				i,
+				c.caseName,
+				c.expectedCode,
+				w.Code,
+			)
+		}
+
+		// To make possible pretty formatting in test cases, we use json.Compact:
+		b := c.expectedBody.Bytes()
+		if b != nil {
+			c.expectedBody.Reset()
+			err := json.Compact(c.expectedBody, b)
+			if err != nil {
+				t.Errorf(
+					"testcase %d %s:\nFailed to compact JSON body: %s",
+					i,
+					c.caseName,
+					err,
+				)
+			}
+		}
+
+		if !bytes.Equal(w.Body.Bytes(), c.expectedBody.Bytes()) {
+			t.Errorf(
+				"testcase %d %s:\nExpected body to be\n%v\nbut got\n%v\n",
+				i,
+				c.caseName,
+				c.expectedBody,
+				w.Body,
+			)
+		}
+	}
+}
+
+func TestRestController_GetAPIKey(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	fakeLogger := log.New(ioutil.Discard, "", log.LstdFlags)
+
+	currentSession := &session.Session{
+		ID:        "12345678-90ab-cdef-0123-4567890abcde",
+		AccountID: 123,
+		CreatedAt: now,
+		ExpiresAt: now.Add(time.Hour),
+	}
+
+	cases := []struct {
+		caseName string
+		// in
+		sessRepo                 session.Repository
+		keyRepo                  apikey.Repository
+		reqContextSessionIDValue interface{}
+		reqPathKeyID             string
+		// out
+		expectedCode int
+		expectedBody *bytes.Buffer
+	}{
+		{
+			caseName: "The key id from path is not a UUID",
+			sessRepo: session.NewFakeRepository(
+				nil,
+				[]session.FakeRepositoryFindByIDResult{
+					{
+						Session: currentSession,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			reqContextSessionIDValue: currentSession.ID,
+			reqPathKeyID:             "abc",
+			// out
+			expectedCode: http.StatusNotFound,
+			expectedBody: bytes.NewBuffer(nil),
+		},
+		{
+			caseName: "Key belongs to another account",
+			sessRepo: session.NewFakeRepository(
+				nil,
+				[]session.FakeRepositoryFindByIDResult{
+					{
+						Session: currentSession,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			keyRepo: apikey.NewFakeRepository(
+				nil,
+				[]apikey.FakeRepositoryFindByIDResult{
+					{
+						Key: &apikey.APIKey{
+							ID:        "fedcba09-8765-4321-0fed-cba987654321",
+							AccountID: 456,
+							Name:      "ci",
+							CreatedAt: now,
+						},
+					},
+				},
+				nil,
+				nil,
+				nil,
+			),
+			reqContextSessionIDValue: currentSession.ID,
+			reqPathKeyID:             "fedcba09-8765-4321-0fed-cba987654321",
+			// out
+			expectedCode: http.StatusNotFound,
+			expectedBody: bytes.NewBuffer(nil),
+		},
+		{
+			caseName: "keyRepo.FindByID failed",
+			sessRepo: session.NewFakeRepository(
+				nil,
+				[]session.FakeRepositoryFindByIDResult{
+					{
+						Session: currentSession,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			keyRepo: apikey.NewFakeRepository(
+				nil,
+				[]apikey.FakeRepositoryFindByIDResult{
+					{
+						Error: fmt.Errorf("FindByID failed"),
+					},
+				},
+				nil,
+				nil,
+				nil,
+			),
+			reqContextSessionIDValue: currentSession.ID,
+			reqPathKeyID:             "fedcba09-8765-4321-0fed-cba987654321",
+			// out
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: bytes.NewBuffer(nil),
+		},
+		{
+			caseName: "Successful",
+			sessRepo: session.NewFakeRepository(
+				nil,
+				[]session.FakeRepositoryFindByIDResult{
+					{
+						Session: currentSession,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			keyRepo: apikey.NewFakeRepository(
+				nil,
+				[]apikey.FakeRepositoryFindByIDResult{
+					{
+						Key: &apikey.APIKey{
+							ID:        "fedcba09-8765-4321-0fed-cba987654321",
+							AccountID: 123,
+							Name:      "ci",
+							CreatedAt: now,
+						},
+					},
+				},
+				nil,
+				nil,
+				nil,
+			),
+			reqContextSessionIDValue: currentSession.ID,
+			reqPathKeyID:             "fedcba09-8765-4321-0fed-cba987654321",
+			// out
+			expectedCode: http.StatusOK,
+			expectedBody: bytes.NewBufferString(fmt.Sprintf(`{
+				"result":{
+					"id":"fedcba09-8765-4321-0fed-cba987654321",
+					"name":"ci",
+					"createdAt":"%s"
+				}
+			}`, now.Format(time.RFC3339))),
+		},
+	}
+
+	for i, c := range cases {
+		ctrl := NewRestController(fakeLogger, c.sessRepo, c.keyRepo, nil)
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/accounts/me/api-keys/"+c.reqPathKeyID, nil)
+		req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyPathParams{}, map[string]string{"id": "me", "keyID": c.reqPathKeyID}))
+		req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeySessionID{}, c.reqContextSessionIDValue))
+		ctrl.GetAPIKey(w, req)
+
+		if w.Code != c.expectedCode {
+			t.Errorf(
+				"testcase %d %s:\nExpected status code to be %v, but got %v\n",
+				i,
+				c.caseName,
+				c.expectedCode,
+				w.Code,
+			)
+		}
+
+		// To make possible pretty formatting in test cases, we use json.Compact:
+		b := c.expectedBody.Bytes()
+		if b != nil {
+			c.expectedBody.Reset()
+			err := json.Compact(c.expectedBody, b)
+			if err != nil {
+				t.Errorf(
+					"testcase %d %s:\nFailed to compact JSON body: %s",
+					i,
+					c.caseName,
+					err,
+				)
+			}
+		}
+
+		if !bytes.Equal(w.Body.Bytes(), c.expectedBody.Bytes()) {
+			t.Errorf(
+				"testcase %d %s:\nExpected body to be\n%v\nbut got\n%v\n",
+				i,
+				c.caseName,
+				c.expectedBody,
+				w.Body,
+			)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package apikeys
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/hypnoglow/pascont/apikey"
+	"github.com/hypnoglow/pascont/kit"
+	"github.com/hypnoglow/pascont/kit/form"
+	"github.com/hypnoglow/pascont/kit/schema"
+	"github.com/hypnoglow/pascont/session"
+)
+
+// PostAPIKeys is a handler for:
+// POST /accounts/:id/api-keys
+func (c RestController) PostAPIKeys(w http.ResponseWriter, req *http.Request) {
+	current, status := session.AccountSession(req, c.sessionRepo, c.logger)
+	if current == nil {
+		w.WriteHeader(status)
+		return
+	}
+
+	var keyForm postAPIKeyForm
+	form.PopulateFormFromJSON(req.Body, &keyForm)
+	if !keyForm.Validate() {
+		kit.RespondWithFormErrors(w, http.StatusBadRequest, keyForm.ValidationErrors())
+		return
+	}
+
+	var expiresAt time.Time
+	if keyForm.ExpiresAt != nil {
+		expiresAt = *keyForm.ExpiresAt
+	}
+
+	key, token, err := apikey.Generate(c.uuidProducer, current.AccountID, keyForm.Name, time.Now(), expiresAt)
+	if err != nil {
+		c.logger.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	err = c.apiKeyRepo.Save(*key)
+	if err == apikey.ErrAlreadyExists {
+		kit.RespondWithError(w, http.StatusConflict, schema.NewError(
+			fmt.Sprintf("API key with name %s already exists", keyForm.Name),
+			"name",
+			keyForm.Name,
+		))
+		return
+	}
+	if err != nil {
+		c.logger.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	// The token is revealed only once, it can not be retrieved later.
+	kit.RespondJSON(w, http.StatusCreated, schema.NewResultBody(
+		postAPIKeySchema{
+			apiKeySchema: newAPIKeySchema(key),
+			Token:        token,
+		},
+		nil,
+	))
+}
+
+const (
+	// nameMaxLen is max len of the API key name in bytes.
+	nameMaxLen = 64
+)
+
+type postAPIKeyForm struct {
+	form.BaseForm
+	Name      string     `json:"name"`
+	ExpiresAt *time.Time `json:"expiresAt"`
+}
+
+func (f *postAPIKeyForm) Validate() bool {
+	if len(f.Name) == 0 {
+		f.AddError("Name must not be empty", "name", f.Name)
+	}
+	if len(f.Name) > nameMaxLen {
+		f.AddError(fmt.Sprintf("Name must be at most %d bytes", nameMaxLen), "name", f.Name)
+	}
+
+	if f.ExpiresAt != nil && time.Now().After(*f.ExpiresAt) {
+		f.AddError(
+			fmt.Sprintf(
+				"ExpiresAt must be greater than %s",
+				time.Now().UTC().Format(time.RFC3339),
+			),
+			"expiresAt",
+			f.ExpiresAt,
+		)
+	}
+
+	return len(f.ValidationErrors()) == 0
+}
+
+type postAPIKeySchema struct {
+	apiKeySchema
+	Token string `json:"token"`
+}
@@ -0,0 +1,315 @@
+package apikeys
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/pascont/apikey"
+	"github.com/hypnoglow/pascont/kit/form"
+	"github.com/hypnoglow/pascont/kit/middleware"
+	"github.com/hypnoglow/pascont/session"
+)
+
+func TestPostAPIKeyForm_Validate(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+
+	cases := []struct {
+		caseName       string
+		Name           string
+		ExpiresAt      *time.Time
+		expectedErrors []form.FormError
+	}{
+		{
+			caseName: "Name is too long",
+			Name:     "1234567890123456789012345678901234567890123456789012345678901234567890",
+			expectedErrors: []form.FormError{
+				{
+					Message: fmt.Sprintf("Name must be at most %d bytes", nameMaxLen),
+					Field:   "name",
+					Value:   "1234567890123456789012345678901234567890123456789012345678901234567890",
+				},
+			},
+		},
+		{
+			caseName:  "ExpiresAt is in the past",
+			Name:      "ci",
+			ExpiresAt: &past,
+			expectedErrors: []form.FormError{
+				{
+					Message: fmt.Sprintf("ExpiresAt must be greater than %s", time.Now().UTC().Format(time.RFC3339)),
+					Field:   "expiresAt",
+					Value:   &past,
+				},
+			},
+		},
+		{
+			caseName:       "Key without expiry",
+			Name:           "ci",
+			expectedErrors: nil,
+		},
+		{
+			caseName:       "Key with expiry",
+			Name:           "ci",
+			ExpiresAt:      &future,
+			expectedErrors: nil,
+		},
+	}
+
+	for i, c := range cases {
+		f := &postAPIKeyForm{
+			Name:      c.Name,
+			ExpiresAt: c.ExpiresAt,
+		}
+
+		f.Validate()
+
+		if !reflect.DeepEqual(f.ValidationErrors(), c.expectedErrors) {
+			t.Errorf(
+				"testcase %d %s\nExpected validation errors to equal\n%#v\n, but got\n%#v\n",
+				i,
+				c.caseName,
+				c.expectedErrors,
+				f.ValidationErrors(),
+			)
+		}
+	}
+}
+
+func TestRestController_PostAPIKeys(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	later := now.Add(time.Hour * 24 * 30)
+	fakeLogger := log.New(ioutil.Discard, "", log.LstdFlags)
+	uuidProducer := func() string {
+		return "fedcba09-8765-4321-0fed-cba987654321"
+	}
+
+	currentSession := &session.Session{
+		ID:        "12345678-90ab-cdef-0123-4567890abcde",
+		AccountID: 123,
+		CreatedAt: now,
+		ExpiresAt: now.Add(time.Hour),
+	}
+
+	// The token is random, so it is replaced in the response body before comparison.
+	tokenPattern := regexp.MustCompile(`"token":"pak_fedcba09-8765-4321-0fed-cba987654321_[A-Za-z0-9_-]{43}"`)
+
+	cases := []struct {
+		caseName string
+		// in
+		sessRepo                 session.Repository
+		keyRepo                  apikey.Repository
+		reqContextSessionIDValue interface{}
+		reqPathID                string
+		reqBody                  io.Reader
+		// out
+		expectedCode int
+		expectedBody *bytes.Buffer
+	}{
+		{
+			caseName:  "There is no session ID from Authorization Header",
+			reqPathID: "me",
+			reqBody:   bytes.NewBufferString(`{"name":"ci"}`),
+			// out
+			expectedCode: http.StatusUnauthorized,
+			expectedBody: bytes.NewBuffer(nil),
+		},
+		{
+			caseName: "Empty name should result in 400",
+			sessRepo: session.NewFakeRepository(
+				nil,
+				[]session.FakeRepositoryFindByIDResult{
+					{
+						Session: currentSession,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			reqContextSessionIDValue: currentSession.ID,
+			reqPathID:                "me",
+			reqBody:                  bytes.NewBufferString(`{"name":""}`),
+			// out
+			expectedCode: http.StatusBadRequest,
+			expectedBody: bytes.NewBufferString(`{
+				"errors":[
+					{
+						"message":"Name must not be empty",
+						"field":"name",
+						"value":""
+					}
+				]
+			}`),
+		},
+		{
+			caseName: "Key with such name already exists",
+			sessRepo: session.NewFakeRepository(
+				nil,
+				[]session.FakeRepositoryFindByIDResult{
+					{
+						Session: currentSession,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			keyRepo: apikey.NewFakeRepository(
+				[]apikey.FakeRepositorySaveResult{
+					{
+						Error: apikey.ErrAlreadyExists,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			reqContextSessionIDValue: currentSession.ID,
+			reqPathID:                "me",
+			reqBody:                  bytes.NewBufferString(`{"name":"ci"}`),
+			// out
+			expectedCode: http.StatusConflict,
+			expectedBody: bytes.NewBufferString(`{
+				"errors":[
+					{
+						"message":"API key with name ci already exists",
+						"field":"name",
+						"value":"ci"
+					}
+				]
+			}`),
+		},
+		{
+			caseName: "keyRepo.Save failed",
+			sessRepo: session.NewFakeRepository(
+				nil,
+				[]session.FakeRepositoryFindByIDResult{
+					{
+						Session: currentSession,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			keyRepo: apikey.NewFakeRepository(
+				[]apikey.FakeRepositorySaveResult{
+					{
+						Error: fmt.Errorf("Save failed"),
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			reqContextSessionIDValue: currentSession.ID,
+			reqPathID:                "me",
+			reqBody:                  bytes.NewBufferString(`{"name":"ci"}`),
+			// out
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: bytes.NewBuffer(nil),
+		},
+		{
+			caseName: "Successful",
+			sessRepo: session.NewFakeRepository(
+				nil,
+				[]session.FakeRepositoryFindByIDResult{
+					{
+						Session: currentSession,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			keyRepo: apikey.NewFakeRepository(
+				[]apikey.FakeRepositorySaveResult{
+					{
+						Error: nil,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			reqContextSessionIDValue: currentSession.ID,
+			reqPathID:                "123",
+			reqBody:                  bytes.NewBufferString(fmt.Sprintf(`{"name":"ci","expiresAt":"%s"}`, later.Format(time.RFC3339))),
+			// out
+			expectedCode: http.StatusCreated,
+			expectedBody: bytes.NewBufferString(fmt.Sprintf(`{
+				"result":{
+					"id":"fedcba09-8765-4321-0fed-cba987654321",
+					"name":"ci",
+					"createdAt":"%s",
+					"expiresAt":"%s",
+					"token":"<token>"
+				}
+			}`, now.Format(time.RFC3339), later.Format(time.RFC3339))),
+		},
+	}
+
+	for i, c := range cases {
+		ctrl := NewRestController(fakeLogger, c.sessRepo, c.keyRepo, uuidProducer)
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/accounts/"+c.reqPathID+"/api-keys", c.reqBody)
+		req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyPathParams{}, map[string]string{"id": c.reqPathID}))
+		req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeySessionID{}, c.reqContextSessionIDValue))
+		ctrl.PostAPIKeys(w, req)
+
+		if w.Code != c.expectedCode {
+			t.Errorf(
+				"testcase %d %s:\nExpected status code to be %v, but got %v\n",
+				i,
+				c.caseName,
+				c.expectedCode,
+				w.Code,
+			)
+		}
+
+		// To make possible pretty formatting in test cases, we use json.Compact:
+		b := c.expectedBody.Bytes()
+		if b != nil {
+			c.expectedBody.Reset()
+			err := json.Compact(c.expectedBody, b)
+			if err != nil {
+				t.Errorf(
+					"testcase %d %s:\nFailed to compact JSON body: %s",
+					i,
+					c.caseName,
+					err,
+				)
+			}
+		}
+
+		body := tokenPattern.ReplaceAll(w.Body.Bytes(), []byte(`"token":"<token>"`))
+		if !bytes.Equal(body, c.expectedBody.Bytes()) {
+			t.Errorf(
+				"testcase %d %s:\nExpected body to be\n%v\nbut got\n%v\n",
+				i,
+				c.caseName,
+				c.expectedBody,
+				w.Body,
+			)
+		}
+	}
+}
@@ -0,0 +1,8 @@
+// Package breach checks passwords against corpora of passwords exposed in data breaches.
+package breach
+
+// Checker checks whether passwords appear in known data breaches.
+type Checker interface {
+	// IsBreached reports whether the password appears in known data breaches.
+	IsBreached(password string) (bool, error)
+}
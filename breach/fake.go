@@ -0,0 +1,25 @@
+package breach
+
+type fakeChecker struct {
+	isBreachedResults       []FakeCheckerIsBreachedResult
+	isBreachedResultCounter int
+}
+
+type FakeCheckerIsBreachedResult struct {
+	Breached bool
+	Error    error
+}
+
+// NewFakeChecker returns a new fake Checker.
+func NewFakeChecker(isBreachedResults []FakeCheckerIsBreachedResult) Checker {
+	return &fakeChecker{
+		isBreachedResults:       isBreachedResults,
+		isBreachedResultCounter: 0,
+	}
+}
+
+func (c *fakeChecker) IsBreached(password string) (bool, error) {
+	res := c.isBreachedResults[c.isBreachedResultCounter]
+	c.isBreachedResultCounter++
+	return res.Breached, res.Error
+}
@@ -0,0 +1,182 @@
+package breach
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha1"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// The index is a binary file, which allows to look up SHA-1 hashes of breached passwords
+// with a binary search, without loading the whole corpus to memory:
+//
+//	magic | offsets: (prefixCount + 1) * uint64 | suffixes: count * suffixSize
+//
+// Hashes are split as in the HIBP k-anonymity model: the first two bytes are a prefix,
+// and offsets[prefix]:offsets[prefix+1] is the range of sorted suffixes having the prefix.
+const (
+	indexMagic  = "PCBRIX01"
+	prefixSize  = 2
+	prefixCount = 1 << (8 * prefixSize)
+	suffixSize  = sha1.Size - prefixSize
+	headerSize  = len(indexMagic) + (prefixCount+1)*8
+)
+
+// ErrInvalidIndex occurs when the file is not a breach index.
+var ErrInvalidIndex = errors.New("File is not a breach index")
+
+// Index is a Checker which looks up passwords in a local index built with BuildIndex.
+type Index struct {
+	r       io.ReaderAt
+	offsets []uint64
+}
+
+// NewIndex returns a new Index reading the index from r.
+func NewIndex(r io.ReaderAt) (*Index, error) {
+	header := make([]byte, headerSize)
+	if _, err := r.ReadAt(header, 0); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil, ErrInvalidIndex
+		}
+		return nil, err
+	}
+	if string(header[:len(indexMagic)]) != indexMagic {
+		return nil, ErrInvalidIndex
+	}
+
+	offsets := make([]uint64, prefixCount+1)
+	for i := range offsets {
+		offsets[i] = binary.BigEndian.Uint64(header[len(indexMagic)+i*8:])
+	}
+
+	return &Index{r: r, offsets: offsets}, nil
+}
+
+// OpenIndex opens the index file at the path.
+// The file is kept open, so the Index should be closed when not needed anymore.
+func OpenIndex(path string) (*Index, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	ix, err := NewIndex(f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return ix, nil
+}
+
+// Close closes the index file, if the Index reads from a file.
+func (ix *Index) Close() error {
+	if c, ok := ix.r.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// Count returns the number of hashes in the index.
+func (ix *Index) Count() uint64 {
+	return ix.offsets[prefixCount]
+}
+
+func (ix *Index) IsBreached(password string) (bool, error) {
+	return ix.Contains(sha1.Sum([]byte(password)))
+}
+
+// Contains reports whether the SHA-1 hash is in the index.
+func (ix *Index) Contains(hash [sha1.Size]byte) (bool, error) {
+	prefix := int(binary.BigEndian.Uint16(hash[:prefixSize]))
+	suffix := hash[prefixSize:]
+
+	buf := make([]byte, suffixSize)
+	lo, hi := ix.offsets[prefix], ix.offsets[prefix+1]
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		if _, err := ix.r.ReadAt(buf, int64(headerSize)+int64(mid)*suffixSize); err != nil {
+			return false, err
+		}
+
+		switch bytes.Compare(buf, suffix) {
+		case 0:
+			return true, nil
+		case -1:
+			lo = mid + 1
+		default:
+			hi = mid
+		}
+	}
+
+	return false, nil
+}
+
+// BuildIndex builds the index from r to w, and returns the number of indexed hashes.
+// r is a dump in the HIBP format: a line per hash, sorted by hash, in the form
+// <SHA-1 hex>[:<count>]. Empty lines are skipped.
+func BuildIndex(w io.WriteSeeker, r io.Reader) (count uint64, err error) {
+	counts := make([]uint64, prefixCount+1)
+
+	bw := bufio.NewWriter(w)
+	if _, err := bw.Write(make([]byte, headerSize)); err != nil {
+		return 0, err
+	}
+
+	var prev []byte
+	s := bufio.NewScanner(r)
+	for line := 1; s.Scan(); line++ {
+		text := strings.TrimSpace(s.Text())
+		if text == "" {
+			continue
+		}
+		if i := strings.IndexByte(text, ':'); i != -1 {
+			text = text[:i]
+		}
+
+		hash, err := hex.DecodeString(text)
+		if err != nil || len(hash) != sha1.Size {
+			return 0, fmt.Errorf("Line %d: not a SHA-1 hash", line)
+		}
+		if prev != nil && bytes.Compare(prev, hash) >= 0 {
+			return 0, fmt.Errorf("Line %d: hashes are not sorted", line)
+		}
+		prev = hash
+
+		counts[int(binary.BigEndian.Uint16(hash[:prefixSize]))+1]++
+		if _, err := bw.Write(hash[prefixSize:]); err != nil {
+			return 0, err
+		}
+	}
+	if err := s.Err(); err != nil {
+		return 0, err
+	}
+	if err := bw.Flush(); err != nil {
+		return 0, err
+	}
+
+	// Counts by prefixes become offsets of the prefixes ranges.
+	header := make([]byte, headerSize)
+	copy(header, indexMagic)
+	for i := range counts {
+		if i > 0 {
+			counts[i] += counts[i-1]
+		}
+		binary.BigEndian.PutUint64(header[len(indexMagic)+i*8:], counts[i])
+	}
+
+	if _, err := w.Seek(0, io.SeekStart); err != nil {
+		return 0, err
+	}
+	if _, err := w.Write(header); err != nil {
+		return 0, err
+	}
+
+	return counts[prefixCount], nil
+}
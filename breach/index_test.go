@@ -0,0 +1,116 @@
+package breach
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+)
+
+// dump is a sorted dump in the HIBP format with hashes of "password", "123456" and "qwerty",
+// and other hashes sharing the prefix with "password".
+const dump = `5BAA0000000000000000000000000000000000AA:1
+5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8:9545824
+5baaffffffffffffffffffffffffffffffffffff:2
+
+7C4A8D09CA3762AF61E59520943DC26494F8941B:37359195
+B1B3773A05C0ED0176787A4F1574FF0075F7521E
+`
+
+func buildTestIndex(t *testing.T, dump string) (*os.File, uint64, error) {
+	f, err := ioutil.TempFile("", "breach_index")
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	os.Remove(f.Name())
+
+	count, err := BuildIndex(f, strings.NewReader(dump))
+	return f, count, err
+}
+
+func TestIndex_IsBreached(t *testing.T) {
+	f, count, err := buildTestIndex(t, dump)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	defer f.Close()
+
+	if count != 5 {
+		t.Errorf("Expected 5 hashes to be indexed, but got %d\n", count)
+	}
+
+	ix, err := NewIndex(f)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	if ix.Count() != 5 {
+		t.Errorf("Expected index to have 5 hashes, but got %d\n", ix.Count())
+	}
+
+	cases := []struct {
+		password string
+		expected bool
+	}{
+		{"password", true},
+		{"123456", true},
+		{"qwerty", true},
+		{"Password", false},
+		{"correct horse battery staple", false},
+		{"", false},
+	}
+
+	for i, c := range cases {
+		breached, err := ix.IsBreached(c.password)
+		if err != nil {
+			t.Errorf("testcase %d %q: Expected no error, but got %s\n", i, c.password, err)
+		}
+		if breached != c.expected {
+			t.Errorf("testcase %d %q: Expected IsBreached to be %v, but got %v\n", i, c.password, c.expected, breached)
+		}
+	}
+}
+
+func TestBuildIndex(t *testing.T) {
+	cases := []struct {
+		caseName      string
+		dump          string
+		expectedError string
+	}{
+		{
+			caseName:      "Hashes are not sorted",
+			dump:          "7C4A8D09CA3762AF61E59520943DC26494F8941B:1\n5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8:1\n",
+			expectedError: "Line 2: hashes are not sorted",
+		},
+		{
+			caseName:      "Duplicate hashes",
+			dump:          "5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8:1\n5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8:1\n",
+			expectedError: "Line 2: hashes are not sorted",
+		},
+		{
+			caseName:      "Not a SHA-1 hash",
+			dump:          "5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8:1\n5BAA61E4C9B93F3F:1\n",
+			expectedError: "Line 2: not a SHA-1 hash",
+		},
+	}
+
+	for i, c := range cases {
+		f, _, err := buildTestIndex(t, c.dump)
+		f.Close()
+		if err == nil || err.Error() != c.expectedError {
+			t.Errorf("testcase %d %s: Expected error to be %q, but got %v\n", i, c.caseName, c.expectedError, err)
+		}
+	}
+}
+
+func TestNewIndex(t *testing.T) {
+	if _, err := NewIndex(bytes.NewReader([]byte("not an index"))); err != ErrInvalidIndex {
+		t.Errorf("Expected error to be %v, but got %v\n", ErrInvalidIndex, err)
+	}
+
+	header := make([]byte, headerSize)
+	copy(header, "NOTMAGIC")
+	if _, err := NewIndex(bytes.NewReader(header)); err != ErrInvalidIndex {
+		t.Errorf("Expected error to be %v, but got %v\n", ErrInvalidIndex, err)
+	}
+}
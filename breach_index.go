@@ -0,0 +1,46 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/hypnoglow/pascont/breach"
+)
+
+// runBreachIndex runs the breach-index command, which builds the index of breached passwords
+// for the password policy from a raw dump of SHA-1 hashes in the HIBP format:
+//
+//	pascont breach-index pwned-passwords-sha1-ordered-by-hash.txt breach.idx
+func runBreachIndex(args []string) (err error) {
+	if len(args) != 2 {
+		return errors.New("Usage: pascont breach-index <dump> <index>")
+	}
+
+	dump, err := os.Open(args[0])
+	if err != nil {
+		return err
+	}
+	defer dump.Close()
+
+	index, err := os.Create(args[1])
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := index.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			os.Remove(args[1])
+		}
+	}()
+
+	count, err := breach.BuildIndex(index, dump)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stdout, "Indexed %d hashes\n", count)
+	return nil
+}
@@ -0,0 +1,14 @@
+package clock
+
+import "time"
+
+// Clock returns the current time.
+// Services depend on a Clock instead of time.Now, so the time can be fixed in tests.
+type Clock func() time.Time
+
+// Fixed returns a Clock which always returns t.
+func Fixed(t time.Time) Clock {
+	return func() time.Time {
+		return t
+	}
+}
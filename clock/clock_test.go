@@ -0,0 +1,15 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFixed(t *testing.T) {
+	now := time.Date(2017, 7, 1, 21, 10, 29, 0, time.UTC)
+
+	c := Fixed(now)
+	if !c().Equal(now) || !c().Equal(now) {
+		t.Errorf("Expected clock to always return %v\n", now)
+	}
+}
@@ -11,6 +11,17 @@ type Config struct {
 	Database configDatabase `json:"database"`
 	JWT      configJWT      `json:"jwt"`
 	Session  configSession  `json:"session"`
+	Admin    configAdmin    `json:"admin"`
+	Tenant   configTenant   `json:"tenant"`
+	TOTP     configTOTP     `json:"totp"`
+	Hasher   configHasher   `json:"hasher"`
+
+	PasswordPolicy configPasswordPolicy `json:"password_policy"`
+	PasswordReset  configPasswordReset  `json:"password_reset"`
+	Notifier       configNotifier       `json:"notifier"`
+	Verification   configVerification   `json:"verification"`
+	Outbox         configOutbox         `json:"outbox"`
+	Webhooks       configWebhooks       `json:"webhooks"`
 }
 
 type configSocket struct {
@@ -34,7 +45,46 @@ type configJWT struct {
 }
 
 type configSession struct {
-	SecretKey string `json:"secret_key"`
+	SecretKey        string `json:"secret_key"`
+	LockoutThreshold int    `json:"lockout_threshold"`
+	LockoutDuration  string `json:"lockout_duration"`
+}
+
+type configAdmin struct {
+	Token string `json:"token"`
+}
+
+type configTenant struct {
+	Default string `json:"default"`
+}
+
+type configTOTP struct {
+	Issuer        string `json:"issuer"`
+	EncryptionKey string `json:"encryption_key"`
+}
+
+type configHasher struct {
+	Algorithm string         `json:"algorithm"`
+	Bcrypt    configBcrypt   `json:"bcrypt"`
+	Argon2id  configArgon2id `json:"argon2id"`
+	Scrypt    configScrypt   `json:"scrypt"`
+	Pepper    configPepper   `json:"pepper"`
+}
+
+type configBcrypt struct {
+	Cost int `json:"cost"`
+}
+
+type configArgon2id struct {
+	Memory      uint32 `json:"memory"`
+	Iterations  uint32 `json:"iterations"`
+	Parallelism uint8  `json:"parallelism"`
+}
+
+type configScrypt struct {
+	LogN uint8 `json:"log_n"`
+	R    int   `json:"r"`
+	P    int   `json:"p"`
 }
 
 // FromJSON returns a Config with data read from r as json.
@@ -48,3 +98,82 @@ func FromJSON(r io.Reader) Config {
 
 	return conf
 }
+
+type configPepper struct {
+	CurrentKeyID string            `json:"current_key_id"`
+	Keys         map[string]string `json:"keys"`
+}
+
+type configPasswordPolicy struct {
+	MinLength           int    `json:"min_length"`
+	MaxLength           int    `json:"max_length"`
+	RequireLowercase    bool   `json:"require_lowercase"`
+	RequireUppercase    bool   `json:"require_uppercase"`
+	RequireDigit        bool   `json:"require_digit"`
+	RequireSymbol       bool   `json:"require_symbol"`
+	ForbidName          bool   `json:"forbid_name"`
+	CommonPasswordsPath string `json:"common_passwords_path"`
+	MinScore            int    `json:"min_score"`
+	BreachIndexPath     string `json:"breach_index_path"`
+}
+
+type configPasswordReset struct {
+	SecretKey string `json:"secret_key"`
+}
+
+type configNotifier struct {
+	File      string                        `json:"file"`
+	SMTP      configSMTP                    `json:"smtp"`
+	Webhook   configWebhook                 `json:"webhook"`
+	Templates map[string]configMailTemplate `json:"templates"`
+}
+
+type configSMTP struct {
+	Addr     string `json:"addr"`
+	From     string `json:"from"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+type configWebhook struct {
+	URL     string `json:"url"`
+	Timeout string `json:"timeout"`
+}
+
+type configMailTemplate struct {
+	Subject  string `json:"subject"`
+	BodyPath string `json:"body_path"`
+}
+
+type configVerification struct {
+	Enabled                   bool   `json:"enabled"`
+	SecretKey                 string `json:"secret_key"`
+	Unverified                string `json:"unverified"`
+	UnverifiedSessionDuration string `json:"unverified_session_duration"`
+}
+
+type configOutbox struct {
+	Routes          map[string][]string `json:"routes"`
+	DefaultChannels []string            `json:"default_channels"`
+	PollInterval    string              `json:"poll_interval"`
+	BatchSize       int                 `json:"batch_size"`
+	MaxAttempts     int                 `json:"max_attempts"`
+	Backoff         string              `json:"backoff"`
+	MaxBackoff      string              `json:"max_backoff"`
+	Lease           string              `json:"lease"`
+	Retention       string              `json:"retention"`
+}
+
+type configWebhooks struct {
+	EncryptionKey string                      `json:"encryption_key"`
+	Timeout       string                      `json:"timeout"`
+	CacheTTL      string                      `json:"cache_ttl"`
+	Subscriptions []configWebhookSubscription `json:"subscriptions"`
+}
+
+type configWebhookSubscription struct {
+	ID     string   `json:"id"`
+	URL    string   `json:"url"`
+	Events []string `json:"events"`
+	Secret string   `json:"secret"`
+}
@@ -0,0 +1,97 @@
+package hasher
+
+import (
+	"golang.org/x/crypto/argon2"
+)
+
+const argon2idID = "argon2id"
+
+// Argon2idParams are tunable parameters of Argon2id.
+type Argon2idParams struct {
+	// Memory is the amount of memory used in KiB.
+	Memory uint32
+
+	// Iterations is the number of passes over the memory.
+	Iterations uint32
+
+	// Parallelism is the number of threads used.
+	Parallelism uint8
+
+	// SaltLength is the length of a random salt in bytes.
+	SaltLength int
+
+	// KeyLength is the length of a hash in bytes.
+	KeyLength uint32
+}
+
+// DefaultArgon2idParams are the parameters recommended by RFC 9106
+// for memory-constrained environments.
+var DefaultArgon2idParams = Argon2idParams{
+	Memory:      64 * 1024,
+	Iterations:  3,
+	Parallelism: 4,
+	SaltLength:  16,
+	KeyLength:   32,
+}
+
+// argon2idHasher is a Hasher that uses Argon2id under the hood.
+// Hashes are encoded in the PHC string format, so they keep their parameters
+// and can be compared after the parameters are changed.
+type argon2idHasher struct {
+	params Argon2idParams
+}
+
+// NewArgon2idHasher returns a new argon2idHasher.
+func NewArgon2idHasher(params Argon2idParams) Rehasher {
+	return argon2idHasher{params}
+}
+
+func (h argon2idHasher) GenerateHashFromPassword(password []byte) (hash []byte, err error) {
+	salt, err := generateSalt(h.params.SaltLength)
+	if err != nil {
+		return nil, err
+	}
+
+	key := argon2.IDKey(password, salt, h.params.Iterations, h.params.Memory, h.params.Parallelism, h.params.KeyLength)
+
+	return phcHash{
+		ID:      argon2idID,
+		Version: argon2.Version,
+		Params: map[string]int{
+			"m": int(h.params.Memory),
+			"t": int(h.params.Iterations),
+			"p": int(h.params.Parallelism),
+		},
+		Salt: salt,
+		Hash: key,
+	}.encode("m", "t", "p"), nil
+}
+
+func (h argon2idHasher) CompareHashWithPassword(hash, password []byte) (err error) {
+	phc, err := parsePHCHash(argon2idID, hash)
+	if err != nil {
+		return err
+	}
+
+	m, t, p := phc.Params["m"], phc.Params["t"], phc.Params["p"]
+	if phc.Version != argon2.Version || m == 0 || t == 0 || p == 0 || p > 255 || len(phc.Hash) == 0 {
+		return ErrInvalidHash
+	}
+
+	key := argon2.IDKey(password, phc.Salt, uint32(t), uint32(m), uint8(p), uint32(len(phc.Hash)))
+	return compareKeys(key, phc.Hash)
+}
+
+func (h argon2idHasher) NeedsRehash(hash []byte) bool {
+	phc, err := parsePHCHash(argon2idID, hash)
+	if err != nil {
+		return true
+	}
+
+	return phc.Version != argon2.Version ||
+		phc.Params["m"] != int(h.params.Memory) ||
+		phc.Params["t"] != int(h.params.Iterations) ||
+		phc.Params["p"] != int(h.params.Parallelism) ||
+		len(phc.Salt) != h.params.SaltLength ||
+		len(phc.Hash) != int(h.params.KeyLength)
+}
@@ -0,0 +1,80 @@
+package hasher
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestArgon2idHasher(t *testing.T) {
+	h := NewArgon2idHasher(Argon2idParams{
+		Memory:      1024,
+		Iterations:  1,
+		Parallelism: 1,
+		SaltLength:  16,
+		KeyLength:   32,
+	})
+
+	hash, err := h.GenerateHashFromPassword([]byte("password"))
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+
+	if !strings.HasPrefix(string(hash), "$argon2id$v=19$m=1024,t=1,p=1$") {
+		t.Errorf("Unexpected hash format %s\n", hash)
+	}
+
+	if err = h.CompareHashWithPassword(hash, []byte("password")); err != nil {
+		t.Errorf("Expected no error, but got %s\n", err)
+	}
+
+	if err = h.CompareHashWithPassword(hash, []byte("wrong_password")); err != ErrMismatchedHashAndPassword {
+		t.Errorf("Expected error to be %v, but got %v\n", ErrMismatchedHashAndPassword, err)
+	}
+
+	other, err := h.GenerateHashFromPassword([]byte("password"))
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	if string(other) == string(hash) {
+		t.Errorf("Expected hashes to be salted\n")
+	}
+}
+
+func TestArgon2idHasher_CompareHashWithPassword(t *testing.T) {
+	// Hashes keep their parameters, so the hasher parameters do not matter.
+	h := NewArgon2idHasher(DefaultArgon2idParams)
+
+	cases := []struct {
+		caseName      string
+		hash          string
+		expectedError error
+	}{
+		{
+			caseName:      "Hash with other parameters",
+			hash:          "$argon2id$v=19$m=1024,t=2,p=1$c29tZXNhbHRzb21lc2FsdA$CKGe5/bX9YnCq2rxjW5yQXKxn31v1GKzhDCrMc6r6vA",
+			expectedError: nil,
+		},
+		{
+			caseName:      "Hash of another version",
+			hash:          "$argon2id$v=16$m=1024,t=2,p=1$c29tZXNhbHRzb21lc2FsdA$CKGe5/bX9YnCq2rxjW5yQXKxn31v1GKzhDCrMc6r6vA",
+			expectedError: ErrInvalidHash,
+		},
+		{
+			caseName:      "Hash with zero memory",
+			hash:          "$argon2id$v=19$m=0,t=2,p=1$c29tZXNhbHRzb21lc2FsdA$CKGe5/bX9YnCq2rxjW5yQXKxn31v1GKzhDCrMc6r6vA",
+			expectedError: ErrInvalidHash,
+		},
+		{
+			caseName:      "bcrypt hash",
+			hash:          "$2a$10$N9qo8uLOickgx2ZMRZoMyeIjZAgcfl7p92ldGxad68LJZdL17lhWy",
+			expectedError: ErrInvalidHash,
+		},
+	}
+
+	for i, c := range cases {
+		err := h.CompareHashWithPassword([]byte(c.hash), []byte("password"))
+		if err != c.expectedError {
+			t.Errorf("testcase %d %s: Expected error to be %v, but got %v\n", i, c.caseName, c.expectedError, err)
+		}
+	}
+}
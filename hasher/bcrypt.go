@@ -8,7 +8,7 @@ type bcryptHasher struct {
 }
 
 // NewBcryptHasher returns a new bcryptHasher.
-func NewBcryptHasher(cost int) Hasher {
+func NewBcryptHasher(cost int) Rehasher {
 	return bcryptHasher{cost}
 }
 
@@ -19,3 +19,8 @@ func (h bcryptHasher) GenerateHashFromPassword(password []byte) (hash []byte, er
 func (h bcryptHasher) CompareHashWithPassword(hash, password []byte) (err error) {
 	return bcrypt.CompareHashAndPassword(hash, password)
 }
+
+func (h bcryptHasher) NeedsRehash(hash []byte) bool {
+	cost, err := bcrypt.Cost(hash)
+	return err != nil || cost != h.Cost
+}
@@ -40,3 +40,34 @@ func (h *fakeHasher) CompareHashWithPassword(hash, password []byte) (err error)
 	h.compareHashWithPasswordResultCounter++
 	return r.Error
 }
+
+// fakeRehasher is a fake Rehasher that always produces deterministic given results.
+type fakeRehasher struct {
+	*fakeHasher
+	needsRehashResults       []FakeNeedsRehashResult
+	needsRehashResultCounter int
+}
+
+// FakeNeedsRehashResult is a result of hasher.NeedsRehash.
+type FakeNeedsRehashResult struct {
+	NeedsRehash bool
+}
+
+// NewFakeRehasher returns a new fakeRehasher.
+func NewFakeRehasher(
+	g []FakeGenerateHashFromPasswordResult,
+	c []FakeCompareHashWithPasswordResult,
+	n []FakeNeedsRehashResult,
+) Rehasher {
+	return &fakeRehasher{
+		fakeHasher:               NewFakeHasher(g, c).(*fakeHasher),
+		needsRehashResults:       n,
+		needsRehashResultCounter: 0,
+	}
+}
+
+func (h *fakeRehasher) NeedsRehash(hash []byte) bool {
+	r := h.needsRehashResults[h.needsRehashResultCounter]
+	h.needsRehashResultCounter++
+	return r.NeedsRehash
+}
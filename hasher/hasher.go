@@ -9,3 +9,13 @@ type Hasher interface {
 	// Returns nil on success, or an error on failure.
 	CompareHashWithPassword(hash, password []byte) (err error)
 }
+
+// Rehasher is a Hasher which can tell whether a hash is produced
+// with other algorithm or parameters than the current ones.
+type Rehasher interface {
+	Hasher
+
+	// NeedsRehash reports whether the hash should be generated again
+	// with the current algorithm and parameters.
+	NeedsRehash(hash []byte) bool
+}
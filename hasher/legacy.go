@@ -0,0 +1,141 @@
+package hasher
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// ErrLegacyHash occurs when a hash is requested from a Hasher of a legacy format.
+// Legacy formats are only supported to verify imported hashes, which are rehashed on login.
+var ErrLegacyHash = errors.New("Legacy hash format can only be verified")
+
+const (
+	// pbkdf2SHA256ID is an algorithm of Django PBKDF2 hashes:
+	// pbkdf2_sha256$<iterations>$<salt>$<base64 hash>
+	pbkdf2SHA256ID = "pbkdf2_sha256"
+
+	// apr1ID is an identifier of Apache MD5-crypt hashes, as produced by htpasswd:
+	// $apr1$<salt>$<hash>
+	apr1ID = "apr1"
+)
+
+// pbkdf2SHA256Hasher is a Hasher which verifies PBKDF2-SHA256 hashes in the Django format.
+type pbkdf2SHA256Hasher struct{}
+
+func (h pbkdf2SHA256Hasher) GenerateHashFromPassword(password []byte) (hash []byte, err error) {
+	return nil, ErrLegacyHash
+}
+
+func (h pbkdf2SHA256Hasher) CompareHashWithPassword(hash, password []byte) (err error) {
+	parts := strings.Split(string(hash), "$")
+	if len(parts) != 4 || parts[0] != pbkdf2SHA256ID {
+		return ErrInvalidHash
+	}
+
+	iterations, err := strconv.Atoi(parts[1])
+	if err != nil || iterations <= 0 {
+		return ErrInvalidHash
+	}
+
+	key, err := base64.StdEncoding.DecodeString(parts[3])
+	if err != nil || len(key) == 0 {
+		return ErrInvalidHash
+	}
+
+	other := pbkdf2.Key(password, []byte(parts[2]), iterations, len(key), sha256.New)
+	return compareKeys(key, other)
+}
+
+// apr1Hasher is a Hasher which verifies Apache MD5-crypt hashes.
+type apr1Hasher struct{}
+
+func (h apr1Hasher) GenerateHashFromPassword(password []byte) (hash []byte, err error) {
+	return nil, ErrLegacyHash
+}
+
+func (h apr1Hasher) CompareHashWithPassword(hash, password []byte) (err error) {
+	parts := strings.Split(string(hash), "$")
+	if len(parts) != 4 || parts[0] != "" || parts[1] != apr1ID || len(parts[2]) > 8 {
+		return ErrInvalidHash
+	}
+
+	other := apr1(password, []byte(parts[2]))
+	return compareKeys([]byte(parts[3]), other)
+}
+
+// apr1Alphabet is an alphabet of the crypt base64 encoding.
+const apr1Alphabet = "./0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// apr1 returns the encoded MD5-crypt digest of the password with the salt, with the "$apr1$" magic.
+// See http://svn.apache.org/viewvc/apr/apr/trunk/crypto/apr_md5.c
+func apr1(password, salt []byte) []byte {
+	magic := []byte("$" + apr1ID + "$")
+
+	alt := md5.New()
+	alt.Write(password)
+	alt.Write(salt)
+	alt.Write(password)
+	altSum := alt.Sum(nil)
+
+	ctx := md5.New()
+	ctx.Write(password)
+	ctx.Write(magic)
+	ctx.Write(salt)
+	for i := len(password); i > 0; i -= md5.Size {
+		if i > md5.Size {
+			ctx.Write(altSum)
+		} else {
+			ctx.Write(altSum[:i])
+		}
+	}
+	for i := len(password); i > 0; i >>= 1 {
+		if i&1 != 0 {
+			ctx.Write([]byte{0})
+		} else {
+			ctx.Write(password[:1])
+		}
+	}
+	sum := ctx.Sum(nil)
+
+	// The rounds are meant to slow down brute forcing, which is not much for today.
+	for i := 0; i < 1000; i++ {
+		round := md5.New()
+		if i&1 != 0 {
+			round.Write(password)
+		} else {
+			round.Write(sum)
+		}
+		if i%3 != 0 {
+			round.Write(salt)
+		}
+		if i%7 != 0 {
+			round.Write(password)
+		}
+		if i&1 != 0 {
+			round.Write(sum)
+		} else {
+			round.Write(password)
+		}
+		sum = round.Sum(nil)
+	}
+
+	encoded := make([]byte, 0, 22)
+	encode := func(v uint, n int) {
+		for ; n > 0; n-- {
+			encoded = append(encoded, apr1Alphabet[v&0x3f])
+			v >>= 6
+		}
+	}
+	for _, g := range [][3]int{{0, 6, 12}, {1, 7, 13}, {2, 8, 14}, {3, 9, 15}, {4, 10, 5}} {
+		encode(uint(sum[g[0]])<<16|uint(sum[g[1]])<<8|uint(sum[g[2]]), 4)
+	}
+	encode(uint(sum[11]), 2)
+
+	return encoded
+}
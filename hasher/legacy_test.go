@@ -0,0 +1,109 @@
+package hasher
+
+import "testing"
+
+func TestPBKDF2SHA256Hasher_CompareHashWithPassword(t *testing.T) {
+	h := pbkdf2SHA256Hasher{}
+
+	cases := []struct {
+		caseName      string
+		hash          string
+		password      string
+		expectedError error
+	}{
+		{
+			caseName:      "Matching password",
+			hash:          "pbkdf2_sha256$1000$seasalt$YIWkt6M1JFXrHg5s0jZjBSc7C2Cz6QvchSJ0h8Y+i7c=",
+			password:      "password",
+			expectedError: nil,
+		},
+		{
+			caseName:      "Wrong password",
+			hash:          "pbkdf2_sha256$1000$seasalt$YIWkt6M1JFXrHg5s0jZjBSc7C2Cz6QvchSJ0h8Y+i7c=",
+			password:      "wrong_password",
+			expectedError: ErrMismatchedHashAndPassword,
+		},
+		{
+			caseName:      "Invalid iterations",
+			hash:          "pbkdf2_sha256$abc$seasalt$YIWkt6M1JFXrHg5s0jZjBSc7C2Cz6QvchSJ0h8Y+i7c=",
+			password:      "password",
+			expectedError: ErrInvalidHash,
+		},
+		{
+			caseName:      "Invalid hash encoding",
+			hash:          "pbkdf2_sha256$1000$seasalt$!!!",
+			password:      "password",
+			expectedError: ErrInvalidHash,
+		},
+		{
+			caseName:      "Other algorithm",
+			hash:          "pbkdf2_sha1$1000$seasalt$YIWkt6M1JFXrHg5s0jZjBSc7C2Cz6QvchSJ0h8Y+i7c=",
+			password:      "password",
+			expectedError: ErrInvalidHash,
+		},
+	}
+
+	for i, c := range cases {
+		err := h.CompareHashWithPassword([]byte(c.hash), []byte(c.password))
+		if err != c.expectedError {
+			t.Errorf("testcase %d %s: Expected error to be %v, but got %v\n", i, c.caseName, c.expectedError, err)
+		}
+	}
+
+	if _, err := h.GenerateHashFromPassword([]byte("password")); err != ErrLegacyHash {
+		t.Errorf("Expected error to be %v, but got %v\n", ErrLegacyHash, err)
+	}
+}
+
+func TestAPR1Hasher_CompareHashWithPassword(t *testing.T) {
+	h := apr1Hasher{}
+
+	cases := []struct {
+		caseName      string
+		hash          string
+		password      string
+		expectedError error
+	}{
+		{
+			caseName:      "Matching password",
+			hash:          "$apr1$r31.....$HqJZimcKQFAMYayBlzkrA/",
+			password:      "myPassword",
+			expectedError: nil,
+		},
+		{
+			caseName:      "Matching password with full length salt",
+			hash:          "$apr1$saltsalt$yAAkm4libquA.ZWLHbSBq/",
+			password:      "password",
+			expectedError: nil,
+		},
+		{
+			caseName:      "Wrong password",
+			hash:          "$apr1$saltsalt$yAAkm4libquA.ZWLHbSBq/",
+			password:      "wrong_password",
+			expectedError: ErrMismatchedHashAndPassword,
+		},
+		{
+			caseName:      "Salt is too long",
+			hash:          "$apr1$saltsaltsalt$yAAkm4libquA.ZWLHbSBq/",
+			password:      "password",
+			expectedError: ErrInvalidHash,
+		},
+		{
+			caseName:      "Other algorithm",
+			hash:          "$1$saltsalt$yAAkm4libquA.ZWLHbSBq/",
+			password:      "password",
+			expectedError: ErrInvalidHash,
+		},
+	}
+
+	for i, c := range cases {
+		err := h.CompareHashWithPassword([]byte(c.hash), []byte(c.password))
+		if err != c.expectedError {
+			t.Errorf("testcase %d %s: Expected error to be %v, but got %v\n", i, c.caseName, c.expectedError, err)
+		}
+	}
+
+	if _, err := h.GenerateHashFromPassword([]byte("password")); err != ErrLegacyHash {
+		t.Errorf("Expected error to be %v, but got %v\n", ErrLegacyHash, err)
+	}
+}
@@ -0,0 +1,65 @@
+package hasher
+
+import (
+	"bytes"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// multiHasher is a Rehasher which generates hashes with the current Hasher,
+// and compares hashes of any supported algorithm, detected by the hash prefix.
+// It allows to change the algorithm or parameters without breaking existing hashes,
+// which can be rehashed on login.
+type multiHasher struct {
+	current Rehasher
+}
+
+// NewMultiHasher returns a new multiHasher.
+func NewMultiHasher(current Rehasher) Rehasher {
+	return multiHasher{current}
+}
+
+// comparers are hashers by prefixes of their hashes.
+// Hashes keep their parameters, so the hashers parameters do not matter for comparison.
+var comparers = []struct {
+	prefix string
+	hasher Hasher
+}{
+	{"$2a$", bcryptHasher{bcrypt.DefaultCost}},
+	{"$2b$", bcryptHasher{bcrypt.DefaultCost}},
+	{"$2y$", bcryptHasher{bcrypt.DefaultCost}},
+	{"$" + argon2idID + "$", argon2idHasher{DefaultArgon2idParams}},
+	{"$" + scryptID + "$", scryptHasher{DefaultScryptParams}},
+	{pbkdf2SHA256ID + "$", pbkdf2SHA256Hasher{}},
+	{"$" + apr1ID + "$", apr1Hasher{}},
+}
+
+// IsSupportedHash reports whether the hash is of any algorithm supported by NewMultiHasher,
+// including legacy ones, which can only be verified.
+func IsSupportedHash(hash []byte) bool {
+	for _, c := range comparers {
+		if bytes.HasPrefix(hash, []byte(c.prefix)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (h multiHasher) GenerateHashFromPassword(password []byte) (hash []byte, err error) {
+	return h.current.GenerateHashFromPassword(password)
+}
+
+func (h multiHasher) CompareHashWithPassword(hash, password []byte) (err error) {
+	for _, c := range comparers {
+		if bytes.HasPrefix(hash, []byte(c.prefix)) {
+			return c.hasher.CompareHashWithPassword(hash, password)
+		}
+	}
+
+	return ErrInvalidHash
+}
+
+func (h multiHasher) NeedsRehash(hash []byte) bool {
+	return h.current.NeedsRehash(hash)
+}
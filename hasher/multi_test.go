@@ -0,0 +1,136 @@
+package hasher
+
+import (
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestMultiHasher(t *testing.T) {
+	password := []byte("password")
+
+	bcryptHash, err := NewBcryptHasher(bcrypt.MinCost).GenerateHashFromPassword(password)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	scryptHash, err := NewScryptHasher(ScryptParams{LogN: 10, R: 8, P: 1, SaltLength: 16, KeyLength: 32}).GenerateHashFromPassword(password)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+
+	argon2idParams := Argon2idParams{Memory: 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+	h := NewMultiHasher(NewArgon2idHasher(argon2idParams))
+
+	argon2idHash, err := h.GenerateHashFromPassword(password)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+
+	cases := []struct {
+		caseName            string
+		hash                []byte
+		password            []byte
+		expectedError       error
+		expectedNeedsRehash bool
+	}{
+		{
+			caseName:            "Hash of the current algorithm and parameters",
+			hash:                argon2idHash,
+			password:            password,
+			expectedError:       nil,
+			expectedNeedsRehash: false,
+		},
+		{
+			caseName:            "Hash of the current algorithm with other parameters",
+			hash:                []byte("$argon2id$v=19$m=1024,t=2,p=1$c29tZXNhbHRzb21lc2FsdA$CKGe5/bX9YnCq2rxjW5yQXKxn31v1GKzhDCrMc6r6vA"),
+			password:            password,
+			expectedError:       nil,
+			expectedNeedsRehash: true,
+		},
+		{
+			caseName:            "bcrypt hash",
+			hash:                bcryptHash,
+			password:            password,
+			expectedError:       nil,
+			expectedNeedsRehash: true,
+		},
+		{
+			caseName:            "bcrypt hash with wrong password",
+			hash:                bcryptHash,
+			password:            []byte("wrong_password"),
+			expectedError:       bcrypt.ErrMismatchedHashAndPassword,
+			expectedNeedsRehash: true,
+		},
+		{
+			caseName:            "scrypt hash",
+			hash:                scryptHash,
+			password:            password,
+			expectedError:       nil,
+			expectedNeedsRehash: true,
+		},
+		{
+			caseName:            "Django PBKDF2 hash",
+			hash:                []byte("pbkdf2_sha256$1000$seasalt$YIWkt6M1JFXrHg5s0jZjBSc7C2Cz6QvchSJ0h8Y+i7c="),
+			password:            password,
+			expectedError:       nil,
+			expectedNeedsRehash: true,
+		},
+		{
+			caseName:            "htpasswd MD5 hash",
+			hash:                []byte("$apr1$saltsalt$yAAkm4libquA.ZWLHbSBq/"),
+			password:            password,
+			expectedError:       nil,
+			expectedNeedsRehash: true,
+		},
+		{
+			caseName:            "Hash of unknown algorithm",
+			hash:                []byte("$1$saltsalt$hash"),
+			password:            password,
+			expectedError:       ErrInvalidHash,
+			expectedNeedsRehash: true,
+		},
+	}
+
+	for i, c := range cases {
+		if err := h.CompareHashWithPassword(c.hash, c.password); err != c.expectedError {
+			t.Errorf("testcase %d %s: Expected error to be %v, but got %v\n", i, c.caseName, c.expectedError, err)
+		}
+		if needsRehash := h.NeedsRehash(c.hash); needsRehash != c.expectedNeedsRehash {
+			t.Errorf("testcase %d %s: Expected NeedsRehash to be %v, but got %v\n", i, c.caseName, c.expectedNeedsRehash, needsRehash)
+		}
+	}
+}
+
+func TestBcryptHasher_NeedsRehash(t *testing.T) {
+	hash, err := NewBcryptHasher(bcrypt.MinCost).GenerateHashFromPassword([]byte("password"))
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+
+	if NewBcryptHasher(bcrypt.MinCost).NeedsRehash(hash) {
+		t.Errorf("Expected hash of the same cost not to need rehash\n")
+	}
+	if !NewBcryptHasher(bcrypt.MinCost + 1).NeedsRehash(hash) {
+		t.Errorf("Expected hash of other cost to need rehash\n")
+	}
+}
+
+func TestIsSupportedHash(t *testing.T) {
+	cases := []struct {
+		hash     string
+		expected bool
+	}{
+		{"$2y$05$abcdefghijklmnopqrstuu5Wl7Z5tgmOzDw3BvQK1sQbp0bn6lLLe", true},
+		{"$argon2id$v=19$m=1024,t=2,p=1$c29tZXNhbHRzb21lc2FsdA$CKGe5/bX9YnCq2rxjW5yQXKxn31v1GKzhDCrMc6r6vA", true},
+		{"pbkdf2_sha256$1000$seasalt$YIWkt6M1JFXrHg5s0jZjBSc7C2Cz6QvchSJ0h8Y+i7c=", true},
+		{"$apr1$saltsalt$yAAkm4libquA.ZWLHbSBq/", true},
+		{"$1$saltsalt$hash", false},
+		{"password", false},
+	}
+
+	for i, c := range cases {
+		if supported := IsSupportedHash([]byte(c.hash)); supported != c.expected {
+			t.Errorf("testcase %d %s: Expected IsSupportedHash to be %v, but got %v\n", i, c.hash, c.expected, supported)
+		}
+	}
+}
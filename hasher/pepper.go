@@ -0,0 +1,100 @@
+package hasher
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strings"
+)
+
+// pepperPrefix is a prefix of peppered hashes: $pepper$<key ID><inner hash>
+const pepperPrefix = "$pepper$"
+
+// ErrUnknownPepper occurs when the hash is peppered with a pepper which is not configured.
+var ErrUnknownPepper = errors.New("Hash is peppered with an unknown pepper")
+
+// pepperHasher is a Rehasher decorator which mixes a secret pepper into passwords
+// as an HMAC before they are passed to the inner Hasher, so a database dump alone
+// is not enough for offline cracking.
+// Hashes record the ID of the pepper key, so peppers can be rotated:
+// hashes with an old pepper, or without a pepper at all, need rehash.
+type pepperHasher struct {
+	inner        Rehasher
+	currentKeyID string
+	peppers      map[string][]byte
+}
+
+// NewPepperHasher returns a new pepperHasher, which peppers new hashes with the pepper
+// of currentKeyID, and compares hashes peppered with any of peppers by key ID.
+// Key IDs MUST NOT contain "$".
+func NewPepperHasher(inner Rehasher, currentKeyID string, peppers map[string][]byte) Rehasher {
+	return pepperHasher{inner, currentKeyID, peppers}
+}
+
+func (h pepperHasher) GenerateHashFromPassword(password []byte) (hash []byte, err error) {
+	pepper, ok := h.peppers[h.currentKeyID]
+	if !ok {
+		return nil, ErrUnknownPepper
+	}
+
+	inner, err := h.inner.GenerateHashFromPassword(mixPepper(pepper, password))
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(pepperPrefix+h.currentKeyID), inner...), nil
+}
+
+func (h pepperHasher) CompareHashWithPassword(hash, password []byte) (err error) {
+	keyID, inner, peppered := splitPepperedHash(hash)
+	if !peppered {
+		// Hashes made before the pepper was configured.
+		return h.inner.CompareHashWithPassword(hash, password)
+	}
+
+	pepper, ok := h.peppers[keyID]
+	if !ok {
+		return ErrUnknownPepper
+	}
+
+	return h.inner.CompareHashWithPassword(inner, mixPepper(pepper, password))
+}
+
+func (h pepperHasher) NeedsRehash(hash []byte) bool {
+	keyID, inner, peppered := splitPepperedHash(hash)
+	if !peppered || keyID != h.currentKeyID {
+		return true
+	}
+
+	return h.inner.NeedsRehash(inner)
+}
+
+// mixPepper returns the HMAC of the password keyed with the pepper.
+// It is base64 encoded, so inner hashers get no zero bytes
+// and the input fits in the bcrypt limit of 72 bytes.
+func mixPepper(pepper, password []byte) []byte {
+	mac := hmac.New(sha256.New, pepper)
+	mac.Write(password)
+
+	sum := mac.Sum(nil)
+	mixed := make([]byte, base64.StdEncoding.EncodedLen(len(sum)))
+	base64.StdEncoding.Encode(mixed, sum)
+	return mixed
+}
+
+// splitPepperedHash returns the pepper key ID and the inner hash of the peppered hash.
+func splitPepperedHash(hash []byte) (keyID string, inner []byte, peppered bool) {
+	if !bytes.HasPrefix(hash, []byte(pepperPrefix)) {
+		return "", nil, false
+	}
+
+	rest := string(hash[len(pepperPrefix):])
+	i := strings.Index(rest, "$")
+	if i <= 0 {
+		return "", nil, false
+	}
+
+	return rest[:i], []byte(rest[i:]), true
+}
@@ -0,0 +1,104 @@
+package hasher
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestPepperHasher(t *testing.T) {
+	password := []byte("password")
+	inner := NewMultiHasher(NewBcryptHasher(bcrypt.MinCost))
+	peppers := map[string][]byte{
+		"k1": []byte("0123456789abcdef"),
+		"k2": []byte("fedcba9876543210"),
+	}
+
+	oldHasher := NewPepperHasher(inner, "k1", peppers)
+	h := NewPepperHasher(inner, "k2", peppers)
+
+	unpepperedHash, err := inner.GenerateHashFromPassword(password)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	oldHash, err := oldHasher.GenerateHashFromPassword(password)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	hash, err := h.GenerateHashFromPassword(password)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+
+	if !strings.HasPrefix(string(hash), "$pepper$k2$2a$") {
+		t.Errorf("Unexpected hash format %s\n", hash)
+	}
+
+	// The inner hash alone does not match the password.
+	_, innerHash, _ := splitPepperedHash(hash)
+	if err = inner.CompareHashWithPassword(innerHash, password); err == nil {
+		t.Errorf("Expected inner hash not to match the password without the pepper\n")
+	}
+
+	cases := []struct {
+		caseName            string
+		hash                []byte
+		password            []byte
+		expectedError       error
+		expectedNeedsRehash bool
+	}{
+		{
+			caseName:            "Hash with the current pepper",
+			hash:                hash,
+			password:            password,
+			expectedError:       nil,
+			expectedNeedsRehash: false,
+		},
+		{
+			caseName:            "Hash with the current pepper and wrong password",
+			hash:                hash,
+			password:            []byte("wrong_password"),
+			expectedError:       bcrypt.ErrMismatchedHashAndPassword,
+			expectedNeedsRehash: false,
+		},
+		{
+			caseName:            "Hash with an old pepper",
+			hash:                oldHash,
+			password:            password,
+			expectedError:       nil,
+			expectedNeedsRehash: true,
+		},
+		{
+			caseName:            "Hash without a pepper",
+			hash:                unpepperedHash,
+			password:            password,
+			expectedError:       nil,
+			expectedNeedsRehash: true,
+		},
+		{
+			caseName:            "Hash with an unknown pepper",
+			hash:                append([]byte("$pepper$k0"), innerHash...),
+			password:            password,
+			expectedError:       ErrUnknownPepper,
+			expectedNeedsRehash: true,
+		},
+	}
+
+	for i, c := range cases {
+		if err := h.CompareHashWithPassword(c.hash, c.password); err != c.expectedError {
+			t.Errorf("testcase %d %s: Expected error to be %v, but got %v\n", i, c.caseName, c.expectedError, err)
+		}
+		if needsRehash := h.NeedsRehash(c.hash); needsRehash != c.expectedNeedsRehash {
+			t.Errorf("testcase %d %s: Expected NeedsRehash to be %v, but got %v\n", i, c.caseName, c.expectedNeedsRehash, needsRehash)
+		}
+	}
+}
+
+func TestPepperHasher_GenerateHashFromPassword(t *testing.T) {
+	h := NewPepperHasher(NewBcryptHasher(bcrypt.MinCost), "k2", map[string][]byte{"k1": []byte("0123456789abcdef")})
+
+	if _, err := h.GenerateHashFromPassword([]byte("password")); err != ErrUnknownPepper {
+		t.Errorf("Expected error to be %v, but got %v\n", ErrUnknownPepper, err)
+	}
+}
@@ -0,0 +1,116 @@
+package hasher
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrMismatchedHashAndPassword occurs when the password does not match the hash.
+	ErrMismatchedHashAndPassword = errors.New("Hash is not the hash of the given password")
+
+	// ErrInvalidHash occurs when the hash is not in the format the Hasher produces.
+	ErrInvalidHash = errors.New("Hash is not in the expected format")
+)
+
+// phcEncoding is the base64 variant of the PHC string format.
+var phcEncoding = base64.RawStdEncoding
+
+// phcHash is a hash in the PHC string format:
+// $<id>[$v=<version>][$<param>=<value>(,<param>=<value>)*][$<salt>[$<hash>]]
+// See https://github.com/P-H-C/phc-string-format
+type phcHash struct {
+	ID      string
+	Version int
+	Params  map[string]int
+	Salt    []byte
+	Hash    []byte
+}
+
+// encode returns the hash in the PHC string format.
+// Params are encoded in the order of names.
+func (h phcHash) encode(names ...string) []byte {
+	parts := []string{"", h.ID}
+	if h.Version != 0 {
+		parts = append(parts, fmt.Sprintf("v=%d", h.Version))
+	}
+
+	params := make([]string, len(names))
+	for i, name := range names {
+		params[i] = fmt.Sprintf("%s=%d", name, h.Params[name])
+	}
+	parts = append(parts, strings.Join(params, ","))
+
+	parts = append(parts, phcEncoding.EncodeToString(h.Salt), phcEncoding.EncodeToString(h.Hash))
+	return []byte(strings.Join(parts, "$"))
+}
+
+// parsePHCHash parses the hash in the PHC string format with the id, params, salt and hash.
+func parsePHCHash(id string, hash []byte) (*phcHash, error) {
+	parts := strings.Split(string(hash), "$")
+	if len(parts) < 5 || parts[0] != "" || parts[1] != id {
+		return nil, ErrInvalidHash
+	}
+
+	h := &phcHash{ID: id, Params: map[string]int{}}
+	parts = parts[2:]
+
+	if strings.HasPrefix(parts[0], "v=") {
+		v, err := strconv.Atoi(strings.TrimPrefix(parts[0], "v="))
+		if err != nil {
+			return nil, ErrInvalidHash
+		}
+		h.Version = v
+		parts = parts[1:]
+	}
+
+	if len(parts) != 3 {
+		return nil, ErrInvalidHash
+	}
+
+	for _, param := range strings.Split(parts[0], ",") {
+		kv := strings.SplitN(param, "=", 2)
+		if len(kv) != 2 {
+			return nil, ErrInvalidHash
+		}
+		v, err := strconv.Atoi(kv[1])
+		if err != nil || v < 0 {
+			return nil, ErrInvalidHash
+		}
+		h.Params[kv[0]] = v
+	}
+
+	var err error
+	if h.Salt, err = phcEncoding.DecodeString(parts[1]); err != nil {
+		return nil, ErrInvalidHash
+	}
+	if h.Hash, err = phcEncoding.DecodeString(parts[2]); err != nil {
+		return nil, ErrInvalidHash
+	}
+
+	return h, nil
+}
+
+// generateSalt returns a new random salt of n bytes.
+func generateSalt(n int) ([]byte, error) {
+	salt := make([]byte, n)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	return salt, nil
+}
+
+// compareKeys compares keys in constant time.
+func compareKeys(a, b []byte) error {
+	if subtle.ConstantTimeCompare(a, b) != 1 {
+		return ErrMismatchedHashAndPassword
+	}
+
+	return nil
+}
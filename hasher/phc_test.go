@@ -0,0 +1,96 @@
+package hasher
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPHCHash_encode(t *testing.T) {
+	h := phcHash{
+		ID:      "argon2id",
+		Version: 19,
+		Params:  map[string]int{"m": 65536, "t": 3, "p": 4},
+		Salt:    []byte("somesalt"),
+		Hash:    []byte("hash"),
+	}
+
+	expected := "$argon2id$v=19$m=65536,t=3,p=4$c29tZXNhbHQ$aGFzaA"
+	if actual := string(h.encode("m", "t", "p")); actual != expected {
+		t.Errorf("Expected %s but got %s\n", expected, actual)
+	}
+
+	parsed, err := parsePHCHash("argon2id", []byte(expected))
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	if !reflect.DeepEqual(*parsed, h) {
+		t.Errorf("Expected %#v but got %#v\n", h, *parsed)
+	}
+}
+
+func TestParsePHCHash(t *testing.T) {
+	cases := []struct {
+		caseName      string
+		id            string
+		hash          string
+		expected      *phcHash
+		expectedError error
+	}{
+		{
+			caseName: "Hash without version",
+			id:       "scrypt",
+			hash:     "$scrypt$ln=15,r=8,p=1$c29tZXNhbHQ$aGFzaA",
+			expected: &phcHash{
+				ID:     "scrypt",
+				Params: map[string]int{"ln": 15, "r": 8, "p": 1},
+				Salt:   []byte("somesalt"),
+				Hash:   []byte("hash"),
+			},
+		},
+		{
+			caseName:      "Hash of another algorithm",
+			id:            "scrypt",
+			hash:          "$argon2id$v=19$m=65536,t=3,p=4$c29tZXNhbHQ$aGFzaA",
+			expectedError: ErrInvalidHash,
+		},
+		{
+			caseName:      "bcrypt hash",
+			id:            "argon2id",
+			hash:          "$2a$10$N9qo8uLOickgx2ZMRZoMyeIjZAgcfl7p92ldGxad68LJZdL17lhWy",
+			expectedError: ErrInvalidHash,
+		},
+		{
+			caseName:      "Hash without salt",
+			id:            "argon2id",
+			hash:          "$argon2id$v=19$m=65536,t=3,p=4$aGFzaA",
+			expectedError: ErrInvalidHash,
+		},
+		{
+			caseName:      "Hash with malformed param",
+			id:            "argon2id",
+			hash:          "$argon2id$v=19$m=65536,t,p=4$c29tZXNhbHQ$aGFzaA",
+			expectedError: ErrInvalidHash,
+		},
+		{
+			caseName:      "Hash with malformed base64",
+			id:            "argon2id",
+			hash:          "$argon2id$v=19$m=65536,t=3,p=4$c29tZXNhbHQ$!!!",
+			expectedError: ErrInvalidHash,
+		},
+	}
+
+	for i, c := range cases {
+		actual, err := parsePHCHash(c.id, []byte(c.hash))
+		if !reflect.DeepEqual(actual, c.expected) || err != c.expectedError {
+			t.Errorf(
+				"testcase %d %s: Expected (%#v, %v) but got (%#v, %v)\n",
+				i,
+				c.caseName,
+				c.expected,
+				c.expectedError,
+				actual,
+				err,
+			)
+		}
+	}
+}
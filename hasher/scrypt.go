@@ -0,0 +1,101 @@
+package hasher
+
+import (
+	"golang.org/x/crypto/scrypt"
+)
+
+const scryptID = "scrypt"
+
+// ScryptParams are tunable parameters of scrypt.
+type ScryptParams struct {
+	// LogN is the binary logarithm of the CPU/memory cost parameter N.
+	LogN uint8
+
+	// R is the block size.
+	R int
+
+	// P is the parallelization parameter.
+	P int
+
+	// SaltLength is the length of a random salt in bytes.
+	SaltLength int
+
+	// KeyLength is the length of a hash in bytes.
+	KeyLength int
+}
+
+// DefaultScryptParams are the parameters recommended for interactive logins.
+var DefaultScryptParams = ScryptParams{
+	LogN:       15,
+	R:          8,
+	P:          1,
+	SaltLength: 16,
+	KeyLength:  32,
+}
+
+// scryptHasher is a Hasher that uses scrypt under the hood.
+// Hashes are encoded in the PHC string format, so they keep their parameters
+// and can be compared after the parameters are changed.
+type scryptHasher struct {
+	params ScryptParams
+}
+
+// NewScryptHasher returns a new scryptHasher.
+func NewScryptHasher(params ScryptParams) Rehasher {
+	return scryptHasher{params}
+}
+
+func (h scryptHasher) GenerateHashFromPassword(password []byte) (hash []byte, err error) {
+	salt, err := generateSalt(h.params.SaltLength)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := scrypt.Key(password, salt, 1<<h.params.LogN, h.params.R, h.params.P, h.params.KeyLength)
+	if err != nil {
+		return nil, err
+	}
+
+	return phcHash{
+		ID: scryptID,
+		Params: map[string]int{
+			"ln": int(h.params.LogN),
+			"r":  h.params.R,
+			"p":  h.params.P,
+		},
+		Salt: salt,
+		Hash: key,
+	}.encode("ln", "r", "p"), nil
+}
+
+func (h scryptHasher) CompareHashWithPassword(hash, password []byte) (err error) {
+	phc, err := parsePHCHash(scryptID, hash)
+	if err != nil {
+		return err
+	}
+
+	ln, r, p := phc.Params["ln"], phc.Params["r"], phc.Params["p"]
+	if ln == 0 || ln > 31 || r == 0 || p == 0 || len(phc.Hash) == 0 {
+		return ErrInvalidHash
+	}
+
+	key, err := scrypt.Key(password, phc.Salt, 1<<uint(ln), r, p, len(phc.Hash))
+	if err != nil {
+		return ErrInvalidHash
+	}
+
+	return compareKeys(key, phc.Hash)
+}
+
+func (h scryptHasher) NeedsRehash(hash []byte) bool {
+	phc, err := parsePHCHash(scryptID, hash)
+	if err != nil {
+		return true
+	}
+
+	return phc.Params["ln"] != int(h.params.LogN) ||
+		phc.Params["r"] != h.params.R ||
+		phc.Params["p"] != h.params.P ||
+		len(phc.Salt) != h.params.SaltLength ||
+		len(phc.Hash) != h.params.KeyLength
+}
@@ -0,0 +1,68 @@
+package hasher
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestScryptHasher(t *testing.T) {
+	h := NewScryptHasher(ScryptParams{
+		LogN:       10,
+		R:          8,
+		P:          1,
+		SaltLength: 16,
+		KeyLength:  32,
+	})
+
+	hash, err := h.GenerateHashFromPassword([]byte("password"))
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+
+	if !strings.HasPrefix(string(hash), "$scrypt$ln=10,r=8,p=1$") {
+		t.Errorf("Unexpected hash format %s\n", hash)
+	}
+
+	if err = h.CompareHashWithPassword(hash, []byte("password")); err != nil {
+		t.Errorf("Expected no error, but got %s\n", err)
+	}
+
+	if err = h.CompareHashWithPassword(hash, []byte("wrong_password")); err != ErrMismatchedHashAndPassword {
+		t.Errorf("Expected error to be %v, but got %v\n", ErrMismatchedHashAndPassword, err)
+	}
+}
+
+func TestScryptHasher_CompareHashWithPassword(t *testing.T) {
+	// Hashes keep their parameters, so the hasher parameters do not matter.
+	h := NewScryptHasher(DefaultScryptParams)
+
+	cases := []struct {
+		caseName      string
+		hash          string
+		expectedError error
+	}{
+		{
+			// RFC 7914 test vector: P="password", S="NaCl", N=1024, r=8, p=16, dkLen=64.
+			caseName:      "RFC 7914 test vector",
+			hash:          "$scrypt$ln=10,r=8,p=16$TmFDbA$/bq+HJ00cgB4VucZDQHp/nxq18vII3gw53N2Y0s3MWIurzDZLiKjiG/xCSedmDDaxyevuUqD7m2DYMvfoswGQA",
+			expectedError: nil,
+		},
+		{
+			caseName:      "Hash with zero cost",
+			hash:          "$scrypt$ln=0,r=8,p=16$TmFDbA$/bq+HJ00cgB4VucZDQHp/nxq18vII3gw53N2Y0s3MWIurzDZLiKjiG/xCSedmDDaxyevuUqD7m2DYMvfoswGQA",
+			expectedError: ErrInvalidHash,
+		},
+		{
+			caseName:      "Hash with invalid parameters",
+			hash:          "$scrypt$ln=10,r=0,p=16$TmFDbA$/bq+HJ00cgB4VucZDQHp/nxq18vII3gw53N2Y0s3MWIurzDZLiKjiG/xCSedmDDaxyevuUqD7m2DYMvfoswGQA",
+			expectedError: ErrInvalidHash,
+		},
+	}
+
+	for i, c := range cases {
+		err := h.CompareHashWithPassword([]byte(c.hash), []byte("password"))
+		if err != c.expectedError {
+			t.Errorf("testcase %d %s: Expected error to be %v, but got %v\n", i, c.caseName, c.expectedError, err)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/hypnoglow/pascont/importer"
+	"github.com/hypnoglow/pascont/postgres"
+)
+
+// runImport runs the import command, which imports accounts with their password hashes
+// from a CSV or JSONL file:
+//
+//	pascont import -tenant default [-format csv|jsonl] accounts.csv
+func runImport(db *sql.DB, args []string) error {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	tenantName := flags.String("tenant", "", "Name of the tenant to import accounts to")
+	format := flags.String("format", "", "Format of the file: csv or jsonl. By default it is the file extension")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *tenantName == "" || flags.NArg() != 1 {
+		return errors.New("Usage: pascont import -tenant <name> [-format csv|jsonl] <file>")
+	}
+
+	path := flags.Arg(0)
+	if *format == "" {
+		*format = strings.TrimPrefix(filepath.Ext(path), ".")
+	}
+
+	t, err := postgres.NewTenantRepository(db).FindByName(*tenantName)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	records, err := importer.Read(f, *format)
+	if err != nil {
+		return err
+	}
+
+	res, err := importer.Import(postgres.NewAccountRepository(db), t.ID, records, time.Now())
+	for _, skip := range res.Skipped {
+		fmt.Fprintf(os.Stderr, "Line %d: skipped %q: %s\n", skip.Record.Line, skip.Record.Name, skip.Reason)
+	}
+	fmt.Fprintf(os.Stdout, "Imported %d accounts, skipped %d\n", res.Imported, len(res.Skipped))
+
+	return err
+}
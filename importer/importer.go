@@ -0,0 +1,57 @@
+// Package importer imports accounts from other applications,
+// keeping their password hashes.
+package importer
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/hypnoglow/pascont/account"
+	"github.com/hypnoglow/pascont/hasher"
+)
+
+// Result is a result of an import.
+type Result struct {
+	Imported int
+	Skipped  []Skip
+}
+
+// Skip is a record which was not imported.
+type Skip struct {
+	Record Record
+	Reason string
+}
+
+// Import adds accounts of the records to the tenant.
+// Records without a name, with a hash of an unsupported format, or with a name which
+// already exists in the tenant are skipped. Imported hashes are verified by the multi hasher
+// and rehashed with the current algorithm on the first successful log in.
+// On a repository error the import stops, and the result so far is returned with the error.
+func Import(repo account.Repository, tenantID int64, records []Record, createdAt time.Time) (Result, error) {
+	var res Result
+	for _, rec := range records {
+		if rec.Name == "" {
+			res.Skipped = append(res.Skipped, Skip{rec, "Name is empty"})
+			continue
+		}
+		if !hasher.IsSupportedHash([]byte(rec.PasswordHash)) {
+			res.Skipped = append(res.Skipped, Skip{rec, "Password hash format is not supported"})
+			continue
+		}
+
+		app := account.NewApplication(tenantID, rec.Name, []byte(rec.PasswordHash), createdAt)
+		_, err := repo.Accept(app)
+		if err == account.ErrAlreadyExists {
+			res.Skipped = append(res.Skipped, Skip{rec, "Account already exists"})
+			continue
+		}
+		if err != nil {
+			return res, errors.Wrapf(err, "Failed to import the record at line %d", rec.Line)
+		}
+
+		res.Imported++
+	}
+
+	return res, nil
+}
@@ -0,0 +1,82 @@
+package importer
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/pascont/account"
+)
+
+func TestImport(t *testing.T) {
+	john := Record{Line: 2, Name: "john", PasswordHash: "pbkdf2_sha256$1000$seasalt$YIWkt6M1JFXrHg5s0jZjBSc7C2Cz6QvchSJ0h8Y+i7c="}
+	jane := Record{Line: 3, Name: "jane", PasswordHash: "$apr1$saltsalt$yAAkm4libquA.ZWLHbSBq/"}
+	plain := Record{Line: 4, Name: "plain", PasswordHash: "password"}
+	noName := Record{Line: 5, Name: "", PasswordHash: "$apr1$saltsalt$yAAkm4libquA.ZWLHbSBq/"}
+
+	cases := []struct {
+		caseName string
+		// in
+		repo    account.Repository
+		records []Record
+		// out
+		expectedResult Result
+		expectedError  bool
+	}{
+		{
+			caseName: "Records are imported or skipped",
+			repo: account.NewFakeRepository(
+				[]account.FakeRepositoryAcceptResult{
+					{
+						Account: &account.Account{ID: 1, Name: "john"},
+					},
+					{
+						Error: account.ErrAlreadyExists,
+					},
+				},
+				nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+				nil,
+			),
+			records: []Record{john, jane, plain, noName},
+			expectedResult: Result{
+				Imported: 1,
+				Skipped: []Skip{
+					{jane, "Account already exists"},
+					{plain, "Password hash format is not supported"},
+					{noName, "Name is empty"},
+				},
+			},
+		},
+		{
+			caseName: "Import stops on a repository error",
+			repo: account.NewFakeRepository(
+				[]account.FakeRepositoryAcceptResult{
+					{
+						Account: &account.Account{ID: 1, Name: "john"},
+					},
+					{
+						Error: fmt.Errorf("Accept failed"),
+					},
+				},
+				nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+				nil,
+			),
+			records: []Record{john, jane, plain},
+			expectedResult: Result{
+				Imported: 1,
+			},
+			expectedError: true,
+		},
+	}
+
+	for i, c := range cases {
+		res, err := Import(c.repo, 1, c.records, time.Now())
+		if (err != nil) != c.expectedError {
+			t.Errorf("testcase %d %s: Expected error to be %v, but got %v\n", i, c.caseName, c.expectedError, err)
+		}
+		if !reflect.DeepEqual(res, c.expectedResult) {
+			t.Errorf("testcase %d %s: Expected result to be\n%#v\nbut got\n%#v\n", i, c.caseName, c.expectedResult, res)
+		}
+	}
+}
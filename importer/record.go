@@ -0,0 +1,107 @@
+package importer
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Record is an account to import, with the password hash made by another application.
+type Record struct {
+	// Line is the line number of the record in the source, starting from 1.
+	Line         int
+	Name         string `json:"name"`
+	PasswordHash string `json:"password_hash"`
+}
+
+const (
+	// FormatCSV is a CSV with a header, having name and password_hash columns.
+	FormatCSV = "csv"
+
+	// FormatJSONL is a JSON object per line, having name and password_hash fields.
+	FormatJSONL = "jsonl"
+)
+
+// Read reads all records from r in the format.
+func Read(r io.Reader, format string) ([]Record, error) {
+	switch format {
+	case FormatCSV:
+		return ReadCSV(r)
+	case FormatJSONL:
+		return ReadJSONL(r)
+	default:
+		return nil, fmt.Errorf("Unknown format %s, must be %s or %s", format, FormatCSV, FormatJSONL)
+	}
+}
+
+// ReadCSV reads all records from the CSV.
+// The first line is a header, which MUST have name and password_hash columns in any order.
+// Other columns are ignored.
+func ReadCSV(r io.Reader) ([]Record, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	nameIndex, hashIndex := -1, -1
+	for i, column := range header {
+		switch strings.TrimSpace(column) {
+		case "name":
+			nameIndex = i
+		case "password_hash":
+			hashIndex = i
+		}
+	}
+	if nameIndex == -1 || hashIndex == -1 {
+		return nil, fmt.Errorf("CSV header must have name and password_hash columns")
+	}
+
+	var records []Record
+	for line := 2; ; line++ {
+		row, err := cr.Read()
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if nameIndex >= len(row) || hashIndex >= len(row) {
+			return nil, fmt.Errorf("CSV line %d: missing columns", line)
+		}
+
+		records = append(records, Record{
+			Line:         line,
+			Name:         row[nameIndex],
+			PasswordHash: row[hashIndex],
+		})
+	}
+}
+
+// ReadJSONL reads all records from the JSON Lines. Empty lines are skipped.
+func ReadJSONL(r io.Reader) ([]Record, error) {
+	s := bufio.NewScanner(r)
+
+	var records []Record
+	for line := 1; s.Scan(); line++ {
+		if strings.TrimSpace(s.Text()) == "" {
+			continue
+		}
+
+		rec := Record{Line: line}
+		if err := json.Unmarshal(s.Bytes(), &rec); err != nil {
+			return nil, fmt.Errorf("JSONL line %d: %s", line, err)
+		}
+		records = append(records, rec)
+	}
+
+	return records, s.Err()
+}
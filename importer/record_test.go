@@ -0,0 +1,82 @@
+package importer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRead(t *testing.T) {
+	cases := []struct {
+		caseName string
+		// in
+		format string
+		input  string
+		// out
+		expectedRecords []Record
+		expectedError   bool
+	}{
+		{
+			caseName: "CSV",
+			format:   FormatCSV,
+			input: "email,name,password_hash\n" +
+				"john@example.com,john,pbkdf2_sha256$1000$seasalt$YIWkt6M1JFXrHg5s0jZjBSc7C2Cz6QvchSJ0h8Y+i7c=\n" +
+				"jane@example.com,jane,$apr1$saltsalt$yAAkm4libquA.ZWLHbSBq/\n",
+			expectedRecords: []Record{
+				{Line: 2, Name: "john", PasswordHash: "pbkdf2_sha256$1000$seasalt$YIWkt6M1JFXrHg5s0jZjBSc7C2Cz6QvchSJ0h8Y+i7c="},
+				{Line: 3, Name: "jane", PasswordHash: "$apr1$saltsalt$yAAkm4libquA.ZWLHbSBq/"},
+			},
+		},
+		{
+			caseName:      "CSV without password_hash column",
+			format:        FormatCSV,
+			input:         "name,password\njohn,secret\n",
+			expectedError: true,
+		},
+		{
+			caseName:      "CSV with missing columns",
+			format:        FormatCSV,
+			input:         "name,password_hash\njohn\n",
+			expectedError: true,
+		},
+		{
+			caseName:        "Empty CSV",
+			format:          FormatCSV,
+			input:           "",
+			expectedRecords: nil,
+		},
+		{
+			caseName: "JSONL",
+			format:   FormatJSONL,
+			input: `{"name":"john","password_hash":"$apr1$saltsalt$yAAkm4libquA.ZWLHbSBq/"}` + "\n" +
+				"\n" +
+				`{"name":"jane","password_hash":"$2y$05$abcdefghijklmnopqrstuu5Wl7Z5tgmOzDw3BvQK1sQbp0bn6lLLe"}`,
+			expectedRecords: []Record{
+				{Line: 1, Name: "john", PasswordHash: "$apr1$saltsalt$yAAkm4libquA.ZWLHbSBq/"},
+				{Line: 3, Name: "jane", PasswordHash: "$2y$05$abcdefghijklmnopqrstuu5Wl7Z5tgmOzDw3BvQK1sQbp0bn6lLLe"},
+			},
+		},
+		{
+			caseName:      "Invalid JSONL",
+			format:        FormatJSONL,
+			input:         `{"name":"john"` + "\n",
+			expectedError: true,
+		},
+		{
+			caseName:      "Unknown format",
+			format:        "xml",
+			input:         "<accounts/>",
+			expectedError: true,
+		},
+	}
+
+	for i, c := range cases {
+		records, err := Read(strings.NewReader(c.input), c.format)
+		if (err != nil) != c.expectedError {
+			t.Errorf("testcase %d %s: Expected error to be %v, but got %v\n", i, c.caseName, c.expectedError, err)
+		}
+		if !reflect.DeepEqual(records, c.expectedRecords) {
+			t.Errorf("testcase %d %s: Expected records to be\n%#v\nbut got\n%#v\n", i, c.caseName, c.expectedRecords, records)
+		}
+	}
+}
@@ -6,7 +6,11 @@ type BaseForm struct {
 }
 
 func (f *BaseForm) AddError(message, field string, value interface{}) {
-	f.errors = append(f.errors, FormError{message, field, value})
+	f.errors = append(f.errors, FormError{Message: message, Field: field, Value: value})
+}
+
+func (f *BaseForm) AddCodedError(code, message, field string, value interface{}) {
+	f.errors = append(f.errors, FormError{Message: message, Field: field, Value: value, Code: code})
 }
 
 func (f BaseForm) ValidationErrors() []FormError {
@@ -17,6 +17,18 @@ func TestBaseForm_AddError(t *testing.T) {
 	}
 }
 
+func TestBaseForm_AddCodedError(t *testing.T) {
+	err := FormError{Message: "Test error", Field: "field", Value: "value", Code: "test_error"}
+	expected := []FormError{err}
+
+	f := BaseForm{}
+	f.AddCodedError(err.Code, err.Message, err.Field, err.Value)
+
+	if !reflect.DeepEqual(expected, f.errors) {
+		t.Errorf("Expected %v but got %v\n", expected, f.errors)
+	}
+}
+
 func TestBaseForm_ValidationErrors(t *testing.T) {
 	expected := []FormError{{Message: "Test error", Field: "field", Value: "value"}}
 	f := BaseForm{errors: expected}
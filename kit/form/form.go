@@ -14,6 +14,10 @@ type Form interface {
 	// AddError reports an error occurred on form validation.
 	AddError(message, field string, value interface{})
 
+	// AddCodedError reports an error occurred on form validation,
+	// with a stable code clients can rely on.
+	AddCodedError(code, message, field string, value interface{})
+
 	// ValidationErrors returns the set of errors of the form.
 	ValidationErrors() []FormError
 }
@@ -23,6 +27,7 @@ type FormError struct {
 	Message string
 	Field   string
 	Value   interface{}
+	Code    string
 }
 
 // PopulateFormFromJSON populates the Form f from JSON body.
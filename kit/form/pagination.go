@@ -0,0 +1,62 @@
+package form
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+const (
+	// PaginationDefaultLimit is a limit used when a request does not specify one.
+	PaginationDefaultLimit = 20
+
+	// PaginationMaxLimit is a max limit a request can specify.
+	PaginationMaxLimit = 100
+)
+
+// PaginationForm is a form for requests to resources that respond with multiple objects.
+type PaginationForm struct {
+	BaseForm
+	Limit  int
+	Offset int
+}
+
+// PopulatePaginationFormFromQuery populates the PaginationForm f
+// from "limit" and "offset" query parameters.
+// Missing parameters are set to defaults.
+func PopulatePaginationFormFromQuery(query url.Values, f *PaginationForm) {
+	f.Limit = PaginationDefaultLimit
+	f.Offset = 0
+
+	if v := query.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil {
+			f.AddError("Limit must be an integer", "limit", v)
+		}
+		f.Limit = limit
+	}
+
+	if v := query.Get("offset"); v != "" {
+		offset, err := strconv.Atoi(v)
+		if err != nil {
+			f.AddError("Offset must be an integer", "offset", v)
+		}
+		f.Offset = offset
+	}
+}
+
+func (f *PaginationForm) Validate() bool {
+	if len(f.ValidationErrors()) != 0 {
+		return false
+	}
+
+	if f.Limit < 1 || f.Limit > PaginationMaxLimit {
+		f.AddError(fmt.Sprintf("Limit must be between 1 and %d", PaginationMaxLimit), "limit", f.Limit)
+	}
+
+	if f.Offset < 0 {
+		f.AddError("Offset must not be negative", "offset", f.Offset)
+	}
+
+	return len(f.ValidationErrors()) == 0
+}
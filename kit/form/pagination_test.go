@@ -0,0 +1,66 @@
+package form
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestPaginationForm(t *testing.T) {
+	cases := []struct {
+		query          url.Values
+		expectedLimit  int
+		expectedOffset int
+		expectedErrors []FormError
+	}{
+		{
+			query:          url.Values{},
+			expectedLimit:  PaginationDefaultLimit,
+			expectedOffset: 0,
+		},
+		{
+			query:          url.Values{"limit": {"5"}, "offset": {"10"}},
+			expectedLimit:  5,
+			expectedOffset: 10,
+		},
+		{
+			query:          url.Values{"limit": {"five"}},
+			expectedLimit:  0,
+			expectedOffset: 0,
+			expectedErrors: []FormError{
+				{Message: "Limit must be an integer", Field: "limit", Value: "five"},
+			},
+		},
+		{
+			query:          url.Values{"limit": {"1000"}, "offset": {"-1"}},
+			expectedLimit:  1000,
+			expectedOffset: -1,
+			expectedErrors: []FormError{
+				{Message: "Limit must be between 1 and 100", Field: "limit", Value: 1000},
+				{Message: "Offset must not be negative", Field: "offset", Value: -1},
+			},
+		},
+	}
+
+	for i, c := range cases {
+		f := &PaginationForm{}
+		PopulatePaginationFormFromQuery(c.query, f)
+		valid := f.Validate()
+
+		if f.Limit != c.expectedLimit {
+			t.Errorf("testcase %d: Expected Limit to be %v but got %v\n", i, c.expectedLimit, f.Limit)
+		}
+
+		if f.Offset != c.expectedOffset {
+			t.Errorf("testcase %d: Expected Offset to be %v but got %v\n", i, c.expectedOffset, f.Offset)
+		}
+
+		if valid != (len(c.expectedErrors) == 0) {
+			t.Errorf("testcase %d: Expected Validate to be %v but got %v\n", i, len(c.expectedErrors) == 0, valid)
+		}
+
+		if !reflect.DeepEqual(f.ValidationErrors(), c.expectedErrors) {
+			t.Errorf("testcase %d: Expected errors to be %v but got %v\n", i, c.expectedErrors, f.ValidationErrors())
+		}
+	}
+}
@@ -143,6 +143,16 @@ func TestRespondWithFormErrors(t *testing.T) {
 			},
 			expectedBody: []byte(`{"errors":[{"message":"A form error occured"}]}`),
 		},
+		{
+			errors: []form.FormError{
+				{
+					Message: "A form error occured",
+					Field:   "field",
+					Code:    "form_error",
+				},
+			},
+			expectedBody: []byte(`{"errors":[{"message":"A form error occured","field":"field","code":"form_error"}]}`),
+		},
 	}
 
 	for i, c := range cases {
@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/hypnoglow/pascont/kit"
+	"github.com/hypnoglow/pascont/kit/schema"
+)
+
+// AdminToken allows only requests authorized with the admin token
+// provided as a Bearer token. Other requests are served by otherwise,
+// e.g. to authorize them another way, or responded with 401 if it is nil.
+// If the admin token is empty, no request is authorized with it.
+func AdminToken(next http.Handler, adminToken string, otherwise http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		token := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+		if adminToken == "" || subtle.ConstantTimeCompare([]byte(token), []byte(adminToken)) != 1 {
+			if otherwise != nil {
+				otherwise.ServeHTTP(w, req)
+				return
+			}
+			kit.RespondWithError(w, http.StatusUnauthorized, schema.ErrorFromMessage(
+				"Admin authorization token provided is invalid.",
+			))
+			return
+		}
+
+		next.ServeHTTP(w, req)
+	})
+}
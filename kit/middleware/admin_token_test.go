@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAdminToken(t *testing.T) {
+	cases := []struct {
+		adminToken              string
+		authHeader              string
+		withOtherwise           bool
+		expectedCode            int
+		expectedCalled          bool
+		expectedOtherwiseCalled bool
+	}{
+		{
+			adminToken:     "secret",
+			authHeader:     "Bearer secret",
+			expectedCode:   http.StatusOK,
+			expectedCalled: true,
+		},
+		{
+			adminToken:     "secret",
+			authHeader:     "Bearer wrong",
+			expectedCode:   http.StatusUnauthorized,
+			expectedCalled: false,
+		},
+		{
+			adminToken:     "secret",
+			authHeader:     "",
+			expectedCode:   http.StatusUnauthorized,
+			expectedCalled: false,
+		},
+		{
+			adminToken:     "",
+			authHeader:     "Bearer ",
+			expectedCode:   http.StatusUnauthorized,
+			expectedCalled: false,
+		},
+		{
+			adminToken:              "secret",
+			authHeader:              "Bearer secret",
+			withOtherwise:           true,
+			expectedCode:            http.StatusOK,
+			expectedCalled:          true,
+			expectedOtherwiseCalled: false,
+		},
+		{
+			adminToken:              "secret",
+			authHeader:              "Bearer session_token",
+			withOtherwise:           true,
+			expectedCode:            http.StatusOK,
+			expectedCalled:          false,
+			expectedOtherwiseCalled: true,
+		},
+		{
+			adminToken:              "",
+			authHeader:              "Bearer ",
+			withOtherwise:           true,
+			expectedCode:            http.StatusOK,
+			expectedCalled:          false,
+			expectedOtherwiseCalled: true,
+		},
+	}
+
+	for i, c := range cases {
+		called := false
+		otherwiseCalled := false
+		var otherwise http.Handler
+		if c.withOtherwise {
+			otherwise = http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				otherwiseCalled = true
+			})
+		}
+		handler := AdminToken(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			called = true
+		}), c.adminToken, otherwise)
+
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/admin/accounts", nil)
+		if c.authHeader != "" {
+			req.Header.Set("Authorization", c.authHeader)
+		}
+		handler.ServeHTTP(w, req)
+
+		if w.Code != c.expectedCode {
+			t.Errorf("testcase %d: Expected status code to be %v but got %v\n", i, c.expectedCode, w.Code)
+		}
+
+		if called != c.expectedCalled {
+			t.Errorf("testcase %d: Expected handler to be called %v but got %v\n", i, c.expectedCalled, called)
+		}
+
+		if otherwiseCalled != c.expectedOtherwiseCalled {
+			t.Errorf("testcase %d: Expected otherwise handler to be called %v but got %v\n", i, c.expectedOtherwiseCalled, otherwiseCalled)
+		}
+	}
+}
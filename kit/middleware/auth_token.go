@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"context"
+	"log"
 	"net/http"
 	"strings"
 
@@ -13,7 +14,19 @@ import (
 
 type ContextKeySessionID struct{}
 
-func AuthToken(next http.Handler, extractor func(token string) (id string, err error)) http.Handler {
+type ContextKeyAccountID struct{}
+
+// AuthToken authorizes the request with the token provided as a Bearer token.
+// If keyAuthenticator finds an API key by the token, the account ID of the key
+// is put to the request context. Otherwise the token MUST be a session token,
+// and its session ID is put to the request context.
+// Authenticator errors are logged to the errorLogger.
+func AuthToken(
+	next http.Handler,
+	extractor func(token string) (id string, err error),
+	keyAuthenticator func(token string) (accountID int64, found bool, err error),
+	errorLogger *log.Logger,
+) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 		token := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
 		if token == "" {
@@ -21,6 +34,25 @@ func AuthToken(next http.Handler, extractor func(token string) (id string, err e
 			return
 		}
 
+		if keyAuthenticator != nil {
+			accountID, found, err := keyAuthenticator(token)
+			if err != nil {
+				errorLogger.Println(err)
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			if found {
+				req = req.WithContext(context.WithValue(
+					req.Context(),
+					ContextKeyAccountID{},
+					accountID,
+				))
+
+				next.ServeHTTP(w, req)
+				return
+			}
+		}
+
 		sessionID, err := extractor(token)
 		if err != nil || uuid.Parse(sessionID) == nil {
 			kit.RespondWithError(w, 401, schema.ErrorFromMessage(
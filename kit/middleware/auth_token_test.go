@@ -0,0 +1,105 @@
+package middleware
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAuthToken(t *testing.T) {
+	const sessionID = "12345678-90ab-cdef-0123-4567890abcde"
+
+	extractor := func(token string) (string, error) {
+		if token != "session_token" {
+			return "", fmt.Errorf("invalid token")
+		}
+		return sessionID, nil
+	}
+
+	cases := []struct {
+		caseName          string
+		token             string
+		keyAccountID      int64
+		keyFound          bool
+		keyError          error
+		expectedCode      int
+		expectedCalled    bool
+		expectedSessionID interface{}
+		expectedAccountID interface{}
+	}{
+		{
+			caseName:       "No token",
+			token:          "",
+			expectedCode:   http.StatusOK,
+			expectedCalled: true,
+		},
+		{
+			caseName:          "Session token",
+			token:             "session_token",
+			expectedCode:      http.StatusOK,
+			expectedCalled:    true,
+			expectedSessionID: sessionID,
+		},
+		{
+			caseName:       "Invalid token",
+			token:          "invalid_token",
+			expectedCode:   http.StatusUnauthorized,
+			expectedCalled: false,
+		},
+		{
+			caseName:          "API key",
+			token:             "api_key",
+			keyAccountID:      123,
+			keyFound:          true,
+			expectedCode:      http.StatusOK,
+			expectedCalled:    true,
+			expectedAccountID: int64(123),
+		},
+		{
+			caseName:       "Key authenticator failed",
+			token:          "api_key",
+			keyError:       fmt.Errorf("authenticate failed"),
+			expectedCode:   http.StatusInternalServerError,
+			expectedCalled: false,
+		},
+	}
+
+	for i, c := range cases {
+		called := false
+		var logs bytes.Buffer
+		keyAuthenticator := func(token string) (int64, bool, error) {
+			return c.keyAccountID, c.keyFound, c.keyError
+		}
+		handler := AuthToken(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			called = true
+			if sid := req.Context().Value(ContextKeySessionID{}); sid != c.expectedSessionID {
+				t.Errorf("testcase %d %s: Expected session ID to be %v but got %v\n", i, c.caseName, c.expectedSessionID, sid)
+			}
+			if accountID := req.Context().Value(ContextKeyAccountID{}); accountID != c.expectedAccountID {
+				t.Errorf("testcase %d %s: Expected account ID to be %v but got %v\n", i, c.caseName, c.expectedAccountID, accountID)
+			}
+		}), extractor, keyAuthenticator, log.New(&logs, "", 0))
+
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if c.token != "" {
+			req.Header.Set("Authorization", "Bearer "+c.token)
+		}
+		handler.ServeHTTP(w, req)
+
+		if w.Code != c.expectedCode {
+			t.Errorf("testcase %d %s: Expected status code to be %v but got %v\n", i, c.caseName, c.expectedCode, w.Code)
+		}
+
+		if called != c.expectedCalled {
+			t.Errorf("testcase %d %s: Expected handler to be called %v but got %v\n", i, c.caseName, c.expectedCalled, called)
+		}
+
+		if logged := logs.Len() > 0; logged != (c.keyError != nil) {
+			t.Errorf("testcase %d %s: Expected authenticator error to be logged %v but got %v\n", i, c.caseName, c.keyError != nil, logged)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+type ContextKeyPathParams struct{}
+
+// PathRoute binds a handler to a path pattern.
+// Pattern segments starting with a colon are parameters,
+// e.g. "/accounts/:id/sessions".
+type PathRoute struct {
+	Pattern string
+	Handler http.Handler
+}
+
+// PathRouter serves a request with the handler of the first route
+// which pattern matches the request path. Matched parameters are put
+// to the request context and can be retrieved with PathParam.
+// If no route matches, it responds with 404.
+func PathRouter(routes ...PathRoute) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		for _, route := range routes {
+			params, ok := matchPath(route.Pattern, req.URL.Path)
+			if !ok {
+				continue
+			}
+
+			req = req.WithContext(context.WithValue(
+				req.Context(),
+				ContextKeyPathParams{},
+				params,
+			))
+
+			route.Handler.ServeHTTP(w, req)
+			return
+		}
+
+		w.WriteHeader(http.StatusNotFound)
+	})
+}
+
+// PathParam returns a path parameter put to the request context by PathRouter.
+func PathParam(req *http.Request, name string) (value string, ok bool) {
+	params, ok := req.Context().Value(ContextKeyPathParams{}).(map[string]string)
+	if !ok {
+		return "", false
+	}
+
+	value, ok = params[name]
+	return value, ok
+}
+
+// matchPath matches the path against the pattern.
+// Parameters never match empty segments.
+func matchPath(pattern, path string) (params map[string]string, ok bool) {
+	patternSegments := strings.Split(strings.Trim(pattern, "/"), "/")
+	pathSegments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(patternSegments) != len(pathSegments) {
+		return nil, false
+	}
+
+	params = make(map[string]string)
+	for i, segment := range patternSegments {
+		if strings.HasPrefix(segment, ":") {
+			if pathSegments[i] == "" {
+				return nil, false
+			}
+			params[strings.TrimPrefix(segment, ":")] = pathSegments[i]
+			continue
+		}
+
+		if segment != pathSegments[i] {
+			return nil, false
+		}
+	}
+
+	return params, true
+}
@@ -0,0 +1,98 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestMatchPath(t *testing.T) {
+	cases := []struct {
+		pattern        string
+		path           string
+		expectedParams map[string]string
+		expectedOk     bool
+	}{
+		{
+			pattern:        "/accounts/:id/sessions",
+			path:           "/accounts/123/sessions",
+			expectedParams: map[string]string{"id": "123"},
+			expectedOk:     true,
+		},
+		{
+			pattern:        "/accounts/:id/sessions",
+			path:           "/accounts/123/sessions/",
+			expectedParams: map[string]string{"id": "123"},
+			expectedOk:     true,
+		},
+		{
+			pattern:    "/accounts/:id/sessions",
+			path:       "/accounts//sessions",
+			expectedOk: false,
+		},
+		{
+			pattern:    "/accounts/:id/sessions",
+			path:       "/accounts/123",
+			expectedOk: false,
+		},
+		{
+			pattern:    "/accounts/:id/sessions",
+			path:       "/accounts/123/password",
+			expectedOk: false,
+		},
+	}
+
+	for i, c := range cases {
+		params, ok := matchPath(c.pattern, c.path)
+
+		if ok != c.expectedOk {
+			t.Errorf("testcase %d: Expected ok to be %v but got %v\n", i, c.expectedOk, ok)
+		}
+
+		if ok && !reflect.DeepEqual(params, c.expectedParams) {
+			t.Errorf("testcase %d: Expected params to be %v but got %v\n", i, c.expectedParams, params)
+		}
+	}
+}
+
+func TestPathRouter(t *testing.T) {
+	var actualID string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		actualID, _ = PathParam(req, "id")
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	router := PathRouter(PathRoute{"/accounts/:id/sessions", handler})
+
+	cases := []struct {
+		path         string
+		expectedCode int
+		expectedID   string
+	}{
+		{
+			path:         "/accounts/123/sessions",
+			expectedCode: http.StatusNoContent,
+			expectedID:   "123",
+		},
+		{
+			path:         "/accounts/123",
+			expectedCode: http.StatusNotFound,
+			expectedID:   "",
+		},
+	}
+
+	for i, c := range cases {
+		actualID = ""
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, c.path, nil))
+
+		if w.Code != c.expectedCode {
+			t.Errorf("testcase %d: Expected status code to be %v but got %v\n", i, c.expectedCode, w.Code)
+		}
+
+		if actualID != c.expectedID {
+			t.Errorf("testcase %d: Expected id to be %v but got %v\n", i, c.expectedID, actualID)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/hypnoglow/pascont/kit"
+	"github.com/hypnoglow/pascont/kit/schema"
+)
+
+// Permission allows only requests authorized with a session
+// which account has the permission. It MUST run after AuthToken,
+// which puts the session ID to the request context.
+// Requests without a session are responded with 401,
+// requests without the permission are responded with 403.
+// Checker errors are logged to the errorLogger.
+func Permission(
+	next http.Handler,
+	permission string,
+	checker func(sessionID, permission string) (bool, error),
+	errorLogger *log.Logger,
+) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		sessionID, ok := req.Context().Value(ContextKeySessionID{}).(string)
+		if !ok {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		allowed, err := checker(sessionID, permission)
+		if err != nil {
+			errorLogger.Println(err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if !allowed {
+			kit.RespondWithError(w, http.StatusForbidden, schema.ErrorFromMessage(
+				"Permission denied: "+permission,
+			))
+			return
+		}
+
+		next.ServeHTTP(w, req)
+	})
+}
@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPermission(t *testing.T) {
+	cases := []struct {
+		caseName       string
+		sessionID      interface{}
+		checkerAllowed bool
+		checkerError   error
+		expectedCode   int
+		expectedCalled bool
+	}{
+		{
+			caseName:       "No session",
+			sessionID:      nil,
+			expectedCode:   http.StatusUnauthorized,
+			expectedCalled: false,
+		},
+		{
+			caseName:       "Checker failed",
+			sessionID:      "12345678-90ab-cdef-0123-4567890abcde",
+			checkerError:   fmt.Errorf("check failed"),
+			expectedCode:   http.StatusInternalServerError,
+			expectedCalled: false,
+		},
+		{
+			caseName:       "Permission denied",
+			sessionID:      "12345678-90ab-cdef-0123-4567890abcde",
+			checkerAllowed: false,
+			expectedCode:   http.StatusForbidden,
+			expectedCalled: false,
+		},
+		{
+			caseName:       "Permission granted",
+			sessionID:      "12345678-90ab-cdef-0123-4567890abcde",
+			checkerAllowed: true,
+			expectedCode:   http.StatusOK,
+			expectedCalled: true,
+		},
+	}
+
+	for i, c := range cases {
+		called := false
+		var logs bytes.Buffer
+		checker := func(sessionID, permission string) (bool, error) {
+			if sessionID != c.sessionID || permission != "accounts:manage" {
+				t.Errorf("testcase %d %s: Unexpected checker arguments %s, %s\n", i, c.caseName, sessionID, permission)
+			}
+			return c.checkerAllowed, c.checkerError
+		}
+		handler := Permission(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			called = true
+		}), "accounts:manage", checker, log.New(&logs, "", 0))
+
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req = req.WithContext(context.WithValue(req.Context(), ContextKeySessionID{}, c.sessionID))
+		handler.ServeHTTP(w, req)
+
+		if w.Code != c.expectedCode {
+			t.Errorf("testcase %d %s: Expected status code to be %v but got %v\n", i, c.caseName, c.expectedCode, w.Code)
+		}
+
+		if called != c.expectedCalled {
+			t.Errorf("testcase %d %s: Expected handler to be called %v but got %v\n", i, c.caseName, c.expectedCalled, called)
+		}
+
+		if logged := logs.Len() > 0; logged != (c.checkerError != nil) {
+			t.Errorf("testcase %d %s: Expected checker error to be logged %v but got %v\n", i, c.caseName, c.checkerError != nil, logged)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"context"
+	"log"
+	"net/http"
+
+	"github.com/hypnoglow/pascont/kit"
+	"github.com/hypnoglow/pascont/kit/schema"
+)
+
+type ContextKeyTenantID struct{}
+
+// Tenant resolves the tenant of the request by the Host header and puts
+// its ID to the request context. Requests for an unknown tenant are
+// responded with 404. Resolver errors are logged to the errorLogger.
+func Tenant(next http.Handler, resolver func(host string) (tenantID int64, found bool, err error), errorLogger *log.Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		tenantID, found, err := resolver(req.Host)
+		if err != nil {
+			errorLogger.Println(err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if !found {
+			kit.RespondWithError(w, http.StatusNotFound, schema.ErrorFromMessage(
+				"Unknown tenant.",
+			))
+			return
+		}
+
+		req = req.WithContext(context.WithValue(
+			req.Context(),
+			ContextKeyTenantID{},
+			tenantID,
+		))
+
+		next.ServeHTTP(w, req)
+	})
+}
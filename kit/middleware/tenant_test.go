@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTenant(t *testing.T) {
+	cases := []struct {
+		caseName         string
+		resolverTenantID int64
+		resolverFound    bool
+		resolverError    error
+		expectedCode     int
+		expectedCalled   bool
+	}{
+		{
+			caseName:       "Resolver failed",
+			resolverError:  fmt.Errorf("resolve failed"),
+			expectedCode:   http.StatusInternalServerError,
+			expectedCalled: false,
+		},
+		{
+			caseName:       "Unknown tenant",
+			resolverFound:  false,
+			expectedCode:   http.StatusNotFound,
+			expectedCalled: false,
+		},
+		{
+			caseName:         "Tenant resolved",
+			resolverTenantID: 2,
+			resolverFound:    true,
+			expectedCode:     http.StatusOK,
+			expectedCalled:   true,
+		},
+	}
+
+	for i, c := range cases {
+		called := false
+		var logs bytes.Buffer
+		resolver := func(host string) (int64, bool, error) {
+			if host != "acme.example.com" {
+				t.Errorf("testcase %d %s: Unexpected resolver argument %s\n", i, c.caseName, host)
+			}
+			return c.resolverTenantID, c.resolverFound, c.resolverError
+		}
+		handler := Tenant(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			called = true
+			if tenantID, _ := req.Context().Value(ContextKeyTenantID{}).(int64); tenantID != c.resolverTenantID {
+				t.Errorf("testcase %d %s: Expected tenant ID to be %v but got %v\n", i, c.caseName, c.resolverTenantID, tenantID)
+			}
+		}), resolver, log.New(&logs, "", 0))
+
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "http://acme.example.com/sessions", nil)
+		handler.ServeHTTP(w, req)
+
+		if w.Code != c.expectedCode {
+			t.Errorf("testcase %d %s: Expected status code to be %v but got %v\n", i, c.caseName, c.expectedCode, w.Code)
+		}
+
+		if called != c.expectedCalled {
+			t.Errorf("testcase %d %s: Expected handler to be called %v but got %v\n", i, c.caseName, c.expectedCalled, called)
+		}
+
+		if logged := logs.Len() > 0; logged != (c.resolverError != nil) {
+			t.Errorf("testcase %d %s: Expected resolver error to be logged %v but got %v\n", i, c.caseName, c.resolverError != nil, logged)
+		}
+	}
+}
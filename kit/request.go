@@ -0,0 +1,15 @@
+package kit
+
+import (
+	"net"
+	"net/http"
+)
+
+// ClientIP returns the IP address of the client that sent the request.
+func ClientIP(req *http.Request) string {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return req.RemoteAddr
+	}
+	return host
+}
@@ -4,6 +4,7 @@ type Error struct {
 	Message string      `json:"message"`
 	Field   string      `json:"field,omitempty"`
 	Value   interface{} `json:"value,omitempty"`
+	Code    string      `json:"code,omitempty"`
 }
 
 func NewError(message, field string, value interface{}) Error {
@@ -0,0 +1,12 @@
+package schema
+
+// PaginationMeta is a meta for resources that respond with multiple objects page by page.
+type PaginationMeta struct {
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+	Total  int `json:"total"`
+}
+
+func NewPaginationMeta(limit, offset, total int) PaginationMeta {
+	return PaginationMeta{limit, offset, total}
+}
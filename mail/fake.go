@@ -0,0 +1,24 @@
+package mail
+
+type fakeSender struct {
+	sendResults       []FakeSenderSendResult
+	sendResultCounter int
+}
+
+type FakeSenderSendResult struct {
+	Error error
+}
+
+// NewFakeSender returns a new fake Sender.
+func NewFakeSender(sendResults []FakeSenderSendResult) Sender {
+	return &fakeSender{
+		sendResults:       sendResults,
+		sendResultCounter: 0,
+	}
+}
+
+func (s *fakeSender) Send(m Message) error {
+	res := s.sendResults[s.sendResultCounter]
+	s.sendResultCounter++
+	return res.Error
+}
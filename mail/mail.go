@@ -0,0 +1,15 @@
+// Package mail sends emails to account holders.
+package mail
+
+// Message is an email message.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Sender sends emails.
+type Sender interface {
+	// Send sends the message.
+	Send(m Message) error
+}
@@ -0,0 +1,72 @@
+package mail
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	netmail "net/mail"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// smtpSender is a Sender which sends emails through an SMTP server.
+type smtpSender struct {
+	addr string
+	from string
+	auth smtp.Auth
+}
+
+// NewSMTPSender returns a new Sender which sends emails through the SMTP server at addr
+// on behalf of the from address. The connection is upgraded with STARTTLS if the server supports it.
+// auth may be nil for servers which do not require authentication.
+func NewSMTPSender(addr, from string, auth smtp.Auth) Sender {
+	return smtpSender{addr, from, auth}
+}
+
+func (s smtpSender) Send(m Message) error {
+	to, err := netmail.ParseAddress(m.To)
+	if err != nil {
+		return errors.Wrapf(err, "Invalid recipient %q", m.To)
+	}
+
+	msg, err := s.compose(to, m)
+	if err != nil {
+		return err
+	}
+
+	err = smtp.SendMail(s.addr, s.auth, s.from, []string{to.Address}, msg)
+	return errors.Wrap(err, "Failed to send an email")
+}
+
+// compose returns the message with headers, ready to be sent.
+func (s smtpSender) compose(to *netmail.Address, m Message) ([]byte, error) {
+	// Header values must not break the header, as it would allow to inject other headers.
+	if strings.ContainsAny(m.Subject, "\r\n") {
+		return nil, fmt.Errorf("Subject must not contain line breaks")
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", s.from)
+	fmt.Fprintf(&buf, "To: %s\r\n", to.String())
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", m.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n")
+	buf.WriteString("\r\n")
+
+	// Line breaks of the body are written as CRLF by the quoted-printable writer.
+	w := quotedprintable.NewWriter(&buf)
+	if _, err := w.Write([]byte(m.Body)); err != nil {
+		return nil, errors.Wrap(err, "Failed to encode an email body")
+	}
+	if err := w.Close(); err != nil {
+		return nil, errors.Wrap(err, "Failed to encode an email body")
+	}
+
+	return buf.Bytes(), nil
+}
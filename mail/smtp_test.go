@@ -0,0 +1,156 @@
+package mail
+
+import (
+	"bytes"
+	"io/ioutil"
+	"mime/quotedprintable"
+	"net"
+	netmail "net/mail"
+	"net/textproto"
+	"strings"
+	"testing"
+)
+
+// fakeSMTPServer is a minimal SMTP server which accepts a single mail.
+type fakeSMTPServer struct {
+	listener net.Listener
+	from     string
+	to       []string
+	data     []byte
+	done     chan struct{}
+}
+
+func newFakeSMTPServer(t *testing.T) *fakeSMTPServer {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %s", err)
+	}
+
+	s := &fakeSMTPServer{listener: l, done: make(chan struct{})}
+	go s.serve()
+	return s
+}
+
+func (s *fakeSMTPServer) Addr() string {
+	return s.listener.Addr().String()
+}
+
+func (s *fakeSMTPServer) Close() {
+	s.listener.Close()
+}
+
+func (s *fakeSMTPServer) serve() {
+	defer close(s.done)
+
+	conn, err := s.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	c := textproto.NewConn(conn)
+	c.PrintfLine("220 localhost ESMTP")
+	for {
+		line, err := c.ReadLine()
+		if err != nil {
+			return
+		}
+
+		cmd := strings.ToUpper(line)
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			c.PrintfLine("250 localhost")
+		case strings.HasPrefix(cmd, "MAIL FROM:"):
+			s.from = strings.Trim(line[len("MAIL FROM:"):], "<>")
+			c.PrintfLine("250 OK")
+		case strings.HasPrefix(cmd, "RCPT TO:"):
+			s.to = append(s.to, strings.Trim(line[len("RCPT TO:"):], "<>"))
+			c.PrintfLine("250 OK")
+		case cmd == "DATA":
+			c.PrintfLine("354 End data with <CR><LF>.<CR><LF>")
+			s.data, err = c.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			c.PrintfLine("250 OK")
+		case cmd == "QUIT":
+			c.PrintfLine("221 Bye")
+			return
+		default:
+			c.PrintfLine("502 Command not implemented")
+		}
+	}
+}
+
+func TestSMTPSender_Send(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	defer server.Close()
+
+	sender := NewSMTPSender(server.Addr(), "pascont@example.com", nil)
+	err := sender.Send(Message{
+		To:      "email@email.com",
+		Subject: "Verify your account",
+		Body:    "Use the token below:\n\nMTIzNDU2Nzg5MA==\n",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	<-server.done
+
+	if server.from != "pascont@example.com" {
+		t.Errorf("Expected sender to be %s, but got %s", "pascont@example.com", server.from)
+	}
+	if len(server.to) != 1 || server.to[0] != "email@email.com" {
+		t.Errorf("Expected recipients to be %v, but got %v", []string{"email@email.com"}, server.to)
+	}
+
+	msg, err := netmail.ReadMessage(bytes.NewReader(server.data))
+	if err != nil {
+		t.Fatalf("Failed to read the message: %s", err)
+	}
+	if subject := msg.Header.Get("Subject"); subject != "Verify your account" {
+		t.Errorf("Expected subject to be %q, but got %q", "Verify your account", subject)
+	}
+	if to := msg.Header.Get("To"); to != "<email@email.com>" {
+		t.Errorf("Expected To header to be %q, but got %q", "<email@email.com>", to)
+	}
+
+	body, err := ioutil.ReadAll(quotedprintable.NewReader(msg.Body))
+	if err != nil {
+		t.Fatalf("Failed to read the message body: %s", err)
+	}
+	expectedBody := "Use the token below:\n\nMTIzNDU2Nzg5MA==\n"
+	if string(body) != expectedBody {
+		t.Errorf("Expected body to be %q, but got %q", expectedBody, body)
+	}
+}
+
+func TestSMTPSender_Send_Invalid(t *testing.T) {
+	cases := []struct {
+		caseName string
+		message  Message
+	}{
+		{
+			caseName: "Invalid recipient",
+			message: Message{
+				To:      "not an email",
+				Subject: "Subject",
+			},
+		},
+		{
+			caseName: "Subject with a line break",
+			message: Message{
+				To:      "email@email.com",
+				Subject: "Subject\r\nBcc: other@email.com",
+			},
+		},
+	}
+
+	// Invalid messages are rejected before connecting to the server.
+	sender := NewSMTPSender("127.0.0.1:1", "pascont@example.com", nil)
+	for i, c := range cases {
+		if err := sender.Send(c.message); err == nil {
+			t.Errorf("testcase %d %s:\nExpected an error, but got nil", i, c.caseName)
+		}
+	}
+}
@@ -5,27 +5,51 @@ import (
 	"database/sql"
 	"encoding/hex"
 	"fmt"
+	"io/ioutil"
 	"log"
+	"net"
 	"net/http"
+	"net/smtp"
 	"net/url"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
+	"text/template"
 	"time"
 
 	_ "github.com/lib/pq"
 	"golang.org/x/crypto/bcrypt"
 
 	"github.com/hypnoglow/pascont/accounts"
+	"github.com/hypnoglow/pascont/admin"
+	"github.com/hypnoglow/pascont/apikey"
+	"github.com/hypnoglow/pascont/apikeys"
+	"github.com/hypnoglow/pascont/breach"
+	"github.com/hypnoglow/pascont/clock"
 	"github.com/hypnoglow/pascont/config"
 	"github.com/hypnoglow/pascont/hasher"
 	"github.com/hypnoglow/pascont/identity"
 	"github.com/hypnoglow/pascont/kit/middleware"
+	"github.com/hypnoglow/pascont/mail"
 	"github.com/hypnoglow/pascont/notary"
+	"github.com/hypnoglow/pascont/notifier"
+	"github.com/hypnoglow/pascont/outbox"
 	"github.com/hypnoglow/pascont/packer"
+	"github.com/hypnoglow/pascont/password"
 	"github.com/hypnoglow/pascont/postgres"
+	"github.com/hypnoglow/pascont/reset"
+	"github.com/hypnoglow/pascont/resets"
+	"github.com/hypnoglow/pascont/role"
+	"github.com/hypnoglow/pascont/sealer"
 	"github.com/hypnoglow/pascont/session"
 	"github.com/hypnoglow/pascont/sessions"
+	"github.com/hypnoglow/pascont/tenant"
+	"github.com/hypnoglow/pascont/twofactor"
+	"github.com/hypnoglow/pascont/verification"
+	"github.com/hypnoglow/pascont/verifications"
+	"github.com/hypnoglow/pascont/webhook"
+	"github.com/hypnoglow/pascont/webhooks"
 )
 
 const (
@@ -38,6 +62,26 @@ const (
 	ServerGracefulTimeout = time.Second * 5
 )
 
+// Notification delivery channels.
+const (
+	ChannelSMTP    = "smtp"
+	ChannelWebhook = "webhook"
+	ChannelLog     = "log"
+)
+
+// Notification delivery defaults, used when the config has no value.
+const (
+	DefaultWebhookTimeout     = time.Second * 10
+	DefaultWebhookCacheTTL    = time.Minute
+	DefaultOutboxBatchSize    = 100
+	DefaultOutboxLease        = time.Minute * 5
+	DefaultOutboxMaxAttempts  = 10
+	DefaultOutboxBackoff      = time.Second * 30
+	DefaultOutboxMaxBackoff   = time.Hour * 6
+	DefaultOutboxPollInterval = time.Second * 5
+	DefaultOutboxRetention    = time.Hour * 24 * 7
+)
+
 func main() {
 	errorLogger := log.New(os.Stderr, "", log.Lshortfile|log.LstdFlags)
 
@@ -49,43 +93,222 @@ func main() {
 	if err := db.Ping(); err != nil {
 		errorLogger.Println("WARNING: Failed to connect to the database at startup.")
 	}
+
+	// Commands other than the server.
+	if len(os.Args) > 1 {
+		var err error
+		switch os.Args[1] {
+		case "import":
+			err = runImport(db, os.Args[2:])
+		case "breach-index":
+			err = runBreachIndex(os.Args[2:])
+		default:
+			err = fmt.Errorf("Unknown command %s", os.Args[1])
+		}
+		if err != nil {
+			errorLogger.Fatalln(err)
+		}
+		return
+	}
+
 	sessionSecretKey := getValidSessionSecretKey(conf)
+	totpSealer := getSealer(conf.TOTP.EncryptionKey, "TOTP.EncryptionKey")
+	webhookSealer := getSealer(conf.Webhooks.EncryptionKey, "Webhooks.EncryptionKey")
+	resetSecretKey := getValidPasswordResetSecretKey(conf)
 
 	// Repositories and services.
 	accountRepo := postgres.NewAccountRepository(db)
 	sessionRepo := postgres.NewSessionRepository(db)
+	roleRepo := postgres.NewRoleRepository(db)
+	tenantRepo := postgres.NewTenantRepository(db)
+	apiKeyRepo := postgres.NewAPIKeyRepository(db)
+	totpRepo := postgres.NewTOTPRepository(db, totpSealer)
+	totpChallengeRepo := postgres.NewTOTPChallengeRepository(db)
+	recoveryRepo := postgres.NewRecoveryRepository(db)
+	resetRepo := postgres.NewResetRepository(db)
+	outboxRepo := postgres.NewOutboxRepository(db)
 	hmacNotary := notary.NewHMACNotary()
 	base64Packer := packer.NewBase64Packer(session.SessionIDLength + session.SessionExpiresAtLength)
-	bcryptHasher := hasher.NewBcryptHasher(bcrypt.DefaultCost)
+	passwordHasher := getPepperHasher(conf, hasher.NewMultiHasher(getHasher(conf)))
+	passwordPolicy := getPasswordPolicy(conf)
+	notifierChannels := getNotifierChannels(conf)
+	outboxRouter := getOutboxRouter(conf, notifierChannels)
+	// Subscriptions are listed for every event, so the list is cached.
+	webhookRepo := webhook.NewCachedRepository(
+		webhook.NewStaticRepository(
+			getWebhookSubscriptions(conf),
+			postgres.NewWebhookRepository(db, webhookSealer),
+		),
+		getWebhookCacheTTL(conf),
+		clock.Clock(time.Now),
+	)
+	eventEmitter := webhook.NewEmitter(webhookRepo, outboxRepo, identity.NewUUIDV4, clock.Clock(time.Now))
+	verificationPacker := packer.NewBase64Packer(verification.AccountIDLength + verification.ExpiresAtLength)
+	resetPacker := packer.NewBase64Packer(reset.IDLength + reset.ExpiresAtLength)
+
+	// Accounts are verified only if it is enabled.
+	var verificationSecretKey []byte
+	var verificationSender verification.Sender
+	if conf.Verification.Enabled {
+		verificationSecretKey = getValidVerificationSecretKey(conf)
+		verificationSender = verification.NewOutboxSender(outboxRepo, outboxRouter, clock.Clock(time.Now))
+	}
+
+	resetSender := reset.NewOutboxSender(
+		resetRepo,
+		identity.NewUUIDV4,
+		hmacNotary,
+		resetPacker,
+		resetSecretKey,
+		outboxRouter,
+		clock.Clock(time.Now),
+	)
 
 	// Controllers.
 	accs := accounts.NewRestController(
 		errorLogger,
 		accountRepo,
-		bcryptHasher,
-		accounts.Options{},
+		sessionRepo,
+		hmacNotary,
+		base64Packer,
+		passwordHasher,
+		identity.NewUUIDV4,
+		accounts.Options{
+			SessionSecretKey: sessionSecretKey,
+			SessionDuration:  sessions.SessionDefaultDuration,
+			LockoutThreshold: conf.Session.LockoutThreshold,
+			LockoutDuration:  getLockoutDuration(conf),
+			PasswordPolicy:   passwordPolicy,
+			Verification:     verificationSender,
+			Outbox:           outboxRouter,
+			Events:           eventEmitter,
+		},
 	)
 	sess := sessions.NewRestController(
 		errorLogger,
 		accountRepo,
 		sessionRepo,
+		roleRepo,
+		totpRepo,
+		totpChallengeRepo,
+		recoveryRepo,
 		hmacNotary,
 		base64Packer,
-		bcryptHasher,
+		passwordHasher,
 		identity.NewUUIDV4,
+		clock.Clock(time.Now),
 		sessions.Options{
 			SessionSecretKey: sessionSecretKey,
+			LockoutThreshold: conf.Session.LockoutThreshold,
+			LockoutDuration:  getLockoutDuration(conf),
+
+			Unverified:                getUnverifiedPolicy(conf),
+			UnverifiedSessionDuration: getUnverifiedSessionDuration(conf),
+
+			Outbox: outboxRouter,
+			Events: eventEmitter,
+		},
+	)
+
+	keys := apikeys.NewRestController(
+		errorLogger,
+		sessionRepo,
+		apiKeyRepo,
+		identity.NewUUIDV4,
+	)
+
+	twoFactor := twofactor.NewRestController(
+		errorLogger,
+		accountRepo,
+		sessionRepo,
+		totpRepo,
+		recoveryRepo,
+		passwordHasher,
+		clock.Clock(time.Now),
+		twofactor.Options{
+			Issuer: conf.TOTP.Issuer,
+		},
+	)
+
+	adm := admin.NewRestController(
+		errorLogger,
+		accountRepo,
+		sessionRepo,
+		roleRepo,
+		resetSender,
+		eventEmitter,
+	)
+
+	rsts := resets.NewRestController(
+		errorLogger,
+		accountRepo,
+		resetRepo,
+		hmacNotary,
+		resetPacker,
+		passwordHasher,
+		resetSender,
+		clock.Clock(time.Now),
+		resets.Options{
+			SecretKey:      resetSecretKey,
+			PasswordPolicy: passwordPolicy,
+			Outbox:         outboxRouter,
+			Events:         eventEmitter,
+		},
+	)
+
+	hooks := webhooks.NewRestController(
+		errorLogger,
+		webhookRepo,
+		outboxRepo,
+		identity.NewUUIDV4,
+		clock.Clock(time.Now),
+	)
+
+	verifs := verifications.NewRestController(
+		errorLogger,
+		accountRepo,
+		verificationSender,
+		hmacNotary,
+		verificationPacker,
+		clock.Clock(time.Now),
+		verifications.Options{
+			SecretKey: verificationSecretKey,
 		},
 	)
 
 	// Routing and middleware.
 
 	tokenExtractor := session.TokenExtractor(base64Packer, hmacNotary, sessionSecretKey)
+	tenantResolver := tenant.HostResolver(tenantRepo, conf.Tenant.Default)
+	// API keys are authenticated on all account routes, but only reading the account accepts them,
+	// other account routes require a session and forbid API keys.
+	apiKeyAuthenticator := apikey.Authenticator(apiKeyRepo, accountRepo, errorLogger)
+	permissionChecker := role.PermissionChecker(sessionRepo, roleRepo)
+	adminPermission := func(h http.Handler, permission role.Permission) http.Handler {
+		return middleware.Permission(h, string(permission), permissionChecker, errorLogger)
+	}
 
 	sessionsHander := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 		switch req.Method {
 		case http.MethodPost:
-			http.HandlerFunc(sess.PostSessions).ServeHTTP(w, req)
+			middleware.Tenant(
+				http.HandlerFunc(sess.PostSessions),
+				tenantResolver,
+				errorLogger,
+			).ServeHTTP(w, req)
+		default:
+			w.Header().Add("Allow", http.MethodPost)
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+	sessionsTOTPHandler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.Method {
+		case http.MethodPost:
+			middleware.Tenant(
+				http.HandlerFunc(sess.PostSessionsTOTP),
+				tenantResolver,
+				errorLogger,
+			).ServeHTTP(w, req)
 		default:
 			w.Header().Add("Allow", http.MethodPost)
 			w.WriteHeader(http.StatusMethodNotAllowed)
@@ -97,22 +320,385 @@ func main() {
 			middleware.AuthToken(
 				http.HandlerFunc(sess.GetSession),
 				tokenExtractor,
+				nil,
+				errorLogger,
 			).ServeHTTP(w, req)
 		case http.MethodPatch:
 			middleware.AuthToken(
 				http.HandlerFunc(sess.PatchSession),
 				tokenExtractor,
+				nil,
+				errorLogger,
+			).ServeHTTP(w, req)
+		case http.MethodDelete:
+			middleware.AuthToken(
+				http.HandlerFunc(sess.DeleteSession),
+				tokenExtractor,
+				nil,
+				errorLogger,
 			).ServeHTTP(w, req)
 		default:
 			w.Header().Add("Allow", http.MethodGet)
 			w.Header().Add("Allow", http.MethodPatch)
+			w.Header().Add("Allow", http.MethodDelete)
 			w.WriteHeader(http.StatusMethodNotAllowed)
 		}
 	})
 	accountsHandler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 		switch req.Method {
 		case http.MethodPost:
-			http.HandlerFunc(accs.PostAccounts).ServeHTTP(w, req)
+			middleware.Tenant(
+				http.HandlerFunc(accs.PostAccounts),
+				tenantResolver,
+				errorLogger,
+			).ServeHTTP(w, req)
+		default:
+			w.Header().Add("Allow", http.MethodPost)
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+	accountSessionsHandler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.Method {
+		case http.MethodGet:
+			middleware.AuthToken(
+				http.HandlerFunc(sess.GetAccountSessions),
+				tokenExtractor,
+				apiKeyAuthenticator,
+				errorLogger,
+			).ServeHTTP(w, req)
+		case http.MethodDelete:
+			middleware.AuthToken(
+				http.HandlerFunc(sess.DeleteAccountSessions),
+				tokenExtractor,
+				apiKeyAuthenticator,
+				errorLogger,
+			).ServeHTTP(w, req)
+		default:
+			w.Header().Add("Allow", http.MethodGet)
+			w.Header().Add("Allow", http.MethodDelete)
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+	accountSessionHandler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.Method {
+		case http.MethodDelete:
+			middleware.AuthToken(
+				http.HandlerFunc(sess.DeleteAccountSession),
+				tokenExtractor,
+				apiKeyAuthenticator,
+				errorLogger,
+			).ServeHTTP(w, req)
+		default:
+			w.Header().Add("Allow", http.MethodDelete)
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+	accountHandler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.Method {
+		case http.MethodGet:
+			middleware.AuthToken(
+				http.HandlerFunc(accs.GetAccount),
+				tokenExtractor,
+				apiKeyAuthenticator,
+				errorLogger,
+			).ServeHTTP(w, req)
+		case http.MethodDelete:
+			middleware.AuthToken(
+				http.HandlerFunc(accs.DeleteAccount),
+				tokenExtractor,
+				apiKeyAuthenticator,
+				errorLogger,
+			).ServeHTTP(w, req)
+		default:
+			w.Header().Add("Allow", http.MethodGet)
+			w.Header().Add("Allow", http.MethodDelete)
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+	accountPasswordHandler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.Method {
+		case http.MethodPatch:
+			middleware.AuthToken(
+				http.HandlerFunc(accs.PatchAccountPassword),
+				tokenExtractor,
+				apiKeyAuthenticator,
+				errorLogger,
+			).ServeHTTP(w, req)
+		default:
+			w.Header().Add("Allow", http.MethodPatch)
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+	accountAPIKeysHandler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.Method {
+		case http.MethodGet:
+			middleware.AuthToken(
+				http.HandlerFunc(keys.GetAPIKeys),
+				tokenExtractor,
+				apiKeyAuthenticator,
+				errorLogger,
+			).ServeHTTP(w, req)
+		case http.MethodPost:
+			middleware.AuthToken(
+				http.HandlerFunc(keys.PostAPIKeys),
+				tokenExtractor,
+				apiKeyAuthenticator,
+				errorLogger,
+			).ServeHTTP(w, req)
+		default:
+			w.Header().Add("Allow", http.MethodGet)
+			w.Header().Add("Allow", http.MethodPost)
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+	accountAPIKeyHandler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.Method {
+		case http.MethodGet:
+			middleware.AuthToken(
+				http.HandlerFunc(keys.GetAPIKey),
+				tokenExtractor,
+				apiKeyAuthenticator,
+				errorLogger,
+			).ServeHTTP(w, req)
+		case http.MethodDelete:
+			middleware.AuthToken(
+				http.HandlerFunc(keys.DeleteAPIKey),
+				tokenExtractor,
+				apiKeyAuthenticator,
+				errorLogger,
+			).ServeHTTP(w, req)
+		default:
+			w.Header().Add("Allow", http.MethodGet)
+			w.Header().Add("Allow", http.MethodDelete)
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+	accountTOTPHandler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.Method {
+		case http.MethodPost:
+			middleware.AuthToken(
+				http.HandlerFunc(twoFactor.PostTOTP),
+				tokenExtractor,
+				apiKeyAuthenticator,
+				errorLogger,
+			).ServeHTTP(w, req)
+		case http.MethodDelete:
+			middleware.AuthToken(
+				http.HandlerFunc(twoFactor.DeleteTOTP),
+				tokenExtractor,
+				apiKeyAuthenticator,
+				errorLogger,
+			).ServeHTTP(w, req)
+		default:
+			w.Header().Add("Allow", http.MethodPost)
+			w.Header().Add("Allow", http.MethodDelete)
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+	accountTOTPConfirmHandler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.Method {
+		case http.MethodPost:
+			middleware.AuthToken(
+				http.HandlerFunc(twoFactor.PostTOTPConfirm),
+				tokenExtractor,
+				apiKeyAuthenticator,
+				errorLogger,
+			).ServeHTTP(w, req)
+		default:
+			w.Header().Add("Allow", http.MethodPost)
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+	accountTOTPRecoveryHandler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.Method {
+		case http.MethodPost:
+			middleware.AuthToken(
+				http.HandlerFunc(twoFactor.PostRecoveryCodes),
+				tokenExtractor,
+				apiKeyAuthenticator,
+				errorLogger,
+			).ServeHTTP(w, req)
+		default:
+			w.Header().Add("Allow", http.MethodPost)
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+	passwordResetsHandler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.Method {
+		case http.MethodPost:
+			middleware.Tenant(
+				http.HandlerFunc(rsts.PostPasswordResets),
+				tenantResolver,
+				errorLogger,
+			).ServeHTTP(w, req)
+		default:
+			w.Header().Add("Allow", http.MethodPost)
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+	passwordResetHandler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.Method {
+		case http.MethodPost:
+			middleware.Tenant(
+				http.HandlerFunc(rsts.PostPasswordReset),
+				tenantResolver,
+				errorLogger,
+			).ServeHTTP(w, req)
+		default:
+			w.Header().Add("Allow", http.MethodPost)
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+	verificationsHandler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.Method {
+		case http.MethodPost:
+			middleware.Tenant(
+				http.HandlerFunc(verifs.PostVerifications),
+				tenantResolver,
+				errorLogger,
+			).ServeHTTP(w, req)
+		default:
+			w.Header().Add("Allow", http.MethodPost)
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+	verificationHandler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.Method {
+		case http.MethodGet, http.MethodPost:
+			middleware.Tenant(
+				http.HandlerFunc(verifs.PostVerification),
+				tenantResolver,
+				errorLogger,
+			).ServeHTTP(w, req)
+		default:
+			w.Header().Add("Allow", http.MethodGet)
+			w.Header().Add("Allow", http.MethodPost)
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+
+	adminAccountsHandler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.Method {
+		case http.MethodGet:
+			http.HandlerFunc(adm.GetAccounts).ServeHTTP(w, req)
+		default:
+			w.Header().Add("Allow", http.MethodGet)
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+	adminAccountHandler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.Method {
+		case http.MethodGet:
+			http.HandlerFunc(adm.GetAccount).ServeHTTP(w, req)
+		default:
+			w.Header().Add("Allow", http.MethodGet)
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+	adminAccountStatusHandler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.Method {
+		case http.MethodPut:
+			http.HandlerFunc(adm.PutAccountStatus).ServeHTTP(w, req)
+		default:
+			w.Header().Add("Allow", http.MethodPut)
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+	adminAccountPasswordResetHandler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.Method {
+		case http.MethodPost:
+			http.HandlerFunc(adm.PostAccountPasswordReset).ServeHTTP(w, req)
+		default:
+			w.Header().Add("Allow", http.MethodPost)
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+	adminAccountSessionsHandler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.Method {
+		case http.MethodDelete:
+			http.HandlerFunc(adm.DeleteAccountSessions).ServeHTTP(w, req)
+		default:
+			w.Header().Add("Allow", http.MethodDelete)
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+
+	adminAccountRolesHandler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.Method {
+		case http.MethodGet:
+			http.HandlerFunc(adm.GetAccountRoles).ServeHTTP(w, req)
+		default:
+			w.Header().Add("Allow", http.MethodGet)
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+	adminAccountRoleHandler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.Method {
+		case http.MethodPut:
+			http.HandlerFunc(adm.PutAccountRole).ServeHTTP(w, req)
+		case http.MethodDelete:
+			http.HandlerFunc(adm.DeleteAccountRole).ServeHTTP(w, req)
+		default:
+			w.Header().Add("Allow", http.MethodPut)
+			w.Header().Add("Allow", http.MethodDelete)
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+	adminRolesHandler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.Method {
+		case http.MethodGet:
+			http.HandlerFunc(adm.GetRoles).ServeHTTP(w, req)
+		default:
+			w.Header().Add("Allow", http.MethodGet)
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+	adminRoleHandler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.Method {
+		case http.MethodPut:
+			http.HandlerFunc(adm.PutRole).ServeHTTP(w, req)
+		default:
+			w.Header().Add("Allow", http.MethodPut)
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+	adminWebhooksHandler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.Method {
+		case http.MethodGet:
+			http.HandlerFunc(hooks.GetWebhooks).ServeHTTP(w, req)
+		case http.MethodPost:
+			http.HandlerFunc(hooks.PostWebhooks).ServeHTTP(w, req)
+		default:
+			w.Header().Add("Allow", http.MethodGet)
+			w.Header().Add("Allow", http.MethodPost)
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+	adminWebhookHandler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.Method {
+		case http.MethodGet:
+			http.HandlerFunc(hooks.GetWebhook).ServeHTTP(w, req)
+		case http.MethodDelete:
+			http.HandlerFunc(hooks.DeleteWebhook).ServeHTTP(w, req)
+		default:
+			w.Header().Add("Allow", http.MethodGet)
+			w.Header().Add("Allow", http.MethodDelete)
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+	adminWebhookDeliveriesHandler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.Method {
+		case http.MethodGet:
+			http.HandlerFunc(hooks.GetWebhookDeliveries).ServeHTTP(w, req)
+		default:
+			w.Header().Add("Allow", http.MethodGet)
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+	adminWebhookDeliveryReplayHandler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.Method {
+		case http.MethodPost:
+			http.HandlerFunc(hooks.PostWebhookDeliveryReplay).ServeHTTP(w, req)
 		default:
 			w.Header().Add("Allow", http.MethodPost)
 			w.WriteHeader(http.StatusMethodNotAllowed)
@@ -121,8 +707,65 @@ func main() {
 
 	mux := http.NewServeMux()
 	mux.Handle(sessions.PathSessions, sessionsHander)
+	mux.Handle(sessions.PathSessionsTOTP, sessionsTOTPHandler)
 	mux.Handle(sessions.PathSession, middleware.PathID(sessionHandler, sessions.PathSession))
 	mux.Handle(accounts.PathAccounts, accountsHandler)
+	mux.Handle(accounts.PathAccount, middleware.PathRouter(
+		middleware.PathRoute{Pattern: accounts.PathAccountByID, Handler: accountHandler},
+		middleware.PathRoute{Pattern: sessions.PathAccountSessions, Handler: accountSessionsHandler},
+		middleware.PathRoute{Pattern: sessions.PathAccountSession, Handler: accountSessionHandler},
+		middleware.PathRoute{Pattern: accounts.PathAccountPassword, Handler: accountPasswordHandler},
+		middleware.PathRoute{Pattern: apikeys.PathAccountAPIKeys, Handler: accountAPIKeysHandler},
+		middleware.PathRoute{Pattern: apikeys.PathAccountAPIKey, Handler: accountAPIKeyHandler},
+		middleware.PathRoute{Pattern: twofactor.PathAccountTOTP, Handler: accountTOTPHandler},
+		middleware.PathRoute{Pattern: twofactor.PathAccountTOTPConfirm, Handler: accountTOTPConfirmHandler},
+		middleware.PathRoute{Pattern: twofactor.PathAccountTOTPRecovery, Handler: accountTOTPRecoveryHandler},
+	))
+	mux.Handle(resets.PathPasswordResets, passwordResetsHandler)
+	mux.Handle(resets.PathPasswordReset, middleware.PathRouter(
+		middleware.PathRoute{Pattern: resets.PathPasswordResetByToken, Handler: passwordResetHandler},
+	))
+	if conf.Verification.Enabled {
+		mux.Handle(verifications.PathVerifications, verificationsHandler)
+		mux.Handle(verifications.PathVerification, middleware.PathRouter(
+			middleware.PathRoute{Pattern: verifications.PathVerificationByToken, Handler: verificationHandler},
+		))
+	}
+	mux.Handle(admin.PathAdmin, middleware.AdminToken(
+		middleware.PathRouter(
+			middleware.PathRoute{Pattern: admin.PathAccounts, Handler: adminAccountsHandler},
+			middleware.PathRoute{Pattern: admin.PathAccount, Handler: adminAccountHandler},
+			middleware.PathRoute{Pattern: admin.PathAccountStatus, Handler: adminAccountStatusHandler},
+			middleware.PathRoute{Pattern: admin.PathAccountPasswordReset, Handler: adminAccountPasswordResetHandler},
+			middleware.PathRoute{Pattern: admin.PathAccountSessions, Handler: adminAccountSessionsHandler},
+			middleware.PathRoute{Pattern: admin.PathAccountRoles, Handler: adminAccountRolesHandler},
+			middleware.PathRoute{Pattern: admin.PathAccountRole, Handler: adminAccountRoleHandler},
+			middleware.PathRoute{Pattern: admin.PathRoles, Handler: adminRolesHandler},
+			middleware.PathRoute{Pattern: admin.PathRole, Handler: adminRoleHandler},
+			middleware.PathRoute{Pattern: webhooks.PathWebhooks, Handler: adminWebhooksHandler},
+			middleware.PathRoute{Pattern: webhooks.PathWebhook, Handler: adminWebhookHandler},
+			middleware.PathRoute{Pattern: webhooks.PathWebhookDeliveries, Handler: adminWebhookDeliveriesHandler},
+			middleware.PathRoute{Pattern: webhooks.PathWebhookDeliveryReplay, Handler: adminWebhookDeliveryReplayHandler},
+		),
+		conf.Admin.Token,
+		// Roles can also be managed by accounts with the permissions, authorized with a session,
+		// but only for accounts of the tenant of the session.
+		middleware.AuthToken(
+			session.Tenant(
+				middleware.PathRouter(
+					middleware.PathRoute{Pattern: admin.PathAccountRoles, Handler: adminPermission(adminAccountRolesHandler, role.PermissionRolesRead)},
+					middleware.PathRoute{Pattern: admin.PathAccountRole, Handler: adminPermission(adminAccountRoleHandler, role.PermissionRolesWrite)},
+					middleware.PathRoute{Pattern: admin.PathRoles, Handler: adminPermission(adminRolesHandler, role.PermissionRolesRead)},
+					middleware.PathRoute{Pattern: admin.PathRole, Handler: adminPermission(adminRoleHandler, role.PermissionRolesWrite)},
+				),
+				sessionRepo,
+				errorLogger,
+			),
+			tokenExtractor,
+			nil,
+			errorLogger,
+		),
+	))
 	handler := middleware.Recover(mux, errorLogger)
 	handler = middleware.Logger(handler, log.New(os.Stdout, "", log.LstdFlags))
 
@@ -134,6 +777,33 @@ func main() {
 	stop := make(chan os.Signal)
 	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
 
+	// Notifications and webhook events are delivered from the outbox in background.
+	// Webhook events are not routed as notifications, so their channel is only known to the dispatcher.
+	dispatcherChannels := map[string]notifier.Notifier{
+		webhook.Channel: webhook.NewNotifier(webhookRepo, hmacNotary, getWebhookClient(conf), clock.Clock(time.Now)),
+	}
+	// Tokens are not kept in the outbox, they are signed again when notifications are delivered.
+	for name, n := range notifierChannels {
+		n = reset.NewTokenNotifier(n, hmacNotary, resetPacker, resetSecretKey)
+		if conf.Verification.Enabled {
+			n = verification.NewTokenNotifier(n, hmacNotary, verificationPacker, verificationSecretKey)
+		}
+		dispatcherChannels[name] = n
+	}
+	dispatcher := outbox.NewDispatcher(
+		outboxRepo,
+		dispatcherChannels,
+		errorLogger,
+		clock.Clock(time.Now),
+		getDispatcherOptions(conf),
+	)
+	stopDispatcher := make(chan struct{})
+	dispatcherStopped := make(chan struct{})
+	go func() {
+		dispatcher.Run(stopDispatcher)
+		close(dispatcherStopped)
+	}()
+
 	go func() {
 		errorLogger.Printf("Listen on %s\n", socket)
 		if err := httpServer.ListenAndServe(); err != nil {
@@ -146,6 +816,9 @@ func main() {
 	errorLogger.Printf("Shutdown server...\n")
 	ctx, _ := context.WithTimeout(context.Background(), ServerGracefulTimeout)
 	httpServer.Shutdown(ctx)
+
+	close(stopDispatcher)
+	<-dispatcherStopped
 }
 
 func getConfig() (conf config.Config) {
@@ -176,6 +849,441 @@ func getValidSessionSecretKey(conf config.Config) (key []byte) {
 	return key
 }
 
+func getValidPasswordResetSecretKey(conf config.Config) (key []byte) {
+	key, err := hex.DecodeString(conf.PasswordReset.SecretKey)
+	if err != nil {
+		panic(err)
+	}
+	// The reset key MUST differ from the session one, otherwise a reset token
+	// could be presented as a session token and vice versa.
+	if len(key) != 16 {
+		panic("config's PasswordReset.SecretKey MUST be 16 bytes long")
+	}
+	if hex.EncodeToString(key) == strings.ToLower(conf.Session.SecretKey) {
+		panic("config's PasswordReset.SecretKey MUST differ from Session.SecretKey")
+	}
+
+	return key
+}
+
+func getValidVerificationSecretKey(conf config.Config) (key []byte) {
+	key, err := hex.DecodeString(conf.Verification.SecretKey)
+	if err != nil {
+		panic(err)
+	}
+	if len(key) != 16 {
+		panic("config's Verification.SecretKey MUST be 16 bytes long")
+	}
+	if hex.EncodeToString(key) == strings.ToLower(conf.Session.SecretKey) {
+		panic("config's Verification.SecretKey MUST differ from Session.SecretKey")
+	}
+
+	return key
+}
+
+func getUnverifiedPolicy(conf config.Config) sessions.UnverifiedPolicy {
+	if !conf.Verification.Enabled {
+		return sessions.UnverifiedAllow
+	}
+
+	switch p := sessions.UnverifiedPolicy(conf.Verification.Unverified); p {
+	case sessions.UnverifiedBlock, sessions.UnverifiedLimit:
+		return p
+	default:
+		panic("config's Verification.Unverified MUST be one of: block, limit")
+	}
+}
+
+func getUnverifiedSessionDuration(conf config.Config) time.Duration {
+	if getUnverifiedPolicy(conf) != sessions.UnverifiedLimit {
+		return 0
+	}
+
+	d, err := time.ParseDuration(conf.Verification.UnverifiedSessionDuration)
+	if err != nil {
+		panic(err)
+	}
+	if d <= 0 {
+		panic("config's Verification.UnverifiedSessionDuration MUST be positive when unverified accounts are limited")
+	}
+
+	return d
+}
+
+// getNotifierChannels returns the channels delivering notifications to account owners by their names:
+// "smtp" sends emails through the SMTP server from the config, if it is set,
+// "webhook" posts notifications to the URL from the config, if it is set,
+// and "log" writes notifications to the file from the config, or to stdout.
+func getNotifierChannels(conf config.Config) map[string]notifier.Notifier {
+	channels := map[string]notifier.Notifier{}
+
+	if c := conf.Notifier.SMTP; c.Addr != "" {
+		var auth smtp.Auth
+		if c.Username != "" {
+			host, _, err := net.SplitHostPort(c.Addr)
+			if err != nil {
+				panic(err)
+			}
+			auth = smtp.PlainAuth("", c.Username, c.Password, host)
+		}
+
+		channels[ChannelSMTP] = notifier.NewMailNotifier(mail.NewSMTPSender(c.Addr, c.From, auth), getMailTemplates(conf))
+	}
+
+	if c := conf.Notifier.Webhook; c.URL != "" {
+		timeout := DefaultWebhookTimeout
+		if c.Timeout != "" {
+			timeout = parsePositiveDuration(c.Timeout, "Notifier.Webhook.Timeout")
+		}
+
+		channels[ChannelWebhook] = notifier.NewWebhookNotifier(c.URL, &http.Client{Timeout: timeout})
+	}
+
+	if conf.Notifier.File == "" {
+		channels[ChannelLog] = notifier.NewWriterNotifier(os.Stdout)
+		return channels
+	}
+
+	// The file is kept open while the server runs.
+	f, err := os.OpenFile(conf.Notifier.File, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		panic(err)
+	}
+	channels[ChannelLog] = notifier.NewWriterNotifier(f)
+
+	return channels
+}
+
+// getMailTemplates returns the email templates from the config by notification kind.
+// Bodies are read from the files at the configured paths.
+func getMailTemplates(conf config.Config) map[string]notifier.MailTemplate {
+	templates := make(map[string]notifier.MailTemplate, len(conf.Notifier.Templates))
+	for kind, c := range conf.Notifier.Templates {
+		if c.Subject == "" || c.BodyPath == "" {
+			panic(fmt.Sprintf("config's Notifier.Templates.%s MUST have both Subject and BodyPath", kind))
+		}
+
+		b, err := ioutil.ReadFile(c.BodyPath)
+		if err != nil {
+			panic(err)
+		}
+
+		templates[kind] = notifier.MailTemplate{
+			Subject: c.Subject,
+			Body:    template.Must(template.New(kind).Parse(string(b))),
+		}
+	}
+
+	return templates
+}
+
+// getOutboxRouter returns the router of notifications to the channels.
+// Without configured default channels, notifications are sent by email if SMTP is set,
+// or logged otherwise. Notifications with tokens are logged only if they are routed to the log explicitly.
+func getOutboxRouter(conf config.Config, channels map[string]notifier.Notifier) outbox.Router {
+	defaults := conf.Outbox.DefaultChannels
+	if len(defaults) == 0 {
+		defaults = []string{ChannelLog}
+		if _, ok := channels[ChannelSMTP]; ok {
+			defaults = []string{ChannelSMTP}
+		}
+	}
+
+	names := append([]string{}, defaults...)
+	for _, routed := range conf.Outbox.Routes {
+		names = append(names, routed...)
+	}
+	for _, name := range names {
+		if _, ok := channels[name]; !ok {
+			panic(fmt.Sprintf("config's Outbox refers to channel %s, which is not configured", name))
+		}
+	}
+
+	routes := map[string][]string{}
+	for kind, routed := range conf.Outbox.Routes {
+		routes[kind] = routed
+	}
+	for _, kind := range []string{notifier.KindPasswordReset, notifier.KindVerification} {
+		if _, ok := routes[kind]; ok {
+			continue
+		}
+
+		routes[kind] = []string{}
+		for _, name := range defaults {
+			if name != ChannelLog {
+				routes[kind] = append(routes[kind], name)
+			}
+		}
+	}
+
+	return outbox.NewRouter(routes, defaults, identity.NewUUIDV4, clock.Clock(time.Now))
+}
+
+func getDispatcherOptions(conf config.Config) outbox.DispatcherOptions {
+	opts := outbox.DispatcherOptions{
+		BatchSize:    DefaultOutboxBatchSize,
+		Lease:        DefaultOutboxLease,
+		MaxAttempts:  DefaultOutboxMaxAttempts,
+		Backoff:      DefaultOutboxBackoff,
+		MaxBackoff:   DefaultOutboxMaxBackoff,
+		PollInterval: DefaultOutboxPollInterval,
+		Retention:    DefaultOutboxRetention,
+	}
+
+	c := conf.Outbox
+	if c.BatchSize < 0 || c.MaxAttempts < 0 {
+		panic("config's Outbox.BatchSize and Outbox.MaxAttempts MUST NOT be negative")
+	}
+	if c.BatchSize > 0 {
+		opts.BatchSize = c.BatchSize
+	}
+	if c.MaxAttempts > 0 {
+		opts.MaxAttempts = c.MaxAttempts
+	}
+	if c.Lease != "" {
+		opts.Lease = parsePositiveDuration(c.Lease, "Outbox.Lease")
+	}
+	if c.Backoff != "" {
+		opts.Backoff = parsePositiveDuration(c.Backoff, "Outbox.Backoff")
+	}
+	if c.MaxBackoff != "" {
+		opts.MaxBackoff = parsePositiveDuration(c.MaxBackoff, "Outbox.MaxBackoff")
+	}
+	if c.PollInterval != "" {
+		opts.PollInterval = parsePositiveDuration(c.PollInterval, "Outbox.PollInterval")
+	}
+	if c.Retention != "" {
+		opts.Retention = parsePositiveDuration(c.Retention, "Outbox.Retention")
+	}
+
+	return opts
+}
+
+// parsePositiveDuration parses the duration of the config option with the name.
+func parsePositiveDuration(s string, name string) time.Duration {
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		panic(err)
+	}
+	if d <= 0 {
+		panic(fmt.Sprintf("config's %s MUST be positive", name))
+	}
+
+	return d
+}
+
+// getSealer returns the sealer encrypting secrets with the hex key of the config option with the name.
+func getSealer(hexKey string, name string) sealer.Sealer {
+	key, err := hex.DecodeString(hexKey)
+	if err != nil {
+		panic(err)
+	}
+	// Secrets are encrypted with AES, so the key MUST be 128, 192 or 256 bit key,
+	// generated with a cryptographically secure pseudo random number generator (CSPRNG).
+	if len(key) != 16 && len(key) != 24 && len(key) != 32 {
+		panic(fmt.Sprintf("config's %s MUST be 16, 24 or 32 bytes long", name))
+	}
+
+	s, err := sealer.NewAESGCMSealer(key)
+	if err != nil {
+		panic(err)
+	}
+
+	return s
+}
+
+// getWebhookSubscriptions returns the webhook subscriptions registered in the config.
+func getWebhookSubscriptions(conf config.Config) []webhook.Subscription {
+	subscriptions := make([]webhook.Subscription, 0, len(conf.Webhooks.Subscriptions))
+	for i, c := range conf.Webhooks.Subscriptions {
+		if c.ID == "" || c.URL == "" {
+			panic(fmt.Sprintf("config's Webhooks.Subscriptions[%d] MUST have both ID and URL", i))
+		}
+		for _, e := range c.Events {
+			if !webhook.IsEventType(e) {
+				panic(fmt.Sprintf("config's Webhooks.Subscriptions[%d] refers to unknown event %s", i, e))
+			}
+		}
+
+		secret, err := hex.DecodeString(c.Secret)
+		if err != nil {
+			panic(err)
+		}
+		// Receivers verify signatures with the secret, so it MUST NOT be guessable.
+		if len(secret) < 16 {
+			panic(fmt.Sprintf("config's Webhooks.Subscriptions[%d].Secret MUST be at least 16 bytes long", i))
+		}
+
+		subscriptions = append(subscriptions, webhook.Subscription{
+			ID:     c.ID,
+			URL:    c.URL,
+			Events: c.Events,
+			Secret: secret,
+		})
+	}
+
+	return subscriptions
+}
+
+// getWebhookClient returns the client delivering webhook events.
+func getWebhookClient(conf config.Config) *http.Client {
+	timeout := DefaultWebhookTimeout
+	if conf.Webhooks.Timeout != "" {
+		timeout = parsePositiveDuration(conf.Webhooks.Timeout, "Webhooks.Timeout")
+	}
+
+	return &http.Client{Timeout: timeout}
+}
+
+// getWebhookCacheTTL returns how long the list of webhook subscriptions is cached.
+func getWebhookCacheTTL(conf config.Config) time.Duration {
+	if conf.Webhooks.CacheTTL == "" {
+		return DefaultWebhookCacheTTL
+	}
+
+	return parsePositiveDuration(conf.Webhooks.CacheTTL, "Webhooks.CacheTTL")
+}
+
+// getPasswordPolicy returns the policy for new passwords from the config.
+func getPasswordPolicy(conf config.Config) password.Policy {
+	c := conf.PasswordPolicy
+
+	// Min length not set in the config is the default one.
+	policy := password.DefaultPolicy
+	if c.MinLength != 0 {
+		policy.MinLength = c.MinLength
+	}
+	policy.MaxLength = c.MaxLength
+	policy.RequireLowercase = c.RequireLowercase
+	policy.RequireUppercase = c.RequireUppercase
+	policy.RequireDigit = c.RequireDigit
+	policy.RequireSymbol = c.RequireSymbol
+	policy.ForbidName = c.ForbidName
+	policy.MinScore = c.MinScore
+
+	if policy.MinLength < 0 || (policy.MaxLength != 0 && policy.MaxLength < policy.MinLength) {
+		panic("config's PasswordPolicy.MaxLength MUST be greater than PasswordPolicy.MinLength")
+	}
+	if policy.MinScore < 0 || policy.MinScore > password.MaxScore {
+		panic(fmt.Sprintf("config's PasswordPolicy.MinScore MUST be between 0 and %d", password.MaxScore))
+	}
+
+	if c.CommonPasswordsPath != "" {
+		f, err := os.Open(c.CommonPasswordsPath)
+		if err != nil {
+			panic(err)
+		}
+		defer f.Close()
+
+		policy.CommonPasswords, err = password.ReadCommonPasswords(f)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	// The index is kept open while the server runs.
+	if c.BreachIndexPath != "" {
+		ix, err := breach.OpenIndex(c.BreachIndexPath)
+		if err != nil {
+			panic(err)
+		}
+		policy.Breaches = ix
+	}
+
+	return policy
+}
+
+// getPepperHasher returns the hasher h decorated with peppers from the config,
+// or h itself if no peppers are configured.
+func getPepperHasher(conf config.Config, h hasher.Rehasher) hasher.Rehasher {
+	if len(conf.Hasher.Pepper.Keys) == 0 {
+		return h
+	}
+
+	peppers := make(map[string][]byte, len(conf.Hasher.Pepper.Keys))
+	for id, k := range conf.Hasher.Pepper.Keys {
+		if id == "" || strings.Contains(id, "$") {
+			panic("config's Hasher.Pepper.Keys IDs MUST be non-empty and MUST NOT contain \"$\"")
+		}
+		pepper, err := hex.DecodeString(k)
+		if err != nil {
+			panic(err)
+		}
+		// Peppers MUST be generated with a CSPRNG, and be long enough not to be brute forced.
+		if len(pepper) < 16 {
+			panic("config's Hasher.Pepper.Keys MUST be at least 16 bytes long")
+		}
+		peppers[id] = pepper
+	}
+
+	if _, ok := peppers[conf.Hasher.Pepper.CurrentKeyID]; !ok {
+		panic("config's Hasher.Pepper.CurrentKeyID MUST be one of Hasher.Pepper.Keys")
+	}
+
+	return hasher.NewPepperHasher(h, conf.Hasher.Pepper.CurrentKeyID, peppers)
+}
+
+func getHasher(conf config.Config) hasher.Rehasher {
+	switch conf.Hasher.Algorithm {
+	case "", "bcrypt":
+		cost := conf.Hasher.Bcrypt.Cost
+		if cost == 0 {
+			cost = bcrypt.DefaultCost
+		}
+		if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+			panic(fmt.Sprintf("config's Hasher.Bcrypt.Cost MUST be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost))
+		}
+		return hasher.NewBcryptHasher(cost)
+	case "argon2id":
+		// Parameters not set in the config are the default ones.
+		params := hasher.DefaultArgon2idParams
+		if conf.Hasher.Argon2id.Memory != 0 {
+			params.Memory = conf.Hasher.Argon2id.Memory
+		}
+		if conf.Hasher.Argon2id.Iterations != 0 {
+			params.Iterations = conf.Hasher.Argon2id.Iterations
+		}
+		if conf.Hasher.Argon2id.Parallelism != 0 {
+			params.Parallelism = conf.Hasher.Argon2id.Parallelism
+		}
+		return hasher.NewArgon2idHasher(params)
+	case "scrypt":
+		// Parameters not set in the config are the default ones.
+		params := hasher.DefaultScryptParams
+		if conf.Hasher.Scrypt.LogN != 0 {
+			params.LogN = conf.Hasher.Scrypt.LogN
+		}
+		if conf.Hasher.Scrypt.R != 0 {
+			params.R = conf.Hasher.Scrypt.R
+		}
+		if conf.Hasher.Scrypt.P != 0 {
+			params.P = conf.Hasher.Scrypt.P
+		}
+		if params.LogN > 31 || params.R < 0 || params.P < 0 {
+			panic("config's Hasher.Scrypt parameters are invalid")
+		}
+		return hasher.NewScryptHasher(params)
+	default:
+		panic("config's Hasher.Algorithm MUST be one of bcrypt, argon2id or scrypt")
+	}
+}
+
+func getLockoutDuration(conf config.Config) time.Duration {
+	if conf.Session.LockoutThreshold <= 0 {
+		return 0
+	}
+
+	d, err := time.ParseDuration(conf.Session.LockoutDuration)
+	if err != nil {
+		panic(err)
+	}
+	if d <= 0 {
+		panic("config's Session.LockoutDuration MUST be positive when Session.LockoutThreshold is set")
+	}
+
+	return d
+}
+
 func getDatabase(conf config.Config) (db *sql.DB) {
 	dsn := fmt.Sprintf(
 		"%s://%s:%s@%s:%s/%s?%s",
@@ -0,0 +1,24 @@
+package notifier
+
+type fakeNotifier struct {
+	notifyResults       []FakeNotifierNotifyResult
+	notifyResultCounter int
+}
+
+type FakeNotifierNotifyResult struct {
+	Error error
+}
+
+// NewFakeNotifier returns a new fake Notifier.
+func NewFakeNotifier(notifyResults []FakeNotifierNotifyResult) Notifier {
+	return &fakeNotifier{
+		notifyResults:       notifyResults,
+		notifyResultCounter: 0,
+	}
+}
+
+func (n *fakeNotifier) Notify(notification Notification) error {
+	res := n.notifyResults[n.notifyResultCounter]
+	n.notifyResultCounter++
+	return res.Error
+}
@@ -0,0 +1,91 @@
+package notifier
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+
+	"github.com/pkg/errors"
+
+	"github.com/hypnoglow/pascont/mail"
+)
+
+// MailTemplate is a template of an email for a kind of notifications.
+// Body is executed with notification params.
+type MailTemplate struct {
+	Subject string
+	Body    *template.Template
+}
+
+// defaultMailTemplates are used for the kinds which have no template configured.
+var defaultMailTemplates = map[string]MailTemplate{
+	KindPasswordReset: {
+		Subject: "Reset your password",
+		Body: template.Must(template.New(KindPasswordReset).Parse(
+			"Someone requested a password reset for your account.\n" +
+				"If it was you, set a new password with the token below before {{.expiresAt}}:\n\n" +
+				"{{.token}}\n\n" +
+				"Otherwise, ignore this email.\n",
+		)),
+	},
+	KindVerification: {
+		Subject: "Verify your account",
+		Body: template.Must(template.New(KindVerification).Parse(
+			"Verify that this email belongs to you with the token below before {{.expiresAt}}:\n\n" +
+				"{{.token}}\n",
+		)),
+	},
+	KindNewLogin: {
+		Subject: "New login to your account",
+		Body: template.Must(template.New(KindNewLogin).Parse(
+			"Your account was logged in at {{.createdAt}} from {{.ip}} ({{.userAgent}}).\n" +
+				"If it was not you, reset your password.\n",
+		)),
+	},
+	KindPasswordChanged: {
+		Subject: "Your password was changed",
+		Body: template.Must(template.New(KindPasswordChanged).Parse(
+			"The password of your account was changed at {{.changedAt}}.\n" +
+				"If it was not you, reset your password.\n",
+		)),
+	},
+}
+
+// mailNotifier is a Notifier which delivers notifications by email.
+// The recipient of notifications is an email address.
+type mailNotifier struct {
+	sender    mail.Sender
+	templates map[string]MailTemplate
+}
+
+// NewMailNotifier returns a new Notifier which delivers notifications with the sender.
+// The templates override the default ones by notification kind.
+func NewMailNotifier(s mail.Sender, templates map[string]MailTemplate) Notifier {
+	merged := make(map[string]MailTemplate, len(defaultMailTemplates)+len(templates))
+	for kind, tmpl := range defaultMailTemplates {
+		merged[kind] = tmpl
+	}
+	for kind, tmpl := range templates {
+		merged[kind] = tmpl
+	}
+
+	return mailNotifier{sender: s, templates: merged}
+}
+
+func (n mailNotifier) Notify(notification Notification) error {
+	tmpl, ok := n.templates[notification.Kind]
+	if !ok {
+		return fmt.Errorf("No email template for %s notifications", notification.Kind)
+	}
+
+	var body bytes.Buffer
+	if err := tmpl.Body.Execute(&body, notification.Params); err != nil {
+		return errors.Wrapf(err, "Failed to compose an email for %s notification", notification.Kind)
+	}
+
+	return n.sender.Send(mail.Message{
+		To:      notification.Recipient,
+		Subject: tmpl.Subject,
+		Body:    body.String(),
+	})
+}
@@ -0,0 +1,113 @@
+package notifier
+
+import (
+	"reflect"
+	"testing"
+	"text/template"
+
+	"github.com/hypnoglow/pascont/mail"
+)
+
+// recordingSender is a mail.Sender which keeps the messages sent.
+type recordingSender struct {
+	messages []mail.Message
+}
+
+func (s *recordingSender) Send(m mail.Message) error {
+	s.messages = append(s.messages, m)
+	return nil
+}
+
+func TestMailNotifier_Notify(t *testing.T) {
+	cases := []struct {
+		caseName string
+		// in
+		templates    map[string]MailTemplate
+		notification Notification
+		// out
+		expectedMessages []mail.Message
+		expectedError    bool
+	}{
+		{
+			caseName: "Verification",
+			notification: Notification{
+				Kind:      KindVerification,
+				TenantID:  1,
+				AccountID: 123,
+				Recipient: "email@email.com",
+				Params: map[string]string{
+					"token":     "token",
+					"expiresAt": "2017-07-01T21:10:29Z",
+				},
+			},
+			expectedMessages: []mail.Message{
+				{
+					To:      "email@email.com",
+					Subject: "Verify your account",
+					Body: "Verify that this email belongs to you with the token below before 2017-07-01T21:10:29Z:\n\n" +
+						"token\n",
+				},
+			},
+		},
+		{
+			caseName: "Configured template",
+			templates: map[string]MailTemplate{
+				KindNewLogin: {
+					Subject: "Login alert",
+					Body:    template.Must(template.New(KindNewLogin).Parse("Login from {{.ip}}\n")),
+				},
+			},
+			notification: Notification{
+				Kind:      KindNewLogin,
+				TenantID:  1,
+				AccountID: 123,
+				Recipient: "email@email.com",
+				Params: map[string]string{
+					"ip":        "127.0.0.1",
+					"userAgent": "Mozilla/5.0",
+					"createdAt": "2017-07-01T21:10:29Z",
+				},
+			},
+			expectedMessages: []mail.Message{
+				{
+					To:      "email@email.com",
+					Subject: "Login alert",
+					Body:    "Login from 127.0.0.1\n",
+				},
+			},
+		},
+		{
+			caseName: "Unknown kind",
+			notification: Notification{
+				Kind:      "unknown",
+				Recipient: "email@email.com",
+			},
+			expectedError: true,
+		},
+	}
+
+	for i, c := range cases {
+		sender := &recordingSender{}
+		err := NewMailNotifier(sender, c.templates).Notify(c.notification)
+
+		if (err != nil) != c.expectedError {
+			t.Errorf(
+				"testcase %d %s:\nExpected error to be %v, but got %v\n",
+				i,
+				c.caseName,
+				c.expectedError,
+				err,
+			)
+		}
+
+		if !reflect.DeepEqual(sender.messages, c.expectedMessages) {
+			t.Errorf(
+				"testcase %d %s:\nExpected messages to be\n%v\nbut got\n%v\n",
+				i,
+				c.caseName,
+				c.expectedMessages,
+				sender.messages,
+			)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+// Package notifier delivers notifications to account holders.
+package notifier
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+)
+
+// Kinds of notifications.
+const (
+	// KindPasswordReset is a notification with a password reset token.
+	// Params: token, expiresAt.
+	// In the outbox, the reset is referred to by the resetID param instead, and the token is added on delivery.
+	KindPasswordReset = "password_reset"
+
+	// KindVerification is a notification with an account verification token.
+	// Params: token, expiresAt.
+	// In the outbox, there is no token, it is added on delivery.
+	KindVerification = "verification"
+
+	// KindNewLogin is an alert about a new session of an account.
+	// Params: ip, userAgent, createdAt.
+	KindNewLogin = "new_login"
+
+	// KindPasswordChanged is an alert about a password change of an account.
+	// Params: changedAt.
+	KindPasswordChanged = "password_changed"
+)
+
+// Notification is a message to an account holder.
+type Notification struct {
+	Kind      string            `json:"kind"`
+	TenantID  int64             `json:"tenantID"`
+	AccountID int64             `json:"accountID"`
+	Recipient string            `json:"recipient"`
+	Params    map[string]string `json:"params"`
+}
+
+// Notifier delivers notifications.
+type Notifier interface {
+	// Notify delivers the notification.
+	Notify(n Notification) error
+}
+
+// writerNotifier is a Notifier which writes notifications to a log or a file
+// as JSON lines, instead of delivering them. It is meant for development and tests.
+type writerNotifier struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewWriterNotifier returns a new writerNotifier.
+func NewWriterNotifier(w io.Writer) Notifier {
+	return &writerNotifier{w: w}
+}
+
+func (n *writerNotifier) Notify(notification Notification) error {
+	b, err := json.Marshal(notification)
+	if err != nil {
+		return err
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	_, err = n.w.Write(append(b, '\n'))
+	return err
+}
@@ -0,0 +1,32 @@
+package notifier
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriterNotifier_Notify(t *testing.T) {
+	var buf bytes.Buffer
+	n := NewWriterNotifier(&buf)
+
+	err := n.Notify(Notification{
+		Kind:      KindPasswordReset,
+		TenantID:  1,
+		AccountID: 123,
+		Recipient: "email@email.com",
+		Params:    map[string]string{"token": "token"},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	err = n.Notify(Notification{Kind: KindPasswordReset, TenantID: 1, AccountID: 124})
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+
+	expected := `{"kind":"password_reset","tenantID":1,"accountID":123,"recipient":"email@email.com","params":{"token":"token"}}` + "\n" +
+		`{"kind":"password_reset","tenantID":1,"accountID":124,"recipient":"","params":null}` + "\n"
+	if buf.String() != expected {
+		t.Errorf("Expected notifications to be written as\n%s\nbut got\n%s\n", expected, buf.String())
+	}
+}
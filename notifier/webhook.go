@@ -0,0 +1,46 @@
+package notifier
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+// webhookNotifier is a Notifier which delivers notifications
+// by POSTing them as JSON to a URL.
+type webhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookNotifier returns a new Notifier which POSTs notifications to the url.
+// If client is nil, http.DefaultClient is used.
+func NewWebhookNotifier(url string, client *http.Client) Notifier {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return webhookNotifier{url: url, client: client}
+}
+
+func (n webhookNotifier) Notify(notification Notification) error {
+	b, err := json.Marshal(notification)
+	if err != nil {
+		return errors.Wrap(err, "Failed to encode a notification")
+	}
+
+	resp, err := n.client.Post(n.url, "application/json", bytes.NewReader(b))
+	if err != nil {
+		return errors.Wrap(err, "Failed to post a notification")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("Webhook responded with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,110 @@
+package notifier
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestWebhookNotifier_Notify(t *testing.T) {
+	cases := []struct {
+		caseName string
+		// in
+		notification Notification
+		status       int
+		// out
+		expectedBody  string
+		expectedError bool
+	}{
+		{
+			caseName: "Delivered",
+			notification: Notification{
+				Kind:      KindPasswordChanged,
+				TenantID:  1,
+				AccountID: 123,
+				Recipient: "email@email.com",
+				Params: map[string]string{
+					"changedAt": "2017-07-01T21:10:29Z",
+				},
+			},
+			status: http.StatusNoContent,
+			expectedBody: `{
+				"kind": "password_changed",
+				"tenantID": 1,
+				"accountID": 123,
+				"recipient": "email@email.com",
+				"params": {"changedAt": "2017-07-01T21:10:29Z"}
+			}`,
+		},
+		{
+			caseName: "Rejected",
+			notification: Notification{
+				Kind:      KindPasswordChanged,
+				TenantID:  1,
+				AccountID: 123,
+				Recipient: "email@email.com",
+			},
+			status: http.StatusInternalServerError,
+			expectedBody: `{
+				"kind": "password_changed",
+				"tenantID": 1,
+				"accountID": 123,
+				"recipient": "email@email.com",
+				"params": null
+			}`,
+			expectedError: true,
+		},
+	}
+
+	for i, c := range cases {
+		var body []byte
+		var contentType string
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			contentType = req.Header.Get("Content-Type")
+			body, _ = ioutil.ReadAll(req.Body)
+			w.WriteHeader(c.status)
+		}))
+
+		err := NewWebhookNotifier(srv.URL, nil).Notify(c.notification)
+		srv.Close()
+
+		if (err != nil) != c.expectedError {
+			t.Errorf(
+				"testcase %d %s:\nExpected error to be %v, but got %v\n",
+				i,
+				c.caseName,
+				c.expectedError,
+				err,
+			)
+		}
+
+		if contentType != "application/json" {
+			t.Errorf(
+				"testcase %d %s:\nExpected Content-Type to be application/json, but got %s\n",
+				i,
+				c.caseName,
+				contentType,
+			)
+		}
+
+		var expected, actual interface{}
+		if err := json.Unmarshal([]byte(c.expectedBody), &expected); err != nil {
+			t.Fatal(err)
+		}
+		if err := json.Unmarshal(body, &actual); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(expected, actual) {
+			t.Errorf(
+				"testcase %d %s:\nExpected body to be\n%s\nbut got\n%s\n",
+				i,
+				c.caseName,
+				c.expectedBody,
+				body,
+			)
+		}
+	}
+}
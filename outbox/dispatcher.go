@@ -0,0 +1,167 @@
+package outbox
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hypnoglow/pascont/clock"
+	"github.com/hypnoglow/pascont/notifier"
+)
+
+// Dispatcher delivers outbox messages to their channels.
+type Dispatcher struct {
+	repo     Repository
+	channels map[string]notifier.Notifier
+	logger   *log.Logger
+	clock    clock.Clock
+	options  DispatcherOptions
+}
+
+// DispatcherOptions is a structure holding Dispatcher options.
+type DispatcherOptions struct {
+	// BatchSize is how many messages are claimed at once.
+	BatchSize int
+
+	// Lease is how long claimed messages are not claimed by other dispatchers.
+	// Messages are delivered one by one and the lease of every message is renewed
+	// right before its delivery, so it must be longer than the delivery of a message takes.
+	Lease time.Duration
+
+	// MaxAttempts is a number of failed delivery attempts after which a message is dead.
+	MaxAttempts int
+
+	// Backoff is a delay before the first retry of a message.
+	// Every next retry is delayed twice as long, up to MaxBackoff.
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+
+	// PollInterval is how often the outbox is checked for due messages.
+	PollInterval time.Duration
+
+	// Retention is how long delivered messages are kept in the outbox.
+	// Dead messages are kept until they are replayed.
+	Retention time.Duration
+}
+
+// NewDispatcher returns a new Dispatcher, which delivers messages to the channels by their names.
+func NewDispatcher(
+	repo Repository,
+	channels map[string]notifier.Notifier,
+	logger *log.Logger,
+	clk clock.Clock,
+	opts DispatcherOptions,
+) Dispatcher {
+	return Dispatcher{
+		repo,
+		channels,
+		logger,
+		clk,
+		opts,
+	}
+}
+
+// Run dispatches messages every poll interval until stop is closed.
+func (d Dispatcher) Run(stop <-chan struct{}) {
+	ticker := time.NewTicker(d.options.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		// Full batches mean there may be more messages due.
+		for {
+			n, err := d.Dispatch()
+			if err != nil {
+				d.logger.Println(err)
+			}
+			if err != nil || n < d.options.BatchSize {
+				break
+			}
+
+			select {
+			case <-stop:
+				return
+			default:
+			}
+		}
+
+		if _, err := d.Purge(); err != nil {
+			d.logger.Println(err)
+		}
+
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Dispatch delivers a batch of due messages.
+// Returns the number of messages claimed for delivery.
+func (d Dispatcher) Dispatch() (int, error) {
+	messages, err := d.repo.Claim(d.clock(), d.options.Lease, d.options.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, m := range messages {
+		// Messages wait for the delivery of the ones before them, so their leases
+		// may have expired and they may have been claimed by other dispatchers meanwhile.
+		renewed, err := d.repo.Renew(m.ID, m.NextAttemptAt, d.clock().Add(d.options.Lease))
+		if err != nil {
+			return len(messages), err
+		}
+		if !renewed {
+			continue
+		}
+
+		if err := d.deliver(m); err != nil {
+			return len(messages), err
+		}
+	}
+
+	return len(messages), nil
+}
+
+// Purge removes messages delivered longer than the retention ago.
+// Returns the number of removed messages.
+func (d Dispatcher) Purge() (int, error) {
+	return d.repo.Purge(d.clock().Add(-d.options.Retention))
+}
+
+// deliver delivers the message and registers the result of the attempt.
+// Returns an error only if the result is not registered.
+func (d Dispatcher) deliver(m Message) error {
+	var err error
+	if channel, ok := d.channels[m.Channel]; ok {
+		err = channel.Notify(m.Notification)
+	} else {
+		err = fmt.Errorf("Unknown channel %s", m.Channel)
+	}
+
+	now := d.clock().UTC().Truncate(time.Second)
+	if err == nil {
+		return d.repo.MarkDelivered(m.ID, now)
+	}
+
+	attempts := m.Attempts + 1
+	dead := attempts >= d.options.MaxAttempts
+	if dead {
+		d.logger.Printf("Outbox message %s to %s is dead after %d attempts: %s\n", m.ID, m.Channel, attempts, err)
+	}
+
+	return d.repo.MarkFailed(m.ID, attempts, now.Add(d.backoff(attempts)), err.Error(), dead)
+}
+
+// backoff returns the delay before the retry after the failed attempts.
+func (d Dispatcher) backoff(attempts int) time.Duration {
+	delay := d.options.Backoff
+	for i := 1; i < attempts && delay < d.options.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > d.options.MaxBackoff {
+		delay = d.options.MaxBackoff
+	}
+
+	return delay
+}
@@ -0,0 +1,244 @@
+package outbox
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/pascont/clock"
+	"github.com/hypnoglow/pascont/notifier"
+)
+
+// failure is a registered failed delivery attempt.
+type failure struct {
+	ID            string
+	Attempts      int
+	NextAttemptAt time.Time
+	LastError     string
+	Dead          bool
+}
+
+// recordingRepository is a Repository which keeps delivery results.
+type recordingRepository struct {
+	Repository
+
+	messages  []Message
+	delivered []string
+	failures  []failure
+	purgedAt  time.Time
+
+	// lost are messages which leases are not renewed.
+	lost     map[string]bool
+	renewals []renewal
+}
+
+// renewal is a renewed message lease.
+type renewal struct {
+	ID          string
+	LeasedUntil time.Time
+	Until       time.Time
+}
+
+func (r *recordingRepository) Add(messages ...Message) error {
+	r.messages = append(r.messages, messages...)
+	return nil
+}
+
+func (r *recordingRepository) Claim(t time.Time, lease time.Duration, limit int) ([]Message, error) {
+	messages := r.messages
+	r.messages = nil
+	for i := range messages {
+		messages[i].NextAttemptAt = t.Add(lease)
+	}
+	return messages, nil
+}
+
+func (r *recordingRepository) Renew(id string, leasedUntil, t time.Time) (bool, error) {
+	if r.lost[id] {
+		return false, nil
+	}
+	r.renewals = append(r.renewals, renewal{id, leasedUntil, t})
+	return true, nil
+}
+
+func (r *recordingRepository) MarkDelivered(id string, deliveredAt time.Time) error {
+	r.delivered = append(r.delivered, id)
+	return nil
+}
+
+func (r *recordingRepository) MarkFailed(id string, attempts int, nextAttemptAt time.Time, lastError string, dead bool) error {
+	r.failures = append(r.failures, failure{id, attempts, nextAttemptAt, lastError, dead})
+	return nil
+}
+
+func (r *recordingRepository) Purge(t time.Time) (int, error) {
+	r.purgedAt = t
+	return 0, nil
+}
+
+// notifierFunc is a Notifier which notifies with the function.
+type notifierFunc func(n notifier.Notification) error
+
+func (f notifierFunc) Notify(n notifier.Notification) error {
+	return f(n)
+}
+
+func TestDispatcher_Dispatch(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	fakeLogger := log.New(ioutil.Discard, "", log.LstdFlags)
+
+	channels := map[string]notifier.Notifier{
+		"ok": notifier.NewFakeNotifier([]notifier.FakeNotifierNotifyResult{
+			{
+				Error: nil,
+			},
+		}),
+		"failing": notifier.NewFakeNotifier([]notifier.FakeNotifierNotifyResult{
+			{
+				Error: fmt.Errorf("Notify failed"),
+			},
+			{
+				Error: fmt.Errorf("Notify failed"),
+			},
+		}),
+	}
+	repo := &recordingRepository{}
+	repo.Add(
+		Message{ID: "1", Channel: "ok"},
+		Message{ID: "2", Channel: "failing"},
+		Message{ID: "3", Channel: "failing", Attempts: 4},
+		Message{ID: "4", Channel: "unknown"},
+	)
+
+	d := NewDispatcher(repo, channels, fakeLogger, clock.Fixed(now), DispatcherOptions{
+		BatchSize:   10,
+		Lease:       time.Minute,
+		MaxAttempts: 5,
+		Backoff:     time.Second,
+		MaxBackoff:  time.Hour,
+	})
+
+	n, err := d.Dispatch()
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	if n != 4 {
+		t.Errorf("Expected 4 messages to be dispatched, but got %d", n)
+	}
+
+	if !reflect.DeepEqual(repo.delivered, []string{"1"}) {
+		t.Errorf("Expected delivered messages to be %v, but got %v", []string{"1"}, repo.delivered)
+	}
+
+	expectedFailures := []failure{
+		{
+			ID:            "2",
+			Attempts:      1,
+			NextAttemptAt: now.Add(time.Second),
+			LastError:     "Notify failed",
+		},
+		{
+			ID:            "3",
+			Attempts:      5,
+			NextAttemptAt: now.Add(time.Second * 16),
+			LastError:     "Notify failed",
+			Dead:          true,
+		},
+		{
+			ID:            "4",
+			Attempts:      1,
+			NextAttemptAt: now.Add(time.Second),
+			LastError:     "Unknown channel unknown",
+		},
+	}
+	if !reflect.DeepEqual(repo.failures, expectedFailures) {
+		t.Errorf("Expected failures to be\n%v\nbut got\n%v\n", expectedFailures, repo.failures)
+	}
+}
+
+func TestDispatcher_Dispatch_Lease(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	fakeLogger := log.New(ioutil.Discard, "", log.LstdFlags)
+
+	// Every delivery takes a minute, which is as long as the lease.
+	clk := now
+	channels := map[string]notifier.Notifier{
+		"slow": notifierFunc(func(n notifier.Notification) error {
+			clk = clk.Add(time.Minute)
+			return nil
+		}),
+	}
+	repo := &recordingRepository{lost: map[string]bool{"2": true}}
+	repo.Add(
+		Message{ID: "1", Channel: "slow"},
+		Message{ID: "2", Channel: "slow"},
+		Message{ID: "3", Channel: "slow"},
+	)
+
+	d := NewDispatcher(repo, channels, fakeLogger, func() time.Time { return clk }, DispatcherOptions{
+		BatchSize:   10,
+		Lease:       time.Minute,
+		MaxAttempts: 5,
+		Backoff:     time.Second,
+		MaxBackoff:  time.Hour,
+	})
+
+	if _, err := d.Dispatch(); err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+
+	// The lease of the message 2 expired during the delivery of the message 1,
+	// and the message is not delivered, as it may be delivered by another dispatcher.
+	if !reflect.DeepEqual(repo.delivered, []string{"1", "3"}) {
+		t.Errorf("Expected delivered messages to be %v, but got %v", []string{"1", "3"}, repo.delivered)
+	}
+
+	expectedRenewals := []renewal{
+		{ID: "1", LeasedUntil: now.Add(time.Minute), Until: now.Add(time.Minute)},
+		{ID: "3", LeasedUntil: now.Add(time.Minute), Until: now.Add(time.Minute * 2)},
+	}
+	if !reflect.DeepEqual(repo.renewals, expectedRenewals) {
+		t.Errorf("Expected renewals to be\n%v\nbut got\n%v\n", expectedRenewals, repo.renewals)
+	}
+}
+
+func TestDispatcher_Purge(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	fakeLogger := log.New(ioutil.Discard, "", log.LstdFlags)
+
+	repo := &recordingRepository{}
+	d := NewDispatcher(repo, nil, fakeLogger, clock.Fixed(now), DispatcherOptions{
+		Retention: time.Hour,
+	})
+
+	if _, err := d.Purge(); err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	if expected := now.Add(-time.Hour); !repo.purgedAt.Equal(expected) {
+		t.Errorf("Expected messages delivered before %s to be purged, but got %s", expected, repo.purgedAt)
+	}
+}
+
+func TestDispatcher_backoff(t *testing.T) {
+	d := Dispatcher{options: DispatcherOptions{Backoff: time.Second, MaxBackoff: time.Minute}}
+
+	cases := []struct {
+		attempts      int
+		expectedDelay time.Duration
+	}{
+		{attempts: 1, expectedDelay: time.Second},
+		{attempts: 2, expectedDelay: time.Second * 2},
+		{attempts: 6, expectedDelay: time.Second * 32},
+		{attempts: 7, expectedDelay: time.Minute},
+		{attempts: 100, expectedDelay: time.Minute},
+	}
+
+	for i, c := range cases {
+		if delay := d.backoff(c.attempts); delay != c.expectedDelay {
+			t.Errorf("testcase %d: Expected delay after %d attempts to be %s, but got %s", i, c.attempts, c.expectedDelay, delay)
+		}
+	}
+}
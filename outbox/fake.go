@@ -0,0 +1,154 @@
+package outbox
+
+import "time"
+
+type fakeRepository struct {
+	addResults                   []FakeRepositoryAddResult
+	addResultCounter             int
+	claimResults                 []FakeRepositoryClaimResult
+	claimResultCounter           int
+	renewResults                 []FakeRepositoryRenewResult
+	renewResultCounter           int
+	markDeliveredResults         []FakeRepositoryMarkDeliveredResult
+	markDeliveredResultCounter   int
+	markFailedResults            []FakeRepositoryMarkFailedResult
+	markFailedResultCounter      int
+	findByIDResults              []FakeRepositoryFindByIDResult
+	findByIDResultCounter        int
+	listByRecipientResults       []FakeRepositoryListByRecipientResult
+	listByRecipientResultCounter int
+	replayResults                []FakeRepositoryReplayResult
+	replayResultCounter          int
+	purgeResults                 []FakeRepositoryPurgeResult
+	purgeResultCounter           int
+}
+
+type FakeRepositoryAddResult struct {
+	Error error
+}
+
+type FakeRepositoryClaimResult struct {
+	Messages []Message
+	Error    error
+}
+
+type FakeRepositoryRenewResult struct {
+	Renewed bool
+	Error   error
+}
+
+type FakeRepositoryMarkDeliveredResult struct {
+	Error error
+}
+
+type FakeRepositoryMarkFailedResult struct {
+	Error error
+}
+
+type FakeRepositoryFindByIDResult struct {
+	Message *Message
+	Error   error
+}
+
+type FakeRepositoryListByRecipientResult struct {
+	Messages []Message
+	Total    int
+	Error    error
+}
+
+type FakeRepositoryReplayResult struct {
+	Error error
+}
+
+type FakeRepositoryPurgeResult struct {
+	Purged int
+	Error  error
+}
+
+// NewFakeRepository returns a new fake Repository.
+func NewFakeRepository(
+	addResults []FakeRepositoryAddResult,
+	claimResults []FakeRepositoryClaimResult,
+	markDeliveredResults []FakeRepositoryMarkDeliveredResult,
+	markFailedResults []FakeRepositoryMarkFailedResult,
+	findByIDResults []FakeRepositoryFindByIDResult,
+	listByRecipientResults []FakeRepositoryListByRecipientResult,
+	replayResults []FakeRepositoryReplayResult,
+	purgeResults []FakeRepositoryPurgeResult,
+	renewResults []FakeRepositoryRenewResult,
+) Repository {
+	return &fakeRepository{
+		addResults:                   addResults,
+		addResultCounter:             0,
+		claimResults:                 claimResults,
+		claimResultCounter:           0,
+		markDeliveredResults:         markDeliveredResults,
+		markDeliveredResultCounter:   0,
+		markFailedResults:            markFailedResults,
+		markFailedResultCounter:      0,
+		findByIDResults:              findByIDResults,
+		findByIDResultCounter:        0,
+		listByRecipientResults:       listByRecipientResults,
+		listByRecipientResultCounter: 0,
+		replayResults:                replayResults,
+		replayResultCounter:          0,
+		purgeResults:                 purgeResults,
+		purgeResultCounter:           0,
+		renewResults:                 renewResults,
+		renewResultCounter:           0,
+	}
+}
+
+func (r *fakeRepository) Add(messages ...Message) error {
+	res := r.addResults[r.addResultCounter]
+	r.addResultCounter++
+	return res.Error
+}
+
+func (r *fakeRepository) Claim(t time.Time, lease time.Duration, limit int) ([]Message, error) {
+	res := r.claimResults[r.claimResultCounter]
+	r.claimResultCounter++
+	return res.Messages, res.Error
+}
+
+func (r *fakeRepository) Renew(id string, leasedUntil, t time.Time) (bool, error) {
+	res := r.renewResults[r.renewResultCounter]
+	r.renewResultCounter++
+	return res.Renewed, res.Error
+}
+
+func (r *fakeRepository) MarkDelivered(id string, deliveredAt time.Time) error {
+	res := r.markDeliveredResults[r.markDeliveredResultCounter]
+	r.markDeliveredResultCounter++
+	return res.Error
+}
+
+func (r *fakeRepository) MarkFailed(id string, attempts int, nextAttemptAt time.Time, lastError string, dead bool) error {
+	res := r.markFailedResults[r.markFailedResultCounter]
+	r.markFailedResultCounter++
+	return res.Error
+}
+
+func (r *fakeRepository) FindByID(id string) (*Message, error) {
+	res := r.findByIDResults[r.findByIDResultCounter]
+	r.findByIDResultCounter++
+	return res.Message, res.Error
+}
+
+func (r *fakeRepository) ListByRecipient(channel, recipient string, status Status, offset, limit int) ([]Message, int, error) {
+	res := r.listByRecipientResults[r.listByRecipientResultCounter]
+	r.listByRecipientResultCounter++
+	return res.Messages, res.Total, res.Error
+}
+
+func (r *fakeRepository) Replay(id string, t time.Time) error {
+	res := r.replayResults[r.replayResultCounter]
+	r.replayResultCounter++
+	return res.Error
+}
+
+func (r *fakeRepository) Purge(t time.Time) (int, error) {
+	res := r.purgeResults[r.purgeResultCounter]
+	r.purgeResultCounter++
+	return res.Purged, res.Error
+}
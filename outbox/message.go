@@ -0,0 +1,90 @@
+// Package outbox provides reliable delivery of notifications, which does not block the changes they are about.
+// Notifications are written to the outbox as messages, along with the changes,
+// and delivered to channels in background with retries.
+package outbox
+
+import (
+	"time"
+
+	"github.com/hypnoglow/pascont/clock"
+	"github.com/hypnoglow/pascont/identity"
+	"github.com/hypnoglow/pascont/notifier"
+)
+
+// Status is a delivery status of a Message.
+type Status string
+
+const (
+	// StatusPending is a status of a message which is not delivered yet.
+	StatusPending = Status("pending")
+
+	// StatusDelivered is a status of a message which is delivered.
+	StatusDelivered = Status("delivered")
+
+	// StatusDead is a status of a message which delivery failed too many times.
+	// Such messages are not retried anymore.
+	StatusDead = Status("dead")
+)
+
+// Message is a notification to deliver to a channel.
+type Message struct {
+	ID           string
+	Channel      string
+	Notification notifier.Notification
+	Status       Status
+	CreatedAt    time.Time
+
+	// Delivery attempts.
+	Attempts      int
+	NextAttemptAt time.Time
+	LastError     string
+	DeliveredAt   time.Time
+}
+
+// Router routes notifications to delivery channels by the notification kind.
+// The zero Router routes notifications nowhere.
+type Router struct {
+	routes       map[string][]string
+	defaults     []string
+	uuidProducer identity.UUIDProducer
+	clock        clock.Clock
+}
+
+// NewRouter returns a new Router, which routes notifications of kinds in routes to the channels of the kind,
+// and notifications of other kinds to the default channels.
+// Message IDs are UUIDs produced by identity.UUIDProducer func.
+func NewRouter(routes map[string][]string, defaults []string, u identity.UUIDProducer, clk clock.Clock) Router {
+	return Router{routes, defaults, u, clk}
+}
+
+// Channels returns the channels notifications of the kind are delivered to.
+func (r Router) Channels(kind string) []string {
+	if channels, ok := r.routes[kind]; ok {
+		return channels
+	}
+
+	return r.defaults
+}
+
+// Messages returns the messages delivering the notification to its channels.
+func (r Router) Messages(n notifier.Notification) []Message {
+	channels := r.Channels(n.Kind)
+	if len(channels) == 0 {
+		return nil
+	}
+
+	now := r.clock().UTC().Truncate(time.Second)
+	messages := make([]Message, len(channels))
+	for i, channel := range channels {
+		messages[i] = Message{
+			ID:            r.uuidProducer(),
+			Channel:       channel,
+			Notification:  n,
+			Status:        StatusPending,
+			CreatedAt:     now,
+			NextAttemptAt: now,
+		}
+	}
+
+	return messages
+}
@@ -0,0 +1,101 @@
+package outbox
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/pascont/clock"
+	"github.com/hypnoglow/pascont/notifier"
+)
+
+func testUUIDProducer() string {
+	return "12345678-90ab-cdef-0123-4567890abcde"
+}
+
+func TestRouter_Messages(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	router := NewRouter(
+		map[string][]string{
+			notifier.KindPasswordReset: {"smtp", "log"},
+			notifier.KindVerification:  {},
+		},
+		[]string{"log"},
+		testUUIDProducer,
+		clock.Fixed(now),
+	)
+
+	reset := notifier.Notification{Kind: notifier.KindPasswordReset, AccountID: 123}
+	other := notifier.Notification{Kind: "other", AccountID: 123}
+
+	cases := []struct {
+		caseName         string
+		router           Router
+		notification     notifier.Notification
+		expectedMessages []Message
+	}{
+		{
+			caseName:     "Kind with channels",
+			router:       router,
+			notification: reset,
+			expectedMessages: []Message{
+				{
+					ID:            testUUIDProducer(),
+					Channel:       "smtp",
+					Notification:  reset,
+					Status:        StatusPending,
+					CreatedAt:     now,
+					NextAttemptAt: now,
+				},
+				{
+					ID:            testUUIDProducer(),
+					Channel:       "log",
+					Notification:  reset,
+					Status:        StatusPending,
+					CreatedAt:     now,
+					NextAttemptAt: now,
+				},
+			},
+		},
+		{
+			caseName:         "Kind without channels",
+			router:           router,
+			notification:     notifier.Notification{Kind: notifier.KindVerification},
+			expectedMessages: nil,
+		},
+		{
+			caseName:     "Other kind goes to default channels",
+			router:       router,
+			notification: other,
+			expectedMessages: []Message{
+				{
+					ID:            testUUIDProducer(),
+					Channel:       "log",
+					Notification:  other,
+					Status:        StatusPending,
+					CreatedAt:     now,
+					NextAttemptAt: now,
+				},
+			},
+		},
+		{
+			caseName:         "Zero router",
+			router:           Router{},
+			notification:     reset,
+			expectedMessages: nil,
+		},
+	}
+
+	for i, c := range cases {
+		messages := c.router.Messages(c.notification)
+		if !reflect.DeepEqual(messages, c.expectedMessages) {
+			t.Errorf(
+				"testcase %d %s:\nExpected messages to be\n%#v\nbut got\n%#v\n",
+				i,
+				c.caseName,
+				c.expectedMessages,
+				messages,
+			)
+		}
+	}
+}
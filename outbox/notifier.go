@@ -0,0 +1,25 @@
+package outbox
+
+import "github.com/hypnoglow/pascont/notifier"
+
+// outboxNotifier is a Notifier which writes notifications to the outbox,
+// so they are delivered in background.
+type outboxNotifier struct {
+	repo   Repository
+	router Router
+}
+
+// NewNotifier returns a new Notifier which writes notifications to the outbox
+// as messages for the channels of the router.
+func NewNotifier(repo Repository, router Router) notifier.Notifier {
+	return outboxNotifier{repo, router}
+}
+
+func (n outboxNotifier) Notify(notification notifier.Notification) error {
+	messages := n.router.Messages(notification)
+	if len(messages) == 0 {
+		return nil
+	}
+
+	return n.repo.Add(messages...)
+}
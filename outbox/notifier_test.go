@@ -0,0 +1,78 @@
+package outbox
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/pascont/notifier"
+)
+
+func TestOutboxNotifier_Notify(t *testing.T) {
+	router := NewRouter(nil, []string{"log"}, testUUIDProducer, time.Now)
+
+	cases := []struct {
+		caseName      string
+		repo          Repository
+		router        Router
+		expectedError bool
+	}{
+		{
+			caseName: "Successful",
+			repo: NewFakeRepository(
+				[]FakeRepositoryAddResult{
+					{
+						Error: nil,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			router: router,
+		},
+		{
+			caseName: "Error on Add",
+			repo: NewFakeRepository(
+				[]FakeRepositoryAddResult{
+					{
+						Error: fmt.Errorf("Add failed"),
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			router:        router,
+			expectedError: true,
+		},
+		{
+			caseName: "Notifications routed nowhere are not written",
+			repo:     NewFakeRepository(nil, nil, nil, nil, nil, nil, nil, nil, nil),
+			router:   Router{},
+		},
+	}
+
+	for i, c := range cases {
+		err := NewNotifier(c.repo, c.router).Notify(notifier.Notification{Kind: notifier.KindPasswordReset})
+		if (err != nil) != c.expectedError {
+			t.Errorf(
+				"testcase %d %s:\nExpected error to be %v, but got %v\n",
+				i,
+				c.caseName,
+				c.expectedError,
+				err,
+			)
+		}
+	}
+}
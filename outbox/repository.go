@@ -0,0 +1,58 @@
+package outbox
+
+import "time"
+
+// Repository is a repository for outbox messages.
+type Repository interface {
+	// Add adds the messages to the outbox.
+	Add(messages ...Message) error
+
+	// Claim retrieves up to limit pending messages which are due at t, and postpones
+	// their next attempt until t + lease, so they are not delivered concurrently by other dispatchers.
+	Claim(t time.Time, lease time.Duration, limit int) ([]Message, error)
+
+	// Renew extends the lease of the claimed message until t, unless it has not been leased
+	// until leasedUntil anymore, i.e. it has expired and the message has been claimed again
+	// or its attempt has been registered.
+	// Returns whether the lease is renewed.
+	Renew(id string, leasedUntil, t time.Time) (bool, error)
+
+	// MarkDelivered marks the message as delivered at deliveredAt.
+	MarkDelivered(id string, deliveredAt time.Time) error
+
+	// MarkFailed registers a failed delivery attempt of the message.
+	// The message is retried at nextAttemptAt, unless it is dead.
+	MarkFailed(id string, attempts int, nextAttemptAt time.Time, lastError string, dead bool) error
+
+	// FindByID retrieves a message for matching id.
+	// If message with such id not found, returns ErrNotFound.
+	// Other errors may occur.
+	FindByID(id string) (*Message, error)
+
+	// ListByRecipient retrieves messages of the channel to the recipient, newest first.
+	// If status is empty, messages of any status are retrieved.
+	// Returns the total number of such messages along with the messages in the page.
+	ListByRecipient(channel, recipient string, status Status, offset, limit int) ([]Message, int, error)
+
+	// Replay makes the message pending again, with no attempts, to be delivered at t,
+	// whatever its status is.
+	// If message with such id not found, returns ErrNotFound.
+	// Other errors may occur.
+	Replay(id string, t time.Time) error
+
+	// Purge removes messages delivered before t.
+	// Returns the number of removed messages.
+	Purge(t time.Time) (int, error)
+}
+
+// repositoryError is an error occurred in Repository.
+type repositoryError string
+
+func (e repositoryError) Error() string {
+	return string(e)
+}
+
+const (
+	// ErrNotFound occurs when outbox message not found.
+	ErrNotFound = repositoryError("Outbox message not found")
+)
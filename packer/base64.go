@@ -31,6 +31,9 @@ func (p base64Packer) Unpack(pack []byte) (message, signature []byte, err error)
 	if err != nil {
 		return nil, nil, err
 	}
+	if n < p.mlen {
+		return nil, nil, fmt.Errorf("pack len is less than packer mlen")
+	}
 
 	return decoded[:p.mlen], decoded[p.mlen:n], nil
 }
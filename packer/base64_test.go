@@ -70,3 +70,13 @@ func TestBase64Packer_Unpack(t *testing.T) {
 		t.Errorf("Expected Unpack to fail due to invalid base64 data")
 	}
 }
+
+func TestBase64Packer_Unpack_Short(t *testing.T) {
+	// Test that Unpack fails on data shorter than the message.
+
+	p := NewBase64Packer(10)
+	_, _, err := p.Unpack([]byte("SGVsbG8="))
+	if err == nil {
+		t.Errorf("Expected Unpack to fail due to short data")
+	}
+}
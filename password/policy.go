@@ -0,0 +1,241 @@
+// Package password provides the policy new account passwords must conform to.
+package password
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/hypnoglow/pascont/breach"
+	"github.com/hypnoglow/pascont/kit/form"
+)
+
+// Codes of policy violations. They are stable, so clients can rely on them,
+// e.g. to show localized messages.
+const (
+	CodeTooShort     = "password_too_short"
+	CodeTooLong      = "password_too_long"
+	CodeNoLowercase  = "password_no_lowercase"
+	CodeNoUppercase  = "password_no_uppercase"
+	CodeNoDigit      = "password_no_digit"
+	CodeNoSymbol     = "password_no_symbol"
+	CodeContainsName = "password_contains_name"
+	CodeCommon       = "password_common"
+	CodeTooWeak      = "password_too_weak"
+	CodeBreached     = "password_breached"
+)
+
+// Policy is a set of rules for new passwords. Rules with zero values are not checked.
+type Policy struct {
+	// MinLength is min len of the password in runes.
+	MinLength int
+
+	// MaxLength is max len of the password in runes.
+	MaxLength int
+
+	// RequireLowercase, RequireUppercase, RequireDigit and RequireSymbol
+	// require the password to contain a character of the class.
+	RequireLowercase bool
+	RequireUppercase bool
+	RequireDigit     bool
+	RequireSymbol    bool
+
+	// ForbidName forbids the password to contain the account name, case insensitive.
+	ForbidName bool
+
+	// CommonPasswords is a set of forbidden passwords in lower case.
+	CommonPasswords map[string]struct{}
+
+	// MinScore is min strength Score of the password.
+	MinScore int
+
+	// Breaches is a checker of passwords exposed in data breaches.
+	// Unlike other rules, it is not checked by Check, as it may fail.
+	Breaches breach.Checker
+}
+
+// DefaultPolicy is a Policy with the rules used when nothing is configured.
+var DefaultPolicy = Policy{
+	MinLength: 8,
+}
+
+// Violation is a violation of a Policy rule.
+type Violation struct {
+	Code    string
+	Message string
+}
+
+// Check checks the password of the account with the name, and returns all violated rules.
+func (p Policy) Check(name, password string) []Violation {
+	var violations []Violation
+	add := func(code, message string) {
+		violations = append(violations, Violation{code, message})
+	}
+
+	length := utf8.RuneCountInString(password)
+	if p.MinLength > 0 && length < p.MinLength {
+		add(CodeTooShort, fmt.Sprintf("Password must be at least %d characters", p.MinLength))
+	}
+	if p.MaxLength > 0 && length > p.MaxLength {
+		add(CodeTooLong, fmt.Sprintf("Password must be at most %d characters", p.MaxLength))
+	}
+
+	classes := classify(password)
+	if p.RequireLowercase && !classes.lower {
+		add(CodeNoLowercase, "Password must contain a lowercase letter")
+	}
+	if p.RequireUppercase && !classes.upper {
+		add(CodeNoUppercase, "Password must contain an uppercase letter")
+	}
+	if p.RequireDigit && !classes.digit {
+		add(CodeNoDigit, "Password must contain a digit")
+	}
+	if p.RequireSymbol && !classes.symbol {
+		add(CodeNoSymbol, "Password must contain a symbol")
+	}
+
+	if p.ForbidName && name != "" && strings.Contains(strings.ToLower(password), strings.ToLower(name)) {
+		add(CodeContainsName, "Password must not contain the account name")
+	}
+
+	if _, ok := p.CommonPasswords[strings.ToLower(password)]; ok {
+		add(CodeCommon, "Password is too common")
+	}
+
+	if p.MinScore > 0 && Score(password) < p.MinScore {
+		add(CodeTooWeak, "Password is too weak")
+	}
+
+	return violations
+}
+
+// Validate checks the password of the account with the name, including whether it is breached,
+// and adds each violated rule to the form as an error of the field, coded with the violation code.
+// Returns an error if the breaches can not be checked.
+func (p Policy) Validate(f form.Form, field, name, password string) error {
+	for _, v := range p.Check(name, password) {
+		f.AddCodedError(v.Code, v.Message, field, nil)
+	}
+
+	breached, err := p.IsBreached(password)
+	if err != nil {
+		return err
+	}
+	if breached {
+		f.AddCodedError(CodeBreached, "Password appears in a known data breach", field, nil)
+	}
+
+	return nil
+}
+
+// IsBreached reports whether the password appears in known data breaches.
+// If the policy has no Breaches checker, passwords are never breached.
+func (p Policy) IsBreached(password string) (bool, error) {
+	if p.Breaches == nil {
+		return false, nil
+	}
+
+	return p.Breaches.IsBreached(password)
+}
+
+// MaxScore is the score of the strongest passwords.
+const MaxScore = 4
+
+// Score estimates the password strength from 0 to MaxScore by its entropy.
+// Characters repeating or following the previous one, like in "aaa" or "123",
+// do not add to the entropy.
+func Score(password string) int {
+	classes := classify(password)
+
+	pool := 0
+	if classes.lower {
+		pool += 26
+	}
+	if classes.upper {
+		pool += 26
+	}
+	if classes.digit {
+		pool += 10
+	}
+	if classes.symbol {
+		pool += 33
+	}
+	if classes.other {
+		pool += 100
+	}
+
+	effective := 0
+	prev := rune(-1)
+	for _, r := range password {
+		if prev == -1 || (r != prev && r != prev+1 && r != prev-1) {
+			effective++
+		}
+		prev = r
+	}
+
+	if pool == 0 {
+		return 0
+	}
+
+	bits := float64(effective) * math.Log2(float64(pool))
+	switch {
+	case bits < 28:
+		return 0
+	case bits < 36:
+		return 1
+	case bits < 60:
+		return 2
+	case bits < 80:
+		return 3
+	default:
+		return MaxScore
+	}
+}
+
+// classes are character classes present in a password.
+type classes struct {
+	lower  bool
+	upper  bool
+	digit  bool
+	symbol bool
+	other  bool
+}
+
+func classify(password string) (c classes) {
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			c.lower = true
+		case unicode.IsUpper(r):
+			c.upper = true
+		case unicode.IsDigit(r):
+			c.digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			c.symbol = true
+		default:
+			c.other = true
+		}
+	}
+	return c
+}
+
+// ReadCommonPasswords reads a list of common passwords, one per line, as a set
+// for Policy.CommonPasswords. Empty lines and lines starting with "#" are skipped.
+func ReadCommonPasswords(r io.Reader) (map[string]struct{}, error) {
+	passwords := make(map[string]struct{})
+
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		passwords[strings.ToLower(line)] = struct{}{}
+	}
+
+	return passwords, s.Err()
+}
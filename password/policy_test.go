@@ -0,0 +1,198 @@
+package password
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hypnoglow/pascont/breach"
+	"github.com/hypnoglow/pascont/kit/form"
+)
+
+func TestPolicy_Check(t *testing.T) {
+	strict := Policy{
+		MinLength:        10,
+		MaxLength:        20,
+		RequireLowercase: true,
+		RequireUppercase: true,
+		RequireDigit:     true,
+		RequireSymbol:    true,
+		ForbidName:       true,
+		CommonPasswords:  map[string]struct{}{"password": {}},
+		MinScore:         3,
+	}
+
+	cases := []struct {
+		caseName           string
+		policy             Policy
+		name               string
+		password           string
+		expectedViolations []Violation
+	}{
+		{
+			caseName: "Default policy with too short password",
+			policy:   DefaultPolicy,
+			name:     "john",
+			password: "pass",
+			expectedViolations: []Violation{
+				{CodeTooShort, "Password must be at least 8 characters"},
+			},
+		},
+		{
+			caseName:           "Length is counted in characters",
+			policy:             DefaultPolicy,
+			name:               "john",
+			password:           "пароль12",
+			expectedViolations: nil,
+		},
+		{
+			caseName: "Strict policy with common password",
+			policy:   strict,
+			name:     "john",
+			password: "PASSWORD",
+			expectedViolations: []Violation{
+				{CodeTooShort, "Password must be at least 10 characters"},
+				{CodeNoLowercase, "Password must contain a lowercase letter"},
+				{CodeNoDigit, "Password must contain a digit"},
+				{CodeNoSymbol, "Password must contain a symbol"},
+				{CodeCommon, "Password is too common"},
+				{CodeTooWeak, "Password is too weak"},
+			},
+		},
+		{
+			caseName: "Strict policy with password containing the name",
+			policy:   strict,
+			name:     "john",
+			password: "x9!Kq#JOHN-z7$Lmw2Pa&",
+			expectedViolations: []Violation{
+				{CodeTooLong, "Password must be at most 20 characters"},
+				{CodeContainsName, "Password must not contain the account name"},
+			},
+		},
+		{
+			caseName:           "Strict policy with strong password",
+			policy:             strict,
+			name:               "john",
+			password:           "x9!Kq#vR-z7$Lmw2",
+			expectedViolations: nil,
+		},
+		{
+			caseName:           "Empty policy",
+			policy:             Policy{},
+			name:               "john",
+			password:           "",
+			expectedViolations: nil,
+		},
+	}
+
+	for i, c := range cases {
+		violations := c.policy.Check(c.name, c.password)
+		if !reflect.DeepEqual(violations, c.expectedViolations) {
+			t.Errorf("testcase %d %s: Expected violations to be\n%#v\nbut got\n%#v\n", i, c.caseName, c.expectedViolations, violations)
+		}
+	}
+}
+
+func TestScore(t *testing.T) {
+	cases := []struct {
+		password      string
+		expectedScore int
+	}{
+		{"", 0},
+		{"aaaaaaaaaaaa", 0},
+		{"123456789", 0},
+		{"abcdefghijkl", 0},
+		{"qwerty", 1},
+		{"password", 1},
+		{"Password1", 2},
+		{"x9!Kq#vR-z7$Lmw2", 4},
+		{"correct horse battery staple", 4},
+	}
+
+	for i, c := range cases {
+		if score := Score(c.password); score != c.expectedScore {
+			t.Errorf("testcase %d %q: Expected score to be %d, but got %d\n", i, c.password, c.expectedScore, score)
+		}
+	}
+}
+
+func TestReadCommonPasswords(t *testing.T) {
+	r := strings.NewReader("# Common passwords\n123456\n\nPassword\n  qwerty  \n")
+
+	passwords, err := ReadCommonPasswords(r)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+
+	expected := map[string]struct{}{"123456": {}, "password": {}, "qwerty": {}}
+	if !reflect.DeepEqual(passwords, expected) {
+		t.Errorf("Expected passwords to be %v, but got %v\n", expected, passwords)
+	}
+}
+
+func TestPolicy_IsBreached(t *testing.T) {
+	breached, err := Policy{}.IsBreached("password")
+	if err != nil || breached {
+		t.Errorf("Expected policy without checker not to report breaches, but got %v, %v\n", breached, err)
+	}
+
+	p := Policy{
+		Breaches: breach.NewFakeChecker([]breach.FakeCheckerIsBreachedResult{
+			{
+				Breached: true,
+			},
+		}),
+	}
+	breached, err = p.IsBreached("password")
+	if err != nil || !breached {
+		t.Errorf("Expected password to be breached, but got %v, %v\n", breached, err)
+	}
+}
+
+type testForm struct {
+	form.BaseForm
+}
+
+func (f *testForm) Validate() bool {
+	return len(f.ValidationErrors()) == 0
+}
+
+func TestPolicy_Validate(t *testing.T) {
+	f := &testForm{}
+	p := Policy{
+		MinLength:  8,
+		ForbidName: true,
+		Breaches: breach.NewFakeChecker([]breach.FakeCheckerIsBreachedResult{
+			{
+				Breached: true,
+			},
+		}),
+	}
+	if err := p.Validate(f, "password", "admin", "admin"); err != nil {
+		t.Fatalf("Expected no error, but got %s\n", err)
+	}
+
+	expected := []form.FormError{
+		{Message: "Password must be at least 8 characters", Field: "password", Code: CodeTooShort},
+		{Message: "Password must not contain the account name", Field: "password", Code: CodeContainsName},
+		{Message: "Password appears in a known data breach", Field: "password", Code: CodeBreached},
+	}
+	if !reflect.DeepEqual(f.ValidationErrors(), expected) {
+		t.Errorf("Expected errors to be %v, but got %v\n", expected, f.ValidationErrors())
+	}
+}
+
+func TestPolicy_Validate_BreachesError(t *testing.T) {
+	f := &testForm{}
+	p := Policy{
+		Breaches: breach.NewFakeChecker([]breach.FakeCheckerIsBreachedResult{
+			{
+				Error: fmt.Errorf("IsBreached failed"),
+			},
+		}),
+	}
+	if err := p.Validate(f, "password", "admin", "password"); err == nil {
+		t.Errorf("Expected an error, but got nil\n")
+	}
+}
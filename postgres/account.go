@@ -3,11 +3,14 @@ package postgres
 import (
 	"database/sql"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/lib/pq"
 	"github.com/pkg/errors"
 
 	"github.com/hypnoglow/pascont/account"
+	"github.com/hypnoglow/pascont/outbox"
 )
 
 const accountTable = "account"
@@ -21,26 +24,51 @@ func NewAccountRepository(db *sql.DB) account.Repository {
 	return &accountRepository{db: db}
 }
 
-func (r accountRepository) Accept(app account.Application) (acc *account.Account, err error) {
+func (r accountRepository) Accept(app account.Application, messages ...outbox.Message) (acc *account.Account, err error) {
 	q := fmt.Sprintf(`
 		INSERT INTO %s
-			(name, password_hash, created_at, updated_at)
+			(tenant_id, name, password_hash, created_at, updated_at, verified_at)
 		VALUES
-			($1, $2, $3, $3)
-		RETURNING id, name, created_at, updated_At
+			($1, $2, $3, $4, $4, $5)
+		RETURNING id, tenant_id, name, created_at, updated_at, status, verified_at
 	`, pq.QuoteIdentifier(accountTable))
 
-	err = r.db.QueryRow(q, app.Name, app.PasswordHash, app.CreatedAt).Scan(
-		&acc.ID,
-		&acc.Name,
-		&acc.CreatedAt,
-		&acc.UpdatedAt,
-	)
+	// The messages are about the new account, which ID is known only after it is added.
+	messages = append([]outbox.Message{}, messages...)
+
+	err = withOutbox(r.db, messages, func(ex execer) error {
+		acc = &account.Account{}
+		verifiedAt := pq.NullTime{Time: app.VerifiedAt, Valid: !app.VerifiedAt.IsZero()}
+		err := ex.QueryRow(q, app.TenantID, app.Name, app.PasswordHash, app.CreatedAt, verifiedAt).Scan(
+			&acc.ID,
+			&acc.TenantID,
+			&acc.Name,
+			&acc.CreatedAt,
+			&acc.UpdatedAt,
+			&acc.Status,
+			&verifiedAt,
+		)
+		acc.VerifiedAt = verifiedAt.Time
+		if isUniqueViolation(err) {
+			return account.ErrAlreadyExists
+		}
+		if err != nil {
+			return errors.Wrap(err, "Failed to accept an application")
+		}
+
+		for i := range messages {
+			messages[i].Notification.AccountID = acc.ID
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
 
-	return acc, errors.Wrap(err, "Failed to accept an application")
+	return acc, nil
 }
 
-func (r accountRepository) Save(acc account.Account, passwordHash []byte) error {
+func (r accountRepository) Save(acc account.Account, passwordHash []byte, messages ...outbox.Message) error {
 	if acc.ID == 0 {
 		return account.ErrNoIdentity
 	}
@@ -55,31 +83,193 @@ func (r accountRepository) Save(acc account.Account, passwordHash []byte) error
 
 	`, pq.QuoteIdentifier(accountTable), pq.QuoteIdentifier(accountTable))
 
-	_, err := r.db.Exec(q, acc.ID, acc.Name, passwordHash, acc.CreatedAt, acc.UpdatedAt)
-	return errors.Wrap(err, "Failed to save an account")
+	return withOutbox(r.db, messages, func(ex execer) error {
+		_, err := ex.Exec(q, acc.ID, acc.Name, passwordHash, acc.CreatedAt, acc.UpdatedAt)
+		return errors.Wrap(err, "Failed to save an account")
+	})
+}
+
+func (r accountRepository) ChangePassword(change account.PasswordChange) error {
+	if change.Account.ID == 0 {
+		return account.ErrNoIdentity
+	}
+
+	return withTx(r.db, func(ex execer) error {
+		return changePassword(ex, change)
+	})
+}
+
+// changePassword applies the password change with ex, which MUST be a transaction.
+func changePassword(ex execer, change account.PasswordChange) error {
+	acc := change.Account
+	q := fmt.Sprintf(`
+		UPDATE %s
+		SET
+			password_hash = $2,
+			updated_at = $3,
+			failed_login_attempts = 0,
+			locked_until = NULL
+		WHERE
+			id = $1
+	`, pq.QuoteIdentifier(accountTable))
+
+	res, err := ex.Exec(q, acc.ID, change.PasswordHash, acc.UpdatedAt)
+	if err != nil {
+		return errors.Wrap(err, "Failed to change an account password")
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "Failed to change an account password")
+	}
+	if n == 0 {
+		return account.ErrNotFound
+	}
+
+	if err := deleteAccountSessions(ex, acc.ID); err != nil {
+		return err
+	}
+	if change.Session != nil {
+		if err := saveSession(ex, *change.Session); err != nil {
+			return err
+		}
+	}
+
+	return addOutboxMessages(ex, change.Messages)
+}
+
+func (r accountRepository) FindByID(id int64) (*account.Account, error) {
+	q := fmt.Sprintf(`
+		SELECT
+			id, tenant_id, name, created_at, updated_at, status, failed_login_attempts, locked_until, verified_at
+		FROM
+			%s
+		WHERE
+			id = $1
+	`, pq.QuoteIdentifier(accountTable))
+
+	acc := &account.Account{}
+	var lockedUntil pq.NullTime
+	var verifiedAt pq.NullTime
+	err := r.db.QueryRow(q, id).Scan(
+		&acc.ID,
+		&acc.TenantID,
+		&acc.Name,
+		&acc.CreatedAt,
+		&acc.UpdatedAt,
+		&acc.Status,
+		&acc.FailedLoginAttempts,
+		&lockedUntil,
+		&verifiedAt,
+	)
+	acc.LockedUntil = lockedUntil.Time
+	acc.VerifiedAt = verifiedAt.Time
+	if err == sql.ErrNoRows {
+		err = account.ErrNotFound
+	} else {
+		err = errors.Wrap(err, "Failed to find an account")
+	}
+
+	return acc, err
 }
 
-func (r accountRepository) FindWithPasswordHashByUsername(name string) (*account.Account, []byte, error) {
+func (r accountRepository) List(query string, offset, limit int) ([]*account.Account, int, error) {
+	// Wildcards in the query are matched literally.
+	pattern := "%" + strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(query) + "%"
+
 	q := fmt.Sprintf(`
 		SELECT
-			id, name, password_hash, created_at, updated_at
+			COUNT(*)
 		FROM
 			%s
 		WHERE
-			name = $1
+			name ILIKE $1
+	`, pq.QuoteIdentifier(accountTable))
+
+	var total int
+	if err := r.db.QueryRow(q, pattern).Scan(&total); err != nil {
+		return nil, 0, errors.Wrap(err, "Failed to count accounts")
+	}
+
+	q = fmt.Sprintf(`
+		SELECT
+			id, tenant_id, name, created_at, updated_at, status, failed_login_attempts, locked_until, verified_at
+		FROM
+			%s
+		WHERE
+			name ILIKE $1
+		ORDER BY
+			id
+		OFFSET $2
+		LIMIT $3
+	`, pq.QuoteIdentifier(accountTable))
+
+	rows, err := r.db.Query(q, pattern, offset, limit)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "Failed to list accounts")
+	}
+	defer rows.Close()
+
+	var accounts []*account.Account
+	for rows.Next() {
+		acc := &account.Account{}
+		var lockedUntil pq.NullTime
+		var verifiedAt pq.NullTime
+		err := rows.Scan(
+			&acc.ID,
+			&acc.TenantID,
+			&acc.Name,
+			&acc.CreatedAt,
+			&acc.UpdatedAt,
+			&acc.Status,
+			&acc.FailedLoginAttempts,
+			&lockedUntil,
+			&verifiedAt,
+		)
+		if err != nil {
+			return nil, 0, errors.Wrap(err, "Failed to list accounts")
+		}
+		acc.LockedUntil = lockedUntil.Time
+		acc.VerifiedAt = verifiedAt.Time
+		accounts = append(accounts, acc)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, errors.Wrap(err, "Failed to list accounts")
+	}
+
+	return accounts, total, nil
+}
+
+func (r accountRepository) FindWithPasswordHashByUsername(tenantID int64, name string) (*account.Account, []byte, error) {
+	q := fmt.Sprintf(`
+		SELECT
+			id, tenant_id, name, password_hash, created_at, updated_at, status, failed_login_attempts, locked_until, verified_at
+		FROM
+			%s
+		WHERE
+			tenant_id = $1
+			AND name = $2
 
 	`, pq.QuoteIdentifier(accountTable))
 
 	acc := &account.Account{}
 	var passwordHash []byte
+	var lockedUntil pq.NullTime
+	var verifiedAt pq.NullTime
 
-	err := r.db.QueryRow(q, name).Scan(
+	err := r.db.QueryRow(q, tenantID, name).Scan(
 		&acc.ID,
+		&acc.TenantID,
 		&acc.Name,
 		&passwordHash,
 		&acc.CreatedAt,
 		&acc.UpdatedAt,
+		&acc.Status,
+		&acc.FailedLoginAttempts,
+		&lockedUntil,
+		&verifiedAt,
 	)
+	acc.LockedUntil = lockedUntil.Time
+	acc.VerifiedAt = verifiedAt.Time
 	if err == sql.ErrNoRows {
 		err = account.ErrNotFound
 	} else {
@@ -89,18 +279,179 @@ func (r accountRepository) FindWithPasswordHashByUsername(name string) (*account
 	return acc, passwordHash, err
 }
 
-func (r accountRepository) Exists(name string) (bool, error) {
+func (r accountRepository) FindWithPasswordHashByID(id int64) (*account.Account, []byte, error) {
+	q := fmt.Sprintf(`
+		SELECT
+			id, tenant_id, name, password_hash, created_at, updated_at, status, failed_login_attempts, locked_until, verified_at
+		FROM
+			%s
+		WHERE
+			id = $1
+
+	`, pq.QuoteIdentifier(accountTable))
+
+	acc := &account.Account{}
+	var passwordHash []byte
+	var lockedUntil pq.NullTime
+	var verifiedAt pq.NullTime
+
+	err := r.db.QueryRow(q, id).Scan(
+		&acc.ID,
+		&acc.TenantID,
+		&acc.Name,
+		&passwordHash,
+		&acc.CreatedAt,
+		&acc.UpdatedAt,
+		&acc.Status,
+		&acc.FailedLoginAttempts,
+		&lockedUntil,
+		&verifiedAt,
+	)
+	acc.LockedUntil = lockedUntil.Time
+	acc.VerifiedAt = verifiedAt.Time
+	if err == sql.ErrNoRows {
+		err = account.ErrNotFound
+	} else {
+		err = errors.Wrap(err, "Failed to find an account")
+	}
+
+	return acc, passwordHash, err
+}
+
+func (r accountRepository) UpdateStatus(id int64, status account.Status, messages ...outbox.Message) error {
+	return withTx(r.db, func(ex execer) error {
+		if err := updateStatus(ex, id, status); err != nil {
+			return err
+		}
+		if status != account.StatusActive {
+			if err := deleteAccountSessions(ex, id); err != nil {
+				return err
+			}
+		}
+		return addOutboxMessages(ex, messages)
+	})
+}
+
+// updateStatus sets the status of the account with ex.
+func updateStatus(ex execer, id int64, status account.Status) error {
+	q := fmt.Sprintf(`
+		UPDATE %s
+		SET
+			status = $2
+		WHERE
+			id = $1
+	`, pq.QuoteIdentifier(accountTable))
+
+	res, err := ex.Exec(q, id, status)
+	if err != nil {
+		return errors.Wrap(err, "Failed to update account status")
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "Failed to update account status")
+	}
+	if n == 0 {
+		return account.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r accountRepository) Verify(id int64, verifiedAt time.Time) error {
+	q := fmt.Sprintf(`
+		UPDATE %s
+		SET
+			verified_at = COALESCE(verified_at, $2)
+		WHERE
+			id = $1
+	`, pq.QuoteIdentifier(accountTable))
+
+	res, err := r.db.Exec(q, id, verifiedAt)
+	if err != nil {
+		return errors.Wrap(err, "Failed to verify an account")
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "Failed to verify an account")
+	}
+	if n == 0 {
+		return account.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r accountRepository) RegisterFailedLogin(id int64, threshold int, lockedUntil time.Time) (bool, error) {
+	// Counter is incremented and checked in a single statement,
+	// so concurrent failed logins can not exceed the threshold unnoticed.
+	q := fmt.Sprintf(`
+		UPDATE %s
+		SET
+			failed_login_attempts = CASE
+				WHEN failed_login_attempts + 1 >= $2 THEN 0
+				ELSE failed_login_attempts + 1
+			END,
+			locked_until = CASE
+				WHEN failed_login_attempts + 1 >= $2 THEN $3
+				ELSE locked_until
+			END
+		WHERE
+			id = $1
+		RETURNING failed_login_attempts = 0
+	`, pq.QuoteIdentifier(accountTable))
+
+	var locked bool
+	err := r.db.QueryRow(q, id, threshold, lockedUntil).Scan(&locked)
+	if err == sql.ErrNoRows {
+		return false, account.ErrNotFound
+	}
+
+	return locked, errors.Wrap(err, "Failed to register a failed login")
+}
+
+func (r accountRepository) ResetFailedLogins(id int64) error {
+	q := fmt.Sprintf(`
+		UPDATE %s
+		SET
+			failed_login_attempts = 0,
+			locked_until = NULL
+		WHERE
+			id = $1
+	`, pq.QuoteIdentifier(accountTable))
+
+	_, err := r.db.Exec(q, id)
+	return errors.Wrap(err, "Failed to reset failed logins")
+}
+
+func (r accountRepository) Delete(id int64, messages ...outbox.Message) error {
+	q := fmt.Sprintf(`
+		DELETE FROM
+			%s
+		WHERE
+			id = $1
+	`, pq.QuoteIdentifier(accountTable))
+
+	return withOutbox(r.db, messages, func(ex execer) error {
+		_, err := ex.Exec(q, id)
+		return errors.Wrap(err, "Failed to delete an account")
+	})
+}
+
+func (r accountRepository) Exists(tenantID int64, name string) (bool, error) {
 	q := fmt.Sprintf(`
 		SELECT
 			id
 		FROM
 			%s
 		WHERE
-			name = $1
+			tenant_id = $1
+			AND name = $2
 	`, pq.QuoteIdentifier(accountTable))
 
 	var id int64
-	if err := r.db.QueryRow(q, name).Scan(&id); err != nil {
+	if err := r.db.QueryRow(q, tenantID, name).Scan(&id); err != nil {
 		if err == sql.ErrNoRows {
 			return false, nil
 		}
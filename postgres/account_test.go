@@ -1,3 +1,352 @@
 package postgres
 
-// TODO
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/hypnoglow/pascont/account"
+	"github.com/hypnoglow/pascont/identity"
+	"github.com/hypnoglow/pascont/notifier"
+	"github.com/hypnoglow/pascont/outbox"
+	"github.com/hypnoglow/pascont/session"
+)
+
+// testDatabaseDSNEnv is an environment variable holding a DSN of the database
+// with pascont schema applied. Tests that need a real database are skipped
+// when it is not set.
+const testDatabaseDSNEnv = "PASCONT_TEST_DATABASE_DSN"
+
+func openTestDB(t *testing.T) *sql.DB {
+	dsn := os.Getenv(testDatabaseDSNEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set", testDatabaseDSNEnv)
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("Failed to open database: %s", err)
+	}
+	if err = db.Ping(); err != nil {
+		t.Fatalf("Failed to ping database: %s", err)
+	}
+
+	return db
+}
+
+func defaultTenantID(t *testing.T, db *sql.DB) int64 {
+	tn, err := NewTenantRepository(db).FindByName("default")
+	if err != nil {
+		t.Fatalf("Failed to find the default tenant: %s", err)
+	}
+
+	return tn.ID
+}
+
+func TestAccountRepository_Accept(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	repo := NewAccountRepository(db)
+	tenantID := defaultTenantID(t, db)
+	name := fmt.Sprintf("accept-%d", time.Now().UnixNano())
+
+	acc, err := repo.Accept(account.NewApplication(tenantID, name, []byte("password_hash"), time.Now()))
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	defer repo.Delete(acc.ID)
+
+	if acc.ID == 0 || acc.Name != name {
+		t.Errorf("Expected accepted account with name %s, but got %#v", name, acc)
+	}
+
+	_, err = repo.Accept(account.NewApplication(tenantID, name, []byte("password_hash"), time.Now()))
+	if err != account.ErrAlreadyExists {
+		t.Errorf("Expected error to be %v, but got %v", account.ErrAlreadyExists, err)
+	}
+}
+
+func TestAccountRepository_Accept_Messages(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	repo := NewAccountRepository(db)
+	outboxRepo := NewOutboxRepository(db)
+	tenantID := defaultTenantID(t, db)
+	name := fmt.Sprintf("accept-messages-%d", time.Now().UnixNano())
+	now := time.Now()
+
+	m := outbox.Message{
+		ID:      identity.NewUUIDV4(),
+		Channel: "smtp",
+		Notification: notifier.Notification{
+			Kind:      notifier.KindVerification,
+			TenantID:  tenantID,
+			Recipient: name,
+		},
+		Status:        outbox.StatusPending,
+		CreatedAt:     now,
+		NextAttemptAt: now,
+	}
+
+	acc, err := repo.Accept(account.NewApplication(tenantID, name, []byte("password_hash"), now), m)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	defer repo.Delete(acc.ID)
+	defer db.Exec(fmt.Sprintf("DELETE FROM %s WHERE id = $1", pq.QuoteIdentifier(outboxTable)), m.ID)
+
+	found, err := outboxRepo.FindByID(m.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	if found.Notification.AccountID != acc.ID {
+		t.Errorf("Expected message to be about account %d, but got %d", acc.ID, found.Notification.AccountID)
+	}
+
+	// The message is not added if the account is not.
+	other := m
+	other.ID = identity.NewUUIDV4()
+	_, err = repo.Accept(account.NewApplication(tenantID, name, []byte("password_hash"), now), other)
+	if err != account.ErrAlreadyExists {
+		t.Errorf("Expected error to be %v, but got %v", account.ErrAlreadyExists, err)
+	}
+	if _, err := outboxRepo.FindByID(other.ID); err != outbox.ErrNotFound {
+		t.Errorf("Expected error to be %v, but got %v", outbox.ErrNotFound, err)
+	}
+}
+
+func TestAccountRepository_Accept_Concurrent(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	repo := NewAccountRepository(db)
+	tenantID := defaultTenantID(t, db)
+	name := fmt.Sprintf("concurrent-%d", time.Now().UnixNano())
+
+	const workers = 10
+
+	var wg sync.WaitGroup
+	accounts := make([]*account.Account, workers)
+	errs := make([]error, workers)
+
+	start := make(chan struct{})
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			accounts[i], errs[i] = repo.Accept(account.NewApplication(tenantID, name, []byte("password_hash"), time.Now()))
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	accepted := 0
+	for i, err := range errs {
+		switch err {
+		case nil:
+			accepted++
+			defer repo.Delete(accounts[i].ID)
+		case account.ErrAlreadyExists:
+		default:
+			t.Errorf("worker %d: Expected no error or %v, but got %v", i, account.ErrAlreadyExists, err)
+		}
+	}
+
+	if accepted != 1 {
+		t.Errorf("Expected exactly 1 account to be accepted, but got %d", accepted)
+	}
+}
+
+func TestAccountRepository_UpdateStatus(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	repo := NewAccountRepository(db)
+	sessionRepo := NewSessionRepository(db)
+	tenantID := defaultTenantID(t, db)
+	name := fmt.Sprintf("status-%d", time.Now().UnixNano())
+
+	acc, err := repo.Accept(account.NewApplication(tenantID, name, []byte("password_hash"), time.Now()))
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	defer repo.Delete(acc.ID)
+
+	if acc.Status != account.StatusActive {
+		t.Errorf("Expected new account status to be %v, but got %v", account.StatusActive, acc.Status)
+	}
+
+	now := time.Now()
+	sess := session.NewSession(identity.NewUUIDV4(), acc.ID, now, now.Add(time.Hour))
+	sess.TenantID = tenantID
+	if err = sessionRepo.Save(*sess); err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+
+	if err = repo.UpdateStatus(acc.ID, account.StatusDisabled); err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	if _, err = sessionRepo.FindByID(sess.ID); err != session.ErrNotFound {
+		t.Errorf("Expected session of the disabled account to be revoked, but got %v", err)
+	}
+
+	acc, err = repo.FindByID(acc.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	if acc.Status != account.StatusDisabled {
+		t.Errorf("Expected account status to be %v, but got %v", account.StatusDisabled, acc.Status)
+	}
+
+	if err = repo.UpdateStatus(-1, account.StatusDisabled); err != account.ErrNotFound {
+		t.Errorf("Expected error to be %v, but got %v", account.ErrNotFound, err)
+	}
+}
+
+func TestAccountRepository_ChangePassword(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	repo := NewAccountRepository(db)
+	sessionRepo := NewSessionRepository(db)
+	tenantID := defaultTenantID(t, db)
+	name := fmt.Sprintf("password-%d", time.Now().UnixNano())
+
+	acc, err := repo.Accept(account.NewApplication(tenantID, name, []byte("password_hash"), time.Now()))
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	defer repo.Delete(acc.ID)
+
+	now := time.Now()
+	if _, err = repo.RegisterFailedLogin(acc.ID, 1, now.Add(time.Hour)); err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	old := session.NewSession(identity.NewUUIDV4(), acc.ID, now, now.Add(time.Hour))
+	old.TenantID = tenantID
+	if err = sessionRepo.Save(*old); err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+
+	issued := session.NewSession(identity.NewUUIDV4(), acc.ID, now, now.Add(time.Hour))
+	issued.TenantID = tenantID
+	err = repo.ChangePassword(account.PasswordChange{
+		Account:      *acc,
+		PasswordHash: []byte("new_password_hash"),
+		Session:      issued,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+
+	_, hash, err := repo.FindWithPasswordHashByID(acc.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	if string(hash) != "new_password_hash" {
+		t.Errorf("Expected password hash to be changed, but got %s", hash)
+	}
+	if acc, err = repo.FindByID(acc.ID); err != nil || acc.IsLocked(now) {
+		t.Errorf("Expected account to be unlocked, but got %#v, %v", acc, err)
+	}
+	if _, err = sessionRepo.FindByID(old.ID); err != session.ErrNotFound {
+		t.Errorf("Expected old session to be revoked, but got %v", err)
+	}
+	if _, err = sessionRepo.FindByID(issued.ID); err != nil {
+		t.Errorf("Expected issued session to be saved, but got %v", err)
+	}
+
+	err = repo.ChangePassword(account.PasswordChange{Account: account.Account{ID: -1}})
+	if err != account.ErrNotFound {
+		t.Errorf("Expected error to be %v, but got %v", account.ErrNotFound, err)
+	}
+}
+
+func TestAccountRepository_Verify(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	repo := NewAccountRepository(db)
+	tenantID := defaultTenantID(t, db)
+	name := fmt.Sprintf("verify-%d", time.Now().UnixNano())
+
+	app := account.NewApplication(tenantID, name, []byte("password_hash"), time.Now())
+	app.VerifiedAt = time.Time{}
+	acc, err := repo.Accept(app)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	defer repo.Delete(acc.ID)
+
+	if acc.IsVerified() {
+		t.Errorf("Expected new account to be pending verification, but it is verified at %v", acc.VerifiedAt)
+	}
+
+	verifiedAt := time.Now().UTC().Truncate(time.Second)
+	if err = repo.Verify(acc.ID, verifiedAt); err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	// Verifying again keeps the time of the first verification.
+	if err = repo.Verify(acc.ID, verifiedAt.Add(time.Hour)); err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+
+	acc, err = repo.FindByID(acc.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	if !acc.VerifiedAt.Equal(verifiedAt) {
+		t.Errorf("Expected account to be verified at %v, but got %v", verifiedAt, acc.VerifiedAt)
+	}
+
+	if err = repo.Verify(-1, verifiedAt); err != account.ErrNotFound {
+		t.Errorf("Expected error to be %v, but got %v", account.ErrNotFound, err)
+	}
+}
+
+func TestAccountRepository_Accept_SameNameInOtherTenant(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	repo := NewAccountRepository(db)
+	tenantID := defaultTenantID(t, db)
+	name := fmt.Sprintf("tenant-%d", time.Now().UnixNano())
+
+	var otherTenantID int64
+	if err := db.QueryRow(`INSERT INTO tenant (name) VALUES ($1) RETURNING id`, name).Scan(&otherTenantID); err != nil {
+		t.Fatalf("Failed to create a tenant: %s", err)
+	}
+	defer db.Exec(`DELETE FROM tenant WHERE id = $1`, otherTenantID)
+
+	acc, err := repo.Accept(account.NewApplication(tenantID, name, []byte("password_hash"), time.Now()))
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	defer repo.Delete(acc.ID)
+
+	other, err := repo.Accept(account.NewApplication(otherTenantID, name, []byte("password_hash"), time.Now()))
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	defer repo.Delete(other.ID)
+
+	if other.TenantID != otherTenantID {
+		t.Errorf("Expected account tenant to be %d, but got %d", otherTenantID, other.TenantID)
+	}
+
+	found, _, err := repo.FindWithPasswordHashByUsername(otherTenantID, name)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	if found.ID != other.ID {
+		t.Errorf("Expected account %d to be found, but got %d", other.ID, found.ID)
+	}
+}
@@ -0,0 +1,168 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+
+	"github.com/hypnoglow/pascont/apikey"
+)
+
+const apiKeyTable = "api_key"
+
+type apiKeyRepository struct {
+	db *sql.DB
+}
+
+// NewAPIKeyRepository returns a new apikey.Repository with PostgreSQL as a storage.
+func NewAPIKeyRepository(db *sql.DB) apikey.Repository {
+	return &apiKeyRepository{db: db}
+}
+
+func (r apiKeyRepository) Save(k apikey.APIKey) error {
+	q := fmt.Sprintf(`
+		INSERT INTO %s
+			(id, account_id, name, hash, created_at, expires_at, last_used_at)
+		VALUES
+			($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (id) DO
+			UPDATE SET
+				(account_id, name, hash, created_at, expires_at, last_used_at)
+				= ($2, $3, $4, $5, $6, $7)
+			WHERE
+				%s.id = $1
+	`, pq.QuoteIdentifier(apiKeyTable), pq.QuoteIdentifier(apiKeyTable))
+
+	_, err := r.db.Exec(
+		q,
+		k.ID,
+		k.AccountID,
+		k.Name,
+		k.Hash,
+		k.CreatedAt,
+		pq.NullTime{Time: k.ExpiresAt, Valid: !k.ExpiresAt.IsZero()},
+		pq.NullTime{Time: k.LastUsedAt, Valid: !k.LastUsedAt.IsZero()},
+	)
+	if isUniqueViolation(err) {
+		return apikey.ErrAlreadyExists
+	}
+
+	return errors.Wrap(err, "Failed to save an API key")
+}
+
+func (r apiKeyRepository) FindByID(id string) (*apikey.APIKey, error) {
+	q := fmt.Sprintf(`
+		SELECT
+			id, account_id, name, hash, created_at, expires_at, last_used_at
+		FROM
+			%s
+		WHERE
+			id = $1
+	`, pq.QuoteIdentifier(apiKeyTable))
+
+	k, err := scanAPIKey(r.db.QueryRow(q, id))
+	if err == sql.ErrNoRows {
+		return nil, apikey.ErrNotFound
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to find an API key")
+	}
+
+	return k, nil
+}
+
+func (r apiKeyRepository) ListByAccount(accountID int64) ([]*apikey.APIKey, error) {
+	q := fmt.Sprintf(`
+		SELECT
+			id, account_id, name, hash, created_at, expires_at, last_used_at
+		FROM
+			%s
+		WHERE
+			account_id = $1
+		ORDER BY
+			created_at, id
+	`, pq.QuoteIdentifier(apiKeyTable))
+
+	rows, err := r.db.Query(q, accountID)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to list account API keys")
+	}
+	defer rows.Close()
+
+	var keys []*apikey.APIKey
+	for rows.Next() {
+		k, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to list account API keys")
+		}
+		keys = append(keys, k)
+	}
+
+	return keys, errors.Wrap(rows.Err(), "Failed to list account API keys")
+}
+
+func (r apiKeyRepository) Touch(id string, lastUsedAt time.Time) error {
+	q := fmt.Sprintf(`
+		UPDATE %s
+		SET
+			last_used_at = $2
+		WHERE
+			id = $1
+	`, pq.QuoteIdentifier(apiKeyTable))
+
+	_, err := r.db.Exec(q, id, lastUsedAt)
+	return errors.Wrap(err, "Failed to touch an API key")
+}
+
+func (r apiKeyRepository) Delete(accountID int64, id string) error {
+	q := fmt.Sprintf(`
+		DELETE FROM
+			%s
+		WHERE
+			account_id = $1
+			AND id = $2
+	`, pq.QuoteIdentifier(apiKeyTable))
+
+	res, err := r.db.Exec(q, accountID, id)
+	if err != nil {
+		return errors.Wrap(err, "Failed to delete an API key")
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "Failed to delete an API key")
+	}
+	if n == 0 {
+		return apikey.ErrNotFound
+	}
+
+	return nil
+}
+
+// scanner is implemented by both sql.Row and sql.Rows.
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanAPIKey(s scanner) (*apikey.APIKey, error) {
+	k := &apikey.APIKey{}
+	var expiresAt, lastUsedAt pq.NullTime
+	if err := s.Scan(
+		&k.ID,
+		&k.AccountID,
+		&k.Name,
+		&k.Hash,
+		&k.CreatedAt,
+		&expiresAt,
+		&lastUsedAt,
+	); err != nil {
+		return nil, err
+	}
+	k.ExpiresAt = expiresAt.Time
+	k.LastUsedAt = lastUsedAt.Time
+
+	return k, nil
+}
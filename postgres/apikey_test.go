@@ -0,0 +1,62 @@
+package postgres
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/pascont/account"
+	"github.com/hypnoglow/pascont/apikey"
+	"github.com/hypnoglow/pascont/identity"
+)
+
+func TestAPIKeyRepository(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	accountRepo := NewAccountRepository(db)
+	repo := NewAPIKeyRepository(db)
+	name := fmt.Sprintf("apikey-%d", time.Now().UnixNano())
+
+	acc, err := accountRepo.Accept(account.NewApplication(defaultTenantID(t, db), name, []byte("password_hash"), time.Now()))
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	defer accountRepo.Delete(acc.ID)
+
+	key, _, err := apikey.Generate(identity.NewUUIDV4, acc.ID, "ci", time.Now(), time.Time{})
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	if err = repo.Save(*key); err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+
+	found, err := repo.FindByID(key.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	if found.Name != "ci" || !found.ExpiresAt.IsZero() || !found.LastUsedAt.IsZero() {
+		t.Errorf("Unexpected API key %#v", found)
+	}
+
+	duplicate, _, err := apikey.Generate(identity.NewUUIDV4, acc.ID, "ci", time.Now(), time.Time{})
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	if err = repo.Save(*duplicate); err != apikey.ErrAlreadyExists {
+		t.Errorf("Expected error to be %v, but got %v", apikey.ErrAlreadyExists, err)
+	}
+
+	if err = repo.Delete(acc.ID+1, key.ID); err != apikey.ErrNotFound {
+		t.Errorf("Expected error to be %v, but got %v", apikey.ErrNotFound, err)
+	}
+
+	if err = repo.Delete(acc.ID, key.ID); err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+
+	if _, err = repo.FindByID(key.ID); err != apikey.ErrNotFound {
+		t.Errorf("Expected error to be %v, but got %v", apikey.ErrNotFound, err)
+	}
+}
@@ -0,0 +1,21 @@
+package postgres
+
+import "github.com/lib/pq"
+
+// uniqueViolation is the PostgreSQL error code for unique_violation.
+const uniqueViolation = pq.ErrorCode("23505")
+
+// isUniqueViolation reports whether err is a unique constraint violation.
+func isUniqueViolation(err error) bool {
+	pqErr, ok := err.(*pq.Error)
+	return ok && pqErr.Code == uniqueViolation
+}
+
+// foreignKeyViolation is the PostgreSQL error code for foreign_key_violation.
+const foreignKeyViolation = pq.ErrorCode("23503")
+
+// isForeignKeyViolation reports whether err is a foreign key constraint violation.
+func isForeignKeyViolation(err error) bool {
+	pqErr, ok := err.(*pq.Error)
+	return ok && pqErr.Code == foreignKeyViolation
+}
@@ -0,0 +1,329 @@
+package postgres
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+
+	"github.com/hypnoglow/pascont/outbox"
+)
+
+const outboxTable = "outbox"
+
+type outboxRepository struct {
+	db *sql.DB
+}
+
+// NewOutboxRepository returns a new outbox.Repository with PostgreSQL as a storage.
+func NewOutboxRepository(db *sql.DB) outbox.Repository {
+	return &outboxRepository{db: db}
+}
+
+func (r outboxRepository) Add(messages ...outbox.Message) error {
+	return addOutboxMessages(r.db, messages)
+}
+
+func (r outboxRepository) Claim(t time.Time, lease time.Duration, limit int) ([]outbox.Message, error) {
+	// Locked messages are skipped, so concurrent dispatchers claim different messages.
+	q := fmt.Sprintf(`
+		UPDATE %s
+		SET
+			next_attempt_at = $2
+		WHERE
+			id IN (
+				SELECT
+					id
+				FROM
+					%s
+				WHERE
+					status = $4
+					AND next_attempt_at <= $1
+				ORDER BY
+					next_attempt_at
+				LIMIT $3
+				FOR UPDATE SKIP LOCKED
+			)
+		RETURNING %s
+	`, pq.QuoteIdentifier(outboxTable), pq.QuoteIdentifier(outboxTable), outboxColumns)
+
+	rows, err := r.db.Query(q, t, t.Add(lease), limit, outbox.StatusPending)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to claim outbox messages")
+	}
+	defer rows.Close()
+
+	var messages []outbox.Message
+	for rows.Next() {
+		m, err := scanOutboxMessage(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to claim outbox messages")
+		}
+		messages = append(messages, *m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "Failed to claim outbox messages")
+	}
+
+	return messages, nil
+}
+
+func (r outboxRepository) Renew(id string, leasedUntil, t time.Time) (bool, error) {
+	q := fmt.Sprintf(`
+		UPDATE %s
+		SET
+			next_attempt_at = $3
+		WHERE
+			id = $1
+			AND status = $4
+			AND next_attempt_at = $2
+	`, pq.QuoteIdentifier(outboxTable))
+
+	res, err := r.db.Exec(q, id, leasedUntil, t, outbox.StatusPending)
+	if err != nil {
+		return false, errors.Wrap(err, "Failed to renew an outbox message lease")
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(err, "Failed to renew an outbox message lease")
+	}
+
+	return n == 1, nil
+}
+
+func (r outboxRepository) MarkDelivered(id string, deliveredAt time.Time) error {
+	q := fmt.Sprintf(`
+		UPDATE %s
+		SET
+			status = $2,
+			delivered_at = $3
+		WHERE
+			id = $1
+	`, pq.QuoteIdentifier(outboxTable))
+
+	_, err := r.db.Exec(q, id, outbox.StatusDelivered, deliveredAt)
+	return errors.Wrap(err, "Failed to mark an outbox message delivered")
+}
+
+func (r outboxRepository) MarkFailed(id string, attempts int, nextAttemptAt time.Time, lastError string, dead bool) error {
+	status := outbox.StatusPending
+	if dead {
+		status = outbox.StatusDead
+	}
+
+	q := fmt.Sprintf(`
+		UPDATE %s
+		SET
+			status = $2,
+			attempts = $3,
+			next_attempt_at = $4,
+			last_error = $5
+		WHERE
+			id = $1
+	`, pq.QuoteIdentifier(outboxTable))
+
+	_, err := r.db.Exec(q, id, status, attempts, nextAttemptAt, lastError)
+	return errors.Wrap(err, "Failed to mark an outbox message failed")
+}
+
+func (r outboxRepository) FindByID(id string) (*outbox.Message, error) {
+	q := fmt.Sprintf(`
+		SELECT
+			%s
+		FROM
+			%s
+		WHERE
+			id = $1
+	`, outboxColumns, pq.QuoteIdentifier(outboxTable))
+
+	m, err := scanOutboxMessage(r.db.QueryRow(q, id))
+	if err == sql.ErrNoRows {
+		return nil, outbox.ErrNotFound
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to find an outbox message")
+	}
+
+	return m, nil
+}
+
+func (r outboxRepository) ListByRecipient(channel, recipient string, status outbox.Status, offset, limit int) ([]outbox.Message, int, error) {
+	// Empty status matches any status.
+	where := `
+		channel = $1
+		AND payload->>'recipient' = $2
+		AND ($3 = '' OR status = $3)
+	`
+
+	q := fmt.Sprintf(`
+		SELECT
+			COUNT(*)
+		FROM
+			%s
+		WHERE
+			%s
+	`, pq.QuoteIdentifier(outboxTable), where)
+
+	var total int
+	if err := r.db.QueryRow(q, channel, recipient, status).Scan(&total); err != nil {
+		return nil, 0, errors.Wrap(err, "Failed to count outbox messages")
+	}
+
+	q = fmt.Sprintf(`
+		SELECT
+			%s
+		FROM
+			%s
+		WHERE
+			%s
+		ORDER BY
+			created_at DESC, id
+		OFFSET $4
+		LIMIT $5
+	`, outboxColumns, pq.QuoteIdentifier(outboxTable), where)
+
+	rows, err := r.db.Query(q, channel, recipient, status, offset, limit)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "Failed to list outbox messages")
+	}
+	defer rows.Close()
+
+	var messages []outbox.Message
+	for rows.Next() {
+		m, err := scanOutboxMessage(rows)
+		if err != nil {
+			return nil, 0, errors.Wrap(err, "Failed to list outbox messages")
+		}
+		messages = append(messages, *m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, errors.Wrap(err, "Failed to list outbox messages")
+	}
+
+	return messages, total, nil
+}
+
+func (r outboxRepository) Replay(id string, t time.Time) error {
+	q := fmt.Sprintf(`
+		UPDATE %s
+		SET
+			status = $2,
+			attempts = 0,
+			next_attempt_at = $3,
+			last_error = '',
+			delivered_at = NULL
+		WHERE
+			id = $1
+	`, pq.QuoteIdentifier(outboxTable))
+
+	res, err := r.db.Exec(q, id, outbox.StatusPending, t)
+	if err != nil {
+		return errors.Wrap(err, "Failed to replay an outbox message")
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "Failed to replay an outbox message")
+	}
+	if n == 0 {
+		return outbox.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r outboxRepository) Purge(t time.Time) (int, error) {
+	q := fmt.Sprintf(`
+		DELETE FROM
+			%s
+		WHERE
+			status = $1
+			AND delivered_at < $2
+	`, pq.QuoteIdentifier(outboxTable))
+
+	res, err := r.db.Exec(q, outbox.StatusDelivered, t)
+	if err != nil {
+		return 0, errors.Wrap(err, "Failed to purge outbox messages")
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "Failed to purge outbox messages")
+	}
+
+	return int(n), nil
+}
+
+// outboxColumns are the columns scanned by scanOutboxMessage.
+const outboxColumns = "id, channel, payload, status, created_at, attempts, next_attempt_at, last_error, delivered_at"
+
+// scanOutboxMessage scans the message from the row of outboxColumns.
+func scanOutboxMessage(row scanner) (*outbox.Message, error) {
+	var m outbox.Message
+	var payload []byte
+	var deliveredAt pq.NullTime
+	err := row.Scan(
+		&m.ID,
+		&m.Channel,
+		&payload,
+		&m.Status,
+		&m.CreatedAt,
+		&m.Attempts,
+		&m.NextAttemptAt,
+		&m.LastError,
+		&deliveredAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(payload, &m.Notification); err != nil {
+		return nil, errors.Wrap(err, "Failed to decode an outbox message")
+	}
+	m.DeliveredAt = deliveredAt.Time
+
+	return &m, nil
+}
+
+// addOutboxMessages adds the messages to the outbox with ex.
+func addOutboxMessages(ex execer, messages []outbox.Message) error {
+	q := fmt.Sprintf(`
+		INSERT INTO %s
+			(id, channel, payload, status, created_at, attempts, next_attempt_at, last_error)
+		VALUES
+			($1, $2, $3, $4, $5, $6, $7, $8)
+	`, pq.QuoteIdentifier(outboxTable))
+
+	for _, m := range messages {
+		payload, err := json.Marshal(m.Notification)
+		if err != nil {
+			return errors.Wrap(err, "Failed to encode an outbox message")
+		}
+
+		_, err = ex.Exec(q, m.ID, m.Channel, payload, m.Status, m.CreatedAt, m.Attempts, m.NextAttemptAt, m.LastError)
+		if err != nil {
+			return errors.Wrap(err, "Failed to add an outbox message")
+		}
+	}
+
+	return nil
+}
+
+// withOutbox runs save and adds the outbox messages in a single transaction,
+// so the messages are written if and only if the change is.
+func withOutbox(db *sql.DB, messages []outbox.Message, save func(ex execer) error) error {
+	if len(messages) == 0 {
+		return save(db)
+	}
+
+	return withTx(db, func(ex execer) error {
+		if err := save(ex); err != nil {
+			return err
+		}
+
+		return addOutboxMessages(ex, messages)
+	})
+}
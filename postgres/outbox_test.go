@@ -0,0 +1,144 @@
+package postgres
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/hypnoglow/pascont/identity"
+	"github.com/hypnoglow/pascont/notifier"
+	"github.com/hypnoglow/pascont/outbox"
+)
+
+func TestOutboxRepository(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	repo := NewOutboxRepository(db)
+	channel := fmt.Sprintf("test-%d", time.Now().UnixNano())
+	now := time.Now().Truncate(time.Millisecond)
+
+	m := outbox.Message{
+		ID:      identity.NewUUIDV4(),
+		Channel: channel,
+		Notification: notifier.Notification{
+			Kind:      notifier.KindPasswordChanged,
+			TenantID:  defaultTenantID(t, db),
+			AccountID: 123,
+			Recipient: "email@email.com",
+			Params:    map[string]string{"changedAt": now.Format(time.RFC3339)},
+		},
+		Status:        outbox.StatusPending,
+		CreatedAt:     now,
+		NextAttemptAt: now,
+	}
+
+	if err := repo.Add(m); err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	defer db.Exec(fmt.Sprintf("DELETE FROM %s WHERE channel = $1", pq.QuoteIdentifier(outboxTable)), channel)
+
+	claimed := claimByID(t, repo, now, m.ID)
+	if claimed == nil {
+		t.Fatalf("Expected message %s to be claimed", m.ID)
+	}
+	if claimed.Notification.Params["changedAt"] != m.Notification.Params["changedAt"] || claimed.Channel != channel {
+		t.Errorf("Expected claimed message to be %#v, but got %#v", m, *claimed)
+	}
+
+	// The message is leased, so it is not claimed again until the lease expires.
+	if claimByID(t, repo, now, m.ID) != nil {
+		t.Errorf("Expected leased message %s not to be claimed", m.ID)
+	}
+
+	// The lease is renewed only by the dispatcher holding it.
+	if renewed, err := repo.Renew(m.ID, claimed.NextAttemptAt.Add(time.Second), now.Add(time.Hour)); err != nil || renewed {
+		t.Errorf("Expected lease of message %s not to be renewed by others, but got %v, %v", m.ID, renewed, err)
+	}
+	if renewed, err := repo.Renew(m.ID, claimed.NextAttemptAt, now.Add(time.Hour)); err != nil || !renewed {
+		t.Errorf("Expected lease of message %s to be renewed, but got %v, %v", m.ID, renewed, err)
+	}
+	if claimByID(t, repo, now.Add(time.Minute*2), m.ID) != nil {
+		t.Errorf("Expected message %s with the renewed lease not to be claimed", m.ID)
+	}
+
+	if err := repo.MarkFailed(m.ID, 1, now, "error", false); err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	if claimed = claimByID(t, repo, now, m.ID); claimed == nil || claimed.Attempts != 1 || claimed.LastError != "error" {
+		t.Fatalf("Expected failed message %s to be claimed again, but got %#v", m.ID, claimed)
+	}
+
+	if err := repo.MarkDelivered(m.ID, now); err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	if claimByID(t, repo, now.Add(time.Hour), m.ID) != nil {
+		t.Errorf("Expected delivered message %s not to be claimed", m.ID)
+	}
+
+	found, err := repo.FindByID(m.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	if found.Status != outbox.StatusDelivered || found.DeliveredAt.IsZero() {
+		t.Errorf("Expected message %s to be delivered, but got %#v", m.ID, *found)
+	}
+
+	listed, total, err := repo.ListByRecipient(channel, "email@email.com", outbox.StatusDelivered, 0, 10)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	if total != 1 || len(listed) != 1 || listed[0].ID != m.ID {
+		t.Errorf("Expected message %s to be listed, but got %d of %#v", m.ID, total, listed)
+	}
+	if _, total, _ = repo.ListByRecipient(channel, "email@email.com", outbox.StatusDead, 0, 10); total != 0 {
+		t.Errorf("Expected no dead messages, but got %d", total)
+	}
+
+	if err := repo.Replay(m.ID, now); err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	if claimed = claimByID(t, repo, now, m.ID); claimed == nil || claimed.Attempts != 0 || claimed.LastError != "" {
+		t.Errorf("Expected replayed message %s to be claimed as a new one, but got %#v", m.ID, claimed)
+	}
+
+	if err := repo.MarkDelivered(m.ID, now); err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	if _, err := repo.Purge(now); err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	if _, err := repo.FindByID(m.ID); err != nil {
+		t.Errorf("Expected message %s delivered at the purge time to be kept, but got %s", m.ID, err)
+	}
+	if _, err := repo.Purge(now.Add(time.Second)); err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	if _, err := repo.FindByID(m.ID); err != outbox.ErrNotFound {
+		t.Errorf("Expected purged message %s not to be found, but got %v", m.ID, err)
+	}
+
+	if err := repo.Replay(identity.NewUUIDV4(), now); err != outbox.ErrNotFound {
+		t.Errorf("Expected error to be %v, but got %v", outbox.ErrNotFound, err)
+	}
+	if _, err := repo.FindByID(identity.NewUUIDV4()); err != outbox.ErrNotFound {
+		t.Errorf("Expected error to be %v, but got %v", outbox.ErrNotFound, err)
+	}
+}
+
+func claimByID(t *testing.T, repo outbox.Repository, now time.Time, id string) *outbox.Message {
+	messages, err := repo.Claim(now, time.Minute, 1000)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+
+	for _, m := range messages {
+		if m.ID == id {
+			return &m
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,113 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+
+	"github.com/hypnoglow/pascont/recovery"
+)
+
+const recoveryCodeTable = "recovery_code"
+
+type recoveryRepository struct {
+	db *sql.DB
+}
+
+// NewRecoveryRepository returns a new recovery.Repository with PostgreSQL as a storage.
+func NewRecoveryRepository(db *sql.DB) recovery.Repository {
+	return &recoveryRepository{db: db}
+}
+
+func (r recoveryRepository) Replace(accountID int64, codes []recovery.Code) (err error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return errors.Wrap(err, "Failed to begin a transaction")
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		err = errors.Wrap(tx.Commit(), "Failed to commit a transaction")
+	}()
+
+	q := fmt.Sprintf(`
+		DELETE FROM
+			%s
+		WHERE
+			account_id = $1
+	`, pq.QuoteIdentifier(recoveryCodeTable))
+
+	if _, err = tx.Exec(q, accountID); err != nil {
+		return errors.Wrap(err, "Failed to delete recovery codes")
+	}
+
+	q = fmt.Sprintf(`
+		INSERT INTO %s
+			(account_id, hash, created_at)
+		VALUES
+			($1, $2, $3)
+	`, pq.QuoteIdentifier(recoveryCodeTable))
+
+	for _, c := range codes {
+		if _, err = tx.Exec(q, accountID, c.Hash, c.CreatedAt); err != nil {
+			return errors.Wrap(err, "Failed to save a recovery code")
+		}
+	}
+
+	return nil
+}
+
+func (r recoveryRepository) ListByAccount(accountID int64) ([]*recovery.Code, error) {
+	q := fmt.Sprintf(`
+		SELECT
+			id, account_id, hash, created_at
+		FROM
+			%s
+		WHERE
+			account_id = $1
+		ORDER BY
+			id
+	`, pq.QuoteIdentifier(recoveryCodeTable))
+
+	rows, err := r.db.Query(q, accountID)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to list recovery codes")
+	}
+	defer rows.Close()
+
+	var codes []*recovery.Code
+	for rows.Next() {
+		c := &recovery.Code{}
+		if err := rows.Scan(&c.ID, &c.AccountID, &c.Hash, &c.CreatedAt); err != nil {
+			return nil, errors.Wrap(err, "Failed to list recovery codes")
+		}
+		codes = append(codes, c)
+	}
+
+	return codes, errors.Wrap(rows.Err(), "Failed to list recovery codes")
+}
+
+func (r recoveryRepository) Use(id int64) (bool, error) {
+	q := fmt.Sprintf(`
+		DELETE FROM
+			%s
+		WHERE
+			id = $1
+	`, pq.QuoteIdentifier(recoveryCodeTable))
+
+	res, err := r.db.Exec(q, id)
+	if err != nil {
+		return false, errors.Wrap(err, "Failed to use a recovery code")
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(err, "Failed to use a recovery code")
+	}
+
+	return n == 1, nil
+}
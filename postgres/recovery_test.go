@@ -0,0 +1,53 @@
+package postgres
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/pascont/account"
+	"github.com/hypnoglow/pascont/recovery"
+)
+
+func TestRecoveryRepository(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	accountRepo := NewAccountRepository(db)
+	repo := NewRecoveryRepository(db)
+	name := fmt.Sprintf("recovery-%d", time.Now().UnixNano())
+
+	acc, err := accountRepo.Accept(account.NewApplication(defaultTenantID(t, db), name, []byte("password_hash"), time.Now()))
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	defer accountRepo.Delete(acc.ID)
+
+	codes := []recovery.Code{
+		{AccountID: acc.ID, Hash: []byte("hash1"), CreatedAt: time.Now()},
+		{AccountID: acc.ID, Hash: []byte("hash2"), CreatedAt: time.Now()},
+	}
+	if err = repo.Replace(acc.ID, codes); err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	if err = repo.Replace(acc.ID, codes[:1]); err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+
+	found, err := repo.ListByAccount(acc.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	if len(found) != 1 || string(found[0].Hash) != "hash1" {
+		t.Fatalf("Expected codes to be replaced, but got %#v", found)
+	}
+
+	used, err := repo.Use(found[0].ID)
+	if err != nil || !used {
+		t.Errorf("Expected code to be used, but got %v, %v", used, err)
+	}
+	used, err = repo.Use(found[0].ID)
+	if err != nil || used {
+		t.Errorf("Expected code not to be used again, but got %v, %v", used, err)
+	}
+}
@@ -0,0 +1,129 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+
+	"github.com/hypnoglow/pascont/account"
+	"github.com/hypnoglow/pascont/outbox"
+	"github.com/hypnoglow/pascont/reset"
+)
+
+const passwordResetTable = "password_reset"
+
+type resetRepository struct {
+	db *sql.DB
+}
+
+// NewResetRepository returns a new reset.Repository with PostgreSQL as a storage.
+func NewResetRepository(db *sql.DB) reset.Repository {
+	return &resetRepository{db: db}
+}
+
+func (r resetRepository) Save(rs reset.Reset, messages ...outbox.Message) error {
+	q := fmt.Sprintf(`
+		INSERT INTO %s
+			(id, account_id, hash, created_at, expires_at)
+		VALUES
+			($1, $2, $3, $4, $5)
+	`, pq.QuoteIdentifier(passwordResetTable))
+
+	return withOutbox(r.db, messages, func(ex execer) error {
+		_, err := ex.Exec(q, rs.ID, rs.AccountID, rs.Hash, rs.CreatedAt, rs.ExpiresAt)
+		return errors.Wrap(err, "Failed to save a password reset")
+	})
+}
+
+func (r resetRepository) Issue(rs reset.Reset, change account.PasswordChange) error {
+	if change.Account.ID == 0 {
+		return account.ErrNoIdentity
+	}
+
+	q := fmt.Sprintf(`
+		INSERT INTO %s
+			(id, account_id, hash, created_at, expires_at)
+		VALUES
+			($1, $2, $3, $4, $5)
+	`, pq.QuoteIdentifier(passwordResetTable))
+
+	return withTx(r.db, func(ex execer) error {
+		if _, err := ex.Exec(q, rs.ID, rs.AccountID, rs.Hash, rs.CreatedAt, rs.ExpiresAt); err != nil {
+			return errors.Wrap(err, "Failed to save a password reset")
+		}
+
+		return changePassword(ex, change)
+	})
+}
+
+func (r resetRepository) FindByID(id string) (*reset.Reset, error) {
+	q := fmt.Sprintf(`
+		SELECT
+			id, account_id, hash, created_at, expires_at
+		FROM
+			%s
+		WHERE
+			id = $1
+	`, pq.QuoteIdentifier(passwordResetTable))
+
+	rs := &reset.Reset{}
+	err := r.db.QueryRow(q, id).Scan(&rs.ID, &rs.AccountID, &rs.Hash, &rs.CreatedAt, &rs.ExpiresAt)
+	if err == sql.ErrNoRows {
+		return nil, reset.ErrNotFound
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to find a password reset")
+	}
+
+	return rs, nil
+}
+
+func (r resetRepository) Complete(id string, change account.PasswordChange) (completed bool, err error) {
+	if change.Account.ID == 0 {
+		return false, account.ErrNoIdentity
+	}
+
+	err = withTx(r.db, func(ex execer) error {
+		q := fmt.Sprintf(`
+			DELETE FROM
+				%s
+			WHERE
+				id = $1
+				AND account_id = $2
+		`, pq.QuoteIdentifier(passwordResetTable))
+
+		res, err := ex.Exec(q, id, change.Account.ID)
+		if err != nil {
+			return errors.Wrap(err, "Failed to use a password reset")
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return errors.Wrap(err, "Failed to use a password reset")
+		}
+		if n == 0 {
+			return nil
+		}
+
+		// Other resets of the account are not needed anymore.
+		q = fmt.Sprintf(`
+			DELETE FROM
+				%s
+			WHERE
+				account_id = $1
+		`, pq.QuoteIdentifier(passwordResetTable))
+
+		if _, err := ex.Exec(q, change.Account.ID); err != nil {
+			return errors.Wrap(err, "Failed to delete password resets")
+		}
+
+		completed = true
+		return changePassword(ex, change)
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return completed, nil
+}
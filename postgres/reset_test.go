@@ -0,0 +1,102 @@
+package postgres
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/pascont/account"
+	"github.com/hypnoglow/pascont/identity"
+	"github.com/hypnoglow/pascont/reset"
+)
+
+func TestResetRepository(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	accountRepo := NewAccountRepository(db)
+	repo := NewResetRepository(db)
+	name := fmt.Sprintf("reset-%d", time.Now().UnixNano())
+
+	acc, err := accountRepo.Accept(account.NewApplication(defaultTenantID(t, db), name, []byte("password_hash"), time.Now()))
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	defer accountRepo.Delete(acc.ID)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	rs := reset.Reset{
+		ID:        identity.NewUUIDV4(),
+		AccountID: acc.ID,
+		Hash:      []byte("hash"),
+		CreatedAt: now,
+		ExpiresAt: now.Add(reset.Duration),
+	}
+	if err = repo.Save(rs); err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+
+	found, err := repo.FindByID(rs.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	if found.AccountID != acc.ID || !bytes.Equal(found.Hash, rs.Hash) || !found.ExpiresAt.Equal(rs.ExpiresAt) {
+		t.Errorf("Expected found reset to equal %#v, but got %#v", rs, found)
+	}
+
+	other := rs
+	other.ID = identity.NewUUIDV4()
+	if err = repo.Save(other); err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	if _, err = accountRepo.RegisterFailedLogin(acc.ID, 1, now.Add(time.Hour)); err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+
+	acc.UpdatedAt = now
+	change := account.PasswordChange{Account: *acc, PasswordHash: []byte("new_password_hash")}
+	completed, err := repo.Complete(rs.ID, change)
+	if err != nil || !completed {
+		t.Errorf("Expected reset to be completed, but got %v, %v", completed, err)
+	}
+	completed, err = repo.Complete(rs.ID, change)
+	if err != nil || completed {
+		t.Errorf("Expected reset not to be completed again, but got %v, %v", completed, err)
+	}
+	if _, err = repo.FindByID(other.ID); err != reset.ErrNotFound {
+		t.Errorf("Expected other resets to be deleted, but got %v", err)
+	}
+
+	changed, passwordHash, err := accountRepo.FindWithPasswordHashByID(acc.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	if string(passwordHash) != "new_password_hash" || changed.FailedLoginAttempts != 0 || !changed.LockedUntil.IsZero() {
+		t.Errorf("Expected password to be changed and lockout to be reset, but got %#v", changed)
+	}
+
+	issued := rs
+	issued.ID = identity.NewUUIDV4()
+	if err = repo.Issue(issued, account.PasswordChange{Account: *acc, PasswordHash: []byte{}}); err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	if _, err = repo.FindByID(issued.ID); err != nil {
+		t.Errorf("Expected issued reset to be found, but got %v", err)
+	}
+	if _, passwordHash, err = accountRepo.FindWithPasswordHashByID(acc.ID); err != nil || len(passwordHash) != 0 {
+		t.Errorf("Expected password to be wiped along with the issue, but got %q, %v", passwordHash, err)
+	}
+
+	// Nothing is saved if the password change fails.
+	failed := rs
+	failed.ID = identity.NewUUIDV4()
+	gone := *acc
+	gone.ID = -1
+	if err = repo.Issue(failed, account.PasswordChange{Account: gone, PasswordHash: []byte{}}); err == nil {
+		t.Errorf("Expected an error on the change of a missing account, but got nil")
+	}
+	if _, err = repo.FindByID(failed.ID); err != reset.ErrNotFound {
+		t.Errorf("Expected reset not to be saved without the change, but got %v", err)
+	}
+}
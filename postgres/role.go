@@ -0,0 +1,224 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+
+	"github.com/hypnoglow/pascont/role"
+)
+
+const (
+	roleTable           = "role"
+	permissionTable     = "permission"
+	rolePermissionTable = "role_permission"
+	accountRoleTable    = "account_role"
+)
+
+type roleRepository struct {
+	db *sql.DB
+}
+
+// NewRoleRepository returns a new role.Repository with PostgreSQL as a storage.
+func NewRoleRepository(db *sql.DB) role.Repository {
+	return &roleRepository{db: db}
+}
+
+func (r roleRepository) Save(rl role.Role) (err error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return errors.Wrap(err, "Failed to begin a transaction")
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		err = errors.Wrap(tx.Commit(), "Failed to commit a transaction")
+	}()
+
+	q := fmt.Sprintf(`
+		INSERT INTO %s
+			(name)
+		VALUES
+			($1)
+		ON CONFLICT (name) DO UPDATE
+			SET name = EXCLUDED.name
+		RETURNING id
+	`, pq.QuoteIdentifier(roleTable))
+
+	var id int64
+	if err = tx.QueryRow(q, rl.Name).Scan(&id); err != nil {
+		return errors.Wrap(err, "Failed to save a role")
+	}
+
+	permissions := make([]string, len(rl.Permissions))
+	for i, p := range rl.Permissions {
+		permissions[i] = string(p)
+	}
+
+	q = fmt.Sprintf(`
+		INSERT INTO %s
+			(name)
+		SELECT unnest($1::VARCHAR[])
+		ON CONFLICT DO NOTHING
+	`, pq.QuoteIdentifier(permissionTable))
+
+	if _, err = tx.Exec(q, pq.Array(permissions)); err != nil {
+		return errors.Wrap(err, "Failed to save role permissions")
+	}
+
+	q = fmt.Sprintf(`
+		DELETE FROM
+			%s
+		WHERE
+			role_id = $1
+	`, pq.QuoteIdentifier(rolePermissionTable))
+
+	if _, err = tx.Exec(q, id); err != nil {
+		return errors.Wrap(err, "Failed to save role permissions")
+	}
+
+	q = fmt.Sprintf(`
+		INSERT INTO %s
+			(role_id, permission)
+		SELECT $1, unnest($2::VARCHAR[])
+	`, pq.QuoteIdentifier(rolePermissionTable))
+
+	if _, err = tx.Exec(q, id, pq.Array(permissions)); err != nil {
+		return errors.Wrap(err, "Failed to save role permissions")
+	}
+
+	return nil
+}
+
+func (r roleRepository) List() ([]*role.Role, error) {
+	q := fmt.Sprintf(`
+		SELECT
+			r.id,
+			r.name,
+			COALESCE(array_agg(rp.permission ORDER BY rp.permission) FILTER (WHERE rp.permission IS NOT NULL), '{}')
+		FROM
+			%s r
+			LEFT JOIN %s rp ON rp.role_id = r.id
+		GROUP BY
+			r.id
+		ORDER BY
+			r.name
+	`, pq.QuoteIdentifier(roleTable), pq.QuoteIdentifier(rolePermissionTable))
+
+	roles, err := r.query(q)
+	return roles, errors.Wrap(err, "Failed to list roles")
+}
+
+func (r roleRepository) FindByAccount(accountID int64) ([]*role.Role, error) {
+	q := fmt.Sprintf(`
+		SELECT
+			r.id,
+			r.name,
+			COALESCE(array_agg(rp.permission ORDER BY rp.permission) FILTER (WHERE rp.permission IS NOT NULL), '{}')
+		FROM
+			%s r
+			JOIN %s ar ON ar.role_id = r.id
+			LEFT JOIN %s rp ON rp.role_id = r.id
+		WHERE
+			ar.account_id = $1
+		GROUP BY
+			r.id
+		ORDER BY
+			r.name
+	`, pq.QuoteIdentifier(roleTable), pq.QuoteIdentifier(accountRoleTable), pq.QuoteIdentifier(rolePermissionTable))
+
+	roles, err := r.query(q, accountID)
+	return roles, errors.Wrap(err, "Failed to find account roles")
+}
+
+// query retrieves roles selected by the q as (id, name, permissions) rows.
+func (r roleRepository) query(q string, args ...interface{}) ([]*role.Role, error) {
+	rows, err := r.db.Query(q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var roles []*role.Role
+	for rows.Next() {
+		rl := &role.Role{}
+		var permissions pq.StringArray
+		if err := rows.Scan(&rl.ID, &rl.Name, &permissions); err != nil {
+			return nil, err
+		}
+		for _, p := range permissions {
+			rl.Permissions = append(rl.Permissions, role.Permission(p))
+		}
+		roles = append(roles, rl)
+	}
+
+	return roles, rows.Err()
+}
+
+func (r roleRepository) Assign(accountID int64, roleName string) error {
+	q := fmt.Sprintf(`
+		SELECT
+			id
+		FROM
+			%s
+		WHERE
+			name = $1
+	`, pq.QuoteIdentifier(roleTable))
+
+	var roleID int64
+	if err := r.db.QueryRow(q, roleName).Scan(&roleID); err != nil {
+		if err == sql.ErrNoRows {
+			return role.ErrNotFound
+		}
+		return errors.Wrap(err, "Failed to find a role")
+	}
+
+	q = fmt.Sprintf(`
+		INSERT INTO %s
+			(account_id, role_id)
+		VALUES
+			($1, $2)
+		ON CONFLICT DO NOTHING
+	`, pq.QuoteIdentifier(accountRoleTable))
+
+	_, err := r.db.Exec(q, accountID, roleID)
+	if isForeignKeyViolation(err) {
+		return role.ErrNotFound
+	}
+
+	return errors.Wrap(err, "Failed to assign a role")
+}
+
+func (r roleRepository) Unassign(accountID int64, roleName string) error {
+	q := fmt.Sprintf(`
+		SELECT
+			id
+		FROM
+			%s
+		WHERE
+			name = $1
+	`, pq.QuoteIdentifier(roleTable))
+
+	var roleID int64
+	if err := r.db.QueryRow(q, roleName).Scan(&roleID); err != nil {
+		if err == sql.ErrNoRows {
+			return role.ErrNotFound
+		}
+		return errors.Wrap(err, "Failed to find a role")
+	}
+
+	q = fmt.Sprintf(`
+		DELETE FROM
+			%s
+		WHERE
+			account_id = $1
+			AND role_id = $2
+	`, pq.QuoteIdentifier(accountRoleTable))
+
+	_, err := r.db.Exec(q, accountID, roleID)
+	return errors.Wrap(err, "Failed to unassign a role")
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/lib/pq"
 	"github.com/pkg/errors"
 
+	"github.com/hypnoglow/pascont/outbox"
 	"github.com/hypnoglow/pascont/session"
 )
 
@@ -22,28 +23,35 @@ func NewSessionRepository(db *sql.DB) session.Repository {
 	return &sessionRepository{db}
 }
 
-func (r sessionRepository) Save(s session.Session) error {
+func (r sessionRepository) Save(s session.Session, messages ...outbox.Message) error {
+	return withOutbox(r.db, messages, func(ex execer) error {
+		return saveSession(ex, s)
+	})
+}
+
+// saveSession saves the session with ex.
+func saveSession(ex execer, s session.Session) error {
 	q := fmt.Sprintf(`
 		INSERT INTO %s
-			(id, account_id, created_at, expires_at)
+			(id, account_id, tenant_id, created_at, expires_at, ip, user_agent, last_seen_at)
 		VALUES
-			($1, $2, $3, $4)
+			($1, $2, $3, $4, $5, $6, $7, $8)
 		ON CONFLICT (id) DO
 			UPDATE SET
-				(account_id, created_at, expires_at)
-				= ($2, $3, $4)
+				(account_id, tenant_id, created_at, expires_at, ip, user_agent, last_seen_at)
+				= ($2, $3, $4, $5, $6, $7, $8)
 			WHERE
 				%s.id = $1
 	`, pq.QuoteIdentifier(sessionTable), pq.QuoteIdentifier(sessionTable))
 
-	_, err := r.db.Exec(q, s.ID, s.AccountID, s.CreatedAt, s.ExpiresAt)
+	_, err := ex.Exec(q, s.ID, s.AccountID, s.TenantID, s.CreatedAt, s.ExpiresAt, s.IP, s.UserAgent, s.LastSeenAt)
 	return errors.Wrap(err, "Failed to save a session")
 }
 
 func (r sessionRepository) FindByID(id string) (*session.Session, error) {
 	q := fmt.Sprintf(`
 		SELECT
-			id, account_id, created_at, expires_at
+			id, account_id, tenant_id, created_at, expires_at, ip, user_agent, last_seen_at
 		FROM
 			%s
 		WHERE
@@ -54,8 +62,12 @@ func (r sessionRepository) FindByID(id string) (*session.Session, error) {
 	if err := r.db.QueryRow(q, id).Scan(
 		&s.ID,
 		&s.AccountID,
+		&s.TenantID,
 		&s.CreatedAt,
 		&s.ExpiresAt,
+		&s.IP,
+		&s.UserAgent,
+		&s.LastSeenAt,
 	); err != nil {
 		if err == sql.ErrNoRows {
 			return s, session.ErrNotFound
@@ -71,7 +83,79 @@ func (r sessionRepository) FindByID(id string) (*session.Session, error) {
 	return s, nil
 }
 
-func (r sessionRepository) Delete(id string) error {
+func (r sessionRepository) ListByAccount(accountID int64, offset, limit int) ([]*session.Session, int, error) {
+	now := time.Now()
+
+	q := fmt.Sprintf(`
+		SELECT
+			COUNT(*)
+		FROM
+			%s
+		WHERE
+			account_id = $1
+			AND expires_at > $2
+	`, pq.QuoteIdentifier(sessionTable))
+
+	var total int
+	if err := r.db.QueryRow(q, accountID, now).Scan(&total); err != nil {
+		return nil, 0, errors.Wrap(err, "Failed to count account sessions")
+	}
+
+	q = fmt.Sprintf(`
+		SELECT
+			id, account_id, tenant_id, created_at, expires_at, ip, user_agent, last_seen_at
+		FROM
+			%s
+		WHERE
+			account_id = $1
+			AND expires_at > $2
+		ORDER BY
+			created_at DESC, id
+		OFFSET $3
+		LIMIT $4
+	`, pq.QuoteIdentifier(sessionTable))
+
+	rows, err := r.db.Query(q, accountID, now, offset, limit)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "Failed to list account sessions")
+	}
+	defer rows.Close()
+
+	var sessions []*session.Session
+	for rows.Next() {
+		s := &session.Session{}
+		if err := rows.Scan(
+			&s.ID,
+			&s.AccountID,
+			&s.TenantID,
+			&s.CreatedAt,
+			&s.ExpiresAt,
+			&s.IP,
+			&s.UserAgent,
+			&s.LastSeenAt,
+		); err != nil {
+			return nil, 0, errors.Wrap(err, "Failed to list account sessions")
+		}
+		sessions = append(sessions, s)
+	}
+
+	return sessions, total, errors.Wrap(rows.Err(), "Failed to list account sessions")
+}
+
+func (r sessionRepository) Touch(id string, lastSeenAt time.Time) error {
+	q := fmt.Sprintf(`
+		UPDATE %s
+		SET
+			last_seen_at = $2
+		WHERE
+			id = $1
+	`, pq.QuoteIdentifier(sessionTable))
+
+	_, err := r.db.Exec(q, id, lastSeenAt)
+	return errors.Wrap(err, "Failed to touch a session")
+}
+
+func (r sessionRepository) Delete(id string, messages ...outbox.Message) error {
 	q := fmt.Sprintf(`
 		DELETE FROM
 			%s
@@ -79,11 +163,20 @@ func (r sessionRepository) Delete(id string) error {
 			id = $1
 	`, pq.QuoteIdentifier(sessionTable))
 
-	_, err := r.db.Exec(q, id)
-	return errors.Wrap(err, "Failed to delete a session")
+	return withOutbox(r.db, messages, func(ex execer) error {
+		_, err := ex.Exec(q, id)
+		return errors.Wrap(err, "Failed to delete a session")
+	})
+}
+
+func (r sessionRepository) DeleteAllByAccount(accountID int64, messages ...outbox.Message) error {
+	return withOutbox(r.db, messages, func(ex execer) error {
+		return deleteAccountSessions(ex, accountID)
+	})
 }
 
-func (r sessionRepository) DeleteAllByAccount(accountID int64) error {
+// deleteAccountSessions removes all sessions of the account with ex.
+func deleteAccountSessions(ex execer, accountID int64) error {
 	q := fmt.Sprintf(`
 		DELETE FROM
 			%s
@@ -91,6 +184,6 @@ func (r sessionRepository) DeleteAllByAccount(accountID int64) error {
 			account_id = $1
 	`, pq.QuoteIdentifier(sessionTable))
 
-	_, err := r.db.Exec(q, accountID)
+	_, err := ex.Exec(q, accountID)
 	return errors.Wrap(err, "Failed to delete account sessions")
 }
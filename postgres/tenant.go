@@ -0,0 +1,68 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+
+	"github.com/hypnoglow/pascont/tenant"
+)
+
+const tenantTable = "tenant"
+
+type tenantRepository struct {
+	db *sql.DB
+}
+
+// NewTenantRepository returns a new tenant.Repository with PostgreSQL as a storage.
+func NewTenantRepository(db *sql.DB) tenant.Repository {
+	return &tenantRepository{db: db}
+}
+
+func (r tenantRepository) FindByName(name string) (*tenant.Tenant, error) {
+	q := fmt.Sprintf(`
+		SELECT
+			id, name, host, created_at
+		FROM
+			%s
+		WHERE
+			name = $1
+	`, pq.QuoteIdentifier(tenantTable))
+
+	return r.find(q, name)
+}
+
+func (r tenantRepository) FindByHost(host string) (*tenant.Tenant, error) {
+	q := fmt.Sprintf(`
+		SELECT
+			id, name, host, created_at
+		FROM
+			%s
+		WHERE
+			host = $1
+	`, pq.QuoteIdentifier(tenantTable))
+
+	return r.find(q, host)
+}
+
+func (r tenantRepository) find(q string, arg interface{}) (*tenant.Tenant, error) {
+	t := &tenant.Tenant{}
+	var host sql.NullString
+	err := r.db.QueryRow(q, arg).Scan(
+		&t.ID,
+		&t.Name,
+		&host,
+		&t.CreatedAt,
+	)
+	t.Host = host.String
+	if err == sql.ErrNoRows {
+		return nil, tenant.ErrNotFound
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to find a tenant")
+	}
+
+	return t, nil
+}
@@ -0,0 +1,30 @@
+package postgres
+
+import (
+	"testing"
+
+	"github.com/hypnoglow/pascont/tenant"
+)
+
+func TestTenantRepository_Find(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	repo := NewTenantRepository(db)
+
+	tn, err := repo.FindByName("default")
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	if tn.ID == 0 || tn.Name != "default" {
+		t.Errorf("Expected default tenant, but got %#v", tn)
+	}
+
+	if _, err = repo.FindByName("non-existent"); err != tenant.ErrNotFound {
+		t.Errorf("Expected error to be %v, but got %v", tenant.ErrNotFound, err)
+	}
+
+	if _, err = repo.FindByHost("non-existent.example.com"); err != tenant.ErrNotFound {
+		t.Errorf("Expected error to be %v, but got %v", tenant.ErrNotFound, err)
+	}
+}
@@ -0,0 +1,237 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+
+	"github.com/hypnoglow/pascont/sealer"
+	"github.com/hypnoglow/pascont/totp"
+)
+
+const (
+	totpTable          = "account_totp"
+	totpChallengeTable = "totp_challenge"
+)
+
+type totpRepository struct {
+	db     *sql.DB
+	sealer sealer.Sealer
+}
+
+// NewTOTPRepository returns a new totp.Repository with PostgreSQL as a storage.
+// Secrets are sealed with s before they are stored.
+func NewTOTPRepository(db *sql.DB, s sealer.Sealer) totp.Repository {
+	return &totpRepository{db: db, sealer: s}
+}
+
+func (r totpRepository) Save(e totp.Enrollment) error {
+	secret, err := r.sealer.Seal(e.Secret)
+	if err != nil {
+		return errors.Wrap(err, "Failed to seal a TOTP secret")
+	}
+
+	q := fmt.Sprintf(`
+		INSERT INTO %s
+			(account_id, secret, confirmed_at, last_used_counter)
+		VALUES
+			($1, $2, $3, $4)
+		ON CONFLICT (account_id) DO
+			UPDATE SET
+				(secret, confirmed_at, last_used_counter)
+				= ($2, $3, $4)
+			WHERE
+				%s.account_id = $1
+	`, pq.QuoteIdentifier(totpTable), pq.QuoteIdentifier(totpTable))
+
+	_, err = r.db.Exec(
+		q,
+		e.AccountID,
+		secret,
+		pq.NullTime{Time: e.ConfirmedAt, Valid: !e.ConfirmedAt.IsZero()},
+		int64(e.LastUsedCounter),
+	)
+	return errors.Wrap(err, "Failed to save a TOTP enrollment")
+}
+
+func (r totpRepository) FindByAccount(accountID int64) (*totp.Enrollment, error) {
+	q := fmt.Sprintf(`
+		SELECT
+			account_id, secret, confirmed_at, last_used_counter
+		FROM
+			%s
+		WHERE
+			account_id = $1
+	`, pq.QuoteIdentifier(totpTable))
+
+	e := &totp.Enrollment{}
+	var secret []byte
+	var confirmedAt pq.NullTime
+	var lastUsedCounter int64
+	err := r.db.QueryRow(q, accountID).Scan(
+		&e.AccountID,
+		&secret,
+		&confirmedAt,
+		&lastUsedCounter,
+	)
+	if err == sql.ErrNoRows {
+		return nil, totp.ErrNotFound
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to find a TOTP enrollment")
+	}
+
+	e.Secret, err = r.sealer.Open(secret)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to open a TOTP secret")
+	}
+	e.ConfirmedAt = confirmedAt.Time
+	e.LastUsedCounter = uint64(lastUsedCounter)
+
+	return e, nil
+}
+
+func (r totpRepository) Confirm(accountID int64, confirmedAt time.Time) error {
+	q := fmt.Sprintf(`
+		UPDATE %s
+		SET
+			confirmed_at = $2
+		WHERE
+			account_id = $1
+	`, pq.QuoteIdentifier(totpTable))
+
+	_, err := r.db.Exec(q, accountID, confirmedAt)
+	return errors.Wrap(err, "Failed to confirm a TOTP enrollment")
+}
+
+func (r totpRepository) UseCounter(accountID int64, counter uint64) (bool, error) {
+	q := fmt.Sprintf(`
+		UPDATE %s
+		SET
+			last_used_counter = $2
+		WHERE
+			account_id = $1
+			AND last_used_counter < $2
+	`, pq.QuoteIdentifier(totpTable))
+
+	res, err := r.db.Exec(q, accountID, int64(counter))
+	if err != nil {
+		return false, errors.Wrap(err, "Failed to use a TOTP counter")
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(err, "Failed to use a TOTP counter")
+	}
+
+	return n == 1, nil
+}
+
+func (r totpRepository) Delete(accountID int64) error {
+	q := fmt.Sprintf(`
+		DELETE FROM
+			%s
+		WHERE
+			account_id = $1
+	`, pq.QuoteIdentifier(totpTable))
+
+	_, err := r.db.Exec(q, accountID)
+	return errors.Wrap(err, "Failed to delete a TOTP enrollment")
+}
+
+type totpChallengeRepository struct {
+	db *sql.DB
+}
+
+// NewTOTPChallengeRepository returns a new totp.ChallengeRepository with PostgreSQL as a storage.
+func NewTOTPChallengeRepository(db *sql.DB) totp.ChallengeRepository {
+	return &totpChallengeRepository{db: db}
+}
+
+func (r totpChallengeRepository) Save(c totp.Challenge) error {
+	// The challenge is inserted only if the account has not too many challenges
+	// which are not expired, in the same statement.
+	q := fmt.Sprintf(`
+		INSERT INTO %[1]s
+			(id, account_id, expires_at)
+		SELECT
+			$1, $2, $3
+		WHERE
+			(SELECT count(*) FROM %[1]s WHERE account_id = $2 AND expires_at > now()) < $4
+	`, pq.QuoteIdentifier(totpChallengeTable))
+
+	res, err := r.db.Exec(q, c.ID, c.AccountID, c.ExpiresAt, totp.MaxActiveChallenges)
+	if err != nil {
+		return errors.Wrap(err, "Failed to save a TOTP challenge")
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "Failed to save a TOTP challenge")
+	}
+	if n == 0 {
+		return totp.ErrTooManyChallenges
+	}
+
+	return nil
+}
+
+func (r totpChallengeRepository) FindByID(id string) (*totp.Challenge, error) {
+	q := fmt.Sprintf(`
+		SELECT
+			id, account_id, expires_at
+		FROM
+			%s
+		WHERE
+			id = $1
+	`, pq.QuoteIdentifier(totpChallengeTable))
+
+	c := &totp.Challenge{}
+	err := r.db.QueryRow(q, id).Scan(&c.ID, &c.AccountID, &c.ExpiresAt)
+	if err == sql.ErrNoRows {
+		return nil, totp.ErrChallengeNotFound
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to find a TOTP challenge")
+	}
+
+	return c, nil
+}
+
+func (r totpChallengeRepository) Attempt(id string) (bool, error) {
+	q := fmt.Sprintf(`
+		UPDATE %s
+		SET
+			attempts = attempts + 1
+		WHERE
+			id = $1
+			AND attempts < $2
+	`, pq.QuoteIdentifier(totpChallengeTable))
+
+	res, err := r.db.Exec(q, id, totp.ChallengeMaxAttempts)
+	if err != nil {
+		return false, errors.Wrap(err, "Failed to count a TOTP challenge attempt")
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(err, "Failed to count a TOTP challenge attempt")
+	}
+
+	return n == 1, nil
+}
+
+func (r totpChallengeRepository) Delete(id string) error {
+	q := fmt.Sprintf(`
+		DELETE FROM
+			%s
+		WHERE
+			id = $1
+	`, pq.QuoteIdentifier(totpChallengeTable))
+
+	_, err := r.db.Exec(q, id)
+	return errors.Wrap(err, "Failed to delete a TOTP challenge")
+}
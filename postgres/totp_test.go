@@ -0,0 +1,113 @@
+package postgres
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/pascont/account"
+	"github.com/hypnoglow/pascont/identity"
+	"github.com/hypnoglow/pascont/sealer"
+	"github.com/hypnoglow/pascont/totp"
+)
+
+func TestTOTPRepository(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	s, err := sealer.NewAESGCMSealer([]byte("0123456789abcdef0123456789abcdef"))
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+
+	accountRepo := NewAccountRepository(db)
+	repo := NewTOTPRepository(db, s)
+	challengeRepo := NewTOTPChallengeRepository(db)
+	name := fmt.Sprintf("totp-%d", time.Now().UnixNano())
+
+	acc, err := accountRepo.Accept(account.NewApplication(defaultTenantID(t, db), name, []byte("password_hash"), time.Now()))
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	defer accountRepo.Delete(acc.ID)
+
+	if _, err = repo.FindByAccount(acc.ID); err != totp.ErrNotFound {
+		t.Errorf("Expected error to be %v, but got %v", totp.ErrNotFound, err)
+	}
+
+	secret := []byte("12345678901234567890")
+	if err = repo.Save(*totp.NewEnrollment(acc.ID, secret)); err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+
+	var stored []byte
+	if err = db.QueryRow(`SELECT secret FROM account_totp WHERE account_id = $1`, acc.ID).Scan(&stored); err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	if bytes.Contains(stored, secret) {
+		t.Errorf("Expected secret to be stored encrypted")
+	}
+
+	if err = repo.Confirm(acc.ID, time.Now()); err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+
+	used, err := repo.UseCounter(acc.ID, 10)
+	if err != nil || !used {
+		t.Errorf("Expected counter to be used, but got %v, %v", used, err)
+	}
+	used, err = repo.UseCounter(acc.ID, 10)
+	if err != nil || used {
+		t.Errorf("Expected counter not to be used again, but got %v, %v", used, err)
+	}
+
+	e, err := repo.FindByAccount(acc.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	if !bytes.Equal(e.Secret, secret) || !e.IsConfirmed() || e.LastUsedCounter != 10 {
+		t.Errorf("Unexpected enrollment %#v", e)
+	}
+
+	c := totp.NewChallenge(identity.NewUUIDV4, acc.ID, time.Now())
+	if err = challengeRepo.Save(*c); err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	if found, err := challengeRepo.FindByID(c.ID); err != nil || found.AccountID != acc.ID {
+		t.Errorf("Unexpected challenge %#v, error %v", found, err)
+	}
+	for i := 0; i < totp.ChallengeMaxAttempts; i++ {
+		if ok, err := challengeRepo.Attempt(c.ID); err != nil || !ok {
+			t.Errorf("Expected attempt %d to be allowed, but got %v, %v", i, ok, err)
+		}
+	}
+	if ok, err := challengeRepo.Attempt(c.ID); err != nil || ok {
+		t.Errorf("Expected no attempts to be left, but got %v, %v", ok, err)
+	}
+	if err = challengeRepo.Delete(c.ID); err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	if ok, err := challengeRepo.Attempt(c.ID); err != nil || ok {
+		t.Errorf("Expected deleted challenge not to be attempted, but got %v, %v", ok, err)
+	}
+
+	for i := 0; i < totp.MaxActiveChallenges; i++ {
+		if err = challengeRepo.Save(*totp.NewChallenge(identity.NewUUIDV4, acc.ID, time.Now())); err != nil {
+			t.Fatalf("Expected no error, but got %s", err)
+		}
+	}
+	if err = challengeRepo.Save(*totp.NewChallenge(identity.NewUUIDV4, acc.ID, time.Now())); err != totp.ErrTooManyChallenges {
+		t.Errorf("Expected error to be %v, but got %v", totp.ErrTooManyChallenges, err)
+	}
+	if _, err = challengeRepo.FindByID(c.ID); err != totp.ErrChallengeNotFound {
+		t.Errorf("Expected error to be %v, but got %v", totp.ErrChallengeNotFound, err)
+	}
+
+	if err = repo.Delete(acc.ID); err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	if _, err = repo.FindByAccount(acc.ID); err != totp.ErrNotFound {
+		t.Errorf("Expected error to be %v, but got %v", totp.ErrNotFound, err)
+	}
+}
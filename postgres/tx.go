@@ -0,0 +1,31 @@
+package postgres
+
+import (
+	"database/sql"
+
+	"github.com/pkg/errors"
+)
+
+// execer executes queries. It is implemented by both *sql.DB and *sql.Tx.
+type execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// withTx runs fn in a transaction, which is committed if fn succeeds
+// and rolled back otherwise.
+func withTx(db *sql.DB, fn func(ex execer) error) (err error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return errors.Wrap(err, "Failed to begin a transaction")
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		err = errors.Wrap(tx.Commit(), "Failed to commit a transaction")
+	}()
+
+	return fn(tx)
+}
@@ -0,0 +1,158 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/pborman/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/hypnoglow/pascont/sealer"
+	"github.com/hypnoglow/pascont/webhook"
+)
+
+const webhookSubscriptionTable = "webhook_subscription"
+
+type webhookRepository struct {
+	db     *sql.DB
+	sealer sealer.Sealer
+}
+
+// NewWebhookRepository returns a new webhook.Repository with PostgreSQL as a storage.
+// Secrets are sealed with s before they are stored.
+func NewWebhookRepository(db *sql.DB, s sealer.Sealer) webhook.Repository {
+	return &webhookRepository{db: db, sealer: s}
+}
+
+func (r webhookRepository) Save(s webhook.Subscription) error {
+	secret, err := r.sealer.Seal(s.Secret)
+	if err != nil {
+		return errors.Wrap(err, "Failed to seal a subscription secret")
+	}
+
+	q := fmt.Sprintf(`
+		INSERT INTO %s
+			(id, url, events, secret, created_at)
+		VALUES
+			($1, $2, $3, $4, $5)
+		ON CONFLICT (id) DO
+			UPDATE SET
+				(url, events, secret)
+				= ($2, $3, $4)
+			WHERE
+				%s.id = $1
+	`, pq.QuoteIdentifier(webhookSubscriptionTable), pq.QuoteIdentifier(webhookSubscriptionTable))
+
+	// Subscriptions to all events have no events.
+	events := s.Events
+	if events == nil {
+		events = []string{}
+	}
+
+	_, err = r.db.Exec(q, s.ID, s.URL, pq.Array(events), secret, s.CreatedAt)
+	return errors.Wrap(err, "Failed to save a subscription")
+}
+
+func (r webhookRepository) FindByID(id string) (*webhook.Subscription, error) {
+	// Stored subscriptions have UUID ids, other ids can only belong to the static ones.
+	if uuid.Parse(id) == nil {
+		return nil, webhook.ErrNotFound
+	}
+
+	q := fmt.Sprintf(`
+		SELECT
+			id, url, events, secret, created_at
+		FROM
+			%s
+		WHERE
+			id = $1
+	`, pq.QuoteIdentifier(webhookSubscriptionTable))
+
+	s, err := r.scanSubscription(r.db.QueryRow(q, id))
+	if err == sql.ErrNoRows {
+		return nil, webhook.ErrNotFound
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to find a subscription")
+	}
+
+	return s, nil
+}
+
+func (r webhookRepository) List() ([]*webhook.Subscription, error) {
+	q := fmt.Sprintf(`
+		SELECT
+			id, url, events, secret, created_at
+		FROM
+			%s
+		ORDER BY
+			created_at, id
+	`, pq.QuoteIdentifier(webhookSubscriptionTable))
+
+	rows, err := r.db.Query(q)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to list subscriptions")
+	}
+	defer rows.Close()
+
+	var subscriptions []*webhook.Subscription
+	for rows.Next() {
+		s, err := r.scanSubscription(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to list subscriptions")
+		}
+		subscriptions = append(subscriptions, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "Failed to list subscriptions")
+	}
+
+	return subscriptions, nil
+}
+
+func (r webhookRepository) Delete(id string) error {
+	if uuid.Parse(id) == nil {
+		return webhook.ErrNotFound
+	}
+
+	q := fmt.Sprintf(`
+		DELETE FROM %s
+		WHERE
+			id = $1
+	`, pq.QuoteIdentifier(webhookSubscriptionTable))
+
+	res, err := r.db.Exec(q, id)
+	if err != nil {
+		return errors.Wrap(err, "Failed to delete a subscription")
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "Failed to delete a subscription")
+	}
+	if n == 0 {
+		return webhook.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r webhookRepository) scanSubscription(row scanner) (*webhook.Subscription, error) {
+	s := &webhook.Subscription{}
+	var events []string
+	var secret []byte
+	if err := row.Scan(&s.ID, &s.URL, pq.Array(&events), &secret, &s.CreatedAt); err != nil {
+		return nil, err
+	}
+	if len(events) > 0 {
+		s.Events = events
+	}
+
+	var err error
+	if s.Secret, err = r.sealer.Open(secret); err != nil {
+		return nil, errors.Wrap(err, "Failed to open a subscription secret")
+	}
+
+	return s, nil
+}
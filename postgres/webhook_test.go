@@ -0,0 +1,75 @@
+package postgres
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/pascont/identity"
+	"github.com/hypnoglow/pascont/sealer"
+	"github.com/hypnoglow/pascont/webhook"
+)
+
+func TestWebhookRepository(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	s, err := sealer.NewAESGCMSealer([]byte("0123456789abcdef0123456789abcdef"))
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+
+	repo := NewWebhookRepository(db, s)
+
+	sub, err := webhook.Generate(identity.NewUUIDV4, "https://example.com/hook", []string{webhook.EventAccountCreated}, time.Now())
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	if err = repo.Save(*sub); err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	defer repo.Delete(sub.ID)
+
+	found, err := repo.FindByID(sub.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	if found.URL != sub.URL || !reflect.DeepEqual(found.Events, sub.Events) || !reflect.DeepEqual(found.Secret, sub.Secret) {
+		t.Errorf("Expected subscription to be %#v, but got %#v", *sub, *found)
+	}
+
+	sub.Events = nil
+	if err = repo.Save(*sub); err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+
+	list, err := repo.List()
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	var listed *webhook.Subscription
+	for _, l := range list {
+		if l.ID == sub.ID {
+			listed = l
+		}
+	}
+	if listed == nil || listed.Events != nil {
+		t.Errorf("Expected subscription %s to all events to be listed, but got %#v", sub.ID, listed)
+	}
+
+	if err = repo.Delete(sub.ID); err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	if err = repo.Delete(sub.ID); err != webhook.ErrNotFound {
+		t.Errorf("Expected error to be %v, but got %v", webhook.ErrNotFound, err)
+	}
+	if _, err = repo.FindByID(sub.ID); err != webhook.ErrNotFound {
+		t.Errorf("Expected error to be %v, but got %v", webhook.ErrNotFound, err)
+	}
+	if _, err = repo.FindByID("billing"); err != webhook.ErrNotFound {
+		t.Errorf("Expected error to be %v, but got %v", webhook.ErrNotFound, err)
+	}
+	if err = repo.Delete("billing"); err != webhook.ErrNotFound {
+		t.Errorf("Expected error to be %v, but got %v", webhook.ErrNotFound, err)
+	}
+}
@@ -0,0 +1,58 @@
+package recovery
+
+type fakeRepository struct {
+	replaceResults             []FakeRepositoryReplaceResult
+	replaceResultCounter       int
+	listByAccountResults       []FakeRepositoryListByAccountResult
+	listByAccountResultCounter int
+	useResults                 []FakeRepositoryUseResult
+	useResultCounter           int
+}
+
+type FakeRepositoryReplaceResult struct {
+	Error error
+}
+
+type FakeRepositoryListByAccountResult struct {
+	Codes []*Code
+	Error error
+}
+
+type FakeRepositoryUseResult struct {
+	Used  bool
+	Error error
+}
+
+// NewFakeRepository returns a new fake Repository.
+func NewFakeRepository(
+	replaceResults []FakeRepositoryReplaceResult,
+	listByAccountResults []FakeRepositoryListByAccountResult,
+	useResults []FakeRepositoryUseResult,
+) Repository {
+	return &fakeRepository{
+		replaceResults:             replaceResults,
+		replaceResultCounter:       0,
+		listByAccountResults:       listByAccountResults,
+		listByAccountResultCounter: 0,
+		useResults:                 useResults,
+		useResultCounter:           0,
+	}
+}
+
+func (r *fakeRepository) Replace(accountID int64, codes []Code) error {
+	res := r.replaceResults[r.replaceResultCounter]
+	r.replaceResultCounter++
+	return res.Error
+}
+
+func (r *fakeRepository) ListByAccount(accountID int64) ([]*Code, error) {
+	res := r.listByAccountResults[r.listByAccountResultCounter]
+	r.listByAccountResultCounter++
+	return res.Codes, res.Error
+}
+
+func (r *fakeRepository) Use(id int64) (bool, error) {
+	res := r.useResults[r.useResultCounter]
+	r.useResultCounter++
+	return res.Used, res.Error
+}
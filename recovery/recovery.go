@@ -0,0 +1,110 @@
+package recovery
+
+import (
+	"crypto/rand"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/hypnoglow/pascont/hasher"
+)
+
+const (
+	// Count is the number of codes in a set.
+	Count = 10
+
+	// codeLength is the length of a code without the separator.
+	codeLength = 10
+
+	// alphabet excludes characters which are easy to confuse, like 0 and o, 1 and l.
+	alphabet = "23456789abcdefghjkmnpqrstuvwxyz"
+)
+
+// Code is a single-use recovery code, which can be spent instead of the second factor.
+// Only a hash of the code is stored.
+type Code struct {
+	ID        int64
+	AccountID int64
+	Hash      []byte
+	CreatedAt time.Time
+}
+
+// Generate returns a new set of Count random codes in the form of "xxxxx-xxxxx".
+func Generate() ([]string, error) {
+	return generate(rand.Reader)
+}
+
+// generate returns a new set of codes with characters picked uniformly by random bytes from r.
+func generate(r io.Reader) ([]string, error) {
+	// Bytes from limit on are rejected, otherwise the first 256 % len(alphabet)
+	// characters of the alphabet would be picked more often than the rest.
+	const limit = 256 - 256%len(alphabet)
+
+	codes := make([]string, Count)
+	b := make([]byte, 1)
+	for i := range codes {
+		code := make([]byte, 0, codeLength)
+		for len(code) < codeLength {
+			if _, err := io.ReadFull(r, b); err != nil {
+				return nil, errors.Wrap(err, "Failed to generate a recovery code")
+			}
+			if int(b[0]) >= limit {
+				continue
+			}
+			code = append(code, alphabet[int(b[0])%len(alphabet)])
+		}
+		codes[i] = string(code[:codeLength/2]) + "-" + string(code[codeLength/2:])
+	}
+
+	return codes, nil
+}
+
+// Normalize returns the code as it is hashed,
+// so codes can be entered in any case and with or without the separator.
+func Normalize(code string) string {
+	code = strings.ToLower(code)
+	code = strings.Replace(code, "-", "", -1)
+	return strings.Replace(code, " ", "", -1)
+}
+
+// NewCodes returns the codes of the account hashed with h.
+func NewCodes(h hasher.Hasher, accountID int64, codes []string, createdAt time.Time) ([]Code, error) {
+	hashed := make([]Code, len(codes))
+	for i, code := range codes {
+		hash, err := h.GenerateHashFromPassword([]byte(Normalize(code)))
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to hash a recovery code")
+		}
+		hashed[i] = Code{
+			AccountID: accountID,
+			Hash:      hash,
+			CreatedAt: createdAt,
+		}
+	}
+
+	return hashed, nil
+}
+
+// Spend checks the code against the codes of the account and marks the matching one used,
+// so every code is accepted only once.
+func Spend(repo Repository, h hasher.Hasher, accountID int64, code string) (bool, error) {
+	codes, err := repo.ListByAccount(accountID)
+	if err != nil {
+		return false, err
+	}
+
+	code = Normalize(code)
+	for _, c := range codes {
+		if h.CompareHashWithPassword(c.Hash, []byte(code)) != nil {
+			continue
+		}
+
+		// The code may have been spent concurrently since the codes were retrieved,
+		// so the repository decides.
+		return repo.Use(c.ID)
+	}
+
+	return false, nil
+}
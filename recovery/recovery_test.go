@@ -0,0 +1,237 @@
+package recovery
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/pascont/hasher"
+)
+
+func TestGenerate(t *testing.T) {
+	codes, err := Generate()
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+
+	if len(codes) != Count {
+		t.Errorf("Expected %d codes, but got %d\n", Count, len(codes))
+	}
+
+	pattern := regexp.MustCompile(`^[23456789abcdefghjkmnpqrstuvwxyz]{5}-[23456789abcdefghjkmnpqrstuvwxyz]{5}$`)
+	seen := map[string]bool{}
+	for _, code := range codes {
+		if !pattern.MatchString(code) {
+			t.Errorf("Unexpected code format %s\n", code)
+		}
+		if seen[code] {
+			t.Errorf("Expected codes to be unique, but got %s twice\n", code)
+		}
+		seen[code] = true
+	}
+}
+
+func TestGenerate_Uniform(t *testing.T) {
+	// Bytes from 248 on would pick the first characters of the alphabet more often,
+	// so they are skipped.
+	var random []byte
+	for i := 0; i < Count; i++ {
+		random = append(random, 248, 255, 0, 1, 2, 3, 4, 5, 6, 7, 8, 247)
+	}
+
+	codes, err := generate(bytes.NewReader(random))
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+
+	for i, code := range codes {
+		if code != "23456-789az" {
+			t.Errorf("code %d: Expected 23456-789az but got %s\n", i, code)
+		}
+	}
+
+	if _, err := generate(bytes.NewReader(random[:len(random)-1])); err == nil {
+		t.Errorf("Expected an error when random bytes run out, but got nil\n")
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	cases := []struct {
+		code     string
+		expected string
+	}{
+		{"abcde-fghjk", "abcdefghjk"},
+		{"ABCDE-FGHJK", "abcdefghjk"},
+		{"abcde fghjk", "abcdefghjk"},
+		{"abcdefghjk", "abcdefghjk"},
+	}
+
+	for i, c := range cases {
+		if actual := Normalize(c.code); actual != c.expected {
+			t.Errorf("testcase %d: Expected %s but got %s\n", i, c.expected, actual)
+		}
+	}
+}
+
+func TestNewCodes(t *testing.T) {
+	now := time.Now()
+
+	h := hasher.NewFakeHasher(
+		[]hasher.FakeGenerateHashFromPasswordResult{
+			{
+				Hash: []byte("hash1"),
+			},
+			{
+				Hash: []byte("hash2"),
+			},
+		},
+		nil,
+	)
+
+	codes, err := NewCodes(h, 123, []string{"abcde-fghjk", "mnpqr-stuvw"}, now)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+
+	expected := []Code{
+		{AccountID: 123, Hash: []byte("hash1"), CreatedAt: now},
+		{AccountID: 123, Hash: []byte("hash2"), CreatedAt: now},
+	}
+	if !reflect.DeepEqual(codes, expected) {
+		t.Errorf("Expected codes to be %#v, but got %#v\n", expected, codes)
+	}
+
+	h = hasher.NewFakeHasher(
+		[]hasher.FakeGenerateHashFromPasswordResult{
+			{
+				Error: fmt.Errorf("Failed to generate hash"),
+			},
+		},
+		nil,
+	)
+	if _, err = NewCodes(h, 123, []string{"abcde-fghjk"}, now); err == nil {
+		t.Errorf("Expected error, but got nil\n")
+	}
+}
+
+func TestSpend(t *testing.T) {
+	codes := []*Code{
+		{ID: 1, AccountID: 123, Hash: []byte("hash1")},
+		{ID: 2, AccountID: 123, Hash: []byte("hash2")},
+	}
+
+	cases := []struct {
+		caseName string
+		// in
+		repo   Repository
+		hasher hasher.Hasher
+		// out
+		expectedOK    bool
+		expectedError error
+	}{
+		{
+			caseName: "repo.ListByAccount failed",
+			repo: NewFakeRepository(
+				nil,
+				[]FakeRepositoryListByAccountResult{
+					{
+						Error: fmt.Errorf("ListByAccount failed"),
+					},
+				},
+				nil,
+			),
+			expectedOK:    false,
+			expectedError: fmt.Errorf("ListByAccount failed"),
+		},
+		{
+			caseName: "Code does not match",
+			repo: NewFakeRepository(
+				nil,
+				[]FakeRepositoryListByAccountResult{
+					{
+						Codes: codes,
+					},
+				},
+				nil,
+			),
+			hasher: hasher.NewFakeHasher(
+				nil,
+				[]hasher.FakeCompareHashWithPasswordResult{
+					{
+						Error: fmt.Errorf("Mismatch"),
+					},
+					{
+						Error: fmt.Errorf("Mismatch"),
+					},
+				},
+			),
+			expectedOK: false,
+		},
+		{
+			caseName: "Code was spent concurrently",
+			repo: NewFakeRepository(
+				nil,
+				[]FakeRepositoryListByAccountResult{
+					{
+						Codes: codes,
+					},
+				},
+				[]FakeRepositoryUseResult{
+					{
+						Used: false,
+					},
+				},
+			),
+			hasher: hasher.NewFakeHasher(
+				nil,
+				[]hasher.FakeCompareHashWithPasswordResult{
+					{
+						Error: nil,
+					},
+				},
+			),
+			expectedOK: false,
+		},
+		{
+			caseName: "Successful",
+			repo: NewFakeRepository(
+				nil,
+				[]FakeRepositoryListByAccountResult{
+					{
+						Codes: codes,
+					},
+				},
+				[]FakeRepositoryUseResult{
+					{
+						Used: true,
+					},
+				},
+			),
+			hasher: hasher.NewFakeHasher(
+				nil,
+				[]hasher.FakeCompareHashWithPasswordResult{
+					{
+						Error: fmt.Errorf("Mismatch"),
+					},
+					{
+						Error: nil,
+					},
+				},
+			),
+			expectedOK: true,
+		},
+	}
+
+	for i, c := range cases {
+		ok, err := Spend(c.repo, c.hasher, 123, "ABCDE-FGHJK")
+		if ok != c.expectedOK {
+			t.Errorf("testcase %d %s: Expected %v but got %v\n", i, c.caseName, c.expectedOK, ok)
+		}
+		if !reflect.DeepEqual(err, c.expectedError) {
+			t.Errorf("testcase %d %s: Expected error to be %v but got %v\n", i, c.caseName, c.expectedError, err)
+		}
+	}
+}
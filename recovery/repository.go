@@ -0,0 +1,14 @@
+package recovery
+
+// Repository is a repository for recovery codes.
+type Repository interface {
+	// Replace replaces all codes of the account with the codes.
+	// Empty codes remove all codes of the account.
+	Replace(accountID int64, codes []Code) error
+
+	// ListByAccount retrieves all unused codes of the account.
+	ListByAccount(accountID int64) ([]*Code, error)
+
+	// Use removes the code with the id. Returns whether it was not used yet.
+	Use(id int64) (bool, error)
+}
@@ -0,0 +1,118 @@
+package reset
+
+import (
+	"github.com/hypnoglow/pascont/account"
+	"github.com/hypnoglow/pascont/outbox"
+)
+
+type fakeRepository struct {
+	saveResults           []FakeRepositorySaveResult
+	saveResultCounter     int
+	findByIDResults       []FakeRepositoryFindByIDResult
+	findByIDResultCounter int
+	completeResults       []FakeRepositoryCompleteResult
+	completeResultCounter int
+	issueResults          []FakeRepositoryIssueResult
+	issueResultCounter    int
+}
+
+type FakeRepositorySaveResult struct {
+	Error error
+}
+
+type FakeRepositoryFindByIDResult struct {
+	Reset *Reset
+	Error error
+}
+
+type FakeRepositoryCompleteResult struct {
+	Completed bool
+	Error     error
+}
+
+type FakeRepositoryIssueResult struct {
+	Error error
+}
+
+// NewFakeRepository returns a new fake Repository.
+func NewFakeRepository(
+	saveResults []FakeRepositorySaveResult,
+	findByIDResults []FakeRepositoryFindByIDResult,
+	completeResults []FakeRepositoryCompleteResult,
+	issueResults []FakeRepositoryIssueResult,
+) Repository {
+	return &fakeRepository{
+		saveResults:           saveResults,
+		saveResultCounter:     0,
+		findByIDResults:       findByIDResults,
+		findByIDResultCounter: 0,
+		completeResults:       completeResults,
+		completeResultCounter: 0,
+		issueResults:          issueResults,
+		issueResultCounter:    0,
+	}
+}
+
+func (r *fakeRepository) Save(rs Reset, messages ...outbox.Message) error {
+	res := r.saveResults[r.saveResultCounter]
+	r.saveResultCounter++
+	return res.Error
+}
+
+func (r *fakeRepository) Issue(rs Reset, change account.PasswordChange) error {
+	res := r.issueResults[r.issueResultCounter]
+	r.issueResultCounter++
+	return res.Error
+}
+
+func (r *fakeRepository) FindByID(id string) (*Reset, error) {
+	res := r.findByIDResults[r.findByIDResultCounter]
+	r.findByIDResultCounter++
+	return res.Reset, res.Error
+}
+
+func (r *fakeRepository) Complete(id string, change account.PasswordChange) (bool, error) {
+	res := r.completeResults[r.completeResultCounter]
+	r.completeResultCounter++
+	return res.Completed, res.Error
+}
+
+type fakeSender struct {
+	sendResults                         []FakeSenderSendResult
+	sendResultCounter                   int
+	sendWithPasswordChangeResults       []FakeSenderSendWithPasswordChangeResult
+	sendWithPasswordChangeResultCounter int
+}
+
+type FakeSenderSendResult struct {
+	Error error
+}
+
+type FakeSenderSendWithPasswordChangeResult struct {
+	Error error
+}
+
+// NewFakeSender returns a new fake Sender.
+func NewFakeSender(
+	sendResults []FakeSenderSendResult,
+	sendWithPasswordChangeResults []FakeSenderSendWithPasswordChangeResult,
+) Sender {
+	return &fakeSender{
+		sendResults:                         sendResults,
+		sendResultCounter:                   0,
+		sendWithPasswordChangeResults:       sendWithPasswordChangeResults,
+		sendWithPasswordChangeResultCounter: 0,
+	}
+}
+
+func (s *fakeSender) Send(acc account.Account) error {
+	res := s.sendResults[s.sendResultCounter]
+	s.sendResultCounter++
+	return res.Error
+}
+
+func (s *fakeSender) SendWithPasswordChange(change account.PasswordChange) error {
+	res := s.sendWithPasswordChangeResults[s.sendWithPasswordChangeResultCounter]
+	s.sendWithPasswordChangeResultCounter++
+	return res.Error
+}
@@ -0,0 +1,51 @@
+package reset
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/hypnoglow/pascont/notary"
+	"github.com/hypnoglow/pascont/notifier"
+	"github.com/hypnoglow/pascont/packer"
+)
+
+// tokenNotifier is a notifier.Notifier which adds reset tokens to notifications before delivery.
+type tokenNotifier struct {
+	next      notifier.Notifier
+	notary    notary.Notary
+	packer    packer.Packer
+	secretKey []byte
+}
+
+// NewTokenNotifier returns a new notifier.Notifier which signs the token of the reset
+// a KindPasswordReset notification refers to, and delivers the notification with the token
+// to the next notifier. Notifications of other kinds are delivered as they are.
+func NewTokenNotifier(next notifier.Notifier, n notary.Notary, p packer.Packer, secretKey []byte) notifier.Notifier {
+	return tokenNotifier{next, n, p, secretKey}
+}
+
+func (tn tokenNotifier) Notify(n notifier.Notification) error {
+	if n.Kind != notifier.KindPasswordReset {
+		return tn.next.Notify(n)
+	}
+
+	expiresAt, err := time.Parse(time.RFC3339, n.Params["expiresAt"])
+	if err != nil {
+		return errors.Wrap(err, "Failed to read the expiration of a password reset")
+	}
+	token, err := NewToken(tn.notary, tn.packer, tn.secretKey, n.Params["resetID"], expiresAt)
+	if err != nil {
+		return err
+	}
+
+	// The params are copied, so the token does not leak into the notification of the caller.
+	params := make(map[string]string, len(n.Params)+1)
+	for k, v := range n.Params {
+		params[k] = v
+	}
+	params["token"] = token
+	n.Params = params
+
+	return tn.next.Notify(n)
+}
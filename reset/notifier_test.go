@@ -0,0 +1,64 @@
+package reset
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/pascont/notary"
+	"github.com/hypnoglow/pascont/notifier"
+	"github.com/hypnoglow/pascont/packer"
+)
+
+func TestTokenNotifier_Notify(t *testing.T) {
+	now := time.Date(2017, 7, 1, 21, 10, 29, 0, time.UTC)
+	n := notary.NewHMACNotary()
+	p := packer.NewBase64Packer(IDLength + ExpiresAtLength)
+	key := []byte("secret_key")
+	uuidProducer := func() string {
+		return "12345678-90ab-cdef-0123-4567890abcde"
+	}
+
+	r, token, err := New(uuidProducer, n, p, key, 123, now)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+
+	var buf bytes.Buffer
+	tn := NewTokenNotifier(notifier.NewWriterNotifier(&buf), n, p, key)
+
+	params := map[string]string{
+		"resetID":   r.ID,
+		"expiresAt": r.ExpiresAt.Format(time.RFC3339),
+	}
+	err = tn.Notify(notifier.Notification{Kind: notifier.KindPasswordReset, AccountID: 123, Params: params})
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	if _, ok := params["token"]; ok {
+		t.Errorf("Expected params of the caller not to be changed, but got %v\n", params)
+	}
+
+	var sent notifier.Notification
+	if err := json.Unmarshal(buf.Bytes(), &sent); err != nil {
+		t.Fatalf("Failed to read the notification: %s", err)
+	}
+	if sent.Params["token"] != token || !r.Matches(sent.Params["token"]) {
+		t.Errorf("Expected notification to have token %s, but got %s\n", token, sent.Params["token"])
+	}
+
+	// Notifications of other kinds are delivered as they are.
+	buf.Reset()
+	err = tn.Notify(notifier.Notification{Kind: notifier.KindNewLogin, AccountID: 123, Params: map[string]string{}})
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	sent = notifier.Notification{}
+	if err := json.Unmarshal(buf.Bytes(), &sent); err != nil {
+		t.Fatalf("Failed to read the notification: %s", err)
+	}
+	if _, ok := sent.Params["token"]; ok {
+		t.Errorf("Expected no token in notification of kind %s, but got %s\n", sent.Kind, sent.Params["token"])
+	}
+}
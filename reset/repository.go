@@ -0,0 +1,38 @@
+package reset
+
+import (
+	"github.com/hypnoglow/pascont/account"
+	"github.com/hypnoglow/pascont/outbox"
+)
+
+// Repository is a repository for a Reset.
+type Repository interface {
+	// Save saves a Reset.
+	// The outbox messages are added along with the reset, in the same transaction.
+	Save(r Reset, messages ...outbox.Message) error
+
+	// Issue saves a Reset and applies the password change, in a single transaction.
+	Issue(r Reset, change account.PasswordChange) error
+
+	// FindByID retrieves a Reset for matching id.
+	// If reset with such id not found, returns ErrNotFound.
+	// Other errors may occur.
+	FindByID(id string) (*Reset, error)
+
+	// Complete removes the reset of the account with the id along with other resets
+	// of the account, and applies the password change, in a single transaction.
+	// Returns false and changes nothing if the reset was already used.
+	Complete(id string, change account.PasswordChange) (bool, error)
+}
+
+// repositoryError is an error occurred in Repository.
+type repositoryError string
+
+func (e repositoryError) Error() string {
+	return string(e)
+}
+
+const (
+	// ErrNotFound occurs when reset not found.
+	ErrNotFound = repositoryError("Password reset not found")
+)
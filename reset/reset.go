@@ -0,0 +1,105 @@
+// Package reset provides password resets for accounts which passwords are forgotten.
+package reset
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/hypnoglow/pascont/identity"
+	"github.com/hypnoglow/pascont/notary"
+	"github.com/hypnoglow/pascont/packer"
+)
+
+const (
+	// Duration is how long a password reset can be completed.
+	Duration = time.Hour
+
+	// IDLength is the length of a reset ID in bytes.
+	IDLength = 36
+
+	// ExpiresAtLength is the length of a reset ExpiresAt in Unix Timestamp form in bytes.
+	ExpiresAtLength = 10
+)
+
+// Reset represents a request to reset the password of an account.
+// It is completed with a token, which is signed and only a hash of it is kept.
+type Reset struct {
+	ID        string
+	AccountID int64
+	Hash      []byte
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
+
+// New creates a new Reset for the account.
+// The Reset ID is a UUID produced by identity.UUIDProducer func.
+// Returns the reset along with its token. The token is not kept,
+// but the same token can be signed again with NewToken to deliver it.
+func New(uuidProducer identity.UUIDProducer, n notary.Notary, p packer.Packer, secretKey []byte, accountID int64, createdAt time.Time) (r *Reset, token string, err error) {
+	createdAt = createdAt.UTC().Truncate(time.Second)
+	r = &Reset{
+		ID:        uuidProducer(),
+		AccountID: accountID,
+		CreatedAt: createdAt,
+		ExpiresAt: createdAt.Add(Duration),
+	}
+
+	token, err = NewToken(n, p, secretKey, r.ID, r.ExpiresAt)
+	if err != nil {
+		return nil, "", err
+	}
+
+	r.Hash = hashToken(token)
+	return r, token, nil
+}
+
+// NewToken returns the signed token of the reset with the id which expires at expiresAt.
+// Signing is deterministic, so the token is the same every time.
+func NewToken(n notary.Notary, p packer.Packer, secretKey []byte, id string, expiresAt time.Time) (string, error) {
+	timestamp := make([]byte, ExpiresAtLength)
+	copy(timestamp, []byte(strconv.FormatInt(expiresAt.Unix(), 10)))
+	message := append([]byte(id), timestamp...)
+
+	pack, err := p.Pack(message, n.Sign(message, secretKey))
+	if err != nil {
+		return "", errors.Wrap(err, "Failed to pack message with reset and it's signature")
+	}
+
+	return string(pack), nil
+}
+
+// ParseToken unpacks the token and verifies its signature.
+// Returns the reset ID, or false if the token is invalid.
+func ParseToken(token string, n notary.Notary, p packer.Packer, secretKey []byte) (id string, ok bool) {
+	message, signature, err := p.Unpack([]byte(token))
+	if err != nil || len(message) != IDLength+ExpiresAtLength {
+		return "", false
+	}
+
+	if !n.Verify(message, signature, secretKey) {
+		return "", false
+	}
+
+	return string(message[:IDLength]), true
+}
+
+// Matches reports whether the token is the token of the reset.
+func (r Reset) Matches(token string) bool {
+	return subtle.ConstantTimeCompare(r.Hash, hashToken(token)) == 1
+}
+
+// IsExpired reports whether the reset is expired at the moment t.
+func (r Reset) IsExpired(t time.Time) bool {
+	return !t.Before(r.ExpiresAt)
+}
+
+// hashToken returns the hash of a reset token to keep.
+// The token is long and random, so a fast hash is enough.
+func hashToken(token string) []byte {
+	sum := sha256.Sum256([]byte(token))
+	return sum[:]
+}
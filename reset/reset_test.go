@@ -0,0 +1,88 @@
+package reset
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/pascont/notary"
+	"github.com/hypnoglow/pascont/packer"
+)
+
+func TestNew(t *testing.T) {
+	now := time.Now()
+	n := notary.NewHMACNotary()
+	p := packer.NewBase64Packer(IDLength + ExpiresAtLength)
+	key := []byte("secret_key")
+	uuidProducer := func() string {
+		return "12345678-90ab-cdef-0123-4567890abcde"
+	}
+
+	r, token, err := New(uuidProducer, n, p, key, 123, now)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+
+	if r.ID != "12345678-90ab-cdef-0123-4567890abcde" || r.AccountID != 123 {
+		t.Errorf("Unexpected reset %#v\n", r)
+	}
+	if !r.ExpiresAt.Equal(now.UTC().Truncate(time.Second).Add(Duration)) {
+		t.Errorf("Expected reset to expire in %s, but got %s\n", Duration, r.ExpiresAt)
+	}
+	if strings.Contains(string(r.Hash), token) || !r.Matches(token) {
+		t.Errorf("Expected reset to keep only the token hash\n")
+	}
+	if r.Matches(token + "x") {
+		t.Errorf("Expected reset not to match other token\n")
+	}
+
+	id, ok := ParseToken(token, n, p, key)
+	if !ok || id != r.ID {
+		t.Errorf("Expected token to be parsed as %s, but got %s, %v\n", r.ID, id, ok)
+	}
+
+	cases := []struct {
+		caseName string
+		token    string
+		key      []byte
+	}{
+		{
+			caseName: "Other key",
+			token:    token,
+			key:      []byte("other_key"),
+		},
+		{
+			caseName: "Tampered token",
+			token:    "A" + token[1:],
+			key:      key,
+		},
+		{
+			caseName: "Short token",
+			token:    "SGVsbG8=",
+			key:      key,
+		},
+		{
+			caseName: "Not a token",
+			token:    "not a token",
+			key:      key,
+		},
+	}
+
+	for i, c := range cases {
+		if _, ok := ParseToken(c.token, n, p, c.key); ok {
+			t.Errorf("testcase %d %s: Expected token to be invalid\n", i, c.caseName)
+		}
+	}
+}
+
+func TestReset_IsExpired(t *testing.T) {
+	now := time.Now()
+	r := Reset{ExpiresAt: now}
+
+	if r.IsExpired(now.Add(-time.Second)) {
+		t.Errorf("Expected reset not to be expired before ExpiresAt\n")
+	}
+	if !r.IsExpired(now) {
+		t.Errorf("Expected reset to be expired at ExpiresAt\n")
+	}
+}
@@ -0,0 +1,89 @@
+package reset
+
+import (
+	"time"
+
+	"github.com/hypnoglow/pascont/account"
+	"github.com/hypnoglow/pascont/clock"
+	"github.com/hypnoglow/pascont/identity"
+	"github.com/hypnoglow/pascont/notary"
+	"github.com/hypnoglow/pascont/notifier"
+	"github.com/hypnoglow/pascont/outbox"
+	"github.com/hypnoglow/pascont/packer"
+)
+
+// Sender sends password reset tokens to account holders.
+type Sender interface {
+	// Send issues a new reset for the account and sends its token to the account holder.
+	Send(acc account.Account) error
+
+	// SendWithPasswordChange issues a new reset for the account of the change
+	// and sends its token to the account holder, along with the password change,
+	// so either the change is applied and the token is sent, or neither.
+	SendWithPasswordChange(change account.PasswordChange) error
+}
+
+// outboxSender is a Sender which delivers tokens through the outbox.
+type outboxSender struct {
+	repo         Repository
+	uuidProducer identity.UUIDProducer
+	notary       notary.Notary
+	packer       packer.Packer
+	secretKey    []byte
+	router       outbox.Router
+	clock        clock.Clock
+}
+
+// NewOutboxSender returns a new Sender which saves resets to the repository along with
+// KindPasswordReset notifications for the channels of the router.
+// Notifications refer to the reset instead of carrying its token, which is signed
+// with the secret key again on delivery by a TokenNotifier.
+func NewOutboxSender(
+	repo Repository,
+	u identity.UUIDProducer,
+	n notary.Notary,
+	p packer.Packer,
+	secretKey []byte,
+	router outbox.Router,
+	clk clock.Clock,
+) Sender {
+	return outboxSender{repo, u, n, p, secretKey, router, clk}
+}
+
+func (s outboxSender) Send(acc account.Account) error {
+	r, messages, err := s.issue(acc)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.Save(*r, messages...)
+}
+
+func (s outboxSender) SendWithPasswordChange(change account.PasswordChange) error {
+	r, messages, err := s.issue(change.Account)
+	if err != nil {
+		return err
+	}
+
+	change.Messages = append(append([]outbox.Message{}, change.Messages...), messages...)
+	return s.repo.Issue(*r, change)
+}
+
+// issue returns a new reset for the account along with the outbox messages sending its token.
+func (s outboxSender) issue(acc account.Account) (*Reset, []outbox.Message, error) {
+	r, _, err := New(s.uuidProducer, s.notary, s.packer, s.secretKey, acc.ID, s.clock())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return r, s.router.Messages(notifier.Notification{
+		Kind:      notifier.KindPasswordReset,
+		TenantID:  acc.TenantID,
+		AccountID: acc.ID,
+		Recipient: acc.Name,
+		Params: map[string]string{
+			"resetID":   r.ID,
+			"expiresAt": r.ExpiresAt.Format(time.RFC3339),
+		},
+	}), nil
+}
@@ -0,0 +1,133 @@
+package reset
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/pascont/account"
+	"github.com/hypnoglow/pascont/clock"
+	"github.com/hypnoglow/pascont/notary"
+	"github.com/hypnoglow/pascont/notifier"
+	"github.com/hypnoglow/pascont/outbox"
+	"github.com/hypnoglow/pascont/packer"
+)
+
+// recordingRepository is a Repository which keeps saved resets with their messages.
+type recordingRepository struct {
+	Repository
+
+	resets   []Reset
+	messages []outbox.Message
+	changes  []account.PasswordChange
+}
+
+func (r *recordingRepository) Save(rs Reset, messages ...outbox.Message) error {
+	r.resets = append(r.resets, rs)
+	r.messages = append(r.messages, messages...)
+	return nil
+}
+
+func (r *recordingRepository) Issue(rs Reset, change account.PasswordChange) error {
+	r.resets = append(r.resets, rs)
+	r.changes = append(r.changes, change)
+	return nil
+}
+
+func TestOutboxSender_Send(t *testing.T) {
+	now := time.Date(2017, 7, 1, 21, 10, 29, 0, time.UTC)
+	n := notary.NewHMACNotary()
+	p := packer.NewBase64Packer(IDLength + ExpiresAtLength)
+	key := []byte("secret_key")
+	uuidProducer := func() string {
+		return "12345678-90ab-cdef-0123-4567890abcde"
+	}
+
+	repo := &recordingRepository{}
+	router := outbox.NewRouter(nil, []string{"smtp"}, uuidProducer, clock.Fixed(now))
+	s := NewOutboxSender(repo, uuidProducer, n, p, key, router, clock.Fixed(now))
+
+	acc := account.Account{ID: 123, TenantID: 1, Name: "email@email.com"}
+	if err := s.Send(acc); err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+
+	if len(repo.resets) != 1 || len(repo.messages) != 1 {
+		t.Fatalf("Expected a reset to be saved along with a message, but got %#v and %#v", repo.resets, repo.messages)
+	}
+
+	sent := repo.messages[0].Notification
+	if sent.Kind != notifier.KindPasswordReset || sent.TenantID != 1 || sent.AccountID != 123 || sent.Recipient != "email@email.com" {
+		t.Errorf("Unexpected notification %#v\n", sent)
+	}
+	expiresAt := now.Add(Duration).Format(time.RFC3339)
+	if sent.Params["expiresAt"] != expiresAt {
+		t.Errorf("Expected token to expire at %s, but got %s\n", expiresAt, sent.Params["expiresAt"])
+	}
+	if sent.Params["resetID"] != repo.resets[0].ID {
+		t.Errorf("Expected notification to refer to reset %s, but got %s\n", repo.resets[0].ID, sent.Params["resetID"])
+	}
+	if _, ok := sent.Params["token"]; ok {
+		t.Errorf("Expected token not to be kept in the outbox, but got %s\n", sent.Params["token"])
+	}
+}
+
+func TestOutboxSender_SendWithPasswordChange(t *testing.T) {
+	now := time.Date(2017, 7, 1, 21, 10, 29, 0, time.UTC)
+	uuidProducer := func() string {
+		return "12345678-90ab-cdef-0123-4567890abcde"
+	}
+
+	repo := &recordingRepository{}
+	router := outbox.NewRouter(nil, []string{"smtp"}, uuidProducer, clock.Fixed(now))
+	s := NewOutboxSender(
+		repo,
+		uuidProducer,
+		notary.NewHMACNotary(),
+		packer.NewBase64Packer(IDLength+ExpiresAtLength),
+		[]byte("secret_key"),
+		router,
+		clock.Fixed(now),
+	)
+
+	acc := account.Account{ID: 123, TenantID: 1, Name: "email@email.com"}
+	event := outbox.Message{ID: "event", Channel: "webhook"}
+	err := s.SendWithPasswordChange(account.PasswordChange{
+		Account:      acc,
+		PasswordHash: []byte{},
+		Messages:     []outbox.Message{event},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+
+	if len(repo.resets) != 1 || len(repo.changes) != 1 {
+		t.Fatalf("Expected a reset to be issued along with the change, but got %#v and %#v", repo.resets, repo.changes)
+	}
+
+	messages := repo.changes[0].Messages
+	if len(messages) != 2 || messages[0].ID != "event" {
+		t.Fatalf("Expected the change messages to be followed by the token message, but got %#v", messages)
+	}
+	sent := messages[1].Notification
+	if sent.Kind != notifier.KindPasswordReset || sent.AccountID != 123 || sent.Params["resetID"] != repo.resets[0].ID {
+		t.Errorf("Unexpected notification %#v\n", sent)
+	}
+}
+
+func TestOutboxSender_Send_SaveError(t *testing.T) {
+	repo := NewFakeRepository([]FakeRepositorySaveResult{{Error: fmt.Errorf("Save failed")}}, nil, nil, nil)
+	s := NewOutboxSender(
+		repo,
+		func() string { return "12345678-90ab-cdef-0123-4567890abcde" },
+		notary.NewHMACNotary(),
+		packer.NewBase64Packer(IDLength+ExpiresAtLength),
+		[]byte("secret_key"),
+		outbox.Router{},
+		clock.Fixed(time.Now()),
+	)
+
+	if err := s.Send(account.Account{ID: 123, TenantID: 1, Name: "email@email.com"}); err == nil {
+		t.Errorf("Expected an error, but got nil\n")
+	}
+}
@@ -0,0 +1,77 @@
+package resets
+
+import (
+	"log"
+
+	"github.com/hypnoglow/pascont/account"
+	"github.com/hypnoglow/pascont/clock"
+	"github.com/hypnoglow/pascont/hasher"
+	"github.com/hypnoglow/pascont/notary"
+	"github.com/hypnoglow/pascont/outbox"
+	"github.com/hypnoglow/pascont/packer"
+	"github.com/hypnoglow/pascont/password"
+	"github.com/hypnoglow/pascont/reset"
+	"github.com/hypnoglow/pascont/webhook"
+)
+
+const (
+	PathPasswordResets = "/password-resets"
+	PathPasswordReset  = "/password-resets/"
+
+	PathPasswordResetByToken = "/password-resets/:token"
+)
+
+// RestController is a REST controller for password resets.
+type RestController struct {
+	logger      *log.Logger
+	accountRepo account.Repository
+	resetRepo   reset.Repository
+	notary      notary.Notary
+	packer      packer.Packer
+	hasher      hasher.Hasher
+	sender      reset.Sender
+	clock       clock.Clock
+	options     Options
+}
+
+// Options is a structure holding resets RestController specific options.
+type Options struct {
+	// SecretKey is a key to sign reset tokens.
+	SecretKey []byte
+
+	// PasswordPolicy is a policy for new account passwords.
+	PasswordPolicy password.Policy
+
+	// Outbox routes password change alerts, which are saved along with the account.
+	// The zero Router sends no alerts.
+	Outbox outbox.Router
+
+	// Events emits password change events to webhook subscriptions.
+	// If nil, no events are emitted.
+	Events webhook.Emitter
+}
+
+// NewRestController returns a new RestController.
+func NewRestController(
+	logger *log.Logger,
+	accountRepo account.Repository,
+	resetRepo reset.Repository,
+	n notary.Notary,
+	p packer.Packer,
+	h hasher.Hasher,
+	s reset.Sender,
+	clk clock.Clock,
+	opts Options,
+) RestController {
+	return RestController{
+		logger,
+		accountRepo,
+		resetRepo,
+		n,
+		p,
+		h,
+		s,
+		clk,
+		opts,
+	}
+}
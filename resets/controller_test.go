@@ -0,0 +1,54 @@
+package resets
+
+import (
+	"io/ioutil"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/pascont/account"
+	"github.com/hypnoglow/pascont/notary"
+	"github.com/hypnoglow/pascont/packer"
+	"github.com/hypnoglow/pascont/reset"
+)
+
+func TestNewRestController(t *testing.T) {
+	NewRestController(
+		log.New(ioutil.Discard, "", log.LstdFlags),
+		account.NewFakeRepository(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil),
+		reset.NewFakeRepository(nil, nil, nil, nil),
+		notary.NewHMACNotary(),
+		packer.NewBase64Packer(reset.IDLength+reset.ExpiresAtLength),
+		nil,
+		nil,
+		time.Now,
+		Options{SecretKey: testSecretKey},
+	)
+}
+
+// testSecretKey is a key to sign reset tokens in tests.
+var testSecretKey = []byte("0123456789abcdef")
+
+// testResetID is an ID of resets issued in tests.
+const testResetID = "12345678-90ab-cdef-0123-4567890abcde"
+
+func testUUIDProducer() string {
+	return testResetID
+}
+
+// newTestReset returns a reset issued in tests at the moment, along with its token.
+func newTestReset(t *testing.T, accountID int64, createdAt time.Time) (*reset.Reset, string) {
+	r, token, err := reset.New(
+		testUUIDProducer,
+		notary.NewHMACNotary(),
+		packer.NewBase64Packer(reset.IDLength+reset.ExpiresAtLength),
+		testSecretKey,
+		accountID,
+		createdAt,
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+
+	return r, token
+}
@@ -0,0 +1,68 @@
+package resets
+
+import (
+	"net/http"
+
+	"github.com/hypnoglow/pascont/account"
+	"github.com/hypnoglow/pascont/kit"
+	"github.com/hypnoglow/pascont/kit/form"
+	"github.com/hypnoglow/pascont/kit/middleware"
+)
+
+// PostPasswordResets is a handler for:
+// POST /password-resets
+//
+// It issues a reset token and sends it to the account holder.
+// The response is the same whether the account exists or not,
+// so accounts can not be enumerated.
+func (c RestController) PostPasswordResets(w http.ResponseWriter, req *http.Request) {
+	tenantID, ok := req.Context().Value(middleware.ContextKeyTenantID{}).(int64)
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	var resetForm postPasswordResetsForm
+	form.PopulateFormFromJSON(req.Body, &resetForm)
+	if !resetForm.Validate() {
+		kit.RespondWithFormErrors(w, http.StatusBadRequest, resetForm.ValidationErrors())
+		return
+	}
+
+	acc, _, err := c.accountRepo.FindWithPasswordHashByUsername(tenantID, resetForm.Name)
+	if err != nil {
+		if err == account.ErrNotFound {
+			w.WriteHeader(http.StatusAccepted)
+		} else {
+			c.logger.Println(err)
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		return
+	}
+
+	// Passwords of accounts which can not be used are not reset.
+	if !acc.IsActive() {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	// Failures are not revealed, as they would reveal the account.
+	if err := c.sender.Send(*acc); err != nil {
+		c.logger.Println(err)
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+type postPasswordResetsForm struct {
+	form.BaseForm
+	Name string `json:"name"`
+}
+
+func (f *postPasswordResetsForm) Validate() bool {
+	if len(f.Name) == 0 {
+		f.AddError("Name must not be empty", "name", f.Name)
+	}
+
+	return len(f.ValidationErrors()) == 0
+}
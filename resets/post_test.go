@@ -0,0 +1,291 @@
+package resets
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/pascont/account"
+	"github.com/hypnoglow/pascont/clock"
+	"github.com/hypnoglow/pascont/kit/middleware"
+	"github.com/hypnoglow/pascont/notary"
+	"github.com/hypnoglow/pascont/packer"
+	"github.com/hypnoglow/pascont/reset"
+)
+
+func TestRestController_PostPasswordResets(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	fakeLogger := log.New(ioutil.Discard, "", log.LstdFlags)
+
+	acc := &account.Account{
+		ID:        123,
+		TenantID:  1,
+		Name:      "email@email.com",
+		Status:    account.StatusActive,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	disabledAcc := &account.Account{
+		ID:        123,
+		TenantID:  1,
+		Name:      "email@email.com",
+		Status:    account.StatusDisabled,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	validBody := `{"name":"email@email.com"}`
+
+	cases := []struct {
+		caseName string
+		// in
+		accRepo     account.Repository
+		sender      reset.Sender
+		reqTenantID int64
+		reqBody     io.Reader
+		// out
+		expectedCode int
+		expectedBody *bytes.Buffer
+	}{
+		{
+			caseName: "Request without a tenant should result in 404",
+			reqBody:  bytes.NewBufferString(validBody),
+			// out
+			expectedCode: http.StatusNotFound,
+			expectedBody: bytes.NewBuffer(nil),
+		},
+		{
+			caseName:    "Empty name should result in 400",
+			reqTenantID: 1,
+			reqBody:     bytes.NewBufferString(`{"name":""}`),
+			// out
+			expectedCode: http.StatusBadRequest,
+			expectedBody: bytes.NewBufferString(`{
+				"errors":[
+					{
+						"message":"Name must not be empty",
+						"field":"name",
+						"value":""
+					}
+				]
+			}`),
+		},
+		{
+			caseName: "Account not found should result in 202",
+			accRepo: account.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				[]account.FakeRepositoryFindWithPasswordHashByUsernameResult{
+					{
+						Error: account.ErrNotFound,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			reqTenantID: 1,
+			reqBody:     bytes.NewBufferString(validBody),
+			// out
+			expectedCode: http.StatusAccepted,
+			expectedBody: bytes.NewBuffer(nil),
+		},
+		{
+			caseName: "Error on FindWithPasswordHashByUsername should result in 500",
+			accRepo: account.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				[]account.FakeRepositoryFindWithPasswordHashByUsernameResult{
+					{
+						Error: fmt.Errorf("FindWithPasswordHashByUsername failed"),
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			reqTenantID: 1,
+			reqBody:     bytes.NewBufferString(validBody),
+			// out
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: bytes.NewBuffer(nil),
+		},
+		{
+			caseName: "Account which is not active should result in 202",
+			accRepo: account.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				[]account.FakeRepositoryFindWithPasswordHashByUsernameResult{
+					{
+						Account:      disabledAcc,
+						PasswordHash: []byte("password_hash"),
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			reqTenantID: 1,
+			reqBody:     bytes.NewBufferString(validBody),
+			// out
+			expectedCode: http.StatusAccepted,
+			expectedBody: bytes.NewBuffer(nil),
+		},
+		{
+			caseName: "Error on sender.Send should result in 202",
+			accRepo: account.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				[]account.FakeRepositoryFindWithPasswordHashByUsernameResult{
+					{
+						Account:      acc,
+						PasswordHash: []byte("password_hash"),
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			sender: reset.NewFakeSender(
+				[]reset.FakeSenderSendResult{
+					{
+						Error: fmt.Errorf("Send failed"),
+					},
+				},
+				nil,
+			),
+			reqTenantID: 1,
+			reqBody:     bytes.NewBufferString(validBody),
+			// out
+			expectedCode: http.StatusAccepted,
+			expectedBody: bytes.NewBuffer(nil),
+		},
+		{
+			caseName: "Successful",
+			accRepo: account.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				[]account.FakeRepositoryFindWithPasswordHashByUsernameResult{
+					{
+						Account:      acc,
+						PasswordHash: []byte("password_hash"),
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			sender: reset.NewFakeSender(
+				[]reset.FakeSenderSendResult{
+					{
+						Error: nil,
+					},
+				},
+				nil,
+			),
+			reqTenantID: 1,
+			reqBody:     bytes.NewBufferString(validBody),
+			// out
+			expectedCode: http.StatusAccepted,
+			expectedBody: bytes.NewBuffer(nil),
+		},
+	}
+
+	for i, c := range cases {
+		ctrl := NewRestController(
+			fakeLogger,
+			c.accRepo,
+			nil,
+			notary.NewHMACNotary(),
+			packer.NewBase64Packer(reset.IDLength+reset.ExpiresAtLength),
+			nil,
+			c.sender,
+			clock.Fixed(now),
+			Options{SecretKey: testSecretKey},
+		)
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, PathPasswordResets, c.reqBody)
+		if c.reqTenantID != 0 {
+			req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyTenantID{}, c.reqTenantID))
+		}
+		ctrl.PostPasswordResets(w, req)
+
+		if w.Code != c.expectedCode {
+			t.Errorf(
+				"testcase %d %s:\nExpected status code to be %v, but got %v\n",
+				i,
+				c.caseName,
+				c.expectedCode,
+				w.Code,
+			)
+		}
+
+		// To make possible pretty formatting in test cases, we use json.Compact:
+		b := c.expectedBody.Bytes()
+		if b != nil {
+			c.expectedBody.Reset()
+			err := json.Compact(c.expectedBody, b)
+			if err != nil {
+				t.Errorf(
+					"testcase %d %s:\nFailed to compact JSON body: %s",
+					i,
+					c.caseName,
+					err,
+				)
+			}
+		}
+
+		if !bytes.Equal(w.Body.Bytes(), c.expectedBody.Bytes()) {
+			t.Errorf(
+				"testcase %d %s:\nExpected body to be\n%v\nbut got\n%v\n",
+				i,
+				c.caseName,
+				c.expectedBody,
+				w.Body,
+			)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package resets
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/hypnoglow/pascont/account"
+	"github.com/hypnoglow/pascont/accounts"
+	"github.com/hypnoglow/pascont/kit"
+	"github.com/hypnoglow/pascont/kit/form"
+	"github.com/hypnoglow/pascont/kit/middleware"
+	"github.com/hypnoglow/pascont/kit/schema"
+	"github.com/hypnoglow/pascont/password"
+	"github.com/hypnoglow/pascont/reset"
+)
+
+// PostPasswordReset is a handler for:
+// POST /password-resets/:token
+//
+// It sets a new password of the account, completing the reset,
+// and revokes all sessions of the account.
+func (c RestController) PostPasswordReset(w http.ResponseWriter, req *http.Request) {
+	tenantID, ok := req.Context().Value(middleware.ContextKeyTenantID{}).(int64)
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	token, _ := middleware.PathParam(req, "token")
+	id, ok := reset.ParseToken(token, c.notary, c.packer, c.options.SecretKey)
+	if !ok {
+		respondInvalidToken(w)
+		return
+	}
+
+	now := c.clock()
+	r, err := c.resetRepo.FindByID(id)
+	if err != nil {
+		if err == reset.ErrNotFound {
+			respondInvalidToken(w)
+		} else {
+			c.logger.Println(err)
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		return
+	}
+	if !r.Matches(token) || r.IsExpired(now) {
+		respondInvalidToken(w)
+		return
+	}
+
+	acc, err := c.accountRepo.FindByID(r.AccountID)
+	if err != nil {
+		if err == account.ErrNotFound {
+			respondInvalidToken(w)
+		} else {
+			c.logger.Println(err)
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		return
+	}
+	if acc.TenantID != tenantID {
+		respondInvalidToken(w)
+		return
+	}
+
+	if !acc.IsActive() {
+		kit.RespondWithError(w, http.StatusForbidden, schema.NewError(
+			fmt.Sprintf("Account is %s", acc.Status),
+			"",
+			nil,
+		))
+		return
+	}
+
+	passwordForm := postPasswordResetForm{policy: c.options.PasswordPolicy, name: acc.Name}
+	form.PopulateFormFromJSON(req.Body, &passwordForm)
+	if !passwordForm.Validate() {
+		if passwordForm.policyErr != nil {
+			c.logger.Println(passwordForm.policyErr)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		kit.RespondWithFormErrors(w, http.StatusBadRequest, passwordForm.ValidationErrors())
+		return
+	}
+
+	passwordHash, err := c.hasher.GenerateHashFromPassword([]byte(passwordForm.Password))
+	if err != nil {
+		c.logger.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	// The reset is completed only once. Completing it resets the lockout too,
+	// as the account holder has proven the access to the account.
+	acc.UpdatedAt = now.UTC().Truncate(time.Second)
+	completed, err := c.resetRepo.Complete(r.ID, account.PasswordChange{
+		Account:      *acc,
+		PasswordHash: passwordHash,
+		Messages: append(
+			c.options.Outbox.Messages(accounts.PasswordChangedNotification(*acc)),
+			accounts.PasswordChangedEvents(c.options.Events, c.logger, *acc)...,
+		),
+	})
+	if err != nil {
+		c.logger.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if !completed {
+		respondInvalidToken(w)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// respondInvalidToken responds the same for any invalid, expired or used token.
+func respondInvalidToken(w http.ResponseWriter) {
+	kit.RespondWithError(w, http.StatusBadRequest, schema.NewError(
+		"Password reset token is invalid or expired",
+		"token",
+		nil,
+	))
+}
+
+type postPasswordResetForm struct {
+	form.BaseForm
+	Password string `json:"password"`
+
+	policy    password.Policy
+	policyErr error
+	name      string
+}
+
+func (f *postPasswordResetForm) Validate() bool {
+	f.policyErr = f.policy.Validate(f, "password", f.name, f.Password)
+
+	return f.policyErr == nil && len(f.ValidationErrors()) == 0
+}
@@ -0,0 +1,351 @@
+package resets
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/pascont/account"
+	"github.com/hypnoglow/pascont/clock"
+	"github.com/hypnoglow/pascont/hasher"
+	"github.com/hypnoglow/pascont/kit/middleware"
+	"github.com/hypnoglow/pascont/notary"
+	"github.com/hypnoglow/pascont/packer"
+	"github.com/hypnoglow/pascont/password"
+	"github.com/hypnoglow/pascont/reset"
+)
+
+func TestRestController_PostPasswordReset(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	fakeLogger := log.New(ioutil.Discard, "", log.LstdFlags)
+
+	acc := &account.Account{
+		ID:        123,
+		TenantID:  1,
+		Name:      "email@email.com",
+		Status:    account.StatusActive,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	disabledAcc := &account.Account{
+		ID:        123,
+		TenantID:  1,
+		Name:      "email@email.com",
+		Status:    account.StatusDisabled,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	r, token := newTestReset(t, acc.ID, now.Add(-time.Minute))
+	expired, expiredToken := newTestReset(t, acc.ID, now.Add(-reset.Duration))
+	otherHash := *r
+	otherHash.Hash = []byte("other_hash")
+
+	validBody := `{"password":"new_password"}`
+	invalidTokenBody := `{
+		"errors":[
+			{
+				"message":"Password reset token is invalid or expired",
+				"field":"token"
+			}
+		]
+	}`
+
+	// foundReset returns a fake reset.Repository which finds the reset.
+	foundReset := func(r *reset.Reset, complete []reset.FakeRepositoryCompleteResult) reset.Repository {
+		return reset.NewFakeRepository(
+			nil,
+			[]reset.FakeRepositoryFindByIDResult{
+				{
+					Reset: r,
+				},
+			},
+			complete,
+			nil,
+		)
+	}
+	// foundAccount returns a fake account.Repository which finds the account.
+	foundAccount := func(acc *account.Account) account.Repository {
+		return account.NewFakeRepository(
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			[]account.FakeRepositoryFindByIDResult{
+				{
+					Account: acc,
+				},
+			},
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+		)
+	}
+	passwordHasher := func() hasher.Hasher {
+		return hasher.NewFakeHasher(
+			[]hasher.FakeGenerateHashFromPasswordResult{
+				{
+					Hash: []byte("new_password_hash"),
+				},
+			},
+			nil,
+		)
+	}
+
+	cases := []struct {
+		caseName string
+		// in
+		accRepo     account.Repository
+		resetRepo   reset.Repository
+		hasher      hasher.Hasher
+		reqTenantID int64
+		reqToken    string
+		reqBody     io.Reader
+		// out
+		expectedCode int
+		expectedBody *bytes.Buffer
+	}{
+		{
+			caseName: "Request without a tenant should result in 404",
+			reqToken: token,
+			reqBody:  bytes.NewBufferString(validBody),
+			// out
+			expectedCode: http.StatusNotFound,
+			expectedBody: bytes.NewBuffer(nil),
+		},
+		{
+			caseName:    "Token with invalid signature should result in 400",
+			reqTenantID: 1,
+			reqToken:    "A" + token[1:],
+			reqBody:     bytes.NewBufferString(validBody),
+			// out
+			expectedCode: http.StatusBadRequest,
+			expectedBody: bytes.NewBufferString(invalidTokenBody),
+		},
+		{
+			caseName: "Reset not found should result in 400",
+			resetRepo: reset.NewFakeRepository(
+				nil,
+				[]reset.FakeRepositoryFindByIDResult{
+					{
+						Error: reset.ErrNotFound,
+					},
+				},
+				nil,
+				nil,
+			),
+			reqTenantID: 1,
+			reqToken:    token,
+			reqBody:     bytes.NewBufferString(validBody),
+			// out
+			expectedCode: http.StatusBadRequest,
+			expectedBody: bytes.NewBufferString(invalidTokenBody),
+		},
+		{
+			caseName: "Error on resetRepo.FindByID should result in 500",
+			resetRepo: reset.NewFakeRepository(
+				nil,
+				[]reset.FakeRepositoryFindByIDResult{
+					{
+						Error: fmt.Errorf("FindByID failed"),
+					},
+				},
+				nil,
+				nil,
+			),
+			reqTenantID: 1,
+			reqToken:    token,
+			reqBody:     bytes.NewBufferString(validBody),
+			// out
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: bytes.NewBuffer(nil),
+		},
+		{
+			caseName:    "Token not matching the reset hash should result in 400",
+			resetRepo:   foundReset(&otherHash, nil),
+			reqTenantID: 1,
+			reqToken:    token,
+			reqBody:     bytes.NewBufferString(validBody),
+			// out
+			expectedCode: http.StatusBadRequest,
+			expectedBody: bytes.NewBufferString(invalidTokenBody),
+		},
+		{
+			caseName:    "Expired reset should result in 400",
+			resetRepo:   foundReset(expired, nil),
+			reqTenantID: 1,
+			reqToken:    expiredToken,
+			reqBody:     bytes.NewBufferString(validBody),
+			// out
+			expectedCode: http.StatusBadRequest,
+			expectedBody: bytes.NewBufferString(invalidTokenBody),
+		},
+		{
+			caseName:    "Reset of an account in other tenant should result in 400",
+			accRepo:     foundAccount(acc),
+			resetRepo:   foundReset(r, nil),
+			reqTenantID: 2,
+			reqToken:    token,
+			reqBody:     bytes.NewBufferString(validBody),
+			// out
+			expectedCode: http.StatusBadRequest,
+			expectedBody: bytes.NewBufferString(invalidTokenBody),
+		},
+		{
+			caseName:    "Account which is not active should result in 403",
+			accRepo:     foundAccount(disabledAcc),
+			resetRepo:   foundReset(r, nil),
+			reqTenantID: 1,
+			reqToken:    token,
+			reqBody:     bytes.NewBufferString(validBody),
+			// out
+			expectedCode: http.StatusForbidden,
+			expectedBody: bytes.NewBufferString(`{
+				"errors":[
+					{
+						"message":"Account is disabled"
+					}
+				]
+			}`),
+		},
+		{
+			caseName:    "Password violating the policy should result in 400",
+			accRepo:     foundAccount(acc),
+			resetRepo:   foundReset(r, nil),
+			reqTenantID: 1,
+			reqToken:    token,
+			reqBody:     bytes.NewBufferString(`{"password":"short"}`),
+			// out
+			expectedCode: http.StatusBadRequest,
+			expectedBody: bytes.NewBufferString(`{
+				"errors":[
+					{
+						"message":"Password must be at least 8 characters",
+						"field":"password",
+						"code":"password_too_short"
+					}
+				]
+			}`),
+		},
+		{
+			caseName: "Reset used concurrently should result in 400",
+			accRepo:  foundAccount(acc),
+			resetRepo: foundReset(r, []reset.FakeRepositoryCompleteResult{
+				{
+					Completed: false,
+				},
+			}),
+			hasher:      passwordHasher(),
+			reqTenantID: 1,
+			reqToken:    token,
+			reqBody:     bytes.NewBufferString(validBody),
+			// out
+			expectedCode: http.StatusBadRequest,
+			expectedBody: bytes.NewBufferString(invalidTokenBody),
+		},
+		{
+			caseName: "Error on resetRepo.Complete should result in 500",
+			accRepo:  foundAccount(acc),
+			resetRepo: foundReset(r, []reset.FakeRepositoryCompleteResult{
+				{
+					Error: fmt.Errorf("Complete failed"),
+				},
+			}),
+			hasher:      passwordHasher(),
+			reqTenantID: 1,
+			reqToken:    token,
+			reqBody:     bytes.NewBufferString(validBody),
+			// out
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: bytes.NewBuffer(nil),
+		},
+		{
+			caseName: "Successful",
+			accRepo:  foundAccount(acc),
+			resetRepo: foundReset(r, []reset.FakeRepositoryCompleteResult{
+				{
+					Completed: true,
+				},
+			}),
+			hasher:      passwordHasher(),
+			reqTenantID: 1,
+			reqToken:    token,
+			reqBody:     bytes.NewBufferString(validBody),
+			// out
+			expectedCode: http.StatusNoContent,
+			expectedBody: bytes.NewBuffer(nil),
+		},
+	}
+
+	for i, c := range cases {
+		ctrl := NewRestController(
+			fakeLogger,
+			c.accRepo,
+			c.resetRepo,
+			notary.NewHMACNotary(),
+			packer.NewBase64Packer(reset.IDLength+reset.ExpiresAtLength),
+			c.hasher,
+			nil,
+			clock.Fixed(now),
+			Options{
+				SecretKey:      testSecretKey,
+				PasswordPolicy: password.DefaultPolicy,
+			},
+		)
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, PathPasswordReset+c.reqToken, c.reqBody)
+		req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyPathParams{}, map[string]string{"token": c.reqToken}))
+		if c.reqTenantID != 0 {
+			req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyTenantID{}, c.reqTenantID))
+		}
+		ctrl.PostPasswordReset(w, req)
+
+		if w.Code != c.expectedCode {
+			t.Errorf(
+				"testcase %d %s:\nExpected status code to be %v, but got %v\n",
+				i,
+				c.caseName,
+				c.expectedCode,
+				w.Code,
+			)
+		}
+
+		// To make possible pretty formatting in test cases, we use json.Compact:
+		b := c.expectedBody.Bytes()
+		if b != nil {
+			c.expectedBody.Reset()
+			err := json.Compact(c.expectedBody, b)
+			if err != nil {
+				t.Errorf(
+					"testcase %d %s:\nFailed to compact JSON body: %s",
+					i,
+					c.caseName,
+					err,
+				)
+			}
+		}
+
+		if !bytes.Equal(w.Body.Bytes(), c.expectedBody.Bytes()) {
+			t.Errorf(
+				"testcase %d %s:\nExpected body to be\n%v\nbut got\n%v\n",
+				i,
+				c.caseName,
+				c.expectedBody,
+				w.Body,
+			)
+		}
+	}
+}
@@ -1,4 +1,4 @@
--- psql -h 127.0.0.1 -p 5432 -U postgres -f resources/migrations/init.sql
+-- psql -h 127.0.0.1 -p 5432 -U postgres -f resources/migrations/001_init.sql
 
 CREATE DATABASE pascont;
 
@@ -0,0 +1,13 @@
+-- psql -h 127.0.0.1 -p 5432 -U postgres -d pascont -f resources/migrations/002_session_client.sql
+
+ALTER TABLE session
+  ADD COLUMN ip           VARCHAR(45)              NOT NULL DEFAULT '',
+  ADD COLUMN user_agent   VARCHAR(512)             NOT NULL DEFAULT '',
+  ADD COLUMN last_seen_at TIMESTAMP WITH TIME ZONE NULL;
+
+UPDATE session SET last_seen_at = created_at;
+
+ALTER TABLE session
+  ALTER COLUMN last_seen_at SET NOT NULL;
+
+CREATE INDEX session_account_id_expires_at_idx ON session (account_id, expires_at);
@@ -0,0 +1,5 @@
+-- psql -h 127.0.0.1 -p 5432 -U postgres -d pascont -f resources/migrations/003_account_lockout.sql
+
+ALTER TABLE account
+  ADD COLUMN failed_login_attempts INTEGER                  NOT NULL DEFAULT 0,
+  ADD COLUMN locked_until          TIMESTAMP WITH TIME ZONE NULL;
@@ -0,0 +1,5 @@
+-- psql -h 127.0.0.1 -p 5432 -U postgres -d pascont -f resources/migrations/004_account_status.sql
+
+ALTER TABLE account
+  ADD COLUMN status VARCHAR(16) NOT NULL DEFAULT 'active'
+    CHECK (status IN ('active', 'disabled', 'suspended', 'pending'));
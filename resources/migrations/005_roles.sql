@@ -0,0 +1,28 @@
+-- psql -h 127.0.0.1 -p 5432 -U postgres -d pascont -f resources/migrations/005_roles.sql
+
+CREATE TABLE role (
+  id   BIGSERIAL,
+  name VARCHAR(64) UNIQUE NOT NULL,
+  PRIMARY KEY (id)
+);
+
+CREATE TABLE permission (
+  name VARCHAR(64) NOT NULL,
+  PRIMARY KEY (name)
+);
+
+CREATE TABLE role_permission (
+  role_id    BIGINT      NOT NULL,
+  permission VARCHAR(64) NOT NULL,
+  PRIMARY KEY (role_id, permission),
+  FOREIGN KEY (role_id) REFERENCES role (id) ON DELETE CASCADE,
+  FOREIGN KEY (permission) REFERENCES permission (name) ON DELETE CASCADE
+);
+
+CREATE TABLE account_role (
+  account_id BIGINT NOT NULL,
+  role_id    BIGINT NOT NULL,
+  PRIMARY KEY (account_id, role_id),
+  FOREIGN KEY (account_id) REFERENCES account (id) ON DELETE CASCADE,
+  FOREIGN KEY (role_id) REFERENCES role (id) ON DELETE CASCADE
+);
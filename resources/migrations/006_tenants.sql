@@ -0,0 +1,30 @@
+-- psql -h 127.0.0.1 -p 5432 -U postgres -d pascont -f resources/migrations/006_tenants.sql
+
+CREATE TABLE tenant (
+  id         BIGSERIAL,
+  name       VARCHAR(64) UNIQUE       NOT NULL,
+  host       VARCHAR(255) UNIQUE      NULL,
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+  PRIMARY KEY (id)
+);
+
+-- Existing accounts and sessions are moved to the default tenant.
+INSERT INTO tenant (name) VALUES ('default');
+
+ALTER TABLE account
+  ADD COLUMN tenant_id BIGINT NULL REFERENCES tenant (id) ON DELETE CASCADE;
+
+UPDATE account SET tenant_id = (SELECT id FROM tenant WHERE name = 'default');
+
+ALTER TABLE account
+  ALTER COLUMN tenant_id SET NOT NULL,
+  DROP CONSTRAINT account_name_key,
+  ADD CONSTRAINT account_tenant_id_name_key UNIQUE (tenant_id, name);
+
+ALTER TABLE session
+  ADD COLUMN tenant_id BIGINT NULL REFERENCES tenant (id) ON DELETE CASCADE;
+
+UPDATE session SET tenant_id = account.tenant_id FROM account WHERE account.id = session.account_id;
+
+ALTER TABLE session
+  ALTER COLUMN tenant_id SET NOT NULL;
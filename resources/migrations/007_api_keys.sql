@@ -0,0 +1,14 @@
+-- psql -h 127.0.0.1 -p 5432 -U postgres -d pascont -f resources/migrations/007_api_keys.sql
+
+CREATE TABLE api_key (
+  id           UUID UNIQUE              NOT NULL,
+  account_id   BIGINT                   NOT NULL,
+  name         VARCHAR(64)              NOT NULL,
+  hash         BYTEA                    NOT NULL,
+  created_at   TIMESTAMP WITH TIME ZONE NOT NULL,
+  expires_at   TIMESTAMP WITH TIME ZONE NULL,
+  last_used_at TIMESTAMP WITH TIME ZONE NULL,
+  PRIMARY KEY (id),
+  UNIQUE (account_id, name),
+  FOREIGN KEY (account_id) REFERENCES account (id) ON DELETE CASCADE
+);
@@ -0,0 +1,18 @@
+-- psql -h 127.0.0.1 -p 5432 -U postgres -d pascont -f resources/migrations/008_totp.sql
+
+CREATE TABLE account_totp (
+  account_id        BIGINT                   NOT NULL,
+  secret            BYTEA                    NOT NULL,
+  confirmed_at      TIMESTAMP WITH TIME ZONE NULL,
+  last_used_counter BIGINT                   NOT NULL DEFAULT 0,
+  PRIMARY KEY (account_id),
+  FOREIGN KEY (account_id) REFERENCES account (id) ON DELETE CASCADE
+);
+
+CREATE TABLE totp_challenge (
+  id         UUID UNIQUE              NOT NULL,
+  account_id BIGINT                   NOT NULL,
+  expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+  PRIMARY KEY (id),
+  FOREIGN KEY (account_id) REFERENCES account (id) ON DELETE CASCADE
+);
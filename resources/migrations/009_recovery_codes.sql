@@ -0,0 +1,10 @@
+-- psql -h 127.0.0.1 -p 5432 -U postgres -d pascont -f resources/migrations/009_recovery_codes.sql
+
+CREATE TABLE recovery_code (
+  id         BIGSERIAL,
+  account_id BIGINT                   NOT NULL,
+  hash       BYTEA                    NOT NULL,
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+  PRIMARY KEY (id),
+  FOREIGN KEY (account_id) REFERENCES account (id) ON DELETE CASCADE
+);
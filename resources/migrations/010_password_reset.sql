@@ -0,0 +1,13 @@
+-- psql -h 127.0.0.1 -p 5432 -U postgres -d pascont -f resources/migrations/010_password_reset.sql
+
+CREATE TABLE password_reset (
+  id         UUID UNIQUE              NOT NULL,
+  account_id BIGINT                   NOT NULL,
+  hash       BYTEA                    NOT NULL,
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+  expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+  PRIMARY KEY (id),
+  FOREIGN KEY (account_id) REFERENCES account (id) ON DELETE CASCADE
+);
+
+CREATE INDEX password_reset_account_id_idx ON password_reset (account_id);
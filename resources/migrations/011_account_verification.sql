@@ -0,0 +1,7 @@
+-- psql -h 127.0.0.1 -p 5432 -U postgres -d pascont -f resources/migrations/011_account_verification.sql
+
+ALTER TABLE account
+  ADD COLUMN verified_at TIMESTAMP WITH TIME ZONE NULL;
+
+-- Accounts created before verification was introduced are considered verified.
+UPDATE account SET verified_at = created_at;
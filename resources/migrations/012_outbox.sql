@@ -0,0 +1,21 @@
+-- psql -h 127.0.0.1 -p 5432 -U postgres -d pascont -f resources/migrations/012_outbox.sql
+
+-- Payloads never hold password reset or verification tokens,
+-- these are issued from the payload params on delivery.
+CREATE TABLE outbox (
+  id              UUID                     NOT NULL PRIMARY KEY,
+  channel         VARCHAR(64)              NOT NULL,
+  payload         JSONB                    NOT NULL,
+  status          VARCHAR(16)              NOT NULL DEFAULT 'pending'
+    CHECK (status IN ('pending', 'delivered', 'dead')),
+  created_at      TIMESTAMP WITH TIME ZONE NOT NULL,
+  attempts        INTEGER                  NOT NULL DEFAULT 0,
+  next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL,
+  last_error      TEXT                     NOT NULL DEFAULT '',
+  delivered_at    TIMESTAMP WITH TIME ZONE NULL
+);
+
+CREATE INDEX outbox_pending_idx ON outbox (next_attempt_at) WHERE status = 'pending';
+
+-- Delivered messages are purged after the retention.
+CREATE INDEX outbox_delivered_idx ON outbox (delivered_at) WHERE status = 'delivered';
@@ -0,0 +1,12 @@
+-- psql -h 127.0.0.1 -p 5432 -U postgres -d pascont -f resources/migrations/013_webhooks.sql
+
+CREATE TABLE webhook_subscription (
+  id         UUID                     NOT NULL PRIMARY KEY,
+  url        TEXT                     NOT NULL,
+  events     TEXT[]                   NOT NULL DEFAULT '{}',
+  secret     BYTEA                    NOT NULL,
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+-- Deliveries of a subscription are looked up by the outbox message recipient.
+CREATE INDEX outbox_recipient_idx ON outbox (channel, (payload->>'recipient'));
@@ -0,0 +1,6 @@
+-- psql -h 127.0.0.1 -p 5432 -U postgres -d pascont -f resources/migrations/014_totp_challenge_attempts.sql
+
+ALTER TABLE totp_challenge
+  ADD COLUMN attempts INTEGER NOT NULL DEFAULT 0;
+
+CREATE INDEX totp_challenge_account_id_expires_at_idx ON totp_challenge (account_id, expires_at);
@@ -0,0 +1,25 @@
+package role
+
+import "github.com/hypnoglow/pascont/session"
+
+// PermissionChecker returns a func which can be used to check whether
+// the account of the session has the permission.
+// Sessions that are not found or expired have no permissions.
+func PermissionChecker(sessionRepo session.Repository, roleRepo Repository) func(sessionID, permission string) (bool, error) {
+	return func(sessionID, permission string) (bool, error) {
+		sess, err := sessionRepo.FindByID(sessionID)
+		if err == session.ErrNotFound || err == session.ErrExpired {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+
+		roles, err := roleRepo.FindByAccount(sess.AccountID)
+		if err != nil {
+			return false, err
+		}
+
+		return HasPermission(roles, Permission(permission)), nil
+	}
+}
@@ -0,0 +1,173 @@
+package role
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/hypnoglow/pascont/session"
+)
+
+func TestPermissionChecker(t *testing.T) {
+	sess := &session.Session{
+		ID:        "12345678-90ab-cdef-0123-4567890abcde",
+		AccountID: 123,
+	}
+
+	cases := []struct {
+		caseName        string
+		sessRepo        session.Repository
+		roleRepo        Repository
+		expectedAllowed bool
+		expectedError   error
+	}{
+		{
+			caseName: "Session is expired",
+			sessRepo: session.NewFakeRepository(
+				nil,
+				[]session.FakeRepositoryFindByIDResult{
+					{
+						Error: session.ErrExpired,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			expectedAllowed: false,
+			expectedError:   nil,
+		},
+		{
+			caseName: "sessionRepo.FindByID failed",
+			sessRepo: session.NewFakeRepository(
+				nil,
+				[]session.FakeRepositoryFindByIDResult{
+					{
+						Error: fmt.Errorf("FindByID failed"),
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			expectedAllowed: false,
+			expectedError:   fmt.Errorf("FindByID failed"),
+		},
+		{
+			caseName: "roleRepo.FindByAccount failed",
+			sessRepo: session.NewFakeRepository(
+				nil,
+				[]session.FakeRepositoryFindByIDResult{
+					{
+						Session: sess,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			roleRepo: NewFakeRepository(
+				nil,
+				nil,
+				[]FakeRepositoryFindByAccountResult{
+					{
+						Error: fmt.Errorf("FindByAccount failed"),
+					},
+				},
+				nil,
+				nil,
+			),
+			expectedAllowed: false,
+			expectedError:   fmt.Errorf("FindByAccount failed"),
+		},
+		{
+			caseName: "Account has no such permission",
+			sessRepo: session.NewFakeRepository(
+				nil,
+				[]session.FakeRepositoryFindByIDResult{
+					{
+						Session: sess,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			roleRepo: NewFakeRepository(
+				nil,
+				nil,
+				[]FakeRepositoryFindByAccountResult{
+					{
+						Roles: []*Role{
+							NewRole("support", []Permission{"accounts:read"}),
+						},
+					},
+				},
+				nil,
+				nil,
+			),
+			expectedAllowed: false,
+			expectedError:   nil,
+		},
+		{
+			caseName: "Account has the permission",
+			sessRepo: session.NewFakeRepository(
+				nil,
+				[]session.FakeRepositoryFindByIDResult{
+					{
+						Session: sess,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			roleRepo: NewFakeRepository(
+				nil,
+				nil,
+				[]FakeRepositoryFindByAccountResult{
+					{
+						Roles: []*Role{
+							NewRole("support", []Permission{"accounts:read"}),
+							NewRole("admin", []Permission{"accounts:manage"}),
+						},
+					},
+				},
+				nil,
+				nil,
+			),
+			expectedAllowed: true,
+			expectedError:   nil,
+		},
+	}
+
+	for i, c := range cases {
+		check := PermissionChecker(c.sessRepo, c.roleRepo)
+		allowed, err := check(sess.ID, "accounts:manage")
+
+		if allowed != c.expectedAllowed {
+			t.Errorf(
+				"testcase %d %s:\nExpected allowed to be %v, but got %v\n",
+				i,
+				c.caseName,
+				c.expectedAllowed,
+				allowed,
+			)
+		}
+
+		if !reflect.DeepEqual(err, c.expectedError) {
+			t.Errorf(
+				"testcase %d %s:\nExpected error to be %v, but got %v\n",
+				i,
+				c.caseName,
+				c.expectedError,
+				err,
+			)
+		}
+	}
+}
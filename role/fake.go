@@ -0,0 +1,88 @@
+package role
+
+type fakeRepository struct {
+	saveResults                []FakeRepositorySaveResult
+	saveResultCounter          int
+	listResults                []FakeRepositoryListResult
+	listResultCounter          int
+	findByAccountResults       []FakeRepositoryFindByAccountResult
+	findByAccountResultCounter int
+	assignResults              []FakeRepositoryAssignResult
+	assignResultCounter        int
+	unassignResults            []FakeRepositoryUnassignResult
+	unassignResultCounter      int
+}
+
+type FakeRepositorySaveResult struct {
+	Error error
+}
+
+type FakeRepositoryListResult struct {
+	Roles []*Role
+	Error error
+}
+
+type FakeRepositoryFindByAccountResult struct {
+	Roles []*Role
+	Error error
+}
+
+type FakeRepositoryAssignResult struct {
+	Error error
+}
+
+type FakeRepositoryUnassignResult struct {
+	Error error
+}
+
+// NewFakeRepository returns a new fake Repository.
+func NewFakeRepository(
+	saveResults []FakeRepositorySaveResult,
+	listResults []FakeRepositoryListResult,
+	findByAccountResults []FakeRepositoryFindByAccountResult,
+	assignResults []FakeRepositoryAssignResult,
+	unassignResults []FakeRepositoryUnassignResult,
+) Repository {
+	return &fakeRepository{
+		saveResults:                saveResults,
+		saveResultCounter:          0,
+		listResults:                listResults,
+		listResultCounter:          0,
+		findByAccountResults:       findByAccountResults,
+		findByAccountResultCounter: 0,
+		assignResults:              assignResults,
+		assignResultCounter:        0,
+		unassignResults:            unassignResults,
+		unassignResultCounter:      0,
+	}
+}
+
+func (r *fakeRepository) Save(role Role) error {
+	res := r.saveResults[r.saveResultCounter]
+	r.saveResultCounter++
+	return res.Error
+}
+
+func (r *fakeRepository) List() ([]*Role, error) {
+	res := r.listResults[r.listResultCounter]
+	r.listResultCounter++
+	return res.Roles, res.Error
+}
+
+func (r *fakeRepository) FindByAccount(accountID int64) ([]*Role, error) {
+	res := r.findByAccountResults[r.findByAccountResultCounter]
+	r.findByAccountResultCounter++
+	return res.Roles, res.Error
+}
+
+func (r *fakeRepository) Assign(accountID int64, roleName string) error {
+	res := r.assignResults[r.assignResultCounter]
+	r.assignResultCounter++
+	return res.Error
+}
+
+func (r *fakeRepository) Unassign(accountID int64, roleName string) error {
+	res := r.unassignResults[r.unassignResultCounter]
+	r.unassignResultCounter++
+	return res.Error
+}
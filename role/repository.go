@@ -0,0 +1,37 @@
+package role
+
+// Repository is a repository for a Role.
+type Repository interface {
+	// Save creates a role or replaces permissions of the role with the same name.
+	Save(r Role) error
+
+	// List retrieves all roles with their permissions.
+	List() ([]*Role, error)
+
+	// FindByAccount retrieves roles assigned to the account.
+	FindByAccount(accountID int64) ([]*Role, error)
+
+	// Assign assigns the role to the account.
+	// Assigning an already assigned role is not an error.
+	// If role or account not found, returns ErrNotFound.
+	// Other errors may occur.
+	Assign(accountID int64, roleName string) error
+
+	// Unassign removes the role from the account.
+	// Unassigning a role which is not assigned is not an error.
+	// If role not found, returns ErrNotFound.
+	// Other errors may occur.
+	Unassign(accountID int64, roleName string) error
+}
+
+// repositoryError is an error occurred in Repository.
+type repositoryError string
+
+func (e repositoryError) Error() string {
+	return string(e)
+}
+
+const (
+	// ErrNotFound occurs when role not found.
+	ErrNotFound = repositoryError("Role not found")
+)
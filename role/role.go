@@ -0,0 +1,59 @@
+package role
+
+// Permission is a name of an action an account may perform,
+// e.g. "accounts:manage".
+type Permission string
+
+// Permissions of the admin API. Accounts with them can use the routes
+// they grant with a session token, as well as operators with the admin token.
+const (
+	// PermissionRolesRead allows to list roles and roles of accounts.
+	PermissionRolesRead = Permission("roles:read")
+
+	// PermissionRolesWrite allows to change roles and to assign them to accounts.
+	PermissionRolesWrite = Permission("roles:write")
+)
+
+// Role is a named set of permissions that can be assigned to accounts.
+type Role struct {
+	ID          int64
+	Name        string
+	Permissions []Permission
+}
+
+// NewRole returns a new Role.
+func NewRole(name string, permissions []Permission) *Role {
+	return &Role{
+		Name:        name,
+		Permissions: permissions,
+	}
+}
+
+// HasPermission reports whether the role grants the permission.
+func (r Role) HasPermission(permission Permission) bool {
+	for _, p := range r.Permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// HasPermission reports whether any of the roles grants the permission.
+func HasPermission(roles []*Role, permission Permission) bool {
+	for _, r := range roles {
+		if r.HasPermission(permission) {
+			return true
+		}
+	}
+	return false
+}
+
+// Names returns names of the roles.
+func Names(roles []*Role) []string {
+	names := make([]string, len(roles))
+	for i, r := range roles {
+		names[i] = r.Name
+	}
+	return names
+}
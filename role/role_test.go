@@ -0,0 +1,78 @@
+package role
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRole_HasPermission(t *testing.T) {
+	r := NewRole("support", []Permission{"accounts:read", "sessions:revoke"})
+
+	cases := []struct {
+		permission Permission
+		expected   bool
+	}{
+		{"accounts:read", true},
+		{"sessions:revoke", true},
+		{"accounts:manage", false},
+		{"", false},
+	}
+
+	for i, c := range cases {
+		if actual := r.HasPermission(c.permission); actual != c.expected {
+			t.Errorf(
+				"testcase %d: Expected HasPermission of %q to be %v but got %v\n",
+				i,
+				c.permission,
+				c.expected,
+				actual,
+			)
+		}
+	}
+}
+
+func TestHasPermission(t *testing.T) {
+	roles := []*Role{
+		NewRole("support", []Permission{"accounts:read"}),
+		NewRole("admin", []Permission{"accounts:manage"}),
+	}
+
+	cases := []struct {
+		roles      []*Role
+		permission Permission
+		expected   bool
+	}{
+		{roles, "accounts:read", true},
+		{roles, "accounts:manage", true},
+		{roles, "roles:manage", false},
+		{nil, "accounts:read", false},
+	}
+
+	for i, c := range cases {
+		if actual := HasPermission(c.roles, c.permission); actual != c.expected {
+			t.Errorf(
+				"testcase %d: Expected HasPermission of %q to be %v but got %v\n",
+				i,
+				c.permission,
+				c.expected,
+				actual,
+			)
+		}
+	}
+}
+
+func TestNames(t *testing.T) {
+	roles := []*Role{
+		NewRole("support", nil),
+		NewRole("admin", nil),
+	}
+
+	expected := []string{"support", "admin"}
+	if actual := Names(roles); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected names to be %v but got %v\n", expected, actual)
+	}
+
+	if actual := Names(nil); len(actual) != 0 {
+		t.Errorf("Expected no names but got %v\n", actual)
+	}
+}
@@ -0,0 +1,54 @@
+package sealer
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+type aesGCMSealer struct {
+	aead cipher.AEAD
+}
+
+// NewAESGCMSealer returns a new Sealer which uses AES-GCM with the key.
+// The key MUST be 16, 24 or 32 bytes long.
+func NewAESGCMSealer(key []byte) (Sealer, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to create a cipher")
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to create a cipher")
+	}
+
+	return aesGCMSealer{aead}, nil
+}
+
+// Seal returns a random nonce followed by the encrypted plaintext.
+func (s aesGCMSealer) Seal(plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, errors.Wrap(err, "Failed to generate a nonce")
+	}
+
+	return s.aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func (s aesGCMSealer) Open(ciphertext []byte) ([]byte, error) {
+	if len(ciphertext) < s.aead.NonceSize() {
+		return nil, fmt.Errorf("Ciphertext is too short")
+	}
+
+	nonce, ciphertext := ciphertext[:s.aead.NonceSize()], ciphertext[s.aead.NonceSize():]
+	plaintext, err := s.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to open a ciphertext")
+	}
+
+	return plaintext, nil
+}
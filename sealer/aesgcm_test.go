@@ -0,0 +1,60 @@
+package sealer
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestNewAESGCMSealer(t *testing.T) {
+	if _, err := NewAESGCMSealer([]byte("short")); err == nil {
+		t.Errorf("Expected an error for a key of invalid length\n")
+	}
+}
+
+func TestAESGCMSealer(t *testing.T) {
+	cases := []struct {
+		key       []byte
+		plaintext []byte
+	}{
+		{
+			[]byte("0123456789abcdef"),
+			[]byte("Hello"),
+		},
+		{
+			[]byte("0123456789abcdef0123456789abcdef"),
+			[]byte("12345678901234567890"),
+		},
+	}
+
+	for i, c := range cases {
+		s, err := NewAESGCMSealer(c.key)
+		if err != nil {
+			t.Fatalf("testcase %d: Expected no error, but got %s\n", i, err)
+		}
+
+		sealed, err := s.Seal(c.plaintext)
+		if err != nil {
+			t.Fatalf("testcase %d: Expected no error, but got %s\n", i, err)
+		}
+		if bytes.Contains(sealed, c.plaintext) {
+			t.Errorf("testcase %d: Expected sealed data not to contain the plaintext\n", i)
+		}
+
+		opened, err := s.Open(sealed)
+		if err != nil {
+			t.Fatalf("testcase %d: Expected no error, but got %s\n", i, err)
+		}
+		if !bytes.Equal(opened, c.plaintext) {
+			t.Errorf("testcase %d: Expected opened data to be %s, but got %s\n", i, c.plaintext, opened)
+		}
+
+		sealed[len(sealed)-1] ^= 0xff
+		if _, err := s.Open(sealed); err == nil {
+			t.Errorf("testcase %d: Expected tampered data not to be opened\n", i)
+		}
+
+		if _, err := s.Open([]byte("x")); err == nil {
+			t.Errorf("testcase %d: Expected too short data not to be opened\n", i)
+		}
+	}
+}
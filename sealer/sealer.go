@@ -0,0 +1,11 @@
+package sealer
+
+// Sealer can encrypt data to be kept at rest and decrypt it back.
+type Sealer interface {
+	// Seal returns the encrypted and authenticated plaintext.
+	Seal(plaintext []byte) (ciphertext []byte, err error)
+
+	// Open decrypts the ciphertext returned by Seal.
+	// Returns an error if the ciphertext was tampered with.
+	Open(ciphertext []byte) (plaintext []byte, err error)
+}
@@ -0,0 +1,90 @@
+package session
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/hypnoglow/pascont/kit/middleware"
+)
+
+const (
+	// PathIDMe can be used in paths instead of the account ID
+	// to refer the account of the session the request is authorized with.
+	PathIDMe = "me"
+)
+
+// AccountSession returns the session the request is authorized with.
+// The session MUST belong to the account from the request path,
+// where PathIDMe stands for the account of the session.
+// Requests authorized with an API key instead are forbidden, as API keys
+// only authorize the routes which accept them explicitly.
+// On failure, returns a status code to respond with.
+// Repository errors are logged with the logger.
+func AccountSession(req *http.Request, repo Repository, logger *log.Logger) (sess *Session, status int) {
+	pathID, _ := middleware.PathParam(req, "id")
+	accountID, err := strconv.ParseInt(pathID, 10, 64)
+	if err != nil && pathID != PathIDMe {
+		return nil, http.StatusNotFound
+	}
+
+	sidFromToken, ok := req.Context().Value(middleware.ContextKeySessionID{}).(string)
+	if !ok {
+		if _, byKey := req.Context().Value(middleware.ContextKeyAccountID{}).(int64); byKey {
+			return nil, http.StatusForbidden
+		}
+		return nil, http.StatusUnauthorized
+	}
+
+	sess, err = repo.FindByID(sidFromToken)
+	if err != nil {
+		if err == ErrNotFound || err == ErrExpired {
+			return nil, http.StatusUnauthorized
+		}
+
+		logger.Println(err)
+		return nil, http.StatusInternalServerError
+	}
+
+	// Account ID from path and from the session MUST match in order to proceed.
+	if pathID != PathIDMe && sess.AccountID != accountID {
+		return nil, http.StatusUnauthorized
+	}
+
+	return sess, http.StatusOK
+}
+
+// Tenant puts the tenant ID of the session the request is authorized with
+// to the request context, as middleware.Tenant does for the tenant served on the host,
+// so the request is scoped to the tenant of the caller. It MUST run after middleware.AuthToken.
+// Requests without a valid session are responded with 401.
+// Repository errors are logged with the logger.
+func Tenant(next http.Handler, repo Repository, logger *log.Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		sidFromToken, ok := req.Context().Value(middleware.ContextKeySessionID{}).(string)
+		if !ok {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		sess, err := repo.FindByID(sidFromToken)
+		if err != nil {
+			if err == ErrNotFound || err == ErrExpired {
+				w.WriteHeader(http.StatusUnauthorized)
+			} else {
+				logger.Println(err)
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+			return
+		}
+
+		req = req.WithContext(context.WithValue(
+			req.Context(),
+			middleware.ContextKeyTenantID{},
+			sess.TenantID,
+		))
+
+		next.ServeHTTP(w, req)
+	})
+}
@@ -0,0 +1,271 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hypnoglow/pascont/kit/middleware"
+)
+
+func TestAccountSession(t *testing.T) {
+	fakeLogger := log.New(ioutil.Discard, "", log.LstdFlags)
+	sess := &Session{ID: "12345678-90ab-cdef-0123-4567890abcde", AccountID: 123}
+
+	cases := []struct {
+		caseName string
+		// in
+		repo         Repository
+		pathID       string
+		sessionID    string
+		keyAccountID int64
+		// out
+		expectedSession *Session
+		expectedStatus  int
+	}{
+		{
+			caseName:       "Invalid account ID should result in 404",
+			pathID:         "abc",
+			sessionID:      sess.ID,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			caseName:       "No session ID should result in 401",
+			pathID:         "123",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			caseName:       "API key instead of a session should result in 403",
+			pathID:         "123",
+			keyAccountID:   123,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			caseName: "Expired session should result in 401",
+			repo: NewFakeRepository(
+				nil,
+				[]FakeRepositoryFindByIDResult{
+					{
+						Error: ErrExpired,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			pathID:         "123",
+			sessionID:      sess.ID,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			caseName: "repo.FindByID failed",
+			repo: NewFakeRepository(
+				nil,
+				[]FakeRepositoryFindByIDResult{
+					{
+						Error: fmt.Errorf("FindByID failed"),
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			pathID:         "123",
+			sessionID:      sess.ID,
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			caseName: "Session of other account should result in 401",
+			repo: NewFakeRepository(
+				nil,
+				[]FakeRepositoryFindByIDResult{
+					{
+						Session: sess,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			pathID:         "456",
+			sessionID:      sess.ID,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			caseName: "Successful",
+			repo: NewFakeRepository(
+				nil,
+				[]FakeRepositoryFindByIDResult{
+					{
+						Session: sess,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			pathID:          "123",
+			sessionID:       sess.ID,
+			expectedSession: sess,
+			expectedStatus:  http.StatusOK,
+		},
+		{
+			caseName: "Successful with me",
+			repo: NewFakeRepository(
+				nil,
+				[]FakeRepositoryFindByIDResult{
+					{
+						Session: sess,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			pathID:          PathIDMe,
+			sessionID:       sess.ID,
+			expectedSession: sess,
+			expectedStatus:  http.StatusOK,
+		},
+	}
+
+	for i, c := range cases {
+		req := httptest.NewRequest(http.MethodGet, "/accounts/"+c.pathID, nil)
+		ctx := context.WithValue(req.Context(), middleware.ContextKeyPathParams{}, map[string]string{"id": c.pathID})
+		if c.sessionID != "" {
+			ctx = context.WithValue(ctx, middleware.ContextKeySessionID{}, c.sessionID)
+		}
+		if c.keyAccountID != 0 {
+			ctx = context.WithValue(ctx, middleware.ContextKeyAccountID{}, c.keyAccountID)
+		}
+
+		actual, status := AccountSession(req.WithContext(ctx), c.repo, fakeLogger)
+
+		if status != c.expectedStatus {
+			t.Errorf(
+				"testcase %d %s: Expected status to be %v, but got %v\n",
+				i,
+				c.caseName,
+				c.expectedStatus,
+				status,
+			)
+		}
+
+		if actual != c.expectedSession {
+			t.Errorf(
+				"testcase %d %s: Expected session to be %v, but got %v\n",
+				i,
+				c.caseName,
+				c.expectedSession,
+				actual,
+			)
+		}
+	}
+}
+
+func TestTenant(t *testing.T) {
+	fakeLogger := log.New(ioutil.Discard, "", log.LstdFlags)
+	sess := &Session{ID: "12345678-90ab-cdef-0123-4567890abcde", AccountID: 123, TenantID: 2}
+
+	cases := []struct {
+		caseName string
+		// in
+		repo      Repository
+		sessionID string
+		// out
+		expectedCode     int
+		expectedTenantID int64
+		expectedCalled   bool
+	}{
+		{
+			caseName:     "No session ID should result in 401",
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			caseName: "Expired session should result in 401",
+			repo: NewFakeRepository(
+				nil,
+				[]FakeRepositoryFindByIDResult{
+					{
+						Error: ErrExpired,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			sessionID:    sess.ID,
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			caseName: "repo.FindByID failed",
+			repo: NewFakeRepository(
+				nil,
+				[]FakeRepositoryFindByIDResult{
+					{
+						Error: fmt.Errorf("FindByID failed"),
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			sessionID:    sess.ID,
+			expectedCode: http.StatusInternalServerError,
+		},
+		{
+			caseName: "Successful",
+			repo: NewFakeRepository(
+				nil,
+				[]FakeRepositoryFindByIDResult{
+					{
+						Session: sess,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			sessionID:        sess.ID,
+			expectedCode:     http.StatusOK,
+			expectedTenantID: 2,
+			expectedCalled:   true,
+		},
+	}
+
+	for i, c := range cases {
+		called := false
+		handler := Tenant(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			called = true
+			if tenantID, _ := req.Context().Value(middleware.ContextKeyTenantID{}).(int64); tenantID != c.expectedTenantID {
+				t.Errorf("testcase %d %s: Expected tenant ID to be %v but got %v\n", i, c.caseName, c.expectedTenantID, tenantID)
+			}
+		}), c.repo, fakeLogger)
+
+		req := httptest.NewRequest(http.MethodGet, "/admin/roles", nil)
+		if c.sessionID != "" {
+			req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeySessionID{}, c.sessionID))
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		if w.Code != c.expectedCode {
+			t.Errorf("testcase %d %s: Expected status code to be %v but got %v\n", i, c.caseName, c.expectedCode, w.Code)
+		}
+		if called != c.expectedCalled {
+			t.Errorf("testcase %d %s: Expected handler to be called %v but got %v\n", i, c.caseName, c.expectedCalled, called)
+		}
+	}
+}
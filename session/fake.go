@@ -1,10 +1,24 @@
 package session
 
+import (
+	"time"
+
+	"github.com/hypnoglow/pascont/outbox"
+)
+
 type fakeRepository struct {
-	saveResults           []FakeRepositorySaveResult
-	saveResultCounter     int
-	findByIDResults       []FakeRepositoryFindByIDResult
-	findByIDResultCounter int
+	saveResults                     []FakeRepositorySaveResult
+	saveResultCounter               int
+	findByIDResults                 []FakeRepositoryFindByIDResult
+	findByIDResultCounter           int
+	listByAccountResults            []FakeRepositoryListByAccountResult
+	listByAccountResultCounter      int
+	touchResults                    []FakeRepositoryTouchResult
+	touchResultCounter              int
+	deleteResults                   []FakeRepositoryDeleteResult
+	deleteResultCounter             int
+	deleteAllByAccountResults       []FakeRepositoryDeleteAllByAccountResult
+	deleteAllByAccountResultCounter int
 }
 
 type FakeRepositorySaveResult struct {
@@ -16,20 +30,50 @@ type FakeRepositoryFindByIDResult struct {
 	Error   error
 }
 
+type FakeRepositoryListByAccountResult struct {
+	Sessions []*Session
+	Total    int
+	Error    error
+}
+
+type FakeRepositoryTouchResult struct {
+	Error error
+}
+
+type FakeRepositoryDeleteResult struct {
+	Error error
+}
+
+type FakeRepositoryDeleteAllByAccountResult struct {
+	Error error
+}
+
 // NewFakeRepository returns a new fake Repository.
 func NewFakeRepository(
 	saveResults []FakeRepositorySaveResult,
 	findByIDResults []FakeRepositoryFindByIDResult,
+	listByAccountResults []FakeRepositoryListByAccountResult,
+	touchResults []FakeRepositoryTouchResult,
+	deleteResults []FakeRepositoryDeleteResult,
+	deleteAllByAccountResults []FakeRepositoryDeleteAllByAccountResult,
 ) Repository {
 	return &fakeRepository{
-		saveResults:           saveResults,
-		saveResultCounter:     0,
-		findByIDResults:       findByIDResults,
-		findByIDResultCounter: 0,
+		saveResults:                     saveResults,
+		saveResultCounter:               0,
+		findByIDResults:                 findByIDResults,
+		findByIDResultCounter:           0,
+		listByAccountResults:            listByAccountResults,
+		listByAccountResultCounter:      0,
+		touchResults:                    touchResults,
+		touchResultCounter:              0,
+		deleteResults:                   deleteResults,
+		deleteResultCounter:             0,
+		deleteAllByAccountResults:       deleteAllByAccountResults,
+		deleteAllByAccountResultCounter: 0,
 	}
 }
 
-func (r *fakeRepository) Save(sess Session) error {
+func (r *fakeRepository) Save(sess Session, messages ...outbox.Message) error {
 	res := r.saveResults[r.saveResultCounter]
 	r.saveResultCounter++
 	return res.Error
@@ -41,12 +85,26 @@ func (r *fakeRepository) FindByID(id string) (*Session, error) {
 	return res.Session, res.Error
 }
 
-func (r *fakeRepository) Delete(id string) error {
-	// NOT FAKED YET
-	return nil
+func (r *fakeRepository) ListByAccount(accountID int64, offset, limit int) ([]*Session, int, error) {
+	res := r.listByAccountResults[r.listByAccountResultCounter]
+	r.listByAccountResultCounter++
+	return res.Sessions, res.Total, res.Error
+}
+
+func (r *fakeRepository) Touch(id string, lastSeenAt time.Time) error {
+	res := r.touchResults[r.touchResultCounter]
+	r.touchResultCounter++
+	return res.Error
 }
 
-func (r *fakeRepository) DeleteAllByAccount(accountID int64) error {
-	// NOT FAKED YET
-	return nil
+func (r *fakeRepository) Delete(id string, messages ...outbox.Message) error {
+	res := r.deleteResults[r.deleteResultCounter]
+	r.deleteResultCounter++
+	return res.Error
+}
+
+func (r *fakeRepository) DeleteAllByAccount(accountID int64, messages ...outbox.Message) error {
+	res := r.deleteAllByAccountResults[r.deleteAllByAccountResultCounter]
+	r.deleteAllByAccountResultCounter++
+	return res.Error
 }
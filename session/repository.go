@@ -1,20 +1,36 @@
 package session
 
+import (
+	"time"
+
+	"github.com/hypnoglow/pascont/outbox"
+)
+
 // Repository is a repository for Session.
 type Repository interface {
 	// Save saves a session to the repository.
-	Save(sess Session) error
+	// The outbox messages are added along with the session, in the same transaction.
+	Save(sess Session, messages ...outbox.Message) error
 
 	// FindByID looks for a session with specified id.
 	// If session not found, returns ErrNotFound.
 	// If session is already expired, returns ErrExpired.
 	FindByID(id string) (*Session, error)
 
+	// ListByAccount returns non-expired sessions of the account,
+	// most recent first, along with the total count of such sessions.
+	ListByAccount(accountID int64, offset, limit int) (sessions []*Session, total int, err error)
+
+	// Touch sets the session LastSeenAt.
+	Touch(id string, lastSeenAt time.Time) error
+
 	// Delete removes the session.
-	Delete(id string) error
+	// The outbox messages are added along with the removal, in the same transaction.
+	Delete(id string, messages ...outbox.Message) error
 
 	// DeleteAllByAccount removes all sessions of the account.
-	DeleteAllByAccount(accountID int64) error
+	// The outbox messages are added along with the removal, in the same transaction.
+	DeleteAllByAccount(accountID int64, messages ...outbox.Message) error
 }
 
 // repositoryError is an error occured in Repository
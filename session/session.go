@@ -3,6 +3,7 @@ package session
 import (
 	"strconv"
 	"time"
+	"unicode/utf8"
 
 	"github.com/pkg/errors"
 
@@ -17,25 +18,36 @@ const (
 
 	// SessionExpiresAtLength is the length of a session ExpiresAt in Unix Timestamp form in bytes.
 	SessionExpiresAtLength = 10
+
+	// UserAgentMaxLength is the maximum length of a session UserAgent in bytes.
+	UserAgentMaxLength = 512
 )
 
 // Session represents session data.
 type Session struct {
 	ID        string
 	AccountID int64
+	TenantID  int64
 	CreatedAt time.Time
 	ExpiresAt time.Time
+
+	// Client metadata.
+	IP         string
+	UserAgent  string
+	LastSeenAt time.Time
 }
 
 // NewSession returns a new Session.
+// The LastSeenAt is set to createdAt.
 func NewSession(id string, accountID int64, createdAt, expiresAt time.Time) *Session {
 	createdAt = createdAt.UTC().Truncate(time.Second)
 	expiresAt = expiresAt.UTC().Truncate(time.Second)
 	return &Session{
-		ID:        id,
-		AccountID: accountID,
-		CreatedAt: createdAt,
-		ExpiresAt: expiresAt,
+		ID:         id,
+		AccountID:  accountID,
+		CreatedAt:  createdAt,
+		ExpiresAt:  expiresAt,
+		LastSeenAt: createdAt,
 	}
 }
 
@@ -55,6 +67,21 @@ func (s Session) Token(notary notary.Notary, packer packer.Packer, secretKey []b
 	return string(pack), nil
 }
 
+// TruncateUserAgent returns the user agent truncated to UserAgentMaxLength bytes.
+// The user agent is cut on a rune boundary, so it stays valid UTF-8.
+func TruncateUserAgent(userAgent string) string {
+	if len(userAgent) <= UserAgentMaxLength {
+		return userAgent
+	}
+
+	n := UserAgentMaxLength
+	for n > 0 && !utf8.RuneStart(userAgent[n]) {
+		n--
+	}
+
+	return userAgent[:n]
+}
+
 // ResetExpiresAt resets session expire date to current time plus duration.
 func (s *Session) ResetExpiresAt(duration time.Duration) {
 	s.ExpiresAt = time.Now().Add(duration).UTC()
@@ -2,6 +2,7 @@ package session
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -129,6 +130,43 @@ func TestSession_ResetExpiresAt(t *testing.T) {
 	}
 }
 
+func TestTruncateUserAgent(t *testing.T) {
+	cases := []struct {
+		caseName  string
+		userAgent string
+		expected  string
+	}{
+		{
+			caseName:  "Short user agent is not truncated",
+			userAgent: "curl/7.58.0",
+			expected:  "curl/7.58.0",
+		},
+		{
+			caseName:  "Long user agent is truncated",
+			userAgent: strings.Repeat("a", UserAgentMaxLength+10),
+			expected:  strings.Repeat("a", UserAgentMaxLength),
+		},
+		{
+			caseName:  "Long user agent is truncated on a rune boundary",
+			userAgent: strings.Repeat("a", UserAgentMaxLength-1) + "ж",
+			expected:  strings.Repeat("a", UserAgentMaxLength-1),
+		},
+	}
+
+	for i, c := range cases {
+		actual := TruncateUserAgent(c.userAgent)
+		if actual != c.expected {
+			t.Errorf(
+				"testcase %d %s: Expected user agent of length %d, but got of length %d\n",
+				i,
+				c.caseName,
+				len(c.expected),
+				len(actual),
+			)
+		}
+	}
+}
+
 func TestCreateSession(t *testing.T) {
 	cases := []struct {
 		uuidProducer identity.UUIDProducer
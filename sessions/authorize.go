@@ -0,0 +1,26 @@
+package sessions
+
+import "github.com/hypnoglow/pascont/account"
+
+// isAccountActive reports whether the account with the id exists and is active.
+// Sessions of accounts that are not active MUST be treated as invalid.
+func (c RestController) isAccountActive(id int64) (bool, error) {
+	acc, err := c.activeAccount(id)
+	return acc != nil, err
+}
+
+// activeAccount returns the account with the id, or nil if it does not exist or is not active.
+func (c RestController) activeAccount(id int64) (*account.Account, error) {
+	acc, err := c.accountRepo.FindByID(id)
+	if err == account.ErrNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !acc.IsActive() {
+		return nil, nil
+	}
+
+	return acc, nil
+}
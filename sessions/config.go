@@ -3,9 +3,12 @@ package sessions
 import "time"
 
 const (
-	// sessionDefaultDuration is a default duration of a session.
-	sessionDefaultDuration = time.Hour * 24 * 3
+	// SessionDefaultDuration is a default duration of a session.
+	SessionDefaultDuration = time.Hour * 24 * 3
 
 	// sessionMaxDuration is a max duration of a session.
 	sessionMaxDuration = time.Hour * 24 * 3
+
+	// lastSeenResolution is how often a session LastSeenAt is updated on requests.
+	lastSeenResolution = time.Minute
 )
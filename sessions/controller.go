@@ -2,34 +2,91 @@ package sessions
 
 import (
 	"log"
+	"time"
 
 	"github.com/hypnoglow/pascont/account"
+	"github.com/hypnoglow/pascont/clock"
 	"github.com/hypnoglow/pascont/hasher"
 	"github.com/hypnoglow/pascont/identity"
 	"github.com/hypnoglow/pascont/notary"
+	"github.com/hypnoglow/pascont/outbox"
 	"github.com/hypnoglow/pascont/packer"
+	"github.com/hypnoglow/pascont/recovery"
+	"github.com/hypnoglow/pascont/role"
 	"github.com/hypnoglow/pascont/session"
+	"github.com/hypnoglow/pascont/totp"
+	"github.com/hypnoglow/pascont/webhook"
 )
 
 const (
-	PathSessions = "/sessions"
-	PathSession  = "/sessions/"
+	PathSessions        = "/sessions"
+	PathSession         = "/sessions/"
+	PathSessionsTOTP    = "/sessions/totp"
+	PathAccountSessions = "/accounts/:id/sessions"
+	PathAccountSession  = "/accounts/:id/sessions/:sessionID"
 )
 
 // RestController is a REST controller for sessions.
 type RestController struct {
-	logger       *log.Logger
-	accountRepo  account.Repository
-	sessionRepo  session.Repository
-	notary       notary.Notary
-	packer       packer.Packer
-	hasher       hasher.Hasher
-	uuidProducer identity.UUIDProducer
-	options      Options
+	logger        *log.Logger
+	accountRepo   account.Repository
+	sessionRepo   session.Repository
+	roleRepo      role.Repository
+	totpRepo      totp.Repository
+	challengeRepo totp.ChallengeRepository
+	recoveryRepo  recovery.Repository
+	notary        notary.Notary
+	packer        packer.Packer
+	hasher        hasher.Hasher
+	uuidProducer  identity.UUIDProducer
+	clock         clock.Clock
+	options       Options
 }
 
 type Options struct {
 	SessionSecretKey []byte
+
+	// LockoutThreshold is a number of consecutive failed logins
+	// after which the account gets locked. Zero disables the lockout.
+	LockoutThreshold int
+
+	// LockoutDuration is how long the account stays locked.
+	LockoutDuration time.Duration
+
+	// Unverified is how accounts pending verification are treated on log in.
+	Unverified UnverifiedPolicy
+
+	// UnverifiedSessionDuration is a duration of sessions of accounts pending verification,
+	// when they are limited.
+	UnverifiedSessionDuration time.Duration
+
+	// Outbox routes new login alerts, which are saved along with new sessions.
+	// The zero Router sends no alerts.
+	Outbox outbox.Router
+
+	// Events emits session events to webhook subscriptions.
+	// If nil, no events are emitted.
+	Events webhook.Emitter
+}
+
+// UnverifiedPolicy is how accounts pending verification are treated on log in.
+type UnverifiedPolicy string
+
+const (
+	// UnverifiedAllow lets accounts pending verification log in as verified ones.
+	UnverifiedAllow = UnverifiedPolicy("")
+
+	// UnverifiedBlock forbids accounts pending verification to log in.
+	UnverifiedBlock = UnverifiedPolicy("block")
+
+	// UnverifiedLimit lets accounts pending verification log in
+	// only for sessions of UnverifiedSessionDuration, which can not be extended.
+	UnverifiedLimit = UnverifiedPolicy("limit")
+)
+
+// isLimited reports whether sessions of the account are limited, as it is pending verification.
+func (c RestController) isLimited(acc account.Account) bool {
+	return c.options.Unverified == UnverifiedLimit && !acc.IsVerified()
 }
 
 // NewRestController returns a new RestController.
@@ -37,20 +94,30 @@ func NewRestController(
 	logger *log.Logger,
 	accountRepo account.Repository,
 	sessionRepo session.Repository,
+	roleRepo role.Repository,
+	totpRepo totp.Repository,
+	challengeRepo totp.ChallengeRepository,
+	recoveryRepo recovery.Repository,
 	n notary.Notary,
 	p packer.Packer,
 	h hasher.Hasher,
 	u identity.UUIDProducer,
+	clk clock.Clock,
 	opts Options,
 ) RestController {
 	return RestController{
 		logger,
 		accountRepo,
 		sessionRepo,
+		roleRepo,
+		totpRepo,
+		challengeRepo,
+		recoveryRepo,
 		n,
 		p,
 		h,
 		u,
+		clk,
 		opts,
 	}
 }
@@ -4,6 +4,7 @@ import (
 	"io/ioutil"
 	"log"
 	"testing"
+	"time"
 
 	"github.com/hypnoglow/pascont/account"
 	"github.com/hypnoglow/pascont/hasher"
@@ -15,14 +16,19 @@ import (
 func TestNewRestController(t *testing.T) {
 	NewRestController(
 		log.New(ioutil.Discard, "", log.LstdFlags),
-		account.NewFakeRepository(nil, nil, nil, nil),
-		session.NewFakeRepository(nil, nil),
+		account.NewFakeRepository(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil),
+		session.NewFakeRepository(nil, nil, nil, nil, nil, nil),
+		nil,
+		nil,
+		nil,
+		nil,
 		notary.NewFakeNotary(nil, nil),
 		packer.NewFakePacker(nil, nil),
 		hasher.NewFakeHasher(nil, nil),
 		func() string {
 			return "12345678-90ab-cdef-0123-4567890abcde"
 		},
+		time.Now,
 		Options{
 			SessionSecretKey: []byte("secret_key"),
 		},
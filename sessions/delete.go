@@ -0,0 +1,54 @@
+package sessions
+
+import (
+	"net/http"
+
+	"github.com/hypnoglow/pascont/kit/middleware"
+	"github.com/hypnoglow/pascont/outbox"
+	"github.com/hypnoglow/pascont/session"
+)
+
+// DeleteSession is a handler for:
+// DELETE /session/:id
+func (c RestController) DeleteSession(w http.ResponseWriter, req *http.Request) {
+	// Check that it is actually a UUID.
+	sid, ok := req.Context().Value(middleware.ContextKeyPathID{}).(string)
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	sidFromToken, ok := req.Context().Value(middleware.ContextKeySessionID{}).(string)
+	if !ok {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	// Session ID from path and from auth token MUST match in order to proceed.
+	if sidFromToken != sid {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	// The session is looked up only to tell the subscribers whose it is.
+	var sess *session.Session
+	if c.options.Events != nil {
+		var err error
+		sess, err = c.sessionRepo.FindByID(sid)
+		if err != nil && err != session.ErrNotFound && err != session.ErrExpired {
+			c.logger.Println(err)
+		}
+	}
+
+	var revoked []outbox.Message
+	if sess != nil {
+		revoked = c.revokedMessages(sess.TenantID, sess.AccountID, sess.ID)
+	}
+	if err := c.sessionRepo.Delete(sid, revoked...); err != nil {
+		c.logger.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
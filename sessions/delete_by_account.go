@@ -0,0 +1,72 @@
+package sessions
+
+import (
+	"net/http"
+
+	"github.com/pborman/uuid"
+
+	"github.com/hypnoglow/pascont/kit/middleware"
+	"github.com/hypnoglow/pascont/session"
+)
+
+// DeleteAccountSessions is a handler for:
+// DELETE /accounts/:id/sessions
+func (c RestController) DeleteAccountSessions(w http.ResponseWriter, req *http.Request) {
+	sess, status := session.AccountSession(req, c.sessionRepo, c.logger)
+	if sess == nil {
+		w.WriteHeader(status)
+		return
+	}
+
+	revoked := c.revokedMessages(sess.TenantID, sess.AccountID, "")
+	if err := c.sessionRepo.DeleteAllByAccount(sess.AccountID, revoked...); err != nil {
+		c.logger.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DeleteAccountSession is a handler for:
+// DELETE /accounts/:id/sessions/:sessionID
+func (c RestController) DeleteAccountSession(w http.ResponseWriter, req *http.Request) {
+	current, status := session.AccountSession(req, c.sessionRepo, c.logger)
+	if current == nil {
+		w.WriteHeader(status)
+		return
+	}
+
+	sid, _ := middleware.PathParam(req, "sessionID")
+	if uuid.Parse(sid) == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	sess, err := c.sessionRepo.FindByID(sid)
+	if err != nil {
+		if err == session.ErrNotFound || err == session.ErrExpired {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		c.logger.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	// Sessions of other accounts are not visible.
+	if sess.AccountID != current.AccountID {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	revoked := c.revokedMessages(sess.TenantID, sess.AccountID, sess.ID)
+	if err := c.sessionRepo.Delete(sess.ID, revoked...); err != nil {
+		c.logger.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,388 @@
+package sessions
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/pascont/kit/middleware"
+	"github.com/hypnoglow/pascont/session"
+)
+
+func TestRestController_DeleteAccountSessions(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	later := now.Add(time.Hour * 24)
+	fakeLogger := log.New(ioutil.Discard, "", log.LstdFlags)
+
+	cases := []struct {
+		caseName string
+		// in
+		sessRepo                 session.Repository
+		reqContextSessionIDValue interface{}
+		reqPathID                string
+		// out
+		expectedCode int
+	}{
+		{
+			caseName:  "The account id from path is not a number",
+			reqPathID: "abc",
+			// out
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			caseName:  "There is no session ID from Authorization Header",
+			reqPathID: "123",
+			// out
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			caseName: "sessionRepo.FindByID returns ErrExpired",
+			sessRepo: session.NewFakeRepository(
+				nil,
+				[]session.FakeRepositoryFindByIDResult{
+					{
+						Error: session.ErrExpired,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			reqContextSessionIDValue: "12345678-90ab-cdef-0123-4567890abcde",
+			reqPathID:                "123",
+			// out
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			caseName: "sessionRepo.FindByID failed",
+			sessRepo: session.NewFakeRepository(
+				nil,
+				[]session.FakeRepositoryFindByIDResult{
+					{
+						Error: fmt.Errorf("FindByID failed"),
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			reqContextSessionIDValue: "12345678-90ab-cdef-0123-4567890abcde",
+			reqPathID:                "123",
+			// out
+			expectedCode: http.StatusInternalServerError,
+		},
+		{
+			caseName: "Session belongs to another account",
+			sessRepo: session.NewFakeRepository(
+				nil,
+				[]session.FakeRepositoryFindByIDResult{
+					{
+						Session: &session.Session{
+							ID:        "12345678-90ab-cdef-0123-4567890abcde",
+							AccountID: 456,
+							CreatedAt: now,
+							ExpiresAt: later,
+						},
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			reqContextSessionIDValue: "12345678-90ab-cdef-0123-4567890abcde",
+			reqPathID:                "123",
+			// out
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			caseName: "sessionRepo.DeleteAllByAccount failed",
+			sessRepo: session.NewFakeRepository(
+				nil,
+				[]session.FakeRepositoryFindByIDResult{
+					{
+						Session: &session.Session{
+							ID:        "12345678-90ab-cdef-0123-4567890abcde",
+							AccountID: 123,
+							CreatedAt: now,
+							ExpiresAt: later,
+						},
+					},
+				},
+				nil,
+				nil,
+				nil,
+				[]session.FakeRepositoryDeleteAllByAccountResult{
+					{
+						Error: fmt.Errorf("DeleteAllByAccount failed"),
+					},
+				},
+			),
+			reqContextSessionIDValue: "12345678-90ab-cdef-0123-4567890abcde",
+			reqPathID:                "123",
+			// out
+			expectedCode: http.StatusInternalServerError,
+		},
+		{
+			caseName: "Successful",
+			sessRepo: session.NewFakeRepository(
+				nil,
+				[]session.FakeRepositoryFindByIDResult{
+					{
+						Session: &session.Session{
+							ID:        "12345678-90ab-cdef-0123-4567890abcde",
+							AccountID: 123,
+							CreatedAt: now,
+							ExpiresAt: later,
+						},
+					},
+				},
+				nil,
+				nil,
+				nil,
+				[]session.FakeRepositoryDeleteAllByAccountResult{
+					{
+						Error: nil,
+					},
+				},
+			),
+			reqContextSessionIDValue: "12345678-90ab-cdef-0123-4567890abcde",
+			reqPathID:                "123",
+			// out
+			expectedCode: http.StatusNoContent,
+		},
+	}
+
+	for i, c := range cases {
+		ctrl := NewRestController(
+			fakeLogger,
+			nil,
+			c.sessRepo,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			time.Now,
+			Options{},
+		)
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodDelete, "/accounts/"+c.reqPathID+"/sessions", nil)
+		req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyPathParams{}, map[string]string{"id": c.reqPathID}))
+		req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeySessionID{}, c.reqContextSessionIDValue))
+		ctrl.DeleteAccountSessions(w, req)
+
+		if w.Code != c.expectedCode {
+			t.Errorf(
+				"testcase %d %s:\nExpected status code to be %v, but got %v\n",
+				i,
+				c.caseName,
+				c.expectedCode,
+				w.Code,
+			)
+		}
+
+		if w.Body.Len() != 0 {
+			t.Errorf(
+				"testcase %d %s:\nExpected body to be empty, but got\n%v\n",
+				i,
+				c.caseName,
+				w.Body,
+			)
+		}
+	}
+}
+
+func TestRestController_DeleteAccountSession(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	later := now.Add(time.Hour * 24)
+	fakeLogger := log.New(ioutil.Discard, "", log.LstdFlags)
+
+	currentSession := &session.Session{
+		ID:        "12345678-90ab-cdef-0123-4567890abcde",
+		AccountID: 123,
+		CreatedAt: now,
+		ExpiresAt: later,
+	}
+
+	cases := []struct {
+		caseName string
+		// in
+		sessRepo         session.Repository
+		reqPathSessionID string
+		// out
+		expectedCode int
+	}{
+		{
+			caseName: "The session id from path is not a UUID",
+			sessRepo: session.NewFakeRepository(
+				nil,
+				[]session.FakeRepositoryFindByIDResult{
+					{
+						Session: currentSession,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			reqPathSessionID: "abc",
+			// out
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			caseName: "The session is not found",
+			sessRepo: session.NewFakeRepository(
+				nil,
+				[]session.FakeRepositoryFindByIDResult{
+					{
+						Session: currentSession,
+					},
+					{
+						Error: session.ErrNotFound,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			reqPathSessionID: "99999999-90ab-cdef-0123-4567890abcde",
+			// out
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			caseName: "The session belongs to another account",
+			sessRepo: session.NewFakeRepository(
+				nil,
+				[]session.FakeRepositoryFindByIDResult{
+					{
+						Session: currentSession,
+					},
+					{
+						Session: &session.Session{
+							ID:        "99999999-90ab-cdef-0123-4567890abcde",
+							AccountID: 456,
+							CreatedAt: now,
+							ExpiresAt: later,
+						},
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			reqPathSessionID: "99999999-90ab-cdef-0123-4567890abcde",
+			// out
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			caseName: "sessionRepo.Delete failed",
+			sessRepo: session.NewFakeRepository(
+				nil,
+				[]session.FakeRepositoryFindByIDResult{
+					{
+						Session: currentSession,
+					},
+					{
+						Session: &session.Session{
+							ID:        "99999999-90ab-cdef-0123-4567890abcde",
+							AccountID: 123,
+							CreatedAt: now,
+							ExpiresAt: later,
+						},
+					},
+				},
+				nil,
+				nil,
+				[]session.FakeRepositoryDeleteResult{
+					{
+						Error: fmt.Errorf("Delete failed"),
+					},
+				},
+				nil,
+			),
+			reqPathSessionID: "99999999-90ab-cdef-0123-4567890abcde",
+			// out
+			expectedCode: http.StatusInternalServerError,
+		},
+		{
+			caseName: "Successful",
+			sessRepo: session.NewFakeRepository(
+				nil,
+				[]session.FakeRepositoryFindByIDResult{
+					{
+						Session: currentSession,
+					},
+					{
+						Session: &session.Session{
+							ID:        "99999999-90ab-cdef-0123-4567890abcde",
+							AccountID: 123,
+							CreatedAt: now,
+							ExpiresAt: later,
+						},
+					},
+				},
+				nil,
+				nil,
+				[]session.FakeRepositoryDeleteResult{
+					{
+						Error: nil,
+					},
+				},
+				nil,
+			),
+			reqPathSessionID: "99999999-90ab-cdef-0123-4567890abcde",
+			// out
+			expectedCode: http.StatusNoContent,
+		},
+	}
+
+	for i, c := range cases {
+		ctrl := NewRestController(
+			fakeLogger,
+			nil,
+			c.sessRepo,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			time.Now,
+			Options{},
+		)
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodDelete, "/accounts/123/sessions/"+c.reqPathSessionID, nil)
+		req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyPathParams{}, map[string]string{
+			"id":        "123",
+			"sessionID": c.reqPathSessionID,
+		}))
+		req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeySessionID{}, currentSession.ID))
+		ctrl.DeleteAccountSession(w, req)
+
+		if w.Code != c.expectedCode {
+			t.Errorf(
+				"testcase %d %s:\nExpected status code to be %v, but got %v\n",
+				i,
+				c.caseName,
+				c.expectedCode,
+				w.Code,
+			)
+		}
+	}
+}
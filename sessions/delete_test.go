@@ -0,0 +1,162 @@
+package sessions
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/pascont/kit/middleware"
+	"github.com/hypnoglow/pascont/session"
+	"github.com/hypnoglow/pascont/webhook"
+)
+
+func TestRestController_DeleteSession(t *testing.T) {
+	fakeLogger := log.New(ioutil.Discard, "", log.LstdFlags)
+
+	cases := []struct {
+		caseName string
+		// in
+		sessRepo                 session.Repository
+		reqContextSessionIDValue interface{}
+		reqContextPathIDValue    interface{}
+		opts                     Options
+		// out
+		expectedCode int
+	}{
+		{
+			caseName:              "The id from request context is not a string",
+			reqContextPathIDValue: 123,
+			// out
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			caseName:              "There is no session ID from Authorization Header",
+			reqContextPathIDValue: "99999999-90ab-cdef-0123-4567890abcde",
+			// out
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			caseName:                 "Session ids from token and from url path do not match",
+			reqContextSessionIDValue: "12345678-90ab-cdef-0123-4567890abcde",
+			reqContextPathIDValue:    "99999999-90ab-cdef-0123-4567890abcde",
+			// out
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			caseName: "sessionRepo.Delete failed",
+			sessRepo: session.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				nil,
+				[]session.FakeRepositoryDeleteResult{
+					{
+						Error: fmt.Errorf("Delete failed"),
+					},
+				},
+				nil,
+			),
+			reqContextSessionIDValue: "12345678-90ab-cdef-0123-4567890abcde",
+			reqContextPathIDValue:    "12345678-90ab-cdef-0123-4567890abcde",
+			// out
+			expectedCode: http.StatusInternalServerError,
+		},
+		{
+			caseName: "Successful",
+			sessRepo: session.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				nil,
+				[]session.FakeRepositoryDeleteResult{
+					{
+						Error: nil,
+					},
+				},
+				nil,
+			),
+			reqContextSessionIDValue: "12345678-90ab-cdef-0123-4567890abcde",
+			reqContextPathIDValue:    "12345678-90ab-cdef-0123-4567890abcde",
+			// out
+			expectedCode: http.StatusNoContent,
+		},
+		{
+			caseName: "Successful with the revocation emitted",
+			sessRepo: session.NewFakeRepository(
+				nil,
+				[]session.FakeRepositoryFindByIDResult{
+					{
+						Session: &session.Session{
+							ID:        "12345678-90ab-cdef-0123-4567890abcde",
+							AccountID: 123,
+							TenantID:  1,
+						},
+						Error: nil,
+					},
+				},
+				nil,
+				nil,
+				[]session.FakeRepositoryDeleteResult{
+					{
+						Error: nil,
+					},
+				},
+				nil,
+			),
+			reqContextSessionIDValue: "12345678-90ab-cdef-0123-4567890abcde",
+			reqContextPathIDValue:    "12345678-90ab-cdef-0123-4567890abcde",
+			opts: Options{
+				Events: webhook.NewFakeEmitter([]webhook.FakeEmitterMessagesResult{{Messages: nil}}, nil),
+			},
+			// out
+			expectedCode: http.StatusNoContent,
+		},
+	}
+
+	for i, c := range cases {
+		ctrl := NewRestController(
+			fakeLogger,
+			nil,
+			c.sessRepo,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			time.Now,
+			c.opts,
+		)
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodDelete, PathSession, nil)
+		req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyPathID{}, c.reqContextPathIDValue))
+		req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeySessionID{}, c.reqContextSessionIDValue))
+		ctrl.DeleteSession(w, req)
+
+		if w.Code != c.expectedCode {
+			t.Errorf(
+				"testcase %d %s:\nExpected status code to be %v, but got %v\n",
+				i,
+				c.caseName,
+				c.expectedCode,
+				w.Code,
+			)
+		}
+
+		if w.Body.Len() != 0 {
+			t.Errorf(
+				"testcase %d %s:\nExpected body to be empty, but got\n%v\n",
+				i,
+				c.caseName,
+				w.Body,
+			)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package sessions
+
+import (
+	"net/http"
+
+	"github.com/hypnoglow/pascont/account"
+	"github.com/hypnoglow/pascont/kit"
+	"github.com/hypnoglow/pascont/outbox"
+	"github.com/hypnoglow/pascont/webhook"
+)
+
+// emitLoginFailed emits the failed log in to the account for the reason.
+func (c RestController) emitLoginFailed(req *http.Request, acc *account.Account, reason string) {
+	webhook.Emit(c.options.Events, c.logger, webhook.Event{
+		Type:      webhook.EventLoginFailed,
+		TenantID:  acc.TenantID,
+		AccountID: acc.ID,
+		Data: map[string]string{
+			"reason":    reason,
+			"ip":        kit.ClientIP(req),
+			"userAgent": req.UserAgent(),
+		},
+	})
+}
+
+// revokedMessages returns the outbox messages telling the subscribers to events
+// about the revocation of the session of the account, to add them along with it.
+// Empty sessionID means all the sessions of the account.
+func (c RestController) revokedMessages(tenantID, accountID int64, sessionID string) []outbox.Message {
+	return webhook.Messages(c.options.Events, c.logger, webhook.SessionRevokedEvent(tenantID, accountID, sessionID))
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/hypnoglow/pascont/kit"
 	"github.com/hypnoglow/pascont/kit/middleware"
 	"github.com/hypnoglow/pascont/kit/schema"
+	"github.com/hypnoglow/pascont/role"
 	"github.com/hypnoglow/pascont/session"
 )
 
@@ -45,14 +46,44 @@ func (c RestController) GetSession(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
+	active, err := c.isAccountActive(sess.AccountID)
+	if err != nil {
+		c.logger.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if !active {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	// Roles are included so that other services can authorize the account
+	// without a second lookup.
+	roles, err := c.roleRepo.FindByAccount(sess.AccountID)
+	if err != nil {
+		c.logger.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	// Failing to track the session activity must not fail the request.
+	now := time.Now().UTC().Truncate(time.Second)
+	if now.Sub(sess.LastSeenAt) >= lastSeenResolution {
+		if err := c.sessionRepo.Touch(sess.ID, now); err != nil {
+			c.logger.Println(err)
+		}
+	}
+
 	kit.RespondJSON(w, http.StatusOK, schema.NewResultBody(
 		getSessionSchema{
 			// token is not changed, so we can return it as it was passed.
 			Token:     strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer "),
 			ID:        sess.ID,
 			AccountID: sess.AccountID,
+			TenantID:  sess.TenantID,
 			CreatedAt: sess.CreatedAt,
 			ExpiresAt: sess.ExpiresAt,
+			Roles:     role.Names(roles),
 		},
 		nil,
 	))
@@ -62,6 +93,8 @@ type getSessionSchema struct {
 	Token     string    `json:"token"`
 	ID        string    `json:"id"`
 	AccountID int64     `json:"accountID"`
+	TenantID  int64     `json:"tenantID"`
 	CreatedAt time.Time `json:"createdAt"`
 	ExpiresAt time.Time `json:"expiresAt"`
+	Roles     []string  `json:"roles"`
 }
@@ -0,0 +1,63 @@
+package sessions
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/hypnoglow/pascont/kit"
+	"github.com/hypnoglow/pascont/kit/form"
+	"github.com/hypnoglow/pascont/kit/schema"
+	"github.com/hypnoglow/pascont/session"
+)
+
+// GetAccountSessions is a handler for:
+// GET /accounts/:id/sessions
+func (c RestController) GetAccountSessions(w http.ResponseWriter, req *http.Request) {
+	current, status := session.AccountSession(req, c.sessionRepo, c.logger)
+	if current == nil {
+		w.WriteHeader(status)
+		return
+	}
+
+	var pageForm form.PaginationForm
+	form.PopulatePaginationFormFromQuery(req.URL.Query(), &pageForm)
+	if !pageForm.Validate() {
+		kit.RespondWithFormErrors(w, http.StatusBadRequest, pageForm.ValidationErrors())
+		return
+	}
+
+	sessions, total, err := c.sessionRepo.ListByAccount(current.AccountID, pageForm.Offset, pageForm.Limit)
+	if err != nil {
+		c.logger.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	results := make([]accountSessionSchema, len(sessions))
+	for i, sess := range sessions {
+		results[i] = accountSessionSchema{
+			ID:         sess.ID,
+			Current:    sess.ID == current.ID,
+			CreatedAt:  sess.CreatedAt,
+			ExpiresAt:  sess.ExpiresAt,
+			IP:         sess.IP,
+			UserAgent:  sess.UserAgent,
+			LastSeenAt: sess.LastSeenAt,
+		}
+	}
+
+	kit.RespondJSON(w, http.StatusOK, schema.NewResultsBody(
+		results,
+		schema.NewPaginationMeta(pageForm.Limit, pageForm.Offset, total),
+	))
+}
+
+type accountSessionSchema struct {
+	ID         string    `json:"id"`
+	Current    bool      `json:"current"`
+	CreatedAt  time.Time `json:"createdAt"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+	IP         string    `json:"ip"`
+	UserAgent  string    `json:"userAgent"`
+	LastSeenAt time.Time `json:"lastSeenAt"`
+}
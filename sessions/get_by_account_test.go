@@ -0,0 +1,225 @@
+package sessions
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/pascont/kit/middleware"
+	"github.com/hypnoglow/pascont/session"
+)
+
+func TestRestController_GetAccountSessions(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	later := now.Add(time.Hour * 24)
+	fakeLogger := log.New(ioutil.Discard, "", log.LstdFlags)
+
+	currentSession := &session.Session{
+		ID:         "12345678-90ab-cdef-0123-4567890abcde",
+		AccountID:  123,
+		CreatedAt:  now,
+		ExpiresAt:  later,
+		IP:         "127.0.0.1",
+		UserAgent:  "curl/7.54.0",
+		LastSeenAt: now,
+	}
+	otherSession := &session.Session{
+		ID:         "99999999-90ab-cdef-0123-4567890abcde",
+		AccountID:  123,
+		CreatedAt:  now,
+		ExpiresAt:  later,
+		IP:         "10.0.0.1",
+		UserAgent:  "Mozilla/5.0",
+		LastSeenAt: now,
+	}
+
+	cases := []struct {
+		caseName string
+		// in
+		sessRepo                 session.Repository
+		reqContextSessionIDValue interface{}
+		reqPathID                string
+		reqQuery                 string
+		// out
+		expectedCode int
+		expectedBody *bytes.Buffer
+	}{
+		{
+			caseName:  "There is no session ID from Authorization Header",
+			reqPathID: "123",
+			// out
+			expectedCode: http.StatusUnauthorized,
+			expectedBody: bytes.NewBuffer(nil),
+		},
+		{
+			caseName: "Invalid pagination",
+			sessRepo: session.NewFakeRepository(
+				nil,
+				[]session.FakeRepositoryFindByIDResult{
+					{
+						Session: currentSession,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			reqContextSessionIDValue: "12345678-90ab-cdef-0123-4567890abcde",
+			reqPathID:                "123",
+			reqQuery:                 "?limit=0",
+			// out
+			expectedCode: http.StatusBadRequest,
+			expectedBody: bytes.NewBufferString(`{
+				"errors":[
+					{
+						"message":"Limit must be between 1 and 100",
+						"field":"limit",
+						"value":0
+					}
+				]
+			}`),
+		},
+		{
+			caseName: "sessionRepo.ListByAccount failed",
+			sessRepo: session.NewFakeRepository(
+				nil,
+				[]session.FakeRepositoryFindByIDResult{
+					{
+						Session: currentSession,
+					},
+				},
+				[]session.FakeRepositoryListByAccountResult{
+					{
+						Error: fmt.Errorf("ListByAccount failed"),
+					},
+				},
+				nil,
+				nil,
+				nil,
+			),
+			reqContextSessionIDValue: "12345678-90ab-cdef-0123-4567890abcde",
+			reqPathID:                "123",
+			// out
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: bytes.NewBuffer(nil),
+		},
+		{
+			caseName: "Successful",
+			sessRepo: session.NewFakeRepository(
+				nil,
+				[]session.FakeRepositoryFindByIDResult{
+					{
+						Session: currentSession,
+					},
+				},
+				[]session.FakeRepositoryListByAccountResult{
+					{
+						Sessions: []*session.Session{currentSession, otherSession},
+						Total:    3,
+					},
+				},
+				nil,
+				nil,
+				nil,
+			),
+			reqContextSessionIDValue: "12345678-90ab-cdef-0123-4567890abcde",
+			reqPathID:                "123",
+			reqQuery:                 "?limit=2",
+			// out
+			expectedCode: http.StatusOK,
+			expectedBody: bytes.NewBufferString(fmt.Sprintf(`{
+				"results":[
+					{
+						"id":"12345678-90ab-cdef-0123-4567890abcde",
+						"current":true,
+						"createdAt":"%[1]s",
+						"expiresAt":"%[2]s",
+						"ip":"127.0.0.1",
+						"userAgent":"curl/7.54.0",
+						"lastSeenAt":"%[1]s"
+					},
+					{
+						"id":"99999999-90ab-cdef-0123-4567890abcde",
+						"current":false,
+						"createdAt":"%[1]s",
+						"expiresAt":"%[2]s",
+						"ip":"10.0.0.1",
+						"userAgent":"Mozilla/5.0",
+						"lastSeenAt":"%[1]s"
+					}
+				],
+				"meta":{
+					"limit":2,
+					"offset":0,
+					"total":3
+				}
+			}`, now.Format(time.RFC3339), later.Format(time.RFC3339))),
+		},
+	}
+
+	for i, c := range cases {
+		ctrl := NewRestController(
+			fakeLogger,
+			nil,
+			c.sessRepo,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			time.Now,
+			Options{},
+		)
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/accounts/"+c.reqPathID+"/sessions"+c.reqQuery, nil)
+		req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyPathParams{}, map[string]string{"id": c.reqPathID}))
+		req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeySessionID{}, c.reqContextSessionIDValue))
+		ctrl.GetAccountSessions(w, req)
+
+		if w.Code != c.expectedCode {
+			t.Errorf(
+				"testcase %d %s:\nExpected status code to be %v, but got %v\n",
+				i,
+				c.caseName,
+				c.expectedCode,
+				w.Code,
+			)
+		}
+
+		// To make possible pretty formatting in test cases, we use json.Compact:
+		b := c.expectedBody.Bytes()
+		if b != nil {
+			c.expectedBody.Reset()
+			err := json.Compact(c.expectedBody, b)
+			if err != nil {
+				t.Errorf(
+					"testcase %d %s:\nFailed to compact JSON body: %s",
+					i,
+					c.caseName,
+					err,
+				)
+			}
+		}
+
+		if !bytes.Equal(w.Body.Bytes(), c.expectedBody.Bytes()) {
+			t.Errorf(
+				"testcase %d %s:\nExpected body to be\n%v\nbut got\n%v\n",
+				i,
+				c.caseName,
+				c.expectedBody,
+				w.Body,
+			)
+		}
+	}
+}
@@ -13,19 +13,24 @@ import (
 	"testing"
 	"time"
 
+	"github.com/hypnoglow/pascont/account"
 	"github.com/hypnoglow/pascont/kit/middleware"
+	"github.com/hypnoglow/pascont/role"
 	"github.com/hypnoglow/pascont/session"
 )
 
 func TestRestController_GetSession(t *testing.T) {
 	now := time.Now().UTC().Truncate(time.Second)
+	earlier := now.Add(-time.Hour)
 	later := now.Add(time.Hour * 24)
 	fakeLogger := log.New(ioutil.Discard, "", log.LstdFlags)
 
 	cases := []struct {
 		caseName string
 		// in
+		accRepo                  account.Repository
 		sessRepo                 session.Repository
+		roleRepo                 role.Repository
 		reqContextSessionIDValue interface{}
 		reqContextPathIDValue    interface{}
 		reqAuthHeader            string
@@ -65,6 +70,10 @@ func TestRestController_GetSession(t *testing.T) {
 						Error: session.ErrNotFound,
 					},
 				},
+				nil,
+				nil,
+				nil,
+				nil,
 			),
 			reqContextSessionIDValue: "12345678-90ab-cdef-0123-4567890abcde",
 			reqContextPathIDValue:    "12345678-90ab-cdef-0123-4567890abcde",
@@ -81,6 +90,10 @@ func TestRestController_GetSession(t *testing.T) {
 						Error: session.ErrExpired,
 					},
 				},
+				nil,
+				nil,
+				nil,
+				nil,
 			),
 			reqContextSessionIDValue: "12345678-90ab-cdef-0123-4567890abcde",
 			reqContextPathIDValue:    "12345678-90ab-cdef-0123-4567890abcde",
@@ -97,6 +110,168 @@ func TestRestController_GetSession(t *testing.T) {
 						Error: fmt.Errorf("FindByID failed"),
 					},
 				},
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			reqContextSessionIDValue: "12345678-90ab-cdef-0123-4567890abcde",
+			reqContextPathIDValue:    "12345678-90ab-cdef-0123-4567890abcde",
+			// out
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: bytes.NewBuffer(nil),
+		},
+		{
+			caseName: "Session of not active account is not valid",
+			accRepo: account.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				[]account.FakeRepositoryFindByIDResult{
+					{
+						Account: &account.Account{
+							ID:     123,
+							Status: account.StatusDisabled,
+						},
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			sessRepo: session.NewFakeRepository(
+				nil,
+				[]session.FakeRepositoryFindByIDResult{
+					{
+						Session: &session.Session{
+							ID:         "12345678-90ab-cdef-0123-4567890abcde",
+							AccountID:  123,
+							TenantID:   1,
+							CreatedAt:  now,
+							ExpiresAt:  later,
+							LastSeenAt: now,
+						},
+						Error: nil,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			reqContextSessionIDValue: "12345678-90ab-cdef-0123-4567890abcde",
+			reqContextPathIDValue:    "12345678-90ab-cdef-0123-4567890abcde",
+			// out
+			expectedCode: http.StatusNotFound,
+			expectedBody: bytes.NewBuffer(nil),
+		},
+		{
+			caseName: "accountRepo.FindByID failed",
+			accRepo: account.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				[]account.FakeRepositoryFindByIDResult{
+					{
+						Error: fmt.Errorf("FindByID failed"),
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			sessRepo: session.NewFakeRepository(
+				nil,
+				[]session.FakeRepositoryFindByIDResult{
+					{
+						Session: &session.Session{
+							ID:         "12345678-90ab-cdef-0123-4567890abcde",
+							AccountID:  123,
+							TenantID:   1,
+							CreatedAt:  now,
+							ExpiresAt:  later,
+							LastSeenAt: now,
+						},
+						Error: nil,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			reqContextSessionIDValue: "12345678-90ab-cdef-0123-4567890abcde",
+			reqContextPathIDValue:    "12345678-90ab-cdef-0123-4567890abcde",
+			// out
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: bytes.NewBuffer(nil),
+		},
+		{
+			caseName: "roleRepo.FindByAccount failed",
+			accRepo: account.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				[]account.FakeRepositoryFindByIDResult{
+					{
+						Account: &account.Account{
+							ID:     123,
+							Status: account.StatusActive,
+						},
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			sessRepo: session.NewFakeRepository(
+				nil,
+				[]session.FakeRepositoryFindByIDResult{
+					{
+						Session: &session.Session{
+							ID:         "12345678-90ab-cdef-0123-4567890abcde",
+							AccountID:  123,
+							TenantID:   1,
+							CreatedAt:  now,
+							ExpiresAt:  later,
+							LastSeenAt: now,
+						},
+						Error: nil,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			roleRepo: role.NewFakeRepository(
+				nil,
+				nil,
+				[]role.FakeRepositoryFindByAccountResult{
+					{
+						Error: fmt.Errorf("FindByAccount failed"),
+					},
+				},
+				nil,
+				nil,
 			),
 			reqContextSessionIDValue: "12345678-90ab-cdef-0123-4567890abcde",
 			reqContextPathIDValue:    "12345678-90ab-cdef-0123-4567890abcde",
@@ -106,19 +281,60 @@ func TestRestController_GetSession(t *testing.T) {
 		},
 		{
 			caseName: "Successful",
+			accRepo: account.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				[]account.FakeRepositoryFindByIDResult{
+					{
+						Account: &account.Account{
+							ID:     123,
+							Status: account.StatusActive,
+						},
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
 			sessRepo: session.NewFakeRepository(
 				nil,
 				[]session.FakeRepositoryFindByIDResult{
 					{
 						Session: &session.Session{
-							ID:        "12345678-90ab-cdef-0123-4567890abcde",
-							AccountID: 123,
-							CreatedAt: now,
-							ExpiresAt: later,
+							ID:         "12345678-90ab-cdef-0123-4567890abcde",
+							AccountID:  123,
+							TenantID:   1,
+							CreatedAt:  now,
+							ExpiresAt:  later,
+							LastSeenAt: now,
 						},
 						Error: nil,
 					},
 				},
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			roleRepo: role.NewFakeRepository(
+				nil,
+				nil,
+				[]role.FakeRepositoryFindByAccountResult{
+					{
+						Roles: []*role.Role{
+							role.NewRole("admin", []role.Permission{"accounts:manage"}),
+						},
+					},
+				},
+				nil,
+				nil,
 			),
 			reqContextSessionIDValue: "12345678-90ab-cdef-0123-4567890abcde",
 			reqContextPathIDValue:    "12345678-90ab-cdef-0123-4567890abcde",
@@ -129,8 +345,160 @@ func TestRestController_GetSession(t *testing.T) {
 					"token":"",
 					"id":"12345678-90ab-cdef-0123-4567890abcde",
 					"accountID":123,
+					"tenantID":1,
 					"createdAt":"%s",
-					"expiresAt":"%s"
+					"expiresAt":"%s",
+					"roles":["admin"]
+				}
+			}`, now.Format(time.RFC3339), later.Format(time.RFC3339))),
+		},
+		{
+			caseName: "Successful with LastSeenAt updated",
+			accRepo: account.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				[]account.FakeRepositoryFindByIDResult{
+					{
+						Account: &account.Account{
+							ID:     123,
+							Status: account.StatusActive,
+						},
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			sessRepo: session.NewFakeRepository(
+				nil,
+				[]session.FakeRepositoryFindByIDResult{
+					{
+						Session: &session.Session{
+							ID:         "12345678-90ab-cdef-0123-4567890abcde",
+							AccountID:  123,
+							TenantID:   1,
+							CreatedAt:  now,
+							ExpiresAt:  later,
+							LastSeenAt: earlier,
+						},
+						Error: nil,
+					},
+				},
+				nil,
+				[]session.FakeRepositoryTouchResult{
+					{
+						Error: nil,
+					},
+				},
+				nil,
+				nil,
+			),
+			roleRepo: role.NewFakeRepository(
+				nil,
+				nil,
+				[]role.FakeRepositoryFindByAccountResult{
+					{
+						Roles: nil,
+					},
+				},
+				nil,
+				nil,
+			),
+			reqContextSessionIDValue: "12345678-90ab-cdef-0123-4567890abcde",
+			reqContextPathIDValue:    "12345678-90ab-cdef-0123-4567890abcde",
+			// out
+			expectedCode: http.StatusOK,
+			expectedBody: bytes.NewBufferString(fmt.Sprintf(`{
+				"result": {
+					"token":"",
+					"id":"12345678-90ab-cdef-0123-4567890abcde",
+					"accountID":123,
+					"tenantID":1,
+					"createdAt":"%s",
+					"expiresAt":"%s",
+					"roles":[]
+				}
+			}`, now.Format(time.RFC3339), later.Format(time.RFC3339))),
+		},
+		{
+			caseName: "Failed sessionRepo.Touch should not fail the request",
+			accRepo: account.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				[]account.FakeRepositoryFindByIDResult{
+					{
+						Account: &account.Account{
+							ID:     123,
+							Status: account.StatusActive,
+						},
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			sessRepo: session.NewFakeRepository(
+				nil,
+				[]session.FakeRepositoryFindByIDResult{
+					{
+						Session: &session.Session{
+							ID:         "12345678-90ab-cdef-0123-4567890abcde",
+							AccountID:  123,
+							TenantID:   1,
+							CreatedAt:  now,
+							ExpiresAt:  later,
+							LastSeenAt: earlier,
+						},
+						Error: nil,
+					},
+				},
+				nil,
+				[]session.FakeRepositoryTouchResult{
+					{
+						Error: fmt.Errorf("Touch failed"),
+					},
+				},
+				nil,
+				nil,
+			),
+			roleRepo: role.NewFakeRepository(
+				nil,
+				nil,
+				[]role.FakeRepositoryFindByAccountResult{
+					{
+						Roles: nil,
+					},
+				},
+				nil,
+				nil,
+			),
+			reqContextSessionIDValue: "12345678-90ab-cdef-0123-4567890abcde",
+			reqContextPathIDValue:    "12345678-90ab-cdef-0123-4567890abcde",
+			// out
+			expectedCode: http.StatusOK,
+			expectedBody: bytes.NewBufferString(fmt.Sprintf(`{
+				"result": {
+					"token":"",
+					"id":"12345678-90ab-cdef-0123-4567890abcde",
+					"accountID":123,
+					"tenantID":1,
+					"createdAt":"%s",
+					"expiresAt":"%s",
+					"roles":[]
 				}
 			}`, now.Format(time.RFC3339), later.Format(time.RFC3339))),
 		},
@@ -139,12 +507,17 @@ func TestRestController_GetSession(t *testing.T) {
 	for i, c := range cases {
 		ctrl := NewRestController(
 			fakeLogger,
-			nil,
+			c.accRepo,
 			c.sessRepo,
+			c.roleRepo,
+			nil,
+			nil,
+			nil,
 			nil,
 			nil,
 			nil,
 			nil,
+			time.Now,
 			Options{},
 		)
 		w := httptest.NewRecorder()
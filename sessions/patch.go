@@ -53,7 +53,28 @@ func (c RestController) PatchSession(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
+	acc, err := c.activeAccount(sess.AccountID)
+	if err != nil {
+		c.logger.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if acc == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	if c.isLimited(*acc) {
+		kit.RespondWithError(w, http.StatusForbidden, schema.NewError(
+			"Account is not verified",
+			"",
+			nil,
+		))
+		return
+	}
+
 	sess.ExpiresAt = sessForm.ExpiresAt.UTC()
+	sess.LastSeenAt = time.Now().UTC().Truncate(time.Second)
 
 	if err := c.sessionRepo.Save(*sess); err != nil {
 		c.logger.Println(err)
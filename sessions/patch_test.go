@@ -14,6 +14,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/hypnoglow/pascont/account"
 	"github.com/hypnoglow/pascont/kit/form"
 	"github.com/hypnoglow/pascont/kit/middleware"
 	"github.com/hypnoglow/pascont/notary"
@@ -89,9 +90,11 @@ func TestRestController_PatchSession(t *testing.T) {
 	cases := []struct {
 		caseName string
 		// in
+		accRepo                  account.Repository
 		sessRepo                 session.Repository
 		notary                   notary.Notary
 		packer                   packer.Packer
+		opts                     Options
 		reqContextSessionIDValue interface{}
 		reqContextPathIDValue    interface{}
 		reqBody                  io.Reader
@@ -153,6 +156,10 @@ func TestRestController_PatchSession(t *testing.T) {
 						Error: session.ErrNotFound,
 					},
 				},
+				nil,
+				nil,
+				nil,
+				nil,
 			),
 			reqContextSessionIDValue: "12345678-90ab-cdef-0123-4567890abcde",
 			reqContextPathIDValue:    "12345678-90ab-cdef-0123-4567890abcde",
@@ -172,6 +179,10 @@ func TestRestController_PatchSession(t *testing.T) {
 						Error: session.ErrExpired,
 					},
 				},
+				nil,
+				nil,
+				nil,
+				nil,
 			),
 			reqContextSessionIDValue: "12345678-90ab-cdef-0123-4567890abcde",
 			reqContextPathIDValue:    "12345678-90ab-cdef-0123-4567890abcde",
@@ -191,6 +202,10 @@ func TestRestController_PatchSession(t *testing.T) {
 						Error: fmt.Errorf("FindByID failed"),
 					},
 				},
+				nil,
+				nil,
+				nil,
+				nil,
 			),
 			reqContextSessionIDValue: "12345678-90ab-cdef-0123-4567890abcde",
 			reqContextPathIDValue:    "12345678-90ab-cdef-0123-4567890abcde",
@@ -203,6 +218,28 @@ func TestRestController_PatchSession(t *testing.T) {
 		},
 		{
 			caseName: "sessionRepo.Save failed",
+			accRepo: account.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				[]account.FakeRepositoryFindByIDResult{
+					{
+						Account: &account.Account{
+							ID:     123,
+							Status: account.StatusActive,
+						},
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
 			sessRepo: session.NewFakeRepository(
 				[]session.FakeRepositorySaveResult{
 					{
@@ -220,6 +257,10 @@ func TestRestController_PatchSession(t *testing.T) {
 						Error: nil,
 					},
 				},
+				nil,
+				nil,
+				nil,
+				nil,
 			),
 			reqContextSessionIDValue: "12345678-90ab-cdef-0123-4567890abcde",
 			reqContextPathIDValue:    "12345678-90ab-cdef-0123-4567890abcde",
@@ -248,6 +289,28 @@ func TestRestController_PatchSession(t *testing.T) {
 				},
 				nil,
 			),
+			accRepo: account.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				[]account.FakeRepositoryFindByIDResult{
+					{
+						Account: &account.Account{
+							ID:     123,
+							Status: account.StatusActive,
+						},
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
 			sessRepo: session.NewFakeRepository(
 				[]session.FakeRepositorySaveResult{
 					{
@@ -265,6 +328,181 @@ func TestRestController_PatchSession(t *testing.T) {
 						Error: nil,
 					},
 				},
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			reqContextSessionIDValue: "12345678-90ab-cdef-0123-4567890abcde",
+			reqContextPathIDValue:    "12345678-90ab-cdef-0123-4567890abcde",
+			reqBody: bytes.NewBufferString(fmt.Sprintf(`{
+				"expiresAt":"%s"
+			}`, later.Format(time.RFC3339))),
+			// out
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: bytes.NewBuffer(nil),
+		},
+		{
+			caseName: "Session of not active account is not valid",
+			accRepo: account.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				[]account.FakeRepositoryFindByIDResult{
+					{
+						Account: &account.Account{
+							ID:     123,
+							Status: account.StatusDisabled,
+						},
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			sessRepo: session.NewFakeRepository(
+				[]session.FakeRepositorySaveResult{
+					{
+						Error: nil,
+					},
+				},
+				[]session.FakeRepositoryFindByIDResult{
+					{
+						Session: &session.Session{
+							ID:        "12345678-90ab-cdef-0123-4567890abcde",
+							AccountID: 123,
+							CreatedAt: now,
+							ExpiresAt: later,
+						},
+						Error: nil,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			reqContextSessionIDValue: "12345678-90ab-cdef-0123-4567890abcde",
+			reqContextPathIDValue:    "12345678-90ab-cdef-0123-4567890abcde",
+			reqBody: bytes.NewBufferString(fmt.Sprintf(`{
+				"expiresAt":"%s"
+			}`, later.Format(time.RFC3339))),
+			// out
+			expectedCode: http.StatusNotFound,
+			expectedBody: bytes.NewBuffer(nil),
+		},
+		{
+			caseName: "Limited session of account pending verification can not be extended",
+			accRepo: account.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				[]account.FakeRepositoryFindByIDResult{
+					{
+						Account: &account.Account{
+							ID:     123,
+							Status: account.StatusActive,
+						},
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			sessRepo: session.NewFakeRepository(
+				nil,
+				[]session.FakeRepositoryFindByIDResult{
+					{
+						Session: &session.Session{
+							ID:        "12345678-90ab-cdef-0123-4567890abcde",
+							AccountID: 123,
+							CreatedAt: now,
+							ExpiresAt: now.Add(time.Hour),
+						},
+						Error: nil,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			opts: Options{
+				Unverified:                UnverifiedLimit,
+				UnverifiedSessionDuration: time.Hour,
+			},
+			reqContextSessionIDValue: "12345678-90ab-cdef-0123-4567890abcde",
+			reqContextPathIDValue:    "12345678-90ab-cdef-0123-4567890abcde",
+			reqBody: bytes.NewBufferString(fmt.Sprintf(`{
+				"expiresAt":"%s"
+			}`, later.Format(time.RFC3339))),
+			// out
+			expectedCode: http.StatusForbidden,
+			expectedHeaderMap: http.Header{
+				"Content-Type": []string{"application/json; charset=utf-8"},
+			},
+			expectedBody: bytes.NewBufferString(`{
+				"errors":[
+					{
+						"message":"Account is not verified"
+					}
+				]
+			}`),
+		},
+		{
+			caseName: "accountRepo.FindByID failed",
+			accRepo: account.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				[]account.FakeRepositoryFindByIDResult{
+					{
+						Error: fmt.Errorf("FindByID failed"),
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			sessRepo: session.NewFakeRepository(
+				[]session.FakeRepositorySaveResult{
+					{
+						Error: nil,
+					},
+				},
+				[]session.FakeRepositoryFindByIDResult{
+					{
+						Session: &session.Session{
+							ID:        "12345678-90ab-cdef-0123-4567890abcde",
+							AccountID: 123,
+							CreatedAt: now,
+							ExpiresAt: later,
+						},
+						Error: nil,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
 			),
 			reqContextSessionIDValue: "12345678-90ab-cdef-0123-4567890abcde",
 			reqContextPathIDValue:    "12345678-90ab-cdef-0123-4567890abcde",
@@ -294,6 +532,28 @@ func TestRestController_PatchSession(t *testing.T) {
 				},
 				nil,
 			),
+			accRepo: account.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				[]account.FakeRepositoryFindByIDResult{
+					{
+						Account: &account.Account{
+							ID:     123,
+							Status: account.StatusActive,
+						},
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
 			sessRepo: session.NewFakeRepository(
 				[]session.FakeRepositorySaveResult{
 					{
@@ -311,6 +571,10 @@ func TestRestController_PatchSession(t *testing.T) {
 						Error: nil,
 					},
 				},
+				nil,
+				nil,
+				nil,
+				nil,
 			),
 			reqContextSessionIDValue: "12345678-90ab-cdef-0123-4567890abcde",
 			reqContextPathIDValue:    "12345678-90ab-cdef-0123-4567890abcde",
@@ -334,13 +598,18 @@ func TestRestController_PatchSession(t *testing.T) {
 	for i, c := range cases {
 		ctrl := NewRestController(
 			fakeLogger,
-			nil,
+			c.accRepo,
 			c.sessRepo,
+			nil,
+			nil,
+			nil,
+			nil,
 			c.notary,
 			c.packer,
 			nil,
 			nil,
-			Options{},
+			time.Now,
+			c.opts,
 		)
 		w := httptest.NewRecorder()
 		req := httptest.NewRequest(http.MethodPatch, PathSession, c.reqBody)
@@ -1,19 +1,34 @@
 package sessions
 
 import (
+	"fmt"
+	"math"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/hypnoglow/pascont/account"
+	"github.com/hypnoglow/pascont/hasher"
 	"github.com/hypnoglow/pascont/kit"
 	"github.com/hypnoglow/pascont/kit/form"
+	"github.com/hypnoglow/pascont/kit/middleware"
 	"github.com/hypnoglow/pascont/kit/schema"
+	"github.com/hypnoglow/pascont/notifier"
 	"github.com/hypnoglow/pascont/session"
+	"github.com/hypnoglow/pascont/totp"
+	"github.com/hypnoglow/pascont/webhook"
 )
 
 // PostSessions is a handler for:
 // POST /sessions
 func (c RestController) PostSessions(w http.ResponseWriter, req *http.Request) {
+	// Accounts are looked up in the tenant the request is made to.
+	tenantID, ok := req.Context().Value(middleware.ContextKeyTenantID{}).(int64)
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
 	var sessForm postSessionForm
 	form.PopulateFormFromJSON(req.Body, &sessForm)
 	if !sessForm.Validate() {
@@ -22,7 +37,7 @@ func (c RestController) PostSessions(w http.ResponseWriter, req *http.Request) {
 	}
 
 	// Name+PasswordHash pair must be correct.
-	acc, passwordHash, err := c.accountRepo.FindWithPasswordHashByUsername(sessForm.Name)
+	acc, passwordHash, err := c.accountRepo.FindWithPasswordHashByUsername(tenantID, sessForm.Name)
 	if err != nil {
 		if err == account.ErrNotFound {
 			w.WriteHeader(http.StatusUnauthorized)
@@ -33,13 +48,144 @@ func (c RestController) PostSessions(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
+	// Passwords are not checked until the lock expires.
+	now := c.clock()
+	if acc.IsLocked(now) {
+		c.emitLoginFailed(req, acc, webhook.LoginFailedLocked)
+		RespondLocked(w, acc.LockedUntil.Sub(now))
+		return
+	}
+
 	if err := c.hasher.CompareHashWithPassword(passwordHash, []byte(sessForm.Password)); err != nil {
-		w.WriteHeader(http.StatusUnauthorized)
+		c.emitLoginFailed(req, acc, webhook.LoginFailedPassword)
+		c.respondFailedLogin(w, acc.ID, now)
+		return
+	}
+
+	// Only active accounts can log in.
+	if !acc.IsActive() {
+		c.emitLoginFailed(req, acc, webhook.LoginFailedInactive)
+		kit.RespondWithError(w, http.StatusForbidden, schema.NewError(
+			fmt.Sprintf("Account is %s", acc.Status),
+			"",
+			nil,
+		))
 		return
 	}
 
-	sess := session.CreateSession(c.uuidProducer, acc.ID, sessionDefaultDuration)
-	if err := c.sessionRepo.Save(*sess); err != nil {
+	if c.options.Unverified == UnverifiedBlock && !acc.IsVerified() {
+		c.emitLoginFailed(req, acc, webhook.LoginFailedUnverified)
+		kit.RespondWithError(w, http.StatusForbidden, schema.NewError(
+			"Account is not verified",
+			"",
+			nil,
+		))
+		return
+	}
+
+	c.rehashPassword(acc, passwordHash, sessForm.Password)
+
+	// Accounts with TOTP enabled must complete the login with a code,
+	// so the failed logins are not reset until then.
+	e, err := c.totpRepo.FindByAccount(acc.ID)
+	if err != nil && err != totp.ErrNotFound {
+		c.logger.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if err == nil && e.IsConfirmed() {
+		challenge := totp.NewChallenge(c.uuidProducer, acc.ID, now)
+		err := c.challengeRepo.Save(*challenge)
+		if err == totp.ErrTooManyChallenges {
+			w.Header().Set("Retry-After", strconv.Itoa(int(totp.ChallengeDuration.Seconds())))
+			kit.RespondWithError(w, http.StatusTooManyRequests, schema.NewError(
+				"Too many logins to complete with a code, try again later",
+				"",
+				nil,
+			))
+			return
+		}
+		if err != nil {
+			c.logger.Println(err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		kit.RespondJSON(w, http.StatusAccepted, schema.NewResultBody(
+			postSessionChallengeSchema{
+				ChallengeID: challenge.ID,
+				ExpiresAt:   challenge.ExpiresAt,
+			},
+			nil,
+		))
+		return
+	}
+
+	c.issueSession(w, req, acc)
+}
+
+// rehashPassword saves a new hash of the password of the account,
+// if the current hash is produced with other algorithm or parameters.
+// Errors are only logged, so they do not fail the login.
+func (c RestController) rehashPassword(acc *account.Account, passwordHash []byte, password string) {
+	rehasher, ok := c.hasher.(hasher.Rehasher)
+	if !ok || !rehasher.NeedsRehash(passwordHash) {
+		return
+	}
+
+	newPasswordHash, err := rehasher.GenerateHashFromPassword([]byte(password))
+	if err != nil {
+		c.logger.Println(err)
+		return
+	}
+
+	if err := c.accountRepo.Save(*acc, newPasswordHash); err != nil {
+		c.logger.Println(err)
+	}
+}
+
+// issueSession resets failed logins of the account which passed the login
+// and responds with a new session.
+func (c RestController) issueSession(w http.ResponseWriter, req *http.Request, acc *account.Account) {
+	if acc.FailedLoginAttempts > 0 {
+		if err := c.accountRepo.ResetFailedLogins(acc.ID); err != nil {
+			c.logger.Println(err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+	}
+
+	duration := SessionDefaultDuration
+	if c.isLimited(*acc) {
+		duration = c.options.UnverifiedSessionDuration
+	}
+
+	sess := session.CreateSession(c.uuidProducer, acc.ID, duration)
+	sess.TenantID = acc.TenantID
+	sess.IP = kit.ClientIP(req)
+	sess.UserAgent = session.TruncateUserAgent(req.UserAgent())
+	messages := c.options.Outbox.Messages(notifier.Notification{
+		Kind:      notifier.KindNewLogin,
+		TenantID:  acc.TenantID,
+		AccountID: acc.ID,
+		Recipient: acc.Name,
+		Params: map[string]string{
+			"ip":        sess.IP,
+			"userAgent": sess.UserAgent,
+			"createdAt": sess.CreatedAt.Format(time.RFC3339),
+		},
+	})
+	messages = append(messages, webhook.Messages(c.options.Events, c.logger, webhook.Event{
+		Type:      webhook.EventLoginSucceeded,
+		TenantID:  acc.TenantID,
+		AccountID: acc.ID,
+		Data: map[string]string{
+			"sessionID": sess.ID,
+			"ip":        sess.IP,
+			"userAgent": sess.UserAgent,
+		},
+	})...)
+	if err := c.sessionRepo.Save(*sess, messages...); err != nil {
 		c.logger.Println(err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
@@ -57,6 +203,7 @@ func (c RestController) PostSessions(w http.ResponseWriter, req *http.Request) {
 			Token:     token,
 			ID:        sess.ID,
 			AccountID: sess.AccountID,
+			TenantID:  sess.TenantID,
 			CreatedAt: sess.CreatedAt,
 			ExpiresAt: sess.ExpiresAt,
 		},
@@ -64,6 +211,43 @@ func (c RestController) PostSessions(w http.ResponseWriter, req *http.Request) {
 	))
 }
 
+// respondFailedLogin registers a failed login of the account at the moment now
+// and responds whether the account got locked.
+func (c RestController) respondFailedLogin(w http.ResponseWriter, accountID int64, now time.Time) {
+	if c.options.LockoutThreshold <= 0 {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	locked, err := c.accountRepo.RegisterFailedLogin(
+		accountID,
+		c.options.LockoutThreshold,
+		now.Add(c.options.LockoutDuration),
+	)
+	if err != nil {
+		c.logger.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if locked {
+		RespondLocked(w, c.options.LockoutDuration)
+		return
+	}
+
+	w.WriteHeader(http.StatusUnauthorized)
+}
+
+// RespondLocked responds that the account is locked for logins
+// and can be retried after d.
+func RespondLocked(w http.ResponseWriter, d time.Duration) {
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(d.Seconds()))))
+	kit.RespondWithError(w, http.StatusTooManyRequests, schema.NewError(
+		"Account is temporarily locked due to too many failed login attempts",
+		"",
+		nil,
+	))
+}
+
 type postSessionForm struct {
 	form.BaseForm
 	Name     string `json:"name"`
@@ -86,6 +270,12 @@ type postSessionSchema struct {
 	Token     string    `json:"token"`
 	ID        string    `json:"id"`
 	AccountID int64     `json:"accountID"`
+	TenantID  int64     `json:"tenantID"`
 	CreatedAt time.Time `json:"createdAt"`
 	ExpiresAt time.Time `json:"expiresAt"`
 }
+
+type postSessionChallengeSchema struct {
+	ChallengeID string    `json:"challengeID"`
+	ExpiresAt   time.Time `json:"expiresAt"`
+}
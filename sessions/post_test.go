@@ -2,6 +2,7 @@ package sessions
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -14,11 +15,17 @@ import (
 	"time"
 
 	"github.com/hypnoglow/pascont/account"
+	"github.com/hypnoglow/pascont/clock"
 	"github.com/hypnoglow/pascont/hasher"
 	"github.com/hypnoglow/pascont/kit/form"
+	"github.com/hypnoglow/pascont/kit/middleware"
 	"github.com/hypnoglow/pascont/notary"
+	"github.com/hypnoglow/pascont/notifier"
+	"github.com/hypnoglow/pascont/outbox"
 	"github.com/hypnoglow/pascont/packer"
 	"github.com/hypnoglow/pascont/session"
+	"github.com/hypnoglow/pascont/totp"
+	"github.com/hypnoglow/pascont/webhook"
 )
 
 func TestPostSessionForm_Validate(t *testing.T) {
@@ -82,21 +89,49 @@ func TestRestController_PostSessions(t *testing.T) {
 	uuidProducer := func() string {
 		return "12345678-90ab-cdef-0123-4567890abcde"
 	}
+	notEnrolled := func() totp.Repository {
+		return totp.NewFakeRepository(
+			nil,
+			[]totp.FakeRepositoryFindByAccountResult{
+				{
+					Error: totp.ErrNotFound,
+				},
+			},
+			nil,
+			nil,
+			nil,
+		)
+	}
 
 	cases := []struct {
 		caseName          string
 		sessRepo          session.Repository
 		accRepo           account.Repository
+		totpRepo          totp.Repository
+		challengeRepo     totp.ChallengeRepository
 		notary            notary.Notary
 		packer            packer.Packer
 		hasher            hasher.Hasher
+		opts              Options
+		reqTenantID       int64
 		reqBody           io.Reader
 		expectedCode      int
 		expectedHeaderMap http.Header
 		expectedBody      *bytes.Buffer
 	}{
 		{
-			caseName: "Empty password should result in 400",
+			caseName: "Request without a tenant should result in 404",
+			reqBody: bytes.NewBufferString(`{
+				"name":"email@email.com",
+				"password":"password"
+			}`),
+			expectedCode:      http.StatusNotFound,
+			expectedHeaderMap: http.Header{},
+			expectedBody:      bytes.NewBuffer(nil),
+		},
+		{
+			caseName:    "Empty password should result in 400",
+			reqTenantID: 1,
 			reqBody: bytes.NewBufferString(`{
 				"name":"email@email.com",
 				"password":""
@@ -114,7 +149,8 @@ func TestRestController_PostSessions(t *testing.T) {
 			}`),
 		},
 		{
-			caseName: "Non-existent name should result in 401",
+			caseName:    "Non-existent name should result in 401",
+			reqTenantID: 1,
 			accRepo: account.NewFakeRepository(
 				nil,
 				nil,
@@ -125,6 +161,15 @@ func TestRestController_PostSessions(t *testing.T) {
 						Error:   account.ErrNotFound,
 					},
 				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
 			),
 			reqBody: bytes.NewBufferString(`{
 				"name":"nonexistent@email.com",
@@ -135,28 +180,1001 @@ func TestRestController_PostSessions(t *testing.T) {
 			expectedBody:      bytes.NewBuffer(nil),
 		},
 		{
-			caseName: "Error on FindWithPasswordHashByUsername should result in 500",
+			caseName:    "Error on FindWithPasswordHashByUsername should result in 500",
+			reqTenantID: 1,
+			accRepo: account.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				[]account.FakeRepositoryFindWithPasswordHashByUsernameResult{
+					{
+						Account: nil,
+						Error:   fmt.Errorf("FindWithPasswordHashByUsername failed"),
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			reqBody: bytes.NewBufferString(`{
+				"name":"email@email.com",
+				"password":"password"
+			}`),
+			expectedCode:      http.StatusInternalServerError,
+			expectedHeaderMap: http.Header{},
+			expectedBody:      bytes.NewBuffer(nil),
+		},
+		{
+			caseName:    "Error on checking password should result in 401",
+			reqTenantID: 1,
+			accRepo: account.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				[]account.FakeRepositoryFindWithPasswordHashByUsernameResult{
+					{
+						Account: &account.Account{
+							ID:        123,
+							Name:      "email@email.com",
+							CreatedAt: now,
+							UpdatedAt: now,
+							Status:    account.StatusActive,
+						},
+						PasswordHash: []byte("password_hash"),
+						Error:        nil,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			hasher: hasher.NewFakeHasher(
+				nil,
+				[]hasher.FakeCompareHashWithPasswordResult{
+					{
+						Error: fmt.Errorf("No match"),
+					},
+				},
+			),
+			reqBody: bytes.NewBufferString(`{
+				"name":"email@email.com",
+				"password":"password"
+			}`),
+			expectedCode:      http.StatusUnauthorized,
+			expectedHeaderMap: http.Header{},
+			expectedBody:      bytes.NewBuffer(nil),
+		},
+		{
+			caseName:    "Locked account should result in 429",
+			reqTenantID: 1,
+			accRepo: account.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				[]account.FakeRepositoryFindWithPasswordHashByUsernameResult{
+					{
+						Account: &account.Account{
+							ID:                  123,
+							Name:                "email@email.com",
+							CreatedAt:           now,
+							UpdatedAt:           now,
+							Status:              account.StatusActive,
+							FailedLoginAttempts: 0,
+							LockedUntil:         now.Add(time.Minute * 10),
+						},
+						PasswordHash: []byte("password_hash"),
+						Error:        nil,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			opts: Options{
+				LockoutThreshold: 5,
+				LockoutDuration:  time.Minute * 15,
+			},
+			reqBody: bytes.NewBufferString(`{
+				"name":"email@email.com",
+				"password":"password"
+			}`),
+			expectedCode: http.StatusTooManyRequests,
+			expectedHeaderMap: http.Header{
+				"Content-Type": []string{"application/json; charset=utf-8"},
+				"Retry-After":  []string{"600"},
+			},
+			expectedBody: bytes.NewBufferString(`{
+				"errors":[
+					{
+						"message":"Account is temporarily locked due to too many failed login attempts"
+					}
+				]
+			}`),
+		},
+		{
+			caseName:    "Wrong password should be registered as failed login",
+			reqTenantID: 1,
+			accRepo: account.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				[]account.FakeRepositoryFindWithPasswordHashByUsernameResult{
+					{
+						Account: &account.Account{
+							ID:                  123,
+							Name:                "email@email.com",
+							CreatedAt:           now,
+							UpdatedAt:           now,
+							Status:              account.StatusActive,
+							FailedLoginAttempts: 1,
+							LockedUntil:         time.Time{},
+						},
+						PasswordHash: []byte("password_hash"),
+						Error:        nil,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				[]account.FakeRepositoryRegisterFailedLoginResult{
+					{
+						Locked: false,
+						Error:  nil,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			hasher: hasher.NewFakeHasher(
+				nil,
+				[]hasher.FakeCompareHashWithPasswordResult{
+					{
+						Error: fmt.Errorf("No match"),
+					},
+				},
+			),
+			opts: Options{
+				LockoutThreshold: 5,
+				LockoutDuration:  time.Minute * 15,
+			},
+			reqBody: bytes.NewBufferString(`{
+				"name":"email@email.com",
+				"password":"password"
+			}`),
+			expectedCode:      http.StatusUnauthorized,
+			expectedHeaderMap: http.Header{},
+			expectedBody:      bytes.NewBuffer(nil),
+		},
+		{
+			caseName:    "Error on accountRepo.RegisterFailedLogin should result in 500",
+			reqTenantID: 1,
+			accRepo: account.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				[]account.FakeRepositoryFindWithPasswordHashByUsernameResult{
+					{
+						Account: &account.Account{
+							ID:                  123,
+							Name:                "email@email.com",
+							CreatedAt:           now,
+							UpdatedAt:           now,
+							Status:              account.StatusActive,
+							FailedLoginAttempts: 1,
+							LockedUntil:         time.Time{},
+						},
+						PasswordHash: []byte("password_hash"),
+						Error:        nil,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				[]account.FakeRepositoryRegisterFailedLoginResult{
+					{
+						Locked: false,
+						Error:  fmt.Errorf("RegisterFailedLogin failed"),
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			hasher: hasher.NewFakeHasher(
+				nil,
+				[]hasher.FakeCompareHashWithPasswordResult{
+					{
+						Error: fmt.Errorf("No match"),
+					},
+				},
+			),
+			opts: Options{
+				LockoutThreshold: 5,
+				LockoutDuration:  time.Minute * 15,
+			},
+			reqBody: bytes.NewBufferString(`{
+				"name":"email@email.com",
+				"password":"password"
+			}`),
+			expectedCode:      http.StatusInternalServerError,
+			expectedHeaderMap: http.Header{},
+			expectedBody:      bytes.NewBuffer(nil),
+		},
+		{
+			caseName:    "Failed login reaching the threshold should result in 429",
+			reqTenantID: 1,
 			accRepo: account.NewFakeRepository(
 				nil,
 				nil,
 				nil,
 				[]account.FakeRepositoryFindWithPasswordHashByUsernameResult{
 					{
-						Account: nil,
-						Error:   fmt.Errorf("FindWithPasswordHashByUsername failed"),
+						Account: &account.Account{
+							ID:                  123,
+							Name:                "email@email.com",
+							CreatedAt:           now,
+							UpdatedAt:           now,
+							Status:              account.StatusActive,
+							FailedLoginAttempts: 4,
+							LockedUntil:         time.Time{},
+						},
+						PasswordHash: []byte("password_hash"),
+						Error:        nil,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				[]account.FakeRepositoryRegisterFailedLoginResult{
+					{
+						Locked: true,
+						Error:  nil,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			hasher: hasher.NewFakeHasher(
+				nil,
+				[]hasher.FakeCompareHashWithPasswordResult{
+					{
+						Error: fmt.Errorf("No match"),
+					},
+				},
+			),
+			opts: Options{
+				LockoutThreshold: 5,
+				LockoutDuration:  time.Minute * 15,
+			},
+			reqBody: bytes.NewBufferString(`{
+				"name":"email@email.com",
+				"password":"password"
+			}`),
+			expectedCode: http.StatusTooManyRequests,
+			expectedHeaderMap: http.Header{
+				"Content-Type": []string{"application/json; charset=utf-8"},
+				"Retry-After":  []string{"900"},
+			},
+			expectedBody: bytes.NewBufferString(`{
+				"errors":[
+					{
+						"message":"Account is temporarily locked due to too many failed login attempts"
+					}
+				]
+			}`),
+		},
+		{
+			caseName:    "Not active account should result in 403",
+			reqTenantID: 1,
+			accRepo: account.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				[]account.FakeRepositoryFindWithPasswordHashByUsernameResult{
+					{
+						Account: &account.Account{
+							ID:        123,
+							Name:      "email@email.com",
+							CreatedAt: now,
+							UpdatedAt: now,
+							Status:    account.StatusDisabled,
+						},
+						PasswordHash: []byte("password_hash"),
+						Error:        nil,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			hasher: hasher.NewFakeHasher(
+				nil,
+				[]hasher.FakeCompareHashWithPasswordResult{
+					{
+						Error: nil,
+					},
+				},
+			),
+			reqBody: bytes.NewBufferString(`{
+				"name":"email@email.com",
+				"password":"password"
+			}`),
+			expectedCode:      http.StatusForbidden,
+			expectedHeaderMap: http.Header{"Content-Type": []string{"application/json; charset=utf-8"}},
+			expectedBody: bytes.NewBufferString(`{
+				"errors":[
+					{
+						"message":"Account is disabled"
+					}
+				]
+			}`),
+		},
+		{
+			caseName:    "Account pending verification should result in 403 when blocked",
+			reqTenantID: 1,
+			opts: Options{
+				Unverified: UnverifiedBlock,
+			},
+			accRepo: account.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				[]account.FakeRepositoryFindWithPasswordHashByUsernameResult{
+					{
+						Account: &account.Account{
+							ID:        123,
+							Name:      "email@email.com",
+							CreatedAt: now,
+							UpdatedAt: now,
+							Status:    account.StatusActive,
+						},
+						PasswordHash: []byte("password_hash"),
+						Error:        nil,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			hasher: hasher.NewFakeHasher(
+				nil,
+				[]hasher.FakeCompareHashWithPasswordResult{
+					{
+						Error: nil,
+					},
+				},
+			),
+			reqBody: bytes.NewBufferString(`{
+				"name":"email@email.com",
+				"password":"password"
+			}`),
+			expectedCode:      http.StatusForbidden,
+			expectedHeaderMap: http.Header{"Content-Type": []string{"application/json; charset=utf-8"}},
+			expectedBody: bytes.NewBufferString(`{
+				"errors":[
+					{
+						"message":"Account is not verified"
+					}
+				]
+			}`),
+		},
+		{
+			caseName:    "Error on totpRepo.FindByAccount should result in 500",
+			reqTenantID: 1,
+			accRepo: account.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				[]account.FakeRepositoryFindWithPasswordHashByUsernameResult{
+					{
+						Account: &account.Account{
+							ID:                  123,
+							TenantID:            1,
+							Name:                "email@email.com",
+							CreatedAt:           now,
+							UpdatedAt:           now,
+							Status:              account.StatusActive,
+							FailedLoginAttempts: 2,
+						},
+						PasswordHash: []byte("password_hash"),
+						Error:        nil,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			totpRepo: totp.NewFakeRepository(
+				nil,
+				[]totp.FakeRepositoryFindByAccountResult{
+					{
+						Error: fmt.Errorf("FindByAccount failed"),
+					},
+				},
+				nil,
+				nil,
+				nil,
+			),
+			hasher: hasher.NewFakeHasher(
+				nil,
+				[]hasher.FakeCompareHashWithPasswordResult{
+					{
+						Error: nil,
+					},
+				},
+			),
+			reqBody: bytes.NewBufferString(`{
+				"name":"email@email.com",
+				"password":"password"
+			}`),
+			expectedCode:      http.StatusInternalServerError,
+			expectedHeaderMap: http.Header{},
+			expectedBody:      bytes.NewBuffer(nil),
+		},
+		{
+			caseName:    "Error on challengeRepo.Save should result in 500",
+			reqTenantID: 1,
+			accRepo: account.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				[]account.FakeRepositoryFindWithPasswordHashByUsernameResult{
+					{
+						Account: &account.Account{
+							ID:                  123,
+							TenantID:            1,
+							Name:                "email@email.com",
+							CreatedAt:           now,
+							UpdatedAt:           now,
+							Status:              account.StatusActive,
+							FailedLoginAttempts: 2,
+						},
+						PasswordHash: []byte("password_hash"),
+						Error:        nil,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			totpRepo: totp.NewFakeRepository(
+				nil,
+				[]totp.FakeRepositoryFindByAccountResult{
+					{
+						Enrollment: &totp.Enrollment{AccountID: 123, ConfirmedAt: now},
+					},
+				},
+				nil,
+				nil,
+				nil,
+			),
+			challengeRepo: totp.NewFakeChallengeRepository(
+				[]totp.FakeChallengeRepositorySaveResult{
+					{
+						Error: fmt.Errorf("Save failed"),
+					},
+				},
+				nil,
+				nil,
+				nil,
+			),
+			hasher: hasher.NewFakeHasher(
+				nil,
+				[]hasher.FakeCompareHashWithPasswordResult{
+					{
+						Error: nil,
+					},
+				},
+			),
+			reqBody: bytes.NewBufferString(`{
+				"name":"email@email.com",
+				"password":"password"
+			}`),
+			expectedCode:      http.StatusInternalServerError,
+			expectedHeaderMap: http.Header{},
+			expectedBody:      bytes.NewBuffer(nil),
+		},
+		{
+			caseName:    "Too many challenges should result in 429",
+			reqTenantID: 1,
+			accRepo: account.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				[]account.FakeRepositoryFindWithPasswordHashByUsernameResult{
+					{
+						Account: &account.Account{
+							ID:                  123,
+							TenantID:            1,
+							Name:                "email@email.com",
+							CreatedAt:           now,
+							UpdatedAt:           now,
+							Status:              account.StatusActive,
+							FailedLoginAttempts: 2,
+						},
+						PasswordHash: []byte("password_hash"),
+						Error:        nil,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			totpRepo: totp.NewFakeRepository(
+				nil,
+				[]totp.FakeRepositoryFindByAccountResult{
+					{
+						Enrollment: &totp.Enrollment{AccountID: 123, ConfirmedAt: now},
+					},
+				},
+				nil,
+				nil,
+				nil,
+			),
+			challengeRepo: totp.NewFakeChallengeRepository(
+				[]totp.FakeChallengeRepositorySaveResult{
+					{
+						Error: totp.ErrTooManyChallenges,
+					},
+				},
+				nil,
+				nil,
+				nil,
+			),
+			hasher: hasher.NewFakeHasher(
+				nil,
+				[]hasher.FakeCompareHashWithPasswordResult{
+					{
+						Error: nil,
+					},
+				},
+			),
+			reqBody: bytes.NewBufferString(`{
+				"name":"email@email.com",
+				"password":"password"
+			}`),
+			expectedCode: http.StatusTooManyRequests,
+			expectedHeaderMap: http.Header{
+				"Content-Type": []string{"application/json; charset=utf-8"},
+				"Retry-After":  []string{"300"},
+			},
+			expectedBody: bytes.NewBufferString(`{
+				"errors":[
+					{
+						"message":"Too many logins to complete with a code, try again later"
+					}
+				]
+			}`),
+		},
+		{
+			caseName:    "Account with TOTP enabled should be challenged",
+			reqTenantID: 1,
+			accRepo: account.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				[]account.FakeRepositoryFindWithPasswordHashByUsernameResult{
+					{
+						Account: &account.Account{
+							ID:                  123,
+							TenantID:            1,
+							Name:                "email@email.com",
+							CreatedAt:           now,
+							UpdatedAt:           now,
+							Status:              account.StatusActive,
+							FailedLoginAttempts: 2,
+						},
+						PasswordHash: []byte("password_hash"),
+						Error:        nil,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			totpRepo: totp.NewFakeRepository(
+				nil,
+				[]totp.FakeRepositoryFindByAccountResult{
+					{
+						Enrollment: &totp.Enrollment{AccountID: 123, ConfirmedAt: now},
+					},
+				},
+				nil,
+				nil,
+				nil,
+			),
+			challengeRepo: totp.NewFakeChallengeRepository(
+				[]totp.FakeChallengeRepositorySaveResult{
+					{
+						Error: nil,
+					},
+				},
+				nil,
+				nil,
+				nil,
+			),
+			hasher: hasher.NewFakeHasher(
+				nil,
+				[]hasher.FakeCompareHashWithPasswordResult{
+					{
+						Error: nil,
+					},
+				},
+			),
+			reqBody: bytes.NewBufferString(`{
+				"name":"email@email.com",
+				"password":"password"
+			}`),
+			expectedCode:      http.StatusAccepted,
+			expectedHeaderMap: http.Header{"Content-Type": []string{"application/json; charset=utf-8"}},
+			expectedBody: bytes.NewBufferString(fmt.Sprintf(`{
+				"result":{
+					"challengeID":"12345678-90ab-cdef-0123-4567890abcde",
+					"expiresAt":"%s"
+				}
+			}`, now.Add(totp.ChallengeDuration).Format(time.RFC3339))),
+		},
+		{
+			caseName:    "Error on accountRepo.ResetFailedLogins should result in 500",
+			reqTenantID: 1,
+			totpRepo:    notEnrolled(),
+			accRepo: account.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				[]account.FakeRepositoryFindWithPasswordHashByUsernameResult{
+					{
+						Account: &account.Account{
+							ID:                  123,
+							Name:                "email@email.com",
+							CreatedAt:           now,
+							UpdatedAt:           now,
+							Status:              account.StatusActive,
+							FailedLoginAttempts: 2,
+							LockedUntil:         now.Add(-time.Minute),
+						},
+						PasswordHash: []byte("password_hash"),
+						Error:        nil,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+				[]account.FakeRepositoryResetFailedLoginsResult{
+					{
+						Error: fmt.Errorf("ResetFailedLogins failed"),
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			hasher: hasher.NewFakeHasher(
+				nil,
+				[]hasher.FakeCompareHashWithPasswordResult{
+					{
+						Error: nil,
+					},
+				},
+			),
+			opts: Options{
+				LockoutThreshold: 5,
+				LockoutDuration:  time.Minute * 15,
+			},
+			reqBody: bytes.NewBufferString(`{
+				"name":"email@email.com",
+				"password":"password"
+			}`),
+			expectedCode:      http.StatusInternalServerError,
+			expectedHeaderMap: http.Header{},
+			expectedBody:      bytes.NewBuffer(nil),
+		},
+		{
+			caseName:    "Error on sessionRepo.Save should result in 500",
+			reqTenantID: 1,
+			totpRepo:    notEnrolled(),
+			accRepo: account.NewFakeRepository(
+				nil,
+				[]account.FakeRepositorySaveResult{
+					{
+						Error: nil,
+					},
+				},
+				nil,
+				[]account.FakeRepositoryFindWithPasswordHashByUsernameResult{
+					{
+						Account: &account.Account{
+							ID:        123,
+							Name:      "email@email.com",
+							CreatedAt: now,
+							UpdatedAt: now,
+							Status:    account.StatusActive,
+						},
+						PasswordHash: []byte("password_hash"),
+						Error:        nil,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			sessRepo: session.NewFakeRepository(
+				[]session.FakeRepositorySaveResult{
+					{
+						Error: fmt.Errorf("Save failed"),
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			hasher: hasher.NewFakeHasher(
+				nil,
+				[]hasher.FakeCompareHashWithPasswordResult{
+					{
+						Error: nil,
+					},
+				},
+			),
+			reqBody: bytes.NewBufferString(`{
+				"name":"email@email.com",
+				"password":"password"
+			}`),
+			expectedCode:      http.StatusInternalServerError,
+			expectedHeaderMap: http.Header{},
+			expectedBody:      bytes.NewBuffer(nil),
+		},
+		{
+			caseName:    "Error on sess.Token should result in 500",
+			reqTenantID: 1,
+			totpRepo:    notEnrolled(),
+			sessRepo: session.NewFakeRepository(
+				[]session.FakeRepositorySaveResult{
+					{
+						Error: nil,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			accRepo: account.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				[]account.FakeRepositoryFindWithPasswordHashByUsernameResult{
+					{
+						Account: &account.Account{
+							ID:        123,
+							Name:      "email@email.com",
+							CreatedAt: now,
+							UpdatedAt: now,
+							Status:    account.StatusActive,
+						},
+						Error: nil,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			notary: notary.NewFakeNotary(
+				[]notary.FakeNotarySignResult{
+					{
+						Signature: []byte("signature"),
+					},
+				},
+				nil,
+			),
+			packer: packer.NewFakePacker(
+				[]packer.FakePackerPackResult{
+					{
+						Pack:  nil,
+						Error: fmt.Errorf("Pack failed"),
+					},
+				},
+				nil,
+			),
+			hasher: hasher.NewFakeHasher(
+				nil,
+				[]hasher.FakeCompareHashWithPasswordResult{
+					{
+						Error: nil,
+					},
+				},
+			),
+			reqBody: bytes.NewBufferString(
+				`{"name":"email@email.com","password":"password"}`,
+			),
+			expectedCode:      http.StatusInternalServerError,
+			expectedHeaderMap: http.Header{},
+			expectedBody:      bytes.NewBuffer(nil),
+		},
+		{
+			caseName:    "Successful",
+			reqTenantID: 1,
+			totpRepo:    notEnrolled(),
+			sessRepo: session.NewFakeRepository(
+				[]session.FakeRepositorySaveResult{
+					{
+						Error: nil,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			accRepo: account.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				[]account.FakeRepositoryFindWithPasswordHashByUsernameResult{
+					{
+						Account: &account.Account{
+							ID:        123,
+							TenantID:  1,
+							Name:      "email@email.com",
+							CreatedAt: now,
+							UpdatedAt: now,
+							Status:    account.StatusActive,
+						},
+						Error: nil,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			notary: notary.NewFakeNotary(
+				[]notary.FakeNotarySignResult{
+					{
+						Signature: []byte("signature"),
+					},
+				},
+				nil,
+			),
+			packer: packer.NewFakePacker(
+				[]packer.FakePackerPackResult{
+					{
+						Pack:  []byte("pack"),
+						Error: nil,
+					},
+				},
+				nil,
+			),
+			hasher: hasher.NewFakeHasher(
+				nil,
+				[]hasher.FakeCompareHashWithPasswordResult{
+					{
+						Error: nil,
+					},
+				},
+			),
+			reqBody: bytes.NewBufferString(
+				`{"name":"email@email.com","password":"password"}`,
+			),
+			expectedCode: http.StatusCreated,
+			expectedHeaderMap: http.Header{
+				"Content-Type": []string{"application/json; charset=utf-8"},
+			},
+			expectedBody: bytes.NewBufferString(fmt.Sprintf(`{
+				"result": {
+					"token":"pack",
+					"id":"12345678-90ab-cdef-0123-4567890abcde",
+					"accountID":123,
+					"tenantID":1,
+					"createdAt":"%s",
+					"expiresAt":"%s"
+				}
+			}`, now.Format(time.RFC3339), now.Add(SessionDefaultDuration).Format(time.RFC3339))),
+		},
+		{
+			caseName: "Error on event emitting should result in 201",
+			opts: Options{
+				Events: webhook.NewFakeEmitter([]webhook.FakeEmitterMessagesResult{{Error: fmt.Errorf("Messages failed")}}, nil),
+			},
+			reqTenantID: 1,
+			totpRepo:    notEnrolled(),
+			sessRepo: session.NewFakeRepository(
+				[]session.FakeRepositorySaveResult{
+					{
+						Error: nil,
 					},
 				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
 			),
-			reqBody: bytes.NewBufferString(`{
-				"name":"email@email.com",
-				"password":"password"
-			}`),
-			expectedCode:      http.StatusInternalServerError,
-			expectedHeaderMap: http.Header{},
-			expectedBody:      bytes.NewBuffer(nil),
-		},
-		{
-			caseName: "Error on checking password should result in 401",
 			accRepo: account.NewFakeRepository(
 				nil,
 				nil,
@@ -165,58 +1183,128 @@ func TestRestController_PostSessions(t *testing.T) {
 					{
 						Account: &account.Account{
 							ID:        123,
+							TenantID:  1,
 							Name:      "email@email.com",
 							CreatedAt: now,
 							UpdatedAt: now,
+							Status:    account.StatusActive,
 						},
-						PasswordHash: []byte("password_hash"),
-						Error:        nil,
+						Error: nil,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			notary: notary.NewFakeNotary(
+				[]notary.FakeNotarySignResult{
+					{
+						Signature: []byte("signature"),
+					},
+				},
+				nil,
+			),
+			packer: packer.NewFakePacker(
+				[]packer.FakePackerPackResult{
+					{
+						Pack:  []byte("pack"),
+						Error: nil,
 					},
 				},
+				nil,
 			),
 			hasher: hasher.NewFakeHasher(
 				nil,
 				[]hasher.FakeCompareHashWithPasswordResult{
 					{
-						Error: fmt.Errorf("No match"),
+						Error: nil,
 					},
 				},
 			),
-			reqBody: bytes.NewBufferString(`{
-				"name":"email@email.com",
-				"password":"password"
-			}`),
-			expectedCode:      http.StatusUnauthorized,
-			expectedHeaderMap: http.Header{},
-			expectedBody:      bytes.NewBuffer(nil),
+			reqBody: bytes.NewBufferString(
+				`{"name":"email@email.com","password":"password"}`,
+			),
+			expectedCode: http.StatusCreated,
+			expectedHeaderMap: http.Header{
+				"Content-Type": []string{"application/json; charset=utf-8"},
+			},
+			expectedBody: bytes.NewBufferString(fmt.Sprintf(`{
+				"result": {
+					"token":"pack",
+					"id":"12345678-90ab-cdef-0123-4567890abcde",
+					"accountID":123,
+					"tenantID":1,
+					"createdAt":"%s",
+					"expiresAt":"%s"
+				}
+			}`, now.Format(time.RFC3339), now.Add(SessionDefaultDuration).Format(time.RFC3339))),
 		},
 		{
-			caseName: "Error on sessionRepo.Save should result in 500",
-			accRepo: account.NewFakeRepository(
-				nil,
-				[]account.FakeRepositorySaveResult{
+			caseName:    "Account pending verification should get a limited session",
+			reqTenantID: 1,
+			opts: Options{
+				Unverified:                UnverifiedLimit,
+				UnverifiedSessionDuration: time.Hour,
+			},
+			totpRepo: notEnrolled(),
+			sessRepo: session.NewFakeRepository(
+				[]session.FakeRepositorySaveResult{
 					{
 						Error: nil,
 					},
 				},
 				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			accRepo: account.NewFakeRepository(
+				nil,
+				nil,
+				nil,
 				[]account.FakeRepositoryFindWithPasswordHashByUsernameResult{
 					{
 						Account: &account.Account{
 							ID:        123,
+							TenantID:  1,
 							Name:      "email@email.com",
 							CreatedAt: now,
 							UpdatedAt: now,
+							Status:    account.StatusActive,
 						},
-						PasswordHash: []byte("password_hash"),
-						Error:        nil,
+						Error: nil,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			notary: notary.NewFakeNotary(
+				[]notary.FakeNotarySignResult{
+					{
+						Signature: []byte("signature"),
 					},
 				},
+				nil,
 			),
-			sessRepo: session.NewFakeRepository(
-				[]session.FakeRepositorySaveResult{
+			packer: packer.NewFakePacker(
+				[]packer.FakePackerPackResult{
 					{
-						Error: fmt.Errorf("Save failed"),
+						Pack:  []byte("pack"),
+						Error: nil,
 					},
 				},
 				nil,
@@ -229,16 +1317,28 @@ func TestRestController_PostSessions(t *testing.T) {
 					},
 				},
 			),
-			reqBody: bytes.NewBufferString(`{
-				"name":"email@email.com",
-				"password":"password"
-			}`),
-			expectedCode:      http.StatusInternalServerError,
-			expectedHeaderMap: http.Header{},
-			expectedBody:      bytes.NewBuffer(nil),
+			reqBody: bytes.NewBufferString(
+				`{"name":"email@email.com","password":"password"}`,
+			),
+			expectedCode: http.StatusCreated,
+			expectedHeaderMap: http.Header{
+				"Content-Type": []string{"application/json; charset=utf-8"},
+			},
+			expectedBody: bytes.NewBufferString(fmt.Sprintf(`{
+				"result": {
+					"token":"pack",
+					"id":"12345678-90ab-cdef-0123-4567890abcde",
+					"accountID":123,
+					"tenantID":1,
+					"createdAt":"%s",
+					"expiresAt":"%s"
+				}
+			}`, now.Format(time.RFC3339), now.Add(time.Hour).Format(time.RFC3339))),
 		},
 		{
-			caseName: "Error on sess.Token should result in 500",
+			caseName:    "Successful with the password rehashed",
+			reqTenantID: 1,
+			totpRepo:    notEnrolled(),
 			sessRepo: session.NewFakeRepository(
 				[]session.FakeRepositorySaveResult{
 					{
@@ -246,22 +1346,41 @@ func TestRestController_PostSessions(t *testing.T) {
 					},
 				},
 				nil,
+				nil,
+				nil,
+				nil,
+				nil,
 			),
 			accRepo: account.NewFakeRepository(
 				nil,
-				nil,
+				[]account.FakeRepositorySaveResult{
+					{
+						Error: nil,
+					},
+				},
 				nil,
 				[]account.FakeRepositoryFindWithPasswordHashByUsernameResult{
 					{
 						Account: &account.Account{
 							ID:        123,
+							TenantID:  1,
 							Name:      "email@email.com",
 							CreatedAt: now,
 							UpdatedAt: now,
+							Status:    account.StatusActive,
 						},
 						Error: nil,
 					},
 				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
 			),
 			notary: notary.NewFakeNotary(
 				[]notary.FakeNotarySignResult{
@@ -274,29 +1393,51 @@ func TestRestController_PostSessions(t *testing.T) {
 			packer: packer.NewFakePacker(
 				[]packer.FakePackerPackResult{
 					{
-						Pack:  nil,
-						Error: fmt.Errorf("Pack failed"),
+						Pack:  []byte("pack"),
+						Error: nil,
 					},
 				},
 				nil,
 			),
-			hasher: hasher.NewFakeHasher(
-				nil,
+			hasher: hasher.NewFakeRehasher(
+				[]hasher.FakeGenerateHashFromPasswordResult{
+					{
+						Hash: []byte("new_password_hash"),
+					},
+				},
 				[]hasher.FakeCompareHashWithPasswordResult{
 					{
 						Error: nil,
 					},
 				},
+				[]hasher.FakeNeedsRehashResult{
+					{
+						NeedsRehash: true,
+					},
+				},
 			),
 			reqBody: bytes.NewBufferString(
 				`{"name":"email@email.com","password":"password"}`,
 			),
-			expectedCode:      http.StatusInternalServerError,
-			expectedHeaderMap: http.Header{},
-			expectedBody:      bytes.NewBuffer(nil),
+			expectedCode: http.StatusCreated,
+			expectedHeaderMap: http.Header{
+				"Content-Type": []string{"application/json; charset=utf-8"},
+			},
+			expectedBody: bytes.NewBufferString(fmt.Sprintf(`{
+				"result": {
+					"token":"pack",
+					"id":"12345678-90ab-cdef-0123-4567890abcde",
+					"accountID":123,
+					"tenantID":1,
+					"createdAt":"%s",
+					"expiresAt":"%s"
+				}
+			}`, now.Format(time.RFC3339), now.Add(SessionDefaultDuration).Format(time.RFC3339))),
 		},
 		{
-			caseName: "Successful",
+			caseName:    "Error on rehashed password save should not fail the login",
+			reqTenantID: 1,
+			totpRepo:    notEnrolled(),
 			sessRepo: session.NewFakeRepository(
 				[]session.FakeRepositorySaveResult{
 					{
@@ -304,22 +1445,41 @@ func TestRestController_PostSessions(t *testing.T) {
 					},
 				},
 				nil,
+				nil,
+				nil,
+				nil,
+				nil,
 			),
 			accRepo: account.NewFakeRepository(
 				nil,
-				nil,
+				[]account.FakeRepositorySaveResult{
+					{
+						Error: fmt.Errorf("Save failed"),
+					},
+				},
 				nil,
 				[]account.FakeRepositoryFindWithPasswordHashByUsernameResult{
 					{
 						Account: &account.Account{
 							ID:        123,
+							TenantID:  1,
 							Name:      "email@email.com",
 							CreatedAt: now,
 							UpdatedAt: now,
+							Status:    account.StatusActive,
 						},
 						Error: nil,
 					},
 				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
 			),
 			notary: notary.NewFakeNotary(
 				[]notary.FakeNotarySignResult{
@@ -338,13 +1498,22 @@ func TestRestController_PostSessions(t *testing.T) {
 				},
 				nil,
 			),
-			hasher: hasher.NewFakeHasher(
-				nil,
+			hasher: hasher.NewFakeRehasher(
+				[]hasher.FakeGenerateHashFromPasswordResult{
+					{
+						Hash: []byte("new_password_hash"),
+					},
+				},
 				[]hasher.FakeCompareHashWithPasswordResult{
 					{
 						Error: nil,
 					},
 				},
+				[]hasher.FakeNeedsRehashResult{
+					{
+						NeedsRehash: true,
+					},
+				},
 			),
 			reqBody: bytes.NewBufferString(
 				`{"name":"email@email.com","password":"password"}`,
@@ -358,10 +1527,11 @@ func TestRestController_PostSessions(t *testing.T) {
 					"token":"pack",
 					"id":"12345678-90ab-cdef-0123-4567890abcde",
 					"accountID":123,
+					"tenantID":1,
 					"createdAt":"%s",
 					"expiresAt":"%s"
 				}
-			}`, now.Format(time.RFC3339), now.Add(sessionDefaultDuration).Format(time.RFC3339))),
+			}`, now.Format(time.RFC3339), now.Add(SessionDefaultDuration).Format(time.RFC3339))),
 		},
 	}
 
@@ -376,14 +1546,22 @@ func TestRestController_PostSessions(t *testing.T) {
 			fakeLogger,
 			c.accRepo,
 			c.sessRepo,
+			nil,
+			c.totpRepo,
+			c.challengeRepo,
+			nil,
 			c.notary,
 			c.packer,
 			c.hasher,
 			uuidProducer,
-			Options{},
+			clock.Fixed(now),
+			c.opts,
 		)
 		w := httptest.NewRecorder()
 		req := httptest.NewRequest(http.MethodPost, PathSessions, c.reqBody)
+		if c.reqTenantID != 0 {
+			req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyTenantID{}, c.reqTenantID))
+		}
 
 		ctrl.PostSessions(w, req)
 
@@ -433,3 +1611,160 @@ func TestRestController_PostSessions(t *testing.T) {
 		}
 	}
 }
+
+// outboxSessionRepository is a session.Repository which keeps the outbox messages saved.
+type outboxSessionRepository struct {
+	session.Repository
+	messages []outbox.Message
+}
+
+func (r *outboxSessionRepository) Save(sess session.Session, messages ...outbox.Message) error {
+	r.messages = append(r.messages, messages...)
+	return r.Repository.Save(sess, messages...)
+}
+
+// recordingEmitter is a webhook.Emitter which keeps the events emitted,
+// and returns a message for each event to add along with the change.
+type recordingEmitter struct {
+	events []webhook.Event
+}
+
+func (em *recordingEmitter) Messages(e webhook.Event) ([]outbox.Message, error) {
+	em.events = append(em.events, e)
+	return []outbox.Message{{ID: e.Type, Channel: webhook.Channel}}, nil
+}
+
+func (em *recordingEmitter) Emit(e webhook.Event) error {
+	em.events = append(em.events, e)
+	return nil
+}
+
+func TestRestController_PostSessions_LoginNotifications(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	uuidProducer := func() string {
+		return "12345678-90ab-cdef-0123-4567890abcde"
+	}
+
+	events := &recordingEmitter{}
+	sessRepo := &outboxSessionRepository{
+		Repository: session.NewFakeRepository(
+			[]session.FakeRepositorySaveResult{
+				{
+					Error: nil,
+				},
+			},
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+		),
+	}
+	accRepo := account.NewFakeRepository(
+		nil,
+		nil,
+		nil,
+		[]account.FakeRepositoryFindWithPasswordHashByUsernameResult{
+			{
+				Account: &account.Account{
+					ID:        123,
+					TenantID:  1,
+					Name:      "email@email.com",
+					CreatedAt: now,
+					UpdatedAt: now,
+					Status:    account.StatusActive,
+				},
+				Error: nil,
+			},
+		},
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+	totpRepo := totp.NewFakeRepository(
+		nil,
+		[]totp.FakeRepositoryFindByAccountResult{
+			{
+				Error: totp.ErrNotFound,
+			},
+		},
+		nil,
+		nil,
+		nil,
+	)
+
+	ctrl := NewRestController(
+		log.New(ioutil.Discard, "", log.LstdFlags),
+		accRepo,
+		sessRepo,
+		nil,
+		totpRepo,
+		nil,
+		nil,
+		notary.NewFakeNotary([]notary.FakeNotarySignResult{{Signature: []byte("signature")}}, nil),
+		packer.NewFakePacker([]packer.FakePackerPackResult{{Pack: []byte("pack")}}, nil),
+		hasher.NewFakeHasher(nil, []hasher.FakeCompareHashWithPasswordResult{{Error: nil}}),
+		uuidProducer,
+		clock.Fixed(now),
+		Options{
+			Outbox: outbox.NewRouter(nil, []string{"smtp", "webhook"}, uuidProducer, clock.Fixed(now)),
+			Events: events,
+		},
+	)
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(
+		http.MethodPost,
+		PathSessions,
+		bytes.NewBufferString(`{"name":"email@email.com","password":"password"}`),
+	)
+	req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyTenantID{}, int64(1)))
+	req.RemoteAddr = "127.0.0.1:12345"
+	req.Header.Set("User-Agent", "Mozilla/5.0")
+
+	ctrl.PostSessions(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status code to be %v, but got %v\n", http.StatusCreated, w.Code)
+	}
+
+	if len(sessRepo.messages) != 3 {
+		t.Fatalf("Expected 3 messages to be saved with the session, but got %#v\n", sessRepo.messages)
+	}
+	if m := sessRepo.messages[2]; m.Channel != webhook.Channel {
+		t.Errorf("Expected the event to be saved with the session, but got %#v\n", m)
+	}
+	for i, channel := range []string{"smtp", "webhook"} {
+		m := sessRepo.messages[i]
+		n := m.Notification
+		if m.Channel != channel ||
+			n.Kind != notifier.KindNewLogin ||
+			n.AccountID != 123 ||
+			n.Recipient != "email@email.com" ||
+			n.Params["ip"] != "127.0.0.1" ||
+			n.Params["userAgent"] != "Mozilla/5.0" {
+			t.Errorf("Expected message %d to be a new login alert to %s, but got %#v\n", i, channel, m)
+		}
+	}
+
+	expectedEvents := []webhook.Event{
+		{
+			Type:      webhook.EventLoginSucceeded,
+			TenantID:  1,
+			AccountID: 123,
+			Data: map[string]string{
+				"sessionID": uuidProducer(),
+				"ip":        "127.0.0.1",
+				"userAgent": "Mozilla/5.0",
+			},
+		},
+	}
+	if !reflect.DeepEqual(events.events, expectedEvents) {
+		t.Errorf("Expected events to be\n%#v\nbut got\n%#v\n", expectedEvents, events.events)
+	}
+}
@@ -0,0 +1,151 @@
+package sessions
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/pborman/uuid"
+
+	"github.com/hypnoglow/pascont/account"
+	"github.com/hypnoglow/pascont/kit"
+	"github.com/hypnoglow/pascont/kit/form"
+	"github.com/hypnoglow/pascont/kit/middleware"
+	"github.com/hypnoglow/pascont/kit/schema"
+	"github.com/hypnoglow/pascont/recovery"
+	"github.com/hypnoglow/pascont/totp"
+	"github.com/hypnoglow/pascont/webhook"
+)
+
+// PostSessionsTOTP is a handler for:
+// POST /sessions/totp
+//
+// It completes the login challenged by PostSessions with a TOTP code
+// or with a recovery code.
+func (c RestController) PostSessionsTOTP(w http.ResponseWriter, req *http.Request) {
+	tenantID, ok := req.Context().Value(middleware.ContextKeyTenantID{}).(int64)
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	var challengeForm postSessionTOTPForm
+	form.PopulateFormFromJSON(req.Body, &challengeForm)
+	if !challengeForm.Validate() {
+		kit.RespondWithFormErrors(w, http.StatusBadRequest, challengeForm.ValidationErrors())
+		return
+	}
+
+	now := c.clock()
+	challenge, err := c.challengeRepo.FindByID(challengeForm.ChallengeID)
+	if err != nil {
+		if err == totp.ErrChallengeNotFound {
+			w.WriteHeader(http.StatusUnauthorized)
+		} else {
+			c.logger.Println(err)
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		return
+	}
+	if challenge.IsExpired(now) {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	acc, err := c.accountRepo.FindByID(challenge.AccountID)
+	if err != nil {
+		if err == account.ErrNotFound {
+			w.WriteHeader(http.StatusUnauthorized)
+		} else {
+			c.logger.Println(err)
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		return
+	}
+	if acc.TenantID != tenantID {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	if acc.IsLocked(now) {
+		c.emitLoginFailed(req, acc, webhook.LoginFailedLocked)
+		RespondLocked(w, acc.LockedUntil.Sub(now))
+		return
+	}
+
+	if !acc.IsActive() {
+		c.emitLoginFailed(req, acc, webhook.LoginFailedInactive)
+		kit.RespondWithError(w, http.StatusForbidden, schema.NewError(
+			fmt.Sprintf("Account is %s", acc.Status),
+			"",
+			nil,
+		))
+		return
+	}
+
+	e, err := c.totpRepo.FindByAccount(acc.ID)
+	if err != nil {
+		if err == totp.ErrNotFound {
+			w.WriteHeader(http.StatusUnauthorized)
+		} else {
+			c.logger.Println(err)
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		return
+	}
+
+	// Challenges allow a few attempts, whether the lockout is enabled or not.
+	allowed, err := c.challengeRepo.Attempt(challenge.ID)
+	if err != nil {
+		c.logger.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if !allowed {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	if len(challengeForm.RecoveryCode) != 0 {
+		ok, err = recovery.Spend(c.recoveryRepo, c.hasher, acc.ID, challengeForm.RecoveryCode)
+	} else {
+		ok, err = totp.UseCode(c.totpRepo, *e, challengeForm.Code, now)
+	}
+	if err != nil {
+		c.logger.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if !ok {
+		c.emitLoginFailed(req, acc, webhook.LoginFailedCode)
+		c.respondFailedLogin(w, acc.ID, now)
+		return
+	}
+
+	// The challenge is completed only once.
+	if err := c.challengeRepo.Delete(challenge.ID); err != nil {
+		c.logger.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	c.issueSession(w, req, acc)
+}
+
+type postSessionTOTPForm struct {
+	form.BaseForm
+	ChallengeID  string `json:"challengeID"`
+	Code         string `json:"code"`
+	RecoveryCode string `json:"recoveryCode"`
+}
+
+func (f *postSessionTOTPForm) Validate() bool {
+	if uuid.Parse(f.ChallengeID) == nil {
+		f.AddError("ChallengeID must be a valid UUID", "challengeID", f.ChallengeID)
+	}
+
+	if len(f.RecoveryCode) == 0 && len(f.Code) != totp.Digits {
+		f.AddError(fmt.Sprintf("Code must be %d digits", totp.Digits), "code", f.Code)
+	}
+
+	return len(f.ValidationErrors()) == 0
+}
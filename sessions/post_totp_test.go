@@ -0,0 +1,555 @@
+package sessions
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/pascont/account"
+	"github.com/hypnoglow/pascont/clock"
+	"github.com/hypnoglow/pascont/hasher"
+	"github.com/hypnoglow/pascont/kit/middleware"
+	"github.com/hypnoglow/pascont/notary"
+	"github.com/hypnoglow/pascont/packer"
+	"github.com/hypnoglow/pascont/recovery"
+	"github.com/hypnoglow/pascont/session"
+	"github.com/hypnoglow/pascont/totp"
+)
+
+func TestRestController_PostSessionsTOTP(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	fakeLogger := log.New(ioutil.Discard, "", log.LstdFlags)
+	uuidProducer := func() string {
+		return "12345678-90ab-cdef-0123-4567890abcde"
+	}
+
+	secret := []byte("12345678901234567890")
+	code := totp.Code(secret, totp.Counter(now))
+	challenge := &totp.Challenge{
+		ID:        "fedcba09-8765-4321-0fed-cba987654321",
+		AccountID: 123,
+		ExpiresAt: now.Add(totp.ChallengeDuration),
+	}
+	activeAccount := account.Account{
+		ID:        123,
+		TenantID:  1,
+		Name:      "email@email.com",
+		CreatedAt: now,
+		UpdatedAt: now,
+		Status:    account.StatusActive,
+	}
+
+	challengeRepo := func(c *totp.Challenge, deleteResults []totp.FakeChallengeRepositoryDeleteResult) totp.ChallengeRepository {
+		return totp.NewFakeChallengeRepository(
+			nil,
+			[]totp.FakeChallengeRepositoryFindByIDResult{
+				{
+					Challenge: c,
+				},
+			},
+			[]totp.FakeChallengeRepositoryAttemptResult{
+				{
+					Allowed: true,
+				},
+			},
+			deleteResults,
+		)
+	}
+	accRepo := func(acc account.Account, registerFailedLoginResults []account.FakeRepositoryRegisterFailedLoginResult) account.Repository {
+		return account.NewFakeRepository(
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			[]account.FakeRepositoryFindByIDResult{
+				{
+					Account: &acc,
+				},
+			},
+			registerFailedLoginResults,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+		)
+	}
+	enrolled := func(useCounterResults []totp.FakeRepositoryUseCounterResult) totp.Repository {
+		return totp.NewFakeRepository(
+			nil,
+			[]totp.FakeRepositoryFindByAccountResult{
+				{
+					Enrollment: &totp.Enrollment{AccountID: 123, Secret: secret, ConfirmedAt: now},
+				},
+			},
+			nil,
+			useCounterResults,
+			nil,
+		)
+	}
+
+	otherTenantAccount := activeAccount
+	otherTenantAccount.TenantID = 2
+	lockedAccount := activeAccount
+	lockedAccount.LockedUntil = now.Add(time.Minute * 10)
+	expiredChallenge := *challenge
+	expiredChallenge.ExpiresAt = now
+
+	cases := []struct {
+		caseName string
+		// in
+		accRepo       account.Repository
+		sessRepo      session.Repository
+		totpRepo      totp.Repository
+		challengeRepo totp.ChallengeRepository
+		recoveryRepo  recovery.Repository
+		hasher        hasher.Hasher
+		notary        notary.Notary
+		packer        packer.Packer
+		opts          Options
+		reqTenantID   int64
+		reqBody       io.Reader
+		// out
+		expectedCode      int
+		expectedHeaderMap http.Header
+		expectedBody      *bytes.Buffer
+	}{
+		{
+			caseName:          "Request without a tenant should result in 404",
+			reqBody:           bytes.NewBufferString(fmt.Sprintf(`{"challengeID":"%s","code":"%s"}`, challenge.ID, code)),
+			expectedCode:      http.StatusNotFound,
+			expectedHeaderMap: http.Header{},
+			expectedBody:      bytes.NewBuffer(nil),
+		},
+		{
+			caseName:          "Invalid challenge ID should result in 400",
+			reqTenantID:       1,
+			reqBody:           bytes.NewBufferString(fmt.Sprintf(`{"challengeID":"123","code":"%s"}`, code)),
+			expectedCode:      http.StatusBadRequest,
+			expectedHeaderMap: http.Header{"Content-Type": []string{"application/json; charset=utf-8"}},
+			expectedBody: bytes.NewBufferString(`{
+				"errors":[
+					{
+						"message":"ChallengeID must be a valid UUID",
+						"field":"challengeID",
+						"value":"123"
+					}
+				]
+			}`),
+		},
+		{
+			caseName:    "Non-existent challenge should result in 401",
+			reqTenantID: 1,
+			challengeRepo: totp.NewFakeChallengeRepository(
+				nil,
+				[]totp.FakeChallengeRepositoryFindByIDResult{
+					{
+						Error: totp.ErrChallengeNotFound,
+					},
+				},
+				nil,
+				nil,
+			),
+			reqBody:           bytes.NewBufferString(fmt.Sprintf(`{"challengeID":"%s","code":"%s"}`, challenge.ID, code)),
+			expectedCode:      http.StatusUnauthorized,
+			expectedHeaderMap: http.Header{},
+			expectedBody:      bytes.NewBuffer(nil),
+		},
+		{
+			caseName:          "Expired challenge should result in 401",
+			reqTenantID:       1,
+			challengeRepo:     challengeRepo(&expiredChallenge, nil),
+			reqBody:           bytes.NewBufferString(fmt.Sprintf(`{"challengeID":"%s","code":"%s"}`, challenge.ID, code)),
+			expectedCode:      http.StatusUnauthorized,
+			expectedHeaderMap: http.Header{},
+			expectedBody:      bytes.NewBuffer(nil),
+		},
+		{
+			caseName:          "Challenge of another tenant should result in 401",
+			reqTenantID:       1,
+			challengeRepo:     challengeRepo(challenge, nil),
+			accRepo:           accRepo(otherTenantAccount, nil),
+			reqBody:           bytes.NewBufferString(fmt.Sprintf(`{"challengeID":"%s","code":"%s"}`, challenge.ID, code)),
+			expectedCode:      http.StatusUnauthorized,
+			expectedHeaderMap: http.Header{},
+			expectedBody:      bytes.NewBuffer(nil),
+		},
+		{
+			caseName:      "Locked account should result in 429",
+			reqTenantID:   1,
+			challengeRepo: challengeRepo(challenge, nil),
+			accRepo:       accRepo(lockedAccount, nil),
+			reqBody:       bytes.NewBufferString(fmt.Sprintf(`{"challengeID":"%s","code":"%s"}`, challenge.ID, code)),
+			expectedCode:  http.StatusTooManyRequests,
+			expectedHeaderMap: http.Header{
+				"Content-Type": []string{"application/json; charset=utf-8"},
+				"Retry-After":  []string{"600"},
+			},
+			expectedBody: bytes.NewBufferString(`{
+				"errors":[
+					{
+						"message":"Account is temporarily locked due to too many failed login attempts"
+					}
+				]
+			}`),
+		},
+		{
+			caseName:    "Error on challengeRepo.Attempt should result in 500",
+			reqTenantID: 1,
+			challengeRepo: totp.NewFakeChallengeRepository(
+				nil,
+				[]totp.FakeChallengeRepositoryFindByIDResult{
+					{
+						Challenge: challenge,
+					},
+				},
+				[]totp.FakeChallengeRepositoryAttemptResult{
+					{
+						Error: fmt.Errorf("Attempt failed"),
+					},
+				},
+				nil,
+			),
+			accRepo:           accRepo(activeAccount, nil),
+			totpRepo:          enrolled(nil),
+			reqBody:           bytes.NewBufferString(fmt.Sprintf(`{"challengeID":"%s","code":"%s"}`, challenge.ID, code)),
+			expectedCode:      http.StatusInternalServerError,
+			expectedHeaderMap: http.Header{},
+			expectedBody:      bytes.NewBuffer(nil),
+		},
+		{
+			caseName:    "Challenge without attempts left should result in 401",
+			reqTenantID: 1,
+			challengeRepo: totp.NewFakeChallengeRepository(
+				nil,
+				[]totp.FakeChallengeRepositoryFindByIDResult{
+					{
+						Challenge: challenge,
+					},
+				},
+				[]totp.FakeChallengeRepositoryAttemptResult{
+					{
+						Allowed: false,
+					},
+				},
+				nil,
+			),
+			accRepo:           accRepo(activeAccount, nil),
+			totpRepo:          enrolled(nil),
+			reqBody:           bytes.NewBufferString(fmt.Sprintf(`{"challengeID":"%s","code":"%s"}`, challenge.ID, code)),
+			expectedCode:      http.StatusUnauthorized,
+			expectedHeaderMap: http.Header{},
+			expectedBody:      bytes.NewBuffer(nil),
+		},
+		{
+			caseName:          "Wrong code should result in 401",
+			reqTenantID:       1,
+			challengeRepo:     challengeRepo(challenge, nil),
+			accRepo:           accRepo(activeAccount, nil),
+			totpRepo:          enrolled(nil),
+			reqBody:           bytes.NewBufferString(fmt.Sprintf(`{"challengeID":"%s","code":"000000"}`, challenge.ID)),
+			expectedCode:      http.StatusUnauthorized,
+			expectedHeaderMap: http.Header{},
+			expectedBody:      bytes.NewBuffer(nil),
+		},
+		{
+			caseName:      "Replayed code reaching the threshold should result in 429",
+			reqTenantID:   1,
+			challengeRepo: challengeRepo(challenge, nil),
+			accRepo: accRepo(activeAccount, []account.FakeRepositoryRegisterFailedLoginResult{
+				{
+					Locked: true,
+				},
+			}),
+			totpRepo: enrolled([]totp.FakeRepositoryUseCounterResult{
+				{
+					Used: false,
+				},
+			}),
+			opts: Options{
+				LockoutThreshold: 5,
+				LockoutDuration:  time.Minute * 15,
+			},
+			reqBody:      bytes.NewBufferString(fmt.Sprintf(`{"challengeID":"%s","code":"%s"}`, challenge.ID, code)),
+			expectedCode: http.StatusTooManyRequests,
+			expectedHeaderMap: http.Header{
+				"Content-Type": []string{"application/json; charset=utf-8"},
+				"Retry-After":  []string{"900"},
+			},
+			expectedBody: bytes.NewBufferString(`{
+				"errors":[
+					{
+						"message":"Account is temporarily locked due to too many failed login attempts"
+					}
+				]
+			}`),
+		},
+		{
+			caseName:      "Wrong recovery code should result in 401",
+			reqTenantID:   1,
+			challengeRepo: challengeRepo(challenge, nil),
+			accRepo:       accRepo(activeAccount, nil),
+			totpRepo:      enrolled(nil),
+			recoveryRepo: recovery.NewFakeRepository(
+				nil,
+				[]recovery.FakeRepositoryListByAccountResult{
+					{
+						Codes: []*recovery.Code{
+							{ID: 1, AccountID: 123, Hash: []byte("code_hash")},
+						},
+					},
+				},
+				nil,
+			),
+			hasher: hasher.NewFakeHasher(
+				nil,
+				[]hasher.FakeCompareHashWithPasswordResult{
+					{
+						Error: fmt.Errorf("Mismatch"),
+					},
+				},
+			),
+			reqBody:           bytes.NewBufferString(fmt.Sprintf(`{"challengeID":"%s","recoveryCode":"abcde-fghjk"}`, challenge.ID)),
+			expectedCode:      http.StatusUnauthorized,
+			expectedHeaderMap: http.Header{},
+			expectedBody:      bytes.NewBuffer(nil),
+		},
+		{
+			caseName:    "Error on challengeRepo.Delete should result in 500",
+			reqTenantID: 1,
+			challengeRepo: challengeRepo(challenge, []totp.FakeChallengeRepositoryDeleteResult{
+				{
+					Error: fmt.Errorf("Delete failed"),
+				},
+			}),
+			accRepo: accRepo(activeAccount, nil),
+			totpRepo: enrolled([]totp.FakeRepositoryUseCounterResult{
+				{
+					Used: true,
+				},
+			}),
+			reqBody:           bytes.NewBufferString(fmt.Sprintf(`{"challengeID":"%s","code":"%s"}`, challenge.ID, code)),
+			expectedCode:      http.StatusInternalServerError,
+			expectedHeaderMap: http.Header{},
+			expectedBody:      bytes.NewBuffer(nil),
+		},
+		{
+			caseName:    "Successful",
+			reqTenantID: 1,
+			challengeRepo: challengeRepo(challenge, []totp.FakeChallengeRepositoryDeleteResult{
+				{
+					Error: nil,
+				},
+			}),
+			accRepo: accRepo(activeAccount, nil),
+			totpRepo: enrolled([]totp.FakeRepositoryUseCounterResult{
+				{
+					Used: true,
+				},
+			}),
+			sessRepo: session.NewFakeRepository(
+				[]session.FakeRepositorySaveResult{
+					{
+						Error: nil,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			notary: notary.NewFakeNotary(
+				[]notary.FakeNotarySignResult{
+					{
+						Signature: []byte("signature"),
+					},
+				},
+				nil,
+			),
+			packer: packer.NewFakePacker(
+				[]packer.FakePackerPackResult{
+					{
+						Pack:  []byte("pack"),
+						Error: nil,
+					},
+				},
+				nil,
+			),
+			reqBody:      bytes.NewBufferString(fmt.Sprintf(`{"challengeID":"%s","code":"%s"}`, challenge.ID, code)),
+			expectedCode: http.StatusCreated,
+			expectedHeaderMap: http.Header{
+				"Content-Type": []string{"application/json; charset=utf-8"},
+			},
+			expectedBody: bytes.NewBufferString(fmt.Sprintf(`{
+				"result": {
+					"token":"pack",
+					"id":"12345678-90ab-cdef-0123-4567890abcde",
+					"accountID":123,
+					"tenantID":1,
+					"createdAt":"%s",
+					"expiresAt":"%s"
+				}
+			}`, now.Format(time.RFC3339), now.Add(SessionDefaultDuration).Format(time.RFC3339))),
+		},
+		{
+			caseName:    "Successful with a recovery code",
+			reqTenantID: 1,
+			challengeRepo: challengeRepo(challenge, []totp.FakeChallengeRepositoryDeleteResult{
+				{
+					Error: nil,
+				},
+			}),
+			accRepo:  accRepo(activeAccount, nil),
+			totpRepo: enrolled(nil),
+			recoveryRepo: recovery.NewFakeRepository(
+				nil,
+				[]recovery.FakeRepositoryListByAccountResult{
+					{
+						Codes: []*recovery.Code{
+							{ID: 1, AccountID: 123, Hash: []byte("code_hash")},
+						},
+					},
+				},
+				[]recovery.FakeRepositoryUseResult{
+					{
+						Used: true,
+					},
+				},
+			),
+			hasher: hasher.NewFakeHasher(
+				nil,
+				[]hasher.FakeCompareHashWithPasswordResult{
+					{
+						Error: nil,
+					},
+				},
+			),
+			sessRepo: session.NewFakeRepository(
+				[]session.FakeRepositorySaveResult{
+					{
+						Error: nil,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			notary: notary.NewFakeNotary(
+				[]notary.FakeNotarySignResult{
+					{
+						Signature: []byte("signature"),
+					},
+				},
+				nil,
+			),
+			packer: packer.NewFakePacker(
+				[]packer.FakePackerPackResult{
+					{
+						Pack:  []byte("pack"),
+						Error: nil,
+					},
+				},
+				nil,
+			),
+			reqBody:      bytes.NewBufferString(fmt.Sprintf(`{"challengeID":"%s","recoveryCode":"ABCDE-FGHJK"}`, challenge.ID)),
+			expectedCode: http.StatusCreated,
+			expectedHeaderMap: http.Header{
+				"Content-Type": []string{"application/json; charset=utf-8"},
+			},
+			expectedBody: bytes.NewBufferString(fmt.Sprintf(`{
+				"result": {
+					"token":"pack",
+					"id":"12345678-90ab-cdef-0123-4567890abcde",
+					"accountID":123,
+					"tenantID":1,
+					"createdAt":"%s",
+					"expiresAt":"%s"
+				}
+			}`, now.Format(time.RFC3339), now.Add(SessionDefaultDuration).Format(time.RFC3339))),
+		},
+	}
+
+	for i, c := range cases {
+		ctrl := NewRestController(
+			fakeLogger,
+			c.accRepo,
+			c.sessRepo,
+			nil,
+			c.totpRepo,
+			c.challengeRepo,
+			c.recoveryRepo,
+			c.notary,
+			c.packer,
+			c.hasher,
+			uuidProducer,
+			clock.Fixed(now),
+			c.opts,
+		)
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, PathSessionsTOTP, c.reqBody)
+		if c.reqTenantID != 0 {
+			req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyTenantID{}, c.reqTenantID))
+		}
+
+		ctrl.PostSessionsTOTP(w, req)
+
+		if w.Code != c.expectedCode {
+			t.Errorf(
+				"testcase %d %s:\nExpected status code to be %v, but got %v\n",
+				i,
+				c.caseName,
+				c.expectedCode,
+				w.Code,
+			)
+		}
+
+		if !reflect.DeepEqual(w.HeaderMap, c.expectedHeaderMap) {
+			t.Errorf(
+				"testcase %d %s:\nExpected header map to be %v, but got %v\n",
+				i,
+				c.caseName,
+				c.expectedHeaderMap,
+				w.HeaderMap,
+			)
+		}
+
+		// To make possible pretty formatting in test cases, we use json.Compact:
+		b := c.expectedBody.Bytes()
+		if b != nil {
+			c.expectedBody.Reset()
+			err := json.Compact(c.expectedBody, b)
+			if err != nil {
+				t.Errorf(
+					"testcase %d %s:\nFailed to compact JSON body: %s",
+					i,
+					c.caseName,
+					err,
+				)
+			}
+		}
+
+		if !bytes.Equal(w.Body.Bytes(), c.expectedBody.Bytes()) {
+			t.Errorf(
+				"testcase %d %s:\nExpected body to be\n%v\nbut got\n%v\n",
+				i,
+				c.caseName,
+				c.expectedBody,
+				w.Body,
+			)
+		}
+	}
+}
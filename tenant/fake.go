@@ -0,0 +1,43 @@
+package tenant
+
+type fakeRepository struct {
+	findByNameResults       []FakeRepositoryFindByNameResult
+	findByNameResultCounter int
+	findByHostResults       []FakeRepositoryFindByHostResult
+	findByHostResultCounter int
+}
+
+type FakeRepositoryFindByNameResult struct {
+	Tenant *Tenant
+	Error  error
+}
+
+type FakeRepositoryFindByHostResult struct {
+	Tenant *Tenant
+	Error  error
+}
+
+// NewFakeRepository returns a new fake Repository.
+func NewFakeRepository(
+	findByNameResults []FakeRepositoryFindByNameResult,
+	findByHostResults []FakeRepositoryFindByHostResult,
+) Repository {
+	return &fakeRepository{
+		findByNameResults:       findByNameResults,
+		findByNameResultCounter: 0,
+		findByHostResults:       findByHostResults,
+		findByHostResultCounter: 0,
+	}
+}
+
+func (r *fakeRepository) FindByName(name string) (*Tenant, error) {
+	res := r.findByNameResults[r.findByNameResultCounter]
+	r.findByNameResultCounter++
+	return res.Tenant, res.Error
+}
+
+func (r *fakeRepository) FindByHost(host string) (*Tenant, error) {
+	res := r.findByHostResults[r.findByHostResultCounter]
+	r.findByHostResultCounter++
+	return res.Tenant, res.Error
+}
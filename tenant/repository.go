@@ -0,0 +1,26 @@
+package tenant
+
+// Repository is a repository for a Tenant.
+type Repository interface {
+	// FindByName retrieves a Tenant for matching name.
+	// If tenant with such name not found, returns ErrNotFound.
+	// Other errors may occur.
+	FindByName(name string) (*Tenant, error)
+
+	// FindByHost retrieves a Tenant served on the host.
+	// If tenant with such host not found, returns ErrNotFound.
+	// Other errors may occur.
+	FindByHost(host string) (*Tenant, error)
+}
+
+// repositoryError is an error occurred in Repository.
+type repositoryError string
+
+func (e repositoryError) Error() string {
+	return string(e)
+}
+
+const (
+	// ErrNotFound occurs when tenant not found.
+	ErrNotFound = repositoryError("Tenant not found")
+)
@@ -0,0 +1,32 @@
+package tenant
+
+import (
+	"net"
+	"strings"
+)
+
+// HostResolver returns a func which can be used to resolve the tenant
+// served on the host. Port, if any, is ignored.
+// If no tenant is served on the host, the tenant named fallback is resolved.
+// If fallback is empty too, the tenant is not found.
+func HostResolver(repo Repository, fallback string) func(host string) (tenantID int64, found bool, err error) {
+	return func(host string) (int64, bool, error) {
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		host = strings.ToLower(host)
+
+		t, err := repo.FindByHost(host)
+		if err == ErrNotFound && fallback != "" {
+			t, err = repo.FindByName(fallback)
+		}
+		if err == ErrNotFound {
+			return 0, false, nil
+		}
+		if err != nil {
+			return 0, false, err
+		}
+
+		return t.ID, true, nil
+	}
+}
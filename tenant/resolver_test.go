@@ -0,0 +1,144 @@
+package tenant
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestHostResolver(t *testing.T) {
+	cases := []struct {
+		caseName string
+		// in
+		repo     Repository
+		fallback string
+		host     string
+		// out
+		expectedTenantID int64
+		expectedFound    bool
+		expectedError    error
+	}{
+		{
+			caseName: "Tenant is served on the host",
+			repo: NewFakeRepository(
+				nil,
+				[]FakeRepositoryFindByHostResult{
+					{
+						Tenant: &Tenant{ID: 2, Name: "acme", Host: "acme.example.com"},
+					},
+				},
+			),
+			fallback:         "default",
+			host:             "acme.example.com:9090",
+			expectedTenantID: 2,
+			expectedFound:    true,
+			expectedError:    nil,
+		},
+		{
+			caseName: "Unknown host falls back to the default tenant",
+			repo: NewFakeRepository(
+				[]FakeRepositoryFindByNameResult{
+					{
+						Tenant: &Tenant{ID: 1, Name: "default"},
+					},
+				},
+				[]FakeRepositoryFindByHostResult{
+					{
+						Error: ErrNotFound,
+					},
+				},
+			),
+			fallback:         "default",
+			host:             "localhost:9090",
+			expectedTenantID: 1,
+			expectedFound:    true,
+			expectedError:    nil,
+		},
+		{
+			caseName: "Unknown host without fallback",
+			repo: NewFakeRepository(
+				nil,
+				[]FakeRepositoryFindByHostResult{
+					{
+						Error: ErrNotFound,
+					},
+				},
+			),
+			fallback:         "",
+			host:             "localhost",
+			expectedTenantID: 0,
+			expectedFound:    false,
+			expectedError:    nil,
+		},
+		{
+			caseName: "Fallback tenant does not exist",
+			repo: NewFakeRepository(
+				[]FakeRepositoryFindByNameResult{
+					{
+						Error: ErrNotFound,
+					},
+				},
+				[]FakeRepositoryFindByHostResult{
+					{
+						Error: ErrNotFound,
+					},
+				},
+			),
+			fallback:         "default",
+			host:             "localhost",
+			expectedTenantID: 0,
+			expectedFound:    false,
+			expectedError:    nil,
+		},
+		{
+			caseName: "repo.FindByHost failed",
+			repo: NewFakeRepository(
+				nil,
+				[]FakeRepositoryFindByHostResult{
+					{
+						Error: fmt.Errorf("FindByHost failed"),
+					},
+				},
+			),
+			fallback:         "default",
+			host:             "localhost",
+			expectedTenantID: 0,
+			expectedFound:    false,
+			expectedError:    fmt.Errorf("FindByHost failed"),
+		},
+	}
+
+	for i, c := range cases {
+		tenantID, found, err := HostResolver(c.repo, c.fallback)(c.host)
+
+		if tenantID != c.expectedTenantID {
+			t.Errorf(
+				"testcase %d %s:\nExpected tenant ID to be %v, but got %v\n",
+				i,
+				c.caseName,
+				c.expectedTenantID,
+				tenantID,
+			)
+		}
+
+		if found != c.expectedFound {
+			t.Errorf(
+				"testcase %d %s:\nExpected found to be %v, but got %v\n",
+				i,
+				c.caseName,
+				c.expectedFound,
+				found,
+			)
+		}
+
+		if !reflect.DeepEqual(err, c.expectedError) {
+			t.Errorf(
+				"testcase %d %s:\nExpected error to be %v, but got %v\n",
+				i,
+				c.caseName,
+				c.expectedError,
+				err,
+			)
+		}
+	}
+}
@@ -0,0 +1,15 @@
+package tenant
+
+import "time"
+
+// Tenant represents an organization that owns its own namespace of accounts.
+type Tenant struct {
+	ID   int64
+	Name string
+
+	// Host is the domain the tenant is served on. Empty if the tenant
+	// has no domain of its own.
+	Host string
+
+	CreatedAt time.Time
+}
@@ -0,0 +1,42 @@
+package totp
+
+import (
+	"time"
+
+	"github.com/hypnoglow/pascont/identity"
+)
+
+const (
+	// ChallengeDuration is how long a challenge can be completed.
+	ChallengeDuration = time.Minute * 5
+
+	// ChallengeMaxAttempts is how many codes can be tried to complete a challenge.
+	ChallengeMaxAttempts = 5
+
+	// MaxActiveChallenges is how many challenges of an account can be not expired at once,
+	// so new challenges do not give unlimited attempts.
+	MaxActiveChallenges = 3
+)
+
+// Challenge represents a login which passed the password check
+// and must be completed with a TOTP code.
+type Challenge struct {
+	ID        string
+	AccountID int64
+	ExpiresAt time.Time
+}
+
+// NewChallenge returns a new Challenge for the account.
+// The Challenge ID is a UUID produced by identity.UUIDProducer func.
+func NewChallenge(uuidProducer identity.UUIDProducer, accountID int64, createdAt time.Time) *Challenge {
+	return &Challenge{
+		ID:        uuidProducer(),
+		AccountID: accountID,
+		ExpiresAt: createdAt.Add(ChallengeDuration).UTC().Truncate(time.Second),
+	}
+}
+
+// IsExpired reports whether the challenge is expired at the moment t.
+func (c Challenge) IsExpired(t time.Time) bool {
+	return !t.Before(c.ExpiresAt)
+}
@@ -0,0 +1,32 @@
+package totp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/pascont/clock"
+)
+
+func TestNewChallenge(t *testing.T) {
+	now := clock.Fixed(time.Date(2017, 7, 1, 21, 10, 29, 0, time.UTC))
+	uuidProducer := func() string {
+		return "12345678-90ab-cdef-0123-4567890abcde"
+	}
+
+	c := NewChallenge(uuidProducer, 123, now())
+	if c.ID != "12345678-90ab-cdef-0123-4567890abcde" || c.AccountID != 123 {
+		t.Errorf("Unexpected challenge %#v\n", c)
+	}
+
+	if !c.ExpiresAt.Equal(now().Add(ChallengeDuration)) {
+		t.Errorf("Expected ExpiresAt to be %v but got %v\n", now().Add(ChallengeDuration), c.ExpiresAt)
+	}
+
+	if c.IsExpired(now()) {
+		t.Errorf("Expected new challenge not to be expired\n")
+	}
+
+	if !c.IsExpired(now().Add(ChallengeDuration)) {
+		t.Errorf("Expected challenge to be expired after %v\n", ChallengeDuration)
+	}
+}
@@ -0,0 +1,42 @@
+package totp
+
+import "time"
+
+// Enrollment represents a TOTP second factor of an account.
+type Enrollment struct {
+	AccountID int64
+	Secret    []byte
+
+	// ConfirmedAt is zero until the enrollment is confirmed with a first code.
+	ConfirmedAt time.Time
+
+	// LastUsedCounter is the time step number of the last accepted code.
+	// Codes of this and earlier time steps are not accepted again.
+	LastUsedCounter uint64
+}
+
+// NewEnrollment returns a new not confirmed Enrollment.
+func NewEnrollment(accountID int64, secret []byte) *Enrollment {
+	return &Enrollment{
+		AccountID: accountID,
+		Secret:    secret,
+	}
+}
+
+// IsConfirmed reports whether the enrollment is confirmed, so the second factor is required.
+func (e Enrollment) IsConfirmed() bool {
+	return !e.ConfirmedAt.IsZero()
+}
+
+// UseCode checks the code of the enrollment at the moment t and marks it used,
+// so every code is accepted only once.
+func UseCode(repo Repository, e Enrollment, code string, t time.Time) (bool, error) {
+	counter, ok := Validate(e.Secret, code, t)
+	if !ok || counter <= e.LastUsedCounter {
+		return false, nil
+	}
+
+	// The code may have been used concurrently since the enrollment was retrieved,
+	// so the repository decides.
+	return repo.UseCounter(e.AccountID, counter)
+}
@@ -0,0 +1,122 @@
+package totp
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/pascont/clock"
+)
+
+func TestEnrollment_IsConfirmed(t *testing.T) {
+	e := NewEnrollment(123, rfcSecret)
+	if e.IsConfirmed() {
+		t.Errorf("Expected new enrollment not to be confirmed\n")
+	}
+
+	e.ConfirmedAt = time.Now()
+	if !e.IsConfirmed() {
+		t.Errorf("Expected enrollment to be confirmed\n")
+	}
+}
+
+func TestUseCode(t *testing.T) {
+	now := clock.Fixed(time.Unix(1111111111, 0))
+	current := Counter(now())
+
+	cases := []struct {
+		caseName        string
+		repo            Repository
+		lastUsedCounter uint64
+		code            string
+		expectedOK      bool
+		expectedError   error
+	}{
+		{
+			caseName:   "Invalid code",
+			code:       "000000",
+			expectedOK: false,
+		},
+		{
+			caseName:        "Code was already used",
+			lastUsedCounter: current,
+			code:            Code(rfcSecret, current),
+			expectedOK:      false,
+		},
+		{
+			caseName:        "Code of earlier time step than the used one",
+			lastUsedCounter: current,
+			code:            Code(rfcSecret, current-1),
+			expectedOK:      false,
+		},
+		{
+			caseName: "Code was used concurrently",
+			repo: NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				[]FakeRepositoryUseCounterResult{
+					{
+						Used: false,
+					},
+				},
+				nil,
+			),
+			lastUsedCounter: current - 1,
+			code:            Code(rfcSecret, current),
+			expectedOK:      false,
+		},
+		{
+			caseName: "repo.UseCounter failed",
+			repo: NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				[]FakeRepositoryUseCounterResult{
+					{
+						Error: fmt.Errorf("UseCounter failed"),
+					},
+				},
+				nil,
+			),
+			code:          Code(rfcSecret, current),
+			expectedOK:    false,
+			expectedError: fmt.Errorf("UseCounter failed"),
+		},
+		{
+			caseName: "Successful",
+			repo: NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				[]FakeRepositoryUseCounterResult{
+					{
+						Used: true,
+					},
+				},
+				nil,
+			),
+			lastUsedCounter: current - 1,
+			code:            Code(rfcSecret, current),
+			expectedOK:      true,
+		},
+	}
+
+	for i, c := range cases {
+		e := Enrollment{
+			AccountID:       123,
+			Secret:          rfcSecret,
+			ConfirmedAt:     now(),
+			LastUsedCounter: c.lastUsedCounter,
+		}
+
+		ok, err := UseCode(c.repo, e, c.code, now())
+		if ok != c.expectedOK {
+			t.Errorf("testcase %d %s: Expected %v but got %v\n", i, c.caseName, c.expectedOK, ok)
+		}
+		if !reflect.DeepEqual(err, c.expectedError) {
+			t.Errorf("testcase %d %s: Expected error to be %v but got %v\n", i, c.caseName, c.expectedError, err)
+		}
+	}
+}
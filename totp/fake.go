@@ -0,0 +1,162 @@
+package totp
+
+import "time"
+
+type fakeRepository struct {
+	saveResults                []FakeRepositorySaveResult
+	saveResultCounter          int
+	findByAccountResults       []FakeRepositoryFindByAccountResult
+	findByAccountResultCounter int
+	confirmResults             []FakeRepositoryConfirmResult
+	confirmResultCounter       int
+	useCounterResults          []FakeRepositoryUseCounterResult
+	useCounterResultCounter    int
+	deleteResults              []FakeRepositoryDeleteResult
+	deleteResultCounter        int
+}
+
+type FakeRepositorySaveResult struct {
+	Error error
+}
+
+type FakeRepositoryFindByAccountResult struct {
+	Enrollment *Enrollment
+	Error      error
+}
+
+type FakeRepositoryConfirmResult struct {
+	Error error
+}
+
+type FakeRepositoryUseCounterResult struct {
+	Used  bool
+	Error error
+}
+
+type FakeRepositoryDeleteResult struct {
+	Error error
+}
+
+// NewFakeRepository returns a new fake Repository.
+func NewFakeRepository(
+	saveResults []FakeRepositorySaveResult,
+	findByAccountResults []FakeRepositoryFindByAccountResult,
+	confirmResults []FakeRepositoryConfirmResult,
+	useCounterResults []FakeRepositoryUseCounterResult,
+	deleteResults []FakeRepositoryDeleteResult,
+) Repository {
+	return &fakeRepository{
+		saveResults:                saveResults,
+		saveResultCounter:          0,
+		findByAccountResults:       findByAccountResults,
+		findByAccountResultCounter: 0,
+		confirmResults:             confirmResults,
+		confirmResultCounter:       0,
+		useCounterResults:          useCounterResults,
+		useCounterResultCounter:    0,
+		deleteResults:              deleteResults,
+		deleteResultCounter:        0,
+	}
+}
+
+func (r *fakeRepository) Save(e Enrollment) error {
+	res := r.saveResults[r.saveResultCounter]
+	r.saveResultCounter++
+	return res.Error
+}
+
+func (r *fakeRepository) FindByAccount(accountID int64) (*Enrollment, error) {
+	res := r.findByAccountResults[r.findByAccountResultCounter]
+	r.findByAccountResultCounter++
+	return res.Enrollment, res.Error
+}
+
+func (r *fakeRepository) Confirm(accountID int64, confirmedAt time.Time) error {
+	res := r.confirmResults[r.confirmResultCounter]
+	r.confirmResultCounter++
+	return res.Error
+}
+
+func (r *fakeRepository) UseCounter(accountID int64, counter uint64) (bool, error) {
+	res := r.useCounterResults[r.useCounterResultCounter]
+	r.useCounterResultCounter++
+	return res.Used, res.Error
+}
+
+func (r *fakeRepository) Delete(accountID int64) error {
+	res := r.deleteResults[r.deleteResultCounter]
+	r.deleteResultCounter++
+	return res.Error
+}
+
+type fakeChallengeRepository struct {
+	saveResults           []FakeChallengeRepositorySaveResult
+	saveResultCounter     int
+	findByIDResults       []FakeChallengeRepositoryFindByIDResult
+	findByIDResultCounter int
+	attemptResults        []FakeChallengeRepositoryAttemptResult
+	attemptResultCounter  int
+	deleteResults         []FakeChallengeRepositoryDeleteResult
+	deleteResultCounter   int
+}
+
+type FakeChallengeRepositorySaveResult struct {
+	Error error
+}
+
+type FakeChallengeRepositoryFindByIDResult struct {
+	Challenge *Challenge
+	Error     error
+}
+
+type FakeChallengeRepositoryAttemptResult struct {
+	Allowed bool
+	Error   error
+}
+
+type FakeChallengeRepositoryDeleteResult struct {
+	Error error
+}
+
+// NewFakeChallengeRepository returns a new fake ChallengeRepository.
+func NewFakeChallengeRepository(
+	saveResults []FakeChallengeRepositorySaveResult,
+	findByIDResults []FakeChallengeRepositoryFindByIDResult,
+	attemptResults []FakeChallengeRepositoryAttemptResult,
+	deleteResults []FakeChallengeRepositoryDeleteResult,
+) ChallengeRepository {
+	return &fakeChallengeRepository{
+		saveResults:           saveResults,
+		saveResultCounter:     0,
+		findByIDResults:       findByIDResults,
+		findByIDResultCounter: 0,
+		attemptResults:        attemptResults,
+		attemptResultCounter:  0,
+		deleteResults:         deleteResults,
+		deleteResultCounter:   0,
+	}
+}
+
+func (r *fakeChallengeRepository) Save(c Challenge) error {
+	res := r.saveResults[r.saveResultCounter]
+	r.saveResultCounter++
+	return res.Error
+}
+
+func (r *fakeChallengeRepository) FindByID(id string) (*Challenge, error) {
+	res := r.findByIDResults[r.findByIDResultCounter]
+	r.findByIDResultCounter++
+	return res.Challenge, res.Error
+}
+
+func (r *fakeChallengeRepository) Attempt(id string) (bool, error) {
+	res := r.attemptResults[r.attemptResultCounter]
+	r.attemptResultCounter++
+	return res.Allowed, res.Error
+}
+
+func (r *fakeChallengeRepository) Delete(id string) error {
+	res := r.deleteResults[r.deleteResultCounter]
+	r.deleteResultCounter++
+	return res.Error
+}
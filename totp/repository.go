@@ -0,0 +1,64 @@
+package totp
+
+import "time"
+
+// Repository is a repository for an Enrollment.
+// Secrets are kept encrypted by implementations.
+type Repository interface {
+	// Save saves an Enrollment, replacing the one of the account if any.
+	Save(e Enrollment) error
+
+	// FindByAccount retrieves the Enrollment of the account.
+	// If the account has no enrollment, returns ErrNotFound.
+	// Other errors may occur.
+	FindByAccount(accountID int64) (*Enrollment, error)
+
+	// Confirm marks the enrollment of the account confirmed.
+	Confirm(accountID int64, confirmedAt time.Time) error
+
+	// UseCounter atomically sets the LastUsedCounter of the enrollment
+	// if it is less than counter. Returns whether it was set.
+	UseCounter(accountID int64, counter uint64) (bool, error)
+
+	// Delete removes the enrollment of the account.
+	Delete(accountID int64) error
+}
+
+// ChallengeRepository is a repository for a Challenge.
+type ChallengeRepository interface {
+	// Save saves a Challenge.
+	// If the account already has MaxActiveChallenges challenges which are not expired,
+	// returns ErrTooManyChallenges. Other errors may occur.
+	Save(c Challenge) error
+
+	// FindByID retrieves a Challenge for matching id.
+	// If challenge with such id not found, returns ErrChallengeNotFound.
+	// Other errors may occur.
+	FindByID(id string) (*Challenge, error)
+
+	// Attempt counts an attempt to complete the challenge with the id.
+	// Returns false if the challenge is not found or has no attempts left
+	// out of ChallengeMaxAttempts.
+	Attempt(id string) (bool, error)
+
+	// Delete removes the challenge.
+	Delete(id string) error
+}
+
+// repositoryError is an error occurred in Repository.
+type repositoryError string
+
+func (e repositoryError) Error() string {
+	return string(e)
+}
+
+const (
+	// ErrNotFound occurs when enrollment not found.
+	ErrNotFound = repositoryError("TOTP enrollment not found")
+
+	// ErrChallengeNotFound occurs when challenge not found.
+	ErrChallengeNotFound = repositoryError("TOTP challenge not found")
+
+	// ErrTooManyChallenges occurs when an account has too many challenges at once.
+	ErrTooManyChallenges = repositoryError("Too many TOTP challenges")
+)
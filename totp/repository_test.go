@@ -0,0 +1,12 @@
+package totp
+
+import "testing"
+
+func TestTOTPRepositoryError_Error(t *testing.T) {
+	msg := "some error"
+
+	err := repositoryError(msg)
+	if err.Error() != msg {
+		t.Errorf("Expected %v but got %v\n", msg, err.Error())
+	}
+}
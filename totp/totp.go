@@ -0,0 +1,108 @@
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// Period is the time step of codes.
+	Period = 30 * time.Second
+
+	// Digits is the number of digits in a code.
+	Digits = 6
+
+	// Skew is the number of time steps before and after the current one
+	// which codes are still accepted, to tolerate clock drift.
+	Skew = 1
+
+	// secretLength is the length of a secret in bytes, as recommended by RFC 4226.
+	secretLength = 20
+)
+
+// GenerateSecret returns a new random secret.
+func GenerateSecret() ([]byte, error) {
+	secret := make([]byte, secretLength)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, errors.Wrap(err, "Failed to generate a TOTP secret")
+	}
+
+	return secret, nil
+}
+
+// EncodeSecret encodes the secret to the form authenticator apps accept.
+func EncodeSecret(secret []byte) string {
+	return base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(secret)
+}
+
+// URI returns an otpauth:// URI of the secret, which can be shown
+// as a QR code to be scanned by an authenticator app.
+func URI(issuer, accountName string, secret []byte) string {
+	query := url.Values{}
+	query.Set("secret", EncodeSecret(secret))
+	query.Set("issuer", issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprintf("%d", Digits))
+	query.Set("period", fmt.Sprintf("%d", int(Period.Seconds())))
+
+	u := url.URL{
+		Scheme:   "otpauth",
+		Host:     "totp",
+		Path:     "/" + issuer + ":" + accountName,
+		RawQuery: query.Encode(),
+	}
+
+	return u.String()
+}
+
+// Counter returns the time step number at the moment t.
+func Counter(t time.Time) uint64 {
+	return uint64(t.Unix()) / uint64(Period.Seconds())
+}
+
+// Code returns the code of the secret for the time step number, as described in RFC 4226.
+func Code(secret []byte, counter uint64) string {
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint64(msg, counter)
+
+	mac := hmac.New(sha1.New, secret)
+	mac.Write(msg)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < Digits; i++ {
+		mod *= 10
+	}
+
+	return fmt.Sprintf("%0*d", Digits, value%mod)
+}
+
+// Validate checks the code of the secret at the moment t, tolerating Skew time steps.
+// Returns the time step number the code belongs to.
+func Validate(secret []byte, code string, t time.Time) (counter uint64, ok bool) {
+	if len(code) != Digits {
+		return 0, false
+	}
+
+	current := Counter(t)
+	for i := -Skew; i <= Skew; i++ {
+		counter = uint64(int64(current) + int64(i))
+		if subtle.ConstantTimeCompare([]byte(Code(secret, counter)), []byte(code)) == 1 {
+			return counter, true
+		}
+	}
+
+	return 0, false
+}
@@ -0,0 +1,113 @@
+package totp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/pascont/clock"
+)
+
+// rfcSecret is the secret of RFC 6238 test vectors.
+var rfcSecret = []byte("12345678901234567890")
+
+func TestCode(t *testing.T) {
+	// RFC 6238 test vectors for SHA1, truncated to Digits.
+	cases := []struct {
+		unix     int64
+		expected string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+
+	for i, c := range cases {
+		actual := Code(rfcSecret, Counter(time.Unix(c.unix, 0)))
+		if actual != c.expected {
+			t.Errorf("testcase %d: Expected code to be %s but got %s\n", i, c.expected, actual)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := clock.Fixed(time.Unix(1111111111, 0))
+	current := Counter(now())
+
+	cases := []struct {
+		caseName        string
+		code            string
+		expectedCounter uint64
+		expectedOK      bool
+	}{
+		{
+			caseName:        "Current code",
+			code:            Code(rfcSecret, current),
+			expectedCounter: current,
+			expectedOK:      true,
+		},
+		{
+			caseName:        "Previous code is within the skew",
+			code:            Code(rfcSecret, current-1),
+			expectedCounter: current - 1,
+			expectedOK:      true,
+		},
+		{
+			caseName:        "Next code is within the skew",
+			code:            Code(rfcSecret, current+1),
+			expectedCounter: current + 1,
+			expectedOK:      true,
+		},
+		{
+			caseName:   "Code is too old",
+			code:       Code(rfcSecret, current-2),
+			expectedOK: false,
+		},
+		{
+			caseName:   "Code is of wrong length",
+			code:       "12345",
+			expectedOK: false,
+		},
+	}
+
+	for i, c := range cases {
+		counter, ok := Validate(rfcSecret, c.code, now())
+		if counter != c.expectedCounter || ok != c.expectedOK {
+			t.Errorf(
+				"testcase %d %s: Expected (%v, %v) but got (%v, %v)\n",
+				i,
+				c.caseName,
+				c.expectedCounter,
+				c.expectedOK,
+				counter,
+				ok,
+			)
+		}
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	a, err := GenerateSecret()
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	b, err := GenerateSecret()
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+
+	if len(a) != secretLength || string(a) == string(b) {
+		t.Errorf("Expected random secrets of %d bytes, but got %x and %x\n", secretLength, a, b)
+	}
+}
+
+func TestURI(t *testing.T) {
+	expected := "otpauth://totp/pascont:email@email.com?algorithm=SHA1&digits=6&issuer=pascont&period=30&secret=GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+	actual := URI("pascont", "email@email.com", rfcSecret)
+	if actual != expected {
+		t.Errorf("Expected URI to be\n%s\nbut got\n%s\n", expected, actual)
+	}
+}
@@ -0,0 +1,82 @@
+package twofactor
+
+import (
+	"net/http"
+
+	"github.com/hypnoglow/pascont/kit"
+	"github.com/hypnoglow/pascont/kit/form"
+	"github.com/hypnoglow/pascont/kit/schema"
+	"github.com/hypnoglow/pascont/session"
+	"github.com/hypnoglow/pascont/totp"
+)
+
+// PostTOTPConfirm is a handler for:
+// POST /accounts/:id/totp/confirm
+//
+// Once confirmed, the account requires a TOTP code to log in.
+// The response has recovery codes, which can be spent instead of a TOTP code.
+func (c RestController) PostTOTPConfirm(w http.ResponseWriter, req *http.Request) {
+	current, status := session.AccountSession(req, c.sessionRepo, c.logger)
+	if current == nil {
+		w.WriteHeader(status)
+		return
+	}
+
+	var f codeForm
+	form.PopulateFormFromJSON(req.Body, &f)
+	if !f.Validate() {
+		kit.RespondWithFormErrors(w, http.StatusBadRequest, f.ValidationErrors())
+		return
+	}
+
+	e, err := c.totpRepo.FindByAccount(current.AccountID)
+	if err == totp.ErrNotFound {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		c.logger.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	if e.IsConfirmed() {
+		kit.RespondWithError(w, http.StatusConflict, schema.ErrorFromMessage("TOTP is already enabled"))
+		return
+	}
+
+	now := c.clock()
+	ok, err := totp.UseCode(c.totpRepo, *e, f.Code, now)
+	if err != nil {
+		c.logger.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if !ok {
+		kit.RespondWithError(w, http.StatusBadRequest, schema.NewError("Code is invalid", "code", f.Code))
+		return
+	}
+
+	// Recovery codes are saved before the confirmation,
+	// so there is no enabled TOTP without them.
+	codes, err := c.newRecoveryCodes(e.AccountID)
+	if err != nil {
+		c.logger.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	if err := c.totpRepo.Confirm(e.AccountID, now); err != nil {
+		c.logger.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	// Recovery codes are revealed only once, they can not be retrieved later.
+	kit.RespondJSON(w, http.StatusOK, schema.NewResultBody(
+		recoveryCodesSchema{
+			RecoveryCodes: codes,
+		},
+		nil,
+	))
+}
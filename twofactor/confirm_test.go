@@ -0,0 +1,284 @@
+package twofactor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/pascont/clock"
+	"github.com/hypnoglow/pascont/hasher"
+	"github.com/hypnoglow/pascont/kit/middleware"
+	"github.com/hypnoglow/pascont/recovery"
+	"github.com/hypnoglow/pascont/session"
+	"github.com/hypnoglow/pascont/totp"
+)
+
+func TestRestController_PostTOTPConfirm(t *testing.T) {
+	now := clock.Fixed(time.Date(2017, 7, 1, 21, 10, 29, 0, time.UTC))
+	fakeLogger := log.New(ioutil.Discard, "", log.LstdFlags)
+	secret := []byte("12345678901234567890")
+	code := totp.Code(secret, totp.Counter(now()))
+
+	currentSession := &session.Session{
+		ID:        "12345678-90ab-cdef-0123-4567890abcde",
+		AccountID: 123,
+		CreatedAt: now(),
+		ExpiresAt: now().Add(time.Hour),
+	}
+	sessRepo := func() session.Repository {
+		return session.NewFakeRepository(
+			nil,
+			[]session.FakeRepositoryFindByIDResult{
+				{
+					Session: currentSession,
+				},
+			},
+			nil,
+			nil,
+			nil,
+			nil,
+		)
+	}
+
+	cases := []struct {
+		caseName string
+		// in
+		sessRepo                 session.Repository
+		totpRepo                 totp.Repository
+		recoveryRepo             recovery.Repository
+		hasher                   hasher.Hasher
+		reqContextSessionIDValue interface{}
+		reqBody                  io.Reader
+		// out
+		expectedCode int
+		expectedBody *bytes.Buffer
+	}{
+		{
+			caseName: "There is no session ID from Authorization Header",
+			reqBody:  bytes.NewBufferString(fmt.Sprintf(`{"code":"%s"}`, code)),
+			// out
+			expectedCode: http.StatusUnauthorized,
+			expectedBody: bytes.NewBuffer(nil),
+		},
+		{
+			caseName:                 "Code of wrong length should result in 400",
+			sessRepo:                 sessRepo(),
+			reqContextSessionIDValue: currentSession.ID,
+			reqBody:                  bytes.NewBufferString(`{"code":"123"}`),
+			// out
+			expectedCode: http.StatusBadRequest,
+			expectedBody: bytes.NewBufferString(`{
+				"errors":[
+					{
+						"message":"Code must be 6 digits",
+						"field":"code",
+						"value":"123"
+					}
+				]
+			}`),
+		},
+		{
+			caseName: "TOTP is not enrolled",
+			sessRepo: sessRepo(),
+			totpRepo: totp.NewFakeRepository(
+				nil,
+				[]totp.FakeRepositoryFindByAccountResult{
+					{
+						Error: totp.ErrNotFound,
+					},
+				},
+				nil,
+				nil,
+				nil,
+			),
+			reqContextSessionIDValue: currentSession.ID,
+			reqBody:                  bytes.NewBufferString(fmt.Sprintf(`{"code":"%s"}`, code)),
+			// out
+			expectedCode: http.StatusNotFound,
+			expectedBody: bytes.NewBuffer(nil),
+		},
+		{
+			caseName: "TOTP is already enabled",
+			sessRepo: sessRepo(),
+			totpRepo: totp.NewFakeRepository(
+				nil,
+				[]totp.FakeRepositoryFindByAccountResult{
+					{
+						Enrollment: &totp.Enrollment{AccountID: 123, Secret: secret, ConfirmedAt: now()},
+					},
+				},
+				nil,
+				nil,
+				nil,
+			),
+			reqContextSessionIDValue: currentSession.ID,
+			reqBody:                  bytes.NewBufferString(fmt.Sprintf(`{"code":"%s"}`, code)),
+			// out
+			expectedCode: http.StatusConflict,
+			expectedBody: bytes.NewBufferString(`{
+				"errors":[
+					{
+						"message":"TOTP is already enabled"
+					}
+				]
+			}`),
+		},
+		{
+			caseName: "Invalid code should result in 400",
+			sessRepo: sessRepo(),
+			totpRepo: totp.NewFakeRepository(
+				nil,
+				[]totp.FakeRepositoryFindByAccountResult{
+					{
+						Enrollment: &totp.Enrollment{AccountID: 123, Secret: secret},
+					},
+				},
+				nil,
+				nil,
+				nil,
+			),
+			reqContextSessionIDValue: currentSession.ID,
+			reqBody:                  bytes.NewBufferString(`{"code":"000000"}`),
+			// out
+			expectedCode: http.StatusBadRequest,
+			expectedBody: bytes.NewBufferString(`{
+				"errors":[
+					{
+						"message":"Code is invalid",
+						"field":"code",
+						"value":"000000"
+					}
+				]
+			}`),
+		},
+		{
+			caseName: "Error on recoveryRepo.Replace should result in 500",
+			sessRepo: sessRepo(),
+			totpRepo: totp.NewFakeRepository(
+				nil,
+				[]totp.FakeRepositoryFindByAccountResult{
+					{
+						Enrollment: &totp.Enrollment{AccountID: 123, Secret: secret},
+					},
+				},
+				nil,
+				[]totp.FakeRepositoryUseCounterResult{
+					{
+						Used: true,
+					},
+				},
+				nil,
+			),
+			recoveryRepo: recovery.NewFakeRepository(
+				[]recovery.FakeRepositoryReplaceResult{
+					{
+						Error: fmt.Errorf("Replace failed"),
+					},
+				},
+				nil,
+				nil,
+			),
+			hasher:                   recoveryCodesHasher(),
+			reqContextSessionIDValue: currentSession.ID,
+			reqBody:                  bytes.NewBufferString(fmt.Sprintf(`{"code":"%s"}`, code)),
+			// out
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: bytes.NewBuffer(nil),
+		},
+		{
+			caseName: "Successful",
+			sessRepo: sessRepo(),
+			totpRepo: totp.NewFakeRepository(
+				nil,
+				[]totp.FakeRepositoryFindByAccountResult{
+					{
+						Enrollment: &totp.Enrollment{AccountID: 123, Secret: secret},
+					},
+				},
+				[]totp.FakeRepositoryConfirmResult{
+					{
+						Error: nil,
+					},
+				},
+				[]totp.FakeRepositoryUseCounterResult{
+					{
+						Used: true,
+					},
+				},
+				nil,
+			),
+			recoveryRepo: recovery.NewFakeRepository(
+				[]recovery.FakeRepositoryReplaceResult{
+					{
+						Error: nil,
+					},
+				},
+				nil,
+				nil,
+			),
+			hasher:                   recoveryCodesHasher(),
+			reqContextSessionIDValue: currentSession.ID,
+			reqBody:                  bytes.NewBufferString(fmt.Sprintf(`{"code":"%s"}`, code)),
+			// out
+			expectedCode: http.StatusOK,
+			expectedBody: bytes.NewBufferString(`{
+				"result":{
+					"recoveryCodes":` + expectedRecoveryCodes + `
+				}
+			}`),
+		},
+	}
+
+	for i, c := range cases {
+		ctrl := NewRestController(fakeLogger, nil, c.sessRepo, c.totpRepo, c.recoveryRepo, c.hasher, now, Options{Issuer: "pascont"})
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/accounts/me/totp/confirm", c.reqBody)
+		req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyPathParams{}, map[string]string{"id": "me"}))
+		req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeySessionID{}, c.reqContextSessionIDValue))
+		ctrl.PostTOTPConfirm(w, req)
+
+		if w.Code != c.expectedCode {
+			t.Errorf(
+				"testcase %d %s:\nExpected status code to be %v, but got %v\n",
+				i,
+				c.caseName,
+				c.expectedCode,
+				w.Code,
+			)
+		}
+
+		// To make possible pretty formatting in test cases, we use json.Compact:
+		b := c.expectedBody.Bytes()
+		if b != nil {
+			c.expectedBody.Reset()
+			err := json.Compact(c.expectedBody, b)
+			if err != nil {
+				t.Errorf(
+					"testcase %d %s:\nFailed to compact JSON body: %s",
+					i,
+					c.caseName,
+					err,
+				)
+			}
+		}
+
+		body := recoveryCodePattern.ReplaceAll(w.Body.Bytes(), []byte(`"<code>"`))
+		if !bytes.Equal(body, c.expectedBody.Bytes()) {
+			t.Errorf(
+				"testcase %d %s:\nExpected body to be\n%v\nbut got\n%v\n",
+				i,
+				c.caseName,
+				c.expectedBody,
+				w.Body,
+			)
+		}
+	}
+}
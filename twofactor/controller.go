@@ -0,0 +1,123 @@
+package twofactor
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hypnoglow/pascont/account"
+	"github.com/hypnoglow/pascont/clock"
+	"github.com/hypnoglow/pascont/hasher"
+	"github.com/hypnoglow/pascont/kit/form"
+	"github.com/hypnoglow/pascont/recovery"
+	"github.com/hypnoglow/pascont/session"
+	"github.com/hypnoglow/pascont/totp"
+)
+
+const (
+	PathAccountTOTP         = "/accounts/:id/totp"
+	PathAccountTOTPConfirm  = "/accounts/:id/totp/confirm"
+	PathAccountTOTPRecovery = "/accounts/:id/totp/recovery-codes"
+)
+
+// RestController is a REST controller for two-factor authentication.
+type RestController struct {
+	logger       *log.Logger
+	accountRepo  account.Repository
+	sessionRepo  session.Repository
+	totpRepo     totp.Repository
+	recoveryRepo recovery.Repository
+	hasher       hasher.Hasher
+	clock        clock.Clock
+	options      Options
+}
+
+type Options struct {
+	// Issuer is shown to the user in the authenticator app.
+	Issuer string
+}
+
+// NewRestController returns a new RestController.
+func NewRestController(
+	logger *log.Logger,
+	accountRepo account.Repository,
+	sessionRepo session.Repository,
+	totpRepo totp.Repository,
+	recoveryRepo recovery.Repository,
+	h hasher.Hasher,
+	clk clock.Clock,
+	opts Options,
+) RestController {
+	return RestController{
+		logger,
+		accountRepo,
+		sessionRepo,
+		totpRepo,
+		recoveryRepo,
+		h,
+		clk,
+		opts,
+	}
+}
+
+// codeForm is a form with a TOTP code.
+type codeForm struct {
+	form.BaseForm
+	Code string `json:"code"`
+}
+
+func (f *codeForm) Validate() bool {
+	if len(f.Code) != totp.Digits {
+		f.AddError(fmt.Sprintf("Code must be %d digits", totp.Digits), "code", f.Code)
+	}
+
+	return len(f.ValidationErrors()) == 0
+}
+
+// secondFactorForm is a form with either a TOTP code or a recovery code.
+type secondFactorForm struct {
+	form.BaseForm
+	Code         string `json:"code"`
+	RecoveryCode string `json:"recoveryCode"`
+}
+
+func (f *secondFactorForm) Validate() bool {
+	if len(f.RecoveryCode) == 0 && len(f.Code) != totp.Digits {
+		f.AddError(fmt.Sprintf("Code must be %d digits", totp.Digits), "code", f.Code)
+	}
+
+	return len(f.ValidationErrors()) == 0
+}
+
+// verifySecondFactor checks the code or the recovery code from the form
+// and marks it used.
+func (c RestController) verifySecondFactor(e totp.Enrollment, f secondFactorForm) (bool, error) {
+	if len(f.RecoveryCode) != 0 {
+		return recovery.Spend(c.recoveryRepo, c.hasher, e.AccountID, f.RecoveryCode)
+	}
+
+	return totp.UseCode(c.totpRepo, e, f.Code, c.clock())
+}
+
+// newRecoveryCodes replaces the recovery codes of the account with a new set
+// and returns the codes to show them once.
+func (c RestController) newRecoveryCodes(accountID int64) ([]string, error) {
+	codes, err := recovery.Generate()
+	if err != nil {
+		return nil, err
+	}
+
+	hashed, err := recovery.NewCodes(c.hasher, accountID, codes, c.clock())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.recoveryRepo.Replace(accountID, hashed); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+type recoveryCodesSchema struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
@@ -0,0 +1,45 @@
+package twofactor
+
+import (
+	"io/ioutil"
+	"log"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/pascont/account"
+	"github.com/hypnoglow/pascont/hasher"
+	"github.com/hypnoglow/pascont/recovery"
+	"github.com/hypnoglow/pascont/session"
+	"github.com/hypnoglow/pascont/totp"
+)
+
+func TestNewRestController(t *testing.T) {
+	NewRestController(
+		log.New(ioutil.Discard, "", log.LstdFlags),
+		account.NewFakeRepository(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil),
+		session.NewFakeRepository(nil, nil, nil, nil, nil, nil),
+		totp.NewFakeRepository(nil, nil, nil, nil, nil),
+		nil,
+		nil,
+		time.Now,
+		Options{Issuer: "pascont"},
+	)
+}
+
+// Recovery codes are random, so they are replaced in the response body before comparison.
+var recoveryCodePattern = regexp.MustCompile(`"[a-z2-9]{5}-[a-z2-9]{5}"`)
+
+// expectedRecoveryCodes is a JSON array of replaced recovery codes.
+var expectedRecoveryCodes = "[" + strings.TrimSuffix(strings.Repeat(`"<code>",`, recovery.Count), ",") + "]"
+
+// recoveryCodesHasher returns a Hasher for a set of recovery codes.
+func recoveryCodesHasher() hasher.Hasher {
+	results := make([]hasher.FakeGenerateHashFromPasswordResult, recovery.Count)
+	for i := range results {
+		results[i].Hash = []byte("code_hash")
+	}
+
+	return hasher.NewFakeHasher(results, nil)
+}
@@ -0,0 +1,79 @@
+package twofactor
+
+import (
+	"net/http"
+
+	"github.com/hypnoglow/pascont/kit"
+	"github.com/hypnoglow/pascont/kit/form"
+	"github.com/hypnoglow/pascont/kit/schema"
+	"github.com/hypnoglow/pascont/session"
+	"github.com/hypnoglow/pascont/totp"
+)
+
+// DeleteTOTP is a handler for:
+// DELETE /accounts/:id/totp
+//
+// A confirmed enrollment can be removed only with a valid code or a recovery code,
+// so a stolen session is not enough to disable the second factor.
+func (c RestController) DeleteTOTP(w http.ResponseWriter, req *http.Request) {
+	current, status := session.AccountSession(req, c.sessionRepo, c.logger)
+	if current == nil {
+		w.WriteHeader(status)
+		return
+	}
+
+	e, err := c.totpRepo.FindByAccount(current.AccountID)
+	if err == totp.ErrNotFound {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		c.logger.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	if e.IsConfirmed() {
+		var f secondFactorForm
+		form.PopulateFormFromJSON(req.Body, &f)
+		if !f.Validate() {
+			kit.RespondWithFormErrors(w, http.StatusBadRequest, f.ValidationErrors())
+			return
+		}
+
+		ok, err := c.verifySecondFactor(*e, f)
+		if err != nil {
+			c.logger.Println(err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if !ok {
+			respondInvalidSecondFactor(w, f)
+			return
+		}
+	}
+
+	if err := c.totpRepo.Delete(e.AccountID); err != nil {
+		c.logger.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	if err := c.recoveryRepo.Replace(e.AccountID, nil); err != nil {
+		c.logger.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// respondInvalidSecondFactor responds that the code or the recovery code from the form is invalid.
+func respondInvalidSecondFactor(w http.ResponseWriter, f secondFactorForm) {
+	if len(f.RecoveryCode) != 0 {
+		kit.RespondWithError(w, http.StatusBadRequest, schema.NewError("Recovery code is invalid", "recoveryCode", nil))
+		return
+	}
+
+	kit.RespondWithError(w, http.StatusBadRequest, schema.NewError("Code is invalid", "code", f.Code))
+}
@@ -0,0 +1,68 @@
+package twofactor
+
+import (
+	"net/http"
+
+	"github.com/hypnoglow/pascont/kit"
+	"github.com/hypnoglow/pascont/kit/schema"
+	"github.com/hypnoglow/pascont/session"
+	"github.com/hypnoglow/pascont/totp"
+)
+
+// PostTOTP is a handler for:
+// POST /accounts/:id/totp
+//
+// It starts the enrollment, which must be confirmed with a first code.
+// Starting it again replaces a not yet confirmed enrollment.
+func (c RestController) PostTOTP(w http.ResponseWriter, req *http.Request) {
+	current, status := session.AccountSession(req, c.sessionRepo, c.logger)
+	if current == nil {
+		w.WriteHeader(status)
+		return
+	}
+
+	e, err := c.totpRepo.FindByAccount(current.AccountID)
+	if err != nil && err != totp.ErrNotFound {
+		c.logger.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if err == nil && e.IsConfirmed() {
+		kit.RespondWithError(w, http.StatusConflict, schema.ErrorFromMessage("TOTP is already enabled"))
+		return
+	}
+
+	acc, err := c.accountRepo.FindByID(current.AccountID)
+	if err != nil {
+		c.logger.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		c.logger.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	if err := c.totpRepo.Save(*totp.NewEnrollment(acc.ID, secret)); err != nil {
+		c.logger.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	// The secret is revealed only once, it can not be retrieved later.
+	kit.RespondJSON(w, http.StatusCreated, schema.NewResultBody(
+		postTOTPSchema{
+			Secret: totp.EncodeSecret(secret),
+			URI:    totp.URI(c.options.Issuer, acc.Name, secret),
+		},
+		nil,
+	))
+}
+
+type postTOTPSchema struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
@@ -0,0 +1,228 @@
+package twofactor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/pascont/account"
+	"github.com/hypnoglow/pascont/kit/middleware"
+	"github.com/hypnoglow/pascont/session"
+	"github.com/hypnoglow/pascont/totp"
+)
+
+func TestRestController_PostTOTP(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	fakeLogger := log.New(ioutil.Discard, "", log.LstdFlags)
+
+	currentSession := &session.Session{
+		ID:        "12345678-90ab-cdef-0123-4567890abcde",
+		AccountID: 123,
+		CreatedAt: now,
+		ExpiresAt: now.Add(time.Hour),
+	}
+
+	// The secret is random, so it is replaced in the response body before comparison.
+	secretPattern := regexp.MustCompile(`[A-Z2-7]{32}`)
+
+	cases := []struct {
+		caseName string
+		// in
+		accRepo                  account.Repository
+		sessRepo                 session.Repository
+		totpRepo                 totp.Repository
+		reqContextSessionIDValue interface{}
+		reqPathID                string
+		// out
+		expectedCode int
+		expectedBody *bytes.Buffer
+	}{
+		{
+			caseName:  "There is no session ID from Authorization Header",
+			reqPathID: "me",
+			// out
+			expectedCode: http.StatusUnauthorized,
+			expectedBody: bytes.NewBuffer(nil),
+		},
+		{
+			caseName: "TOTP is already enabled",
+			sessRepo: session.NewFakeRepository(
+				nil,
+				[]session.FakeRepositoryFindByIDResult{
+					{
+						Session: currentSession,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			totpRepo: totp.NewFakeRepository(
+				nil,
+				[]totp.FakeRepositoryFindByAccountResult{
+					{
+						Enrollment: &totp.Enrollment{AccountID: 123, ConfirmedAt: now},
+					},
+				},
+				nil,
+				nil,
+				nil,
+			),
+			reqContextSessionIDValue: currentSession.ID,
+			reqPathID:                "me",
+			// out
+			expectedCode: http.StatusConflict,
+			expectedBody: bytes.NewBufferString(`{
+				"errors":[
+					{
+						"message":"TOTP is already enabled"
+					}
+				]
+			}`),
+		},
+		{
+			caseName: "totpRepo.FindByAccount failed",
+			sessRepo: session.NewFakeRepository(
+				nil,
+				[]session.FakeRepositoryFindByIDResult{
+					{
+						Session: currentSession,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			totpRepo: totp.NewFakeRepository(
+				nil,
+				[]totp.FakeRepositoryFindByAccountResult{
+					{
+						Error: fmt.Errorf("FindByAccount failed"),
+					},
+				},
+				nil,
+				nil,
+				nil,
+			),
+			reqContextSessionIDValue: currentSession.ID,
+			reqPathID:                "me",
+			// out
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: bytes.NewBuffer(nil),
+		},
+		{
+			caseName: "Successful",
+			accRepo: account.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				[]account.FakeRepositoryFindByIDResult{
+					{
+						Account: &account.Account{ID: 123, Name: "email@email.com"},
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			sessRepo: session.NewFakeRepository(
+				nil,
+				[]session.FakeRepositoryFindByIDResult{
+					{
+						Session: currentSession,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			totpRepo: totp.NewFakeRepository(
+				[]totp.FakeRepositorySaveResult{
+					{
+						Error: nil,
+					},
+				},
+				[]totp.FakeRepositoryFindByAccountResult{
+					{
+						Error: totp.ErrNotFound,
+					},
+				},
+				nil,
+				nil,
+				nil,
+			),
+			reqContextSessionIDValue: currentSession.ID,
+			reqPathID:                "123",
+			// out
+			expectedCode: http.StatusCreated,
+			expectedBody: bytes.NewBufferString(`{
+				"result":{
+					"secret":"<secret>",
+					"uri":"otpauth://totp/pascont:email@email.com?algorithm=SHA1\u0026digits=6\u0026issuer=pascont\u0026period=30\u0026secret=<secret>"
+				}
+			}`),
+		},
+	}
+
+	for i, c := range cases {
+		ctrl := NewRestController(fakeLogger, c.accRepo, c.sessRepo, c.totpRepo, nil, nil, time.Now, Options{Issuer: "pascont"})
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/accounts/"+c.reqPathID+"/totp", nil)
+		req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyPathParams{}, map[string]string{"id": c.reqPathID}))
+		req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeySessionID{}, c.reqContextSessionIDValue))
+		ctrl.PostTOTP(w, req)
+
+		if w.Code != c.expectedCode {
+			t.Errorf(
+				"testcase %d %s:\nExpected status code to be %v, but got %v\n",
+				i,
+				c.caseName,
+				c.expectedCode,
+				w.Code,
+			)
+		}
+
+		// To make possible pretty formatting in test cases, we use json.Compact:
+		b := c.expectedBody.Bytes()
+		if b != nil {
+			c.expectedBody.Reset()
+			err := json.Compact(c.expectedBody, b)
+			if err != nil {
+				t.Errorf(
+					"testcase %d %s:\nFailed to compact JSON body: %s",
+					i,
+					c.caseName,
+					err,
+				)
+			}
+		}
+
+		body := secretPattern.ReplaceAll(w.Body.Bytes(), []byte(`<secret>`))
+		if !bytes.Equal(body, c.expectedBody.Bytes()) {
+			t.Errorf(
+				"testcase %d %s:\nExpected body to be\n%v\nbut got\n%v\n",
+				i,
+				c.caseName,
+				c.expectedBody,
+				w.Body,
+			)
+		}
+	}
+}
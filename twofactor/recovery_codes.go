@@ -0,0 +1,67 @@
+package twofactor
+
+import (
+	"net/http"
+
+	"github.com/hypnoglow/pascont/kit"
+	"github.com/hypnoglow/pascont/kit/form"
+	"github.com/hypnoglow/pascont/kit/schema"
+	"github.com/hypnoglow/pascont/session"
+	"github.com/hypnoglow/pascont/totp"
+)
+
+// PostRecoveryCodes is a handler for:
+// POST /accounts/:id/totp/recovery-codes
+//
+// It replaces the recovery codes with a new set.
+func (c RestController) PostRecoveryCodes(w http.ResponseWriter, req *http.Request) {
+	current, status := session.AccountSession(req, c.sessionRepo, c.logger)
+	if current == nil {
+		w.WriteHeader(status)
+		return
+	}
+
+	var f secondFactorForm
+	form.PopulateFormFromJSON(req.Body, &f)
+	if !f.Validate() {
+		kit.RespondWithFormErrors(w, http.StatusBadRequest, f.ValidationErrors())
+		return
+	}
+
+	e, err := c.totpRepo.FindByAccount(current.AccountID)
+	if err != nil && err != totp.ErrNotFound {
+		c.logger.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if err == totp.ErrNotFound || !e.IsConfirmed() {
+		kit.RespondWithError(w, http.StatusConflict, schema.ErrorFromMessage("TOTP is not enabled"))
+		return
+	}
+
+	ok, err := c.verifySecondFactor(*e, f)
+	if err != nil {
+		c.logger.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if !ok {
+		respondInvalidSecondFactor(w, f)
+		return
+	}
+
+	codes, err := c.newRecoveryCodes(e.AccountID)
+	if err != nil {
+		c.logger.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	// Recovery codes are revealed only once, they can not be retrieved later.
+	kit.RespondJSON(w, http.StatusCreated, schema.NewResultBody(
+		recoveryCodesSchema{
+			RecoveryCodes: codes,
+		},
+		nil,
+	))
+}
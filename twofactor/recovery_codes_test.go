@@ -0,0 +1,217 @@
+package twofactor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/pascont/clock"
+	"github.com/hypnoglow/pascont/hasher"
+	"github.com/hypnoglow/pascont/kit/middleware"
+	"github.com/hypnoglow/pascont/recovery"
+	"github.com/hypnoglow/pascont/session"
+	"github.com/hypnoglow/pascont/totp"
+)
+
+func TestRestController_PostRecoveryCodes(t *testing.T) {
+	now := clock.Fixed(time.Date(2017, 7, 1, 21, 10, 29, 0, time.UTC))
+	fakeLogger := log.New(ioutil.Discard, "", log.LstdFlags)
+	secret := []byte("12345678901234567890")
+	code := totp.Code(secret, totp.Counter(now()))
+
+	currentSession := &session.Session{
+		ID:        "12345678-90ab-cdef-0123-4567890abcde",
+		AccountID: 123,
+		CreatedAt: now(),
+		ExpiresAt: now().Add(time.Hour),
+	}
+	sessRepo := func() session.Repository {
+		return session.NewFakeRepository(
+			nil,
+			[]session.FakeRepositoryFindByIDResult{
+				{
+					Session: currentSession,
+				},
+			},
+			nil,
+			nil,
+			nil,
+			nil,
+		)
+	}
+
+	cases := []struct {
+		caseName string
+		// in
+		sessRepo                 session.Repository
+		totpRepo                 totp.Repository
+		recoveryRepo             recovery.Repository
+		hasher                   hasher.Hasher
+		reqContextSessionIDValue interface{}
+		reqBody                  io.Reader
+		// out
+		expectedCode int
+		expectedBody *bytes.Buffer
+	}{
+		{
+			caseName: "There is no session ID from Authorization Header",
+			reqBody:  bytes.NewBufferString(fmt.Sprintf(`{"code":"%s"}`, code)),
+			// out
+			expectedCode: http.StatusUnauthorized,
+			expectedBody: bytes.NewBuffer(nil),
+		},
+		{
+			caseName: "TOTP is not enabled",
+			sessRepo: sessRepo(),
+			totpRepo: totp.NewFakeRepository(
+				nil,
+				[]totp.FakeRepositoryFindByAccountResult{
+					{
+						Enrollment: &totp.Enrollment{AccountID: 123, Secret: secret},
+					},
+				},
+				nil,
+				nil,
+				nil,
+			),
+			reqContextSessionIDValue: currentSession.ID,
+			reqBody:                  bytes.NewBufferString(fmt.Sprintf(`{"code":"%s"}`, code)),
+			// out
+			expectedCode: http.StatusConflict,
+			expectedBody: bytes.NewBufferString(`{
+				"errors":[
+					{
+						"message":"TOTP is not enabled"
+					}
+				]
+			}`),
+		},
+		{
+			caseName: "Invalid recovery code should result in 400",
+			sessRepo: sessRepo(),
+			totpRepo: totp.NewFakeRepository(
+				nil,
+				[]totp.FakeRepositoryFindByAccountResult{
+					{
+						Enrollment: &totp.Enrollment{AccountID: 123, Secret: secret, ConfirmedAt: now()},
+					},
+				},
+				nil,
+				nil,
+				nil,
+			),
+			recoveryRepo: recovery.NewFakeRepository(
+				nil,
+				[]recovery.FakeRepositoryListByAccountResult{
+					{
+						Codes: nil,
+					},
+				},
+				nil,
+			),
+			reqContextSessionIDValue: currentSession.ID,
+			reqBody:                  bytes.NewBufferString(`{"recoveryCode":"abcde-fghjk"}`),
+			// out
+			expectedCode: http.StatusBadRequest,
+			expectedBody: bytes.NewBufferString(`{
+				"errors":[
+					{
+						"message":"Recovery code is invalid",
+						"field":"recoveryCode"
+					}
+				]
+			}`),
+		},
+		{
+			caseName: "Successful",
+			sessRepo: sessRepo(),
+			totpRepo: totp.NewFakeRepository(
+				nil,
+				[]totp.FakeRepositoryFindByAccountResult{
+					{
+						Enrollment: &totp.Enrollment{AccountID: 123, Secret: secret, ConfirmedAt: now()},
+					},
+				},
+				nil,
+				[]totp.FakeRepositoryUseCounterResult{
+					{
+						Used: true,
+					},
+				},
+				nil,
+			),
+			recoveryRepo: recovery.NewFakeRepository(
+				[]recovery.FakeRepositoryReplaceResult{
+					{
+						Error: nil,
+					},
+				},
+				nil,
+				nil,
+			),
+			hasher:                   recoveryCodesHasher(),
+			reqContextSessionIDValue: currentSession.ID,
+			reqBody:                  bytes.NewBufferString(fmt.Sprintf(`{"code":"%s"}`, code)),
+			// out
+			expectedCode: http.StatusCreated,
+			expectedBody: bytes.NewBufferString(`{
+				"result":{
+					"recoveryCodes":` + expectedRecoveryCodes + `
+				}
+			}`),
+		},
+	}
+
+	for i, c := range cases {
+		ctrl := NewRestController(fakeLogger, nil, c.sessRepo, c.totpRepo, c.recoveryRepo, c.hasher, now, Options{Issuer: "pascont"})
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/accounts/me/totp/recovery-codes", c.reqBody)
+		req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyPathParams{}, map[string]string{"id": "me"}))
+		req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeySessionID{}, c.reqContextSessionIDValue))
+		ctrl.PostRecoveryCodes(w, req)
+
+		if w.Code != c.expectedCode {
+			t.Errorf(
+				"testcase %d %s:\nExpected status code to be %v, but got %v\n",
+				i,
+				c.caseName,
+				c.expectedCode,
+				w.Code,
+			)
+		}
+
+		// To make possible pretty formatting in test cases, we use json.Compact:
+		b := c.expectedBody.Bytes()
+		if b != nil {
+			c.expectedBody.Reset()
+			err := json.Compact(c.expectedBody, b)
+			if err != nil {
+				t.Errorf(
+					"testcase %d %s:\nFailed to compact JSON body: %s",
+					i,
+					c.caseName,
+					err,
+				)
+			}
+		}
+
+		body := recoveryCodePattern.ReplaceAll(w.Body.Bytes(), []byte(`"<code>"`))
+		if !bytes.Equal(body, c.expectedBody.Bytes()) {
+			t.Errorf(
+				"testcase %d %s:\nExpected body to be\n%v\nbut got\n%v\n",
+				i,
+				c.caseName,
+				c.expectedBody,
+				w.Body,
+			)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package verification
+
+import (
+	"github.com/hypnoglow/pascont/account"
+	"github.com/hypnoglow/pascont/outbox"
+)
+
+type fakeSender struct {
+	sendResults           []FakeSenderSendResult
+	sendResultCounter     int
+	messagesResults       []FakeSenderMessagesResult
+	messagesResultCounter int
+}
+
+type FakeSenderSendResult struct {
+	Error error
+}
+
+type FakeSenderMessagesResult struct {
+	Messages []outbox.Message
+}
+
+// NewFakeSender returns a new fake Sender.
+func NewFakeSender(sendResults []FakeSenderSendResult, messagesResults []FakeSenderMessagesResult) Sender {
+	return &fakeSender{
+		sendResults:           sendResults,
+		sendResultCounter:     0,
+		messagesResults:       messagesResults,
+		messagesResultCounter: 0,
+	}
+}
+
+func (s *fakeSender) Send(acc account.Account) error {
+	res := s.sendResults[s.sendResultCounter]
+	s.sendResultCounter++
+	return res.Error
+}
+
+func (s *fakeSender) Messages(acc account.Account) []outbox.Message {
+	res := s.messagesResults[s.messagesResultCounter]
+	s.messagesResultCounter++
+	return res.Messages
+}
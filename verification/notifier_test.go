@@ -0,0 +1,54 @@
+package verification
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/pascont/notary"
+	"github.com/hypnoglow/pascont/notifier"
+	"github.com/hypnoglow/pascont/packer"
+)
+
+func TestTokenNotifier_Notify(t *testing.T) {
+	n := notary.NewHMACNotary()
+	p := packer.NewBase64Packer(AccountIDLength + ExpiresAtLength)
+	key := []byte("secret_key")
+
+	var buf bytes.Buffer
+	tn := NewTokenNotifier(notifier.NewWriterNotifier(&buf), n, p, key)
+
+	params := map[string]string{"expiresAt": "2017-07-02T21:10:29Z"}
+	err := tn.Notify(notifier.Notification{Kind: notifier.KindVerification, AccountID: 123, Params: params})
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	if _, ok := params["token"]; ok {
+		t.Errorf("Expected params of the caller not to be changed, but got %v\n", params)
+	}
+
+	var sent notifier.Notification
+	if err := json.Unmarshal(buf.Bytes(), &sent); err != nil {
+		t.Fatalf("Failed to read the notification: %s", err)
+	}
+
+	accountID, expiresAt, ok := ParseToken(sent.Params["token"], n, p, key)
+	if !ok || accountID != 123 || expiresAt.Format(time.RFC3339) != "2017-07-02T21:10:29Z" {
+		t.Errorf("Expected token to verify account %d until %s, but got %d, %s, %v\n", 123, "2017-07-02T21:10:29Z", accountID, expiresAt, ok)
+	}
+
+	// Notifications of other kinds are delivered as they are.
+	buf.Reset()
+	err = tn.Notify(notifier.Notification{Kind: notifier.KindNewLogin, AccountID: 123, Params: map[string]string{}})
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	sent = notifier.Notification{}
+	if err := json.Unmarshal(buf.Bytes(), &sent); err != nil {
+		t.Fatalf("Failed to read the notification: %s", err)
+	}
+	if _, ok := sent.Params["token"]; ok {
+		t.Errorf("Expected no token in notification of kind %s, but got %s\n", sent.Kind, sent.Params["token"])
+	}
+}
@@ -0,0 +1,58 @@
+package verification
+
+import (
+	"time"
+
+	"github.com/hypnoglow/pascont/account"
+	"github.com/hypnoglow/pascont/clock"
+	"github.com/hypnoglow/pascont/notifier"
+	"github.com/hypnoglow/pascont/outbox"
+)
+
+// Sender sends verification tokens to account holders.
+type Sender interface {
+	// Send issues a new verification token for the account and sends it to the account holder.
+	Send(acc account.Account) error
+
+	// Messages returns the outbox messages sending a new verification token to the account holder,
+	// so they are added along with a change of the account.
+	Messages(acc account.Account) []outbox.Message
+}
+
+// outboxSender is a Sender which delivers tokens through the outbox.
+type outboxSender struct {
+	repo   outbox.Repository
+	router outbox.Router
+	clock  clock.Clock
+}
+
+// NewOutboxSender returns a new Sender which adds KindVerification notifications
+// for the channels of the router to the outbox.
+// Notifications refer to the account instead of carrying the token, which is signed
+// on delivery by a TokenNotifier.
+func NewOutboxSender(repo outbox.Repository, router outbox.Router, clk clock.Clock) Sender {
+	return outboxSender{repo, router, clk}
+}
+
+func (s outboxSender) Send(acc account.Account) error {
+	messages := s.Messages(acc)
+	if len(messages) == 0 {
+		return nil
+	}
+
+	return s.repo.Add(messages...)
+}
+
+func (s outboxSender) Messages(acc account.Account) []outbox.Message {
+	expiresAt := s.clock().UTC().Truncate(time.Second).Add(Duration)
+
+	return s.router.Messages(notifier.Notification{
+		Kind:      notifier.KindVerification,
+		TenantID:  acc.TenantID,
+		AccountID: acc.ID,
+		Recipient: acc.Name,
+		Params: map[string]string{
+			"expiresAt": expiresAt.Format(time.RFC3339),
+		},
+	})
+}
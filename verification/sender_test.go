@@ -0,0 +1,54 @@
+package verification
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/pascont/account"
+	"github.com/hypnoglow/pascont/clock"
+	"github.com/hypnoglow/pascont/notifier"
+	"github.com/hypnoglow/pascont/outbox"
+)
+
+// recordingRepository is an outbox.Repository which keeps added messages.
+type recordingRepository struct {
+	outbox.Repository
+
+	messages []outbox.Message
+}
+
+func (r *recordingRepository) Add(messages ...outbox.Message) error {
+	r.messages = append(r.messages, messages...)
+	return nil
+}
+
+func TestOutboxSender_Send(t *testing.T) {
+	now := time.Date(2017, 7, 1, 21, 10, 29, 0, time.UTC)
+	uuidProducer := func() string {
+		return "12345678-90ab-cdef-0123-4567890abcde"
+	}
+
+	repo := &recordingRepository{}
+	router := outbox.NewRouter(nil, []string{"smtp"}, uuidProducer, clock.Fixed(now))
+	s := NewOutboxSender(repo, router, clock.Fixed(now))
+
+	acc := account.Account{ID: 123, TenantID: 1, Name: "email@email.com"}
+	if err := s.Send(acc); err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+
+	if len(repo.messages) != 1 {
+		t.Fatalf("Expected a message to be added, but got %#v", repo.messages)
+	}
+
+	sent := repo.messages[0].Notification
+	if sent.Kind != notifier.KindVerification || sent.TenantID != 1 || sent.AccountID != 123 || sent.Recipient != "email@email.com" {
+		t.Errorf("Unexpected notification %#v\n", sent)
+	}
+	if sent.Params["expiresAt"] != "2017-07-02T21:10:29Z" {
+		t.Errorf("Expected token to expire at %s, but got %s\n", "2017-07-02T21:10:29Z", sent.Params["expiresAt"])
+	}
+	if _, ok := sent.Params["token"]; ok {
+		t.Errorf("Expected token not to be kept in the outbox, but got %s\n", sent.Params["token"])
+	}
+}
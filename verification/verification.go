@@ -0,0 +1,61 @@
+// Package verification verifies that account names, which are emails, belong to account holders.
+package verification
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/hypnoglow/pascont/notary"
+	"github.com/hypnoglow/pascont/packer"
+)
+
+const (
+	// Duration is how long a verification token is valid.
+	Duration = time.Hour * 24
+
+	// AccountIDLength is the length of an account ID in a token in bytes.
+	AccountIDLength = 20
+
+	// ExpiresAtLength is the length of a token ExpiresAt in Unix Timestamp form in bytes.
+	ExpiresAtLength = 10
+)
+
+// NewToken returns a signed token which verifies the account until expiresAt.
+// Tokens are not stored, so a token can be used more than once until it expires.
+func NewToken(n notary.Notary, p packer.Packer, secretKey []byte, accountID int64, expiresAt time.Time) (string, error) {
+	message := []byte(fmt.Sprintf("%0*d%0*d", AccountIDLength, accountID, ExpiresAtLength, expiresAt.Unix()))
+
+	pack, err := p.Pack(message, n.Sign(message, secretKey))
+	if err != nil {
+		return "", errors.Wrap(err, "Failed to pack message with verification and it's signature")
+	}
+
+	return string(pack), nil
+}
+
+// ParseToken unpacks the token and verifies its signature.
+// Returns the account ID and the expiration time of the token, or false if the token is invalid.
+func ParseToken(token string, n notary.Notary, p packer.Packer, secretKey []byte) (accountID int64, expiresAt time.Time, ok bool) {
+	message, signature, err := p.Unpack([]byte(token))
+	if err != nil || len(message) != AccountIDLength+ExpiresAtLength {
+		return 0, time.Time{}, false
+	}
+
+	if !n.Verify(message, signature, secretKey) {
+		return 0, time.Time{}, false
+	}
+
+	accountID, err = strconv.ParseInt(string(message[:AccountIDLength]), 10, 64)
+	if err != nil {
+		return 0, time.Time{}, false
+	}
+	timestamp, err := strconv.ParseInt(string(message[AccountIDLength:]), 10, 64)
+	if err != nil {
+		return 0, time.Time{}, false
+	}
+
+	return accountID, time.Unix(timestamp, 0).UTC(), true
+}
@@ -0,0 +1,66 @@
+package verification
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/pascont/notary"
+	"github.com/hypnoglow/pascont/packer"
+)
+
+func TestNewToken(t *testing.T) {
+	expiresAt := time.Now().UTC().Truncate(time.Second).Add(Duration)
+	n := notary.NewHMACNotary()
+	p := packer.NewBase64Packer(AccountIDLength + ExpiresAtLength)
+	key := []byte("secret_key")
+
+	token, err := NewToken(n, p, key, 123, expiresAt)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+
+	accountID, tokenExpiresAt, ok := ParseToken(token, n, p, key)
+	if !ok || accountID != 123 || !tokenExpiresAt.Equal(expiresAt) {
+		t.Errorf(
+			"Expected token to be parsed as %d, %s, but got %d, %s, %v\n",
+			123,
+			expiresAt,
+			accountID,
+			tokenExpiresAt,
+			ok,
+		)
+	}
+
+	cases := []struct {
+		caseName string
+		token    string
+		key      []byte
+	}{
+		{
+			caseName: "Other key",
+			token:    token,
+			key:      []byte("other_key"),
+		},
+		{
+			caseName: "Tampered token",
+			token:    "A" + token[1:],
+			key:      key,
+		},
+		{
+			caseName: "Short token",
+			token:    "SGVsbG8=",
+			key:      key,
+		},
+		{
+			caseName: "Not a token",
+			token:    "not a token",
+			key:      key,
+		},
+	}
+
+	for i, c := range cases {
+		if _, _, ok := ParseToken(c.token, n, p, c.key); ok {
+			t.Errorf("testcase %d %s: Expected token to be invalid\n", i, c.caseName)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package verifications
+
+import (
+	"log"
+
+	"github.com/hypnoglow/pascont/account"
+	"github.com/hypnoglow/pascont/clock"
+	"github.com/hypnoglow/pascont/notary"
+	"github.com/hypnoglow/pascont/packer"
+	"github.com/hypnoglow/pascont/verification"
+)
+
+const (
+	PathVerifications = "/verifications"
+	PathVerification  = "/verifications/"
+
+	PathVerificationByToken = "/verifications/:token"
+)
+
+// RestController is a REST controller for account verifications.
+type RestController struct {
+	logger      *log.Logger
+	accountRepo account.Repository
+	sender      verification.Sender
+	notary      notary.Notary
+	packer      packer.Packer
+	clock       clock.Clock
+	options     Options
+}
+
+// Options is a structure holding verifications RestController specific options.
+type Options struct {
+	// SecretKey is a key verification tokens are signed with.
+	SecretKey []byte
+}
+
+// NewRestController returns a new RestController.
+func NewRestController(
+	logger *log.Logger,
+	accountRepo account.Repository,
+	s verification.Sender,
+	n notary.Notary,
+	p packer.Packer,
+	clk clock.Clock,
+	opts Options,
+) RestController {
+	return RestController{
+		logger,
+		accountRepo,
+		s,
+		n,
+		p,
+		clk,
+		opts,
+	}
+}
@@ -0,0 +1,44 @@
+package verifications
+
+import (
+	"io/ioutil"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/pascont/account"
+	"github.com/hypnoglow/pascont/notary"
+	"github.com/hypnoglow/pascont/packer"
+	"github.com/hypnoglow/pascont/verification"
+)
+
+func TestNewRestController(t *testing.T) {
+	NewRestController(
+		log.New(ioutil.Discard, "", log.LstdFlags),
+		account.NewFakeRepository(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil),
+		verification.NewFakeSender(nil, nil),
+		notary.NewHMACNotary(),
+		packer.NewBase64Packer(verification.AccountIDLength+verification.ExpiresAtLength),
+		time.Now,
+		Options{SecretKey: testSecretKey},
+	)
+}
+
+// testSecretKey is a key to sign verification tokens in tests.
+var testSecretKey = []byte("0123456789abcdef")
+
+// newTestToken returns a verification token issued in tests.
+func newTestToken(t *testing.T, accountID int64, expiresAt time.Time) string {
+	token, err := verification.NewToken(
+		notary.NewHMACNotary(),
+		packer.NewBase64Packer(verification.AccountIDLength+verification.ExpiresAtLength),
+		testSecretKey,
+		accountID,
+		expiresAt,
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+
+	return token
+}
@@ -0,0 +1,67 @@
+package verifications
+
+import (
+	"net/http"
+
+	"github.com/hypnoglow/pascont/account"
+	"github.com/hypnoglow/pascont/kit"
+	"github.com/hypnoglow/pascont/kit/form"
+	"github.com/hypnoglow/pascont/kit/middleware"
+)
+
+// PostVerifications is a handler for:
+// POST /verifications
+//
+// It sends a new verification token to the holder of the account pending verification.
+// The response is the same whether the account exists or not,
+// so accounts can not be enumerated.
+func (c RestController) PostVerifications(w http.ResponseWriter, req *http.Request) {
+	tenantID, ok := req.Context().Value(middleware.ContextKeyTenantID{}).(int64)
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	var verificationForm postVerificationsForm
+	form.PopulateFormFromJSON(req.Body, &verificationForm)
+	if !verificationForm.Validate() {
+		kit.RespondWithFormErrors(w, http.StatusBadRequest, verificationForm.ValidationErrors())
+		return
+	}
+
+	acc, _, err := c.accountRepo.FindWithPasswordHashByUsername(tenantID, verificationForm.Name)
+	if err != nil {
+		if err == account.ErrNotFound {
+			w.WriteHeader(http.StatusAccepted)
+		} else {
+			c.logger.Println(err)
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		return
+	}
+
+	if acc.IsVerified() || !acc.IsActive() {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	// Delivery failures are not revealed, as they would reveal the account.
+	if err := c.sender.Send(*acc); err != nil {
+		c.logger.Println(err)
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+type postVerificationsForm struct {
+	form.BaseForm
+	Name string `json:"name"`
+}
+
+func (f *postVerificationsForm) Validate() bool {
+	if len(f.Name) == 0 {
+		f.AddError("Name must not be empty", "name", f.Name)
+	}
+
+	return len(f.ValidationErrors()) == 0
+}
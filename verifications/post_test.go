@@ -0,0 +1,239 @@
+package verifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/pascont/account"
+	"github.com/hypnoglow/pascont/clock"
+	"github.com/hypnoglow/pascont/kit/middleware"
+	"github.com/hypnoglow/pascont/notary"
+	"github.com/hypnoglow/pascont/packer"
+	"github.com/hypnoglow/pascont/verification"
+)
+
+func TestRestController_PostVerifications(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	fakeLogger := log.New(ioutil.Discard, "", log.LstdFlags)
+
+	pendingAcc := &account.Account{
+		ID:        123,
+		TenantID:  1,
+		Name:      "email@email.com",
+		Status:    account.StatusActive,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	verifiedAcc := &account.Account{
+		ID:         123,
+		TenantID:   1,
+		Name:       "email@email.com",
+		Status:     account.StatusActive,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+		VerifiedAt: now,
+	}
+	disabledAcc := &account.Account{
+		ID:        123,
+		TenantID:  1,
+		Name:      "email@email.com",
+		Status:    account.StatusDisabled,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	validBody := `{"name":"email@email.com"}`
+
+	// foundAccount returns a fake account.Repository which finds the account by name.
+	foundAccount := func(acc *account.Account, err error) account.Repository {
+		return account.NewFakeRepository(
+			nil,
+			nil,
+			nil,
+			[]account.FakeRepositoryFindWithPasswordHashByUsernameResult{
+				{
+					Account:      acc,
+					PasswordHash: []byte("password_hash"),
+					Error:        err,
+				},
+			},
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+		)
+	}
+
+	cases := []struct {
+		caseName string
+		// in
+		accRepo     account.Repository
+		sender      verification.Sender
+		reqTenantID int64
+		reqBody     io.Reader
+		// out
+		expectedCode int
+		expectedBody *bytes.Buffer
+	}{
+		{
+			caseName: "Request without a tenant should result in 404",
+			reqBody:  bytes.NewBufferString(validBody),
+			// out
+			expectedCode: http.StatusNotFound,
+			expectedBody: bytes.NewBuffer(nil),
+		},
+		{
+			caseName:    "Empty name should result in 400",
+			reqTenantID: 1,
+			reqBody:     bytes.NewBufferString(`{"name":""}`),
+			// out
+			expectedCode: http.StatusBadRequest,
+			expectedBody: bytes.NewBufferString(`{
+				"errors":[
+					{
+						"message":"Name must not be empty",
+						"field":"name",
+						"value":""
+					}
+				]
+			}`),
+		},
+		{
+			caseName:    "Account not found should result in 202",
+			accRepo:     foundAccount(nil, account.ErrNotFound),
+			reqTenantID: 1,
+			reqBody:     bytes.NewBufferString(validBody),
+			// out
+			expectedCode: http.StatusAccepted,
+			expectedBody: bytes.NewBuffer(nil),
+		},
+		{
+			caseName:    "Error on FindWithPasswordHashByUsername should result in 500",
+			accRepo:     foundAccount(nil, fmt.Errorf("FindWithPasswordHashByUsername failed")),
+			reqTenantID: 1,
+			reqBody:     bytes.NewBufferString(validBody),
+			// out
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: bytes.NewBuffer(nil),
+		},
+		{
+			caseName:    "Account which is verified already should result in 202",
+			accRepo:     foundAccount(verifiedAcc, nil),
+			reqTenantID: 1,
+			reqBody:     bytes.NewBufferString(validBody),
+			// out
+			expectedCode: http.StatusAccepted,
+			expectedBody: bytes.NewBuffer(nil),
+		},
+		{
+			caseName:    "Account which is not active should result in 202",
+			accRepo:     foundAccount(disabledAcc, nil),
+			reqTenantID: 1,
+			reqBody:     bytes.NewBufferString(validBody),
+			// out
+			expectedCode: http.StatusAccepted,
+			expectedBody: bytes.NewBuffer(nil),
+		},
+		{
+			caseName: "Error on sender.Send should result in 202",
+			accRepo:  foundAccount(pendingAcc, nil),
+			sender: verification.NewFakeSender(
+				[]verification.FakeSenderSendResult{
+					{
+						Error: fmt.Errorf("Send failed"),
+					},
+				},
+				nil,
+			),
+			reqTenantID: 1,
+			reqBody:     bytes.NewBufferString(validBody),
+			// out
+			expectedCode: http.StatusAccepted,
+			expectedBody: bytes.NewBuffer(nil),
+		},
+		{
+			caseName: "Successful",
+			accRepo:  foundAccount(pendingAcc, nil),
+			sender: verification.NewFakeSender(
+				[]verification.FakeSenderSendResult{
+					{
+						Error: nil,
+					},
+				},
+				nil,
+			),
+			reqTenantID: 1,
+			reqBody:     bytes.NewBufferString(validBody),
+			// out
+			expectedCode: http.StatusAccepted,
+			expectedBody: bytes.NewBuffer(nil),
+		},
+	}
+
+	for i, c := range cases {
+		ctrl := NewRestController(
+			fakeLogger,
+			c.accRepo,
+			c.sender,
+			notary.NewHMACNotary(),
+			packer.NewBase64Packer(verification.AccountIDLength+verification.ExpiresAtLength),
+			clock.Fixed(now),
+			Options{SecretKey: testSecretKey},
+		)
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, PathVerifications, c.reqBody)
+		if c.reqTenantID != 0 {
+			req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyTenantID{}, c.reqTenantID))
+		}
+		ctrl.PostVerifications(w, req)
+
+		if w.Code != c.expectedCode {
+			t.Errorf(
+				"testcase %d %s:\nExpected status code to be %v, but got %v\n",
+				i,
+				c.caseName,
+				c.expectedCode,
+				w.Code,
+			)
+		}
+
+		// To make possible pretty formatting in test cases, we use json.Compact:
+		b := c.expectedBody.Bytes()
+		if b != nil {
+			c.expectedBody.Reset()
+			err := json.Compact(c.expectedBody, b)
+			if err != nil {
+				t.Errorf(
+					"testcase %d %s:\nFailed to compact JSON body: %s",
+					i,
+					c.caseName,
+					err,
+				)
+			}
+		}
+
+		if !bytes.Equal(w.Body.Bytes(), c.expectedBody.Bytes()) {
+			t.Errorf(
+				"testcase %d %s:\nExpected body to be\n%v\nbut got\n%v\n",
+				i,
+				c.caseName,
+				c.expectedBody,
+				w.Body,
+			)
+		}
+	}
+}
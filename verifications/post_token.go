@@ -0,0 +1,75 @@
+package verifications
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/hypnoglow/pascont/account"
+	"github.com/hypnoglow/pascont/kit"
+	"github.com/hypnoglow/pascont/kit/middleware"
+	"github.com/hypnoglow/pascont/kit/schema"
+	"github.com/hypnoglow/pascont/verification"
+)
+
+// PostVerification is a handler for:
+// POST /verifications/:token
+// GET /verifications/:token
+//
+// It verifies the account the token is issued for.
+// GET is served too, so the token can be sent as a link.
+func (c RestController) PostVerification(w http.ResponseWriter, req *http.Request) {
+	tenantID, ok := req.Context().Value(middleware.ContextKeyTenantID{}).(int64)
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	token, _ := middleware.PathParam(req, "token")
+	accountID, expiresAt, ok := verification.ParseToken(token, c.notary, c.packer, c.options.SecretKey)
+	if !ok {
+		respondInvalidToken(w)
+		return
+	}
+
+	now := c.clock()
+	if !now.Before(expiresAt) {
+		respondInvalidToken(w)
+		return
+	}
+
+	acc, err := c.accountRepo.FindByID(accountID)
+	if err != nil {
+		if err == account.ErrNotFound {
+			respondInvalidToken(w)
+		} else {
+			c.logger.Println(err)
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		return
+	}
+	if acc.TenantID != tenantID {
+		respondInvalidToken(w)
+		return
+	}
+
+	if err := c.accountRepo.Verify(acc.ID, now.UTC().Truncate(time.Second)); err != nil {
+		if err == account.ErrNotFound {
+			respondInvalidToken(w)
+		} else {
+			c.logger.Println(err)
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// respondInvalidToken responds the same for any invalid or expired token.
+func respondInvalidToken(w http.ResponseWriter) {
+	kit.RespondWithError(w, http.StatusBadRequest, schema.NewError(
+		"Verification token is invalid or expired",
+		"token",
+		nil,
+	))
+}
@@ -0,0 +1,253 @@
+package verifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/pascont/account"
+	"github.com/hypnoglow/pascont/clock"
+	"github.com/hypnoglow/pascont/kit/middleware"
+	"github.com/hypnoglow/pascont/notary"
+	"github.com/hypnoglow/pascont/packer"
+	"github.com/hypnoglow/pascont/verification"
+)
+
+func TestRestController_PostVerification(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	fakeLogger := log.New(ioutil.Discard, "", log.LstdFlags)
+
+	acc := &account.Account{
+		ID:        123,
+		TenantID:  1,
+		Name:      "email@email.com",
+		Status:    account.StatusActive,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	token := newTestToken(t, acc.ID, now.Add(verification.Duration))
+	expiredToken := newTestToken(t, acc.ID, now)
+	invalidTokenBody := `{
+		"errors":[
+			{
+				"message":"Verification token is invalid or expired",
+				"field":"token"
+			}
+		]
+	}`
+
+	// foundAccount returns a fake account.Repository which finds the account by ID.
+	foundAccount := func(acc *account.Account, verify []account.FakeRepositoryVerifyResult) account.Repository {
+		return account.NewFakeRepository(
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			[]account.FakeRepositoryFindByIDResult{
+				{
+					Account: acc,
+				},
+			},
+			nil,
+			nil,
+			nil,
+			nil,
+			verify,
+			nil,
+		)
+	}
+
+	cases := []struct {
+		caseName string
+		// in
+		accRepo     account.Repository
+		reqTenantID int64
+		reqToken    string
+		// out
+		expectedCode int
+		expectedBody *bytes.Buffer
+	}{
+		{
+			caseName: "Request without a tenant should result in 404",
+			reqToken: token,
+			// out
+			expectedCode: http.StatusNotFound,
+			expectedBody: bytes.NewBuffer(nil),
+		},
+		{
+			caseName:    "Token with invalid signature should result in 400",
+			reqTenantID: 1,
+			reqToken:    "A" + token[1:],
+			// out
+			expectedCode: http.StatusBadRequest,
+			expectedBody: bytes.NewBufferString(invalidTokenBody),
+		},
+		{
+			caseName:    "Expired token should result in 400",
+			reqTenantID: 1,
+			reqToken:    expiredToken,
+			// out
+			expectedCode: http.StatusBadRequest,
+			expectedBody: bytes.NewBufferString(invalidTokenBody),
+		},
+		{
+			caseName: "Account not found should result in 400",
+			accRepo: account.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				[]account.FakeRepositoryFindByIDResult{
+					{
+						Error: account.ErrNotFound,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			reqTenantID: 1,
+			reqToken:    token,
+			// out
+			expectedCode: http.StatusBadRequest,
+			expectedBody: bytes.NewBufferString(invalidTokenBody),
+		},
+		{
+			caseName: "Error on accountRepo.FindByID should result in 500",
+			accRepo: account.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				[]account.FakeRepositoryFindByIDResult{
+					{
+						Error: fmt.Errorf("FindByID failed"),
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			reqTenantID: 1,
+			reqToken:    token,
+			// out
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: bytes.NewBuffer(nil),
+		},
+		{
+			caseName:    "Account in other tenant should result in 400",
+			accRepo:     foundAccount(acc, nil),
+			reqTenantID: 2,
+			reqToken:    token,
+			// out
+			expectedCode: http.StatusBadRequest,
+			expectedBody: bytes.NewBufferString(invalidTokenBody),
+		},
+		{
+			caseName: "Error on accountRepo.Verify should result in 500",
+			accRepo: foundAccount(
+				acc,
+				[]account.FakeRepositoryVerifyResult{
+					{
+						Error: fmt.Errorf("Verify failed"),
+					},
+				},
+			),
+			reqTenantID: 1,
+			reqToken:    token,
+			// out
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: bytes.NewBuffer(nil),
+		},
+		{
+			caseName: "Successful",
+			accRepo: foundAccount(
+				acc,
+				[]account.FakeRepositoryVerifyResult{
+					{
+						Error: nil,
+					},
+				},
+			),
+			reqTenantID: 1,
+			reqToken:    token,
+			// out
+			expectedCode: http.StatusNoContent,
+			expectedBody: bytes.NewBuffer(nil),
+		},
+	}
+
+	for i, c := range cases {
+		ctrl := NewRestController(
+			fakeLogger,
+			c.accRepo,
+			nil,
+			notary.NewHMACNotary(),
+			packer.NewBase64Packer(verification.AccountIDLength+verification.ExpiresAtLength),
+			clock.Fixed(now),
+			Options{SecretKey: testSecretKey},
+		)
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, PathVerification+c.reqToken, nil)
+		req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyPathParams{}, map[string]string{"token": c.reqToken}))
+		if c.reqTenantID != 0 {
+			req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyTenantID{}, c.reqTenantID))
+		}
+		ctrl.PostVerification(w, req)
+
+		if w.Code != c.expectedCode {
+			t.Errorf(
+				"testcase %d %s:\nExpected status code to be %v, but got %v\n",
+				i,
+				c.caseName,
+				c.expectedCode,
+				w.Code,
+			)
+		}
+
+		// To make possible pretty formatting in test cases, we use json.Compact:
+		b := c.expectedBody.Bytes()
+		if b != nil {
+			c.expectedBody.Reset()
+			err := json.Compact(c.expectedBody, b)
+			if err != nil {
+				t.Errorf(
+					"testcase %d %s:\nFailed to compact JSON body: %s",
+					i,
+					c.caseName,
+					err,
+				)
+			}
+		}
+
+		if !bytes.Equal(w.Body.Bytes(), c.expectedBody.Bytes()) {
+			t.Errorf(
+				"testcase %d %s:\nExpected body to be\n%v\nbut got\n%v\n",
+				i,
+				c.caseName,
+				c.expectedBody,
+				w.Body,
+			)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package webhook
+
+import (
+	"log"
+	"time"
+
+	"github.com/hypnoglow/pascont/clock"
+	"github.com/hypnoglow/pascont/identity"
+	"github.com/hypnoglow/pascont/outbox"
+)
+
+// Emitter emits events to their subscriptions.
+type Emitter interface {
+	// Messages returns the outbox messages delivering the event to each subscription to the event type,
+	// so they are added along with the change the event is about.
+	// The event ID and time are set here.
+	Messages(e Event) ([]outbox.Message, error)
+
+	// Emit writes the event to the outbox for each subscription to the event type.
+	// It is meant for events which are not about a change.
+	// The event ID and time are set on emit.
+	Emit(e Event) error
+}
+
+// Emit emits the event with em, unless it is nil.
+// Events do not block what they are about, so failures are only logged.
+func Emit(em Emitter, logger *log.Logger, e Event) {
+	if em == nil {
+		return
+	}
+
+	if err := em.Emit(e); err != nil {
+		logger.Println(err)
+	}
+}
+
+// Messages returns the outbox messages delivering the events with em, unless it is nil.
+// Events do not block the changes they are about, so failures are only logged
+// and the change is made without the events.
+func Messages(em Emitter, logger *log.Logger, events ...Event) []outbox.Message {
+	if em == nil {
+		return nil
+	}
+
+	var messages []outbox.Message
+	for _, e := range events {
+		m, err := em.Messages(e)
+		if err != nil {
+			logger.Println(err)
+			return nil
+		}
+		messages = append(messages, m...)
+	}
+
+	return messages
+}
+
+type outboxEmitter struct {
+	subscriptions Repository
+	outboxRepo    outbox.Repository
+	uuidProducer  identity.UUIDProducer
+	clock         clock.Clock
+}
+
+// NewEmitter returns a new Emitter, which writes events for the subscriptions to the outbox.
+// Event and message IDs are UUIDs produced by identity.UUIDProducer func.
+func NewEmitter(subscriptions Repository, outboxRepo outbox.Repository, u identity.UUIDProducer, clk clock.Clock) Emitter {
+	return outboxEmitter{subscriptions, outboxRepo, u, clk}
+}
+
+func (em outboxEmitter) Emit(e Event) error {
+	messages, err := em.Messages(e)
+	if err != nil {
+		return err
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+
+	return em.outboxRepo.Add(messages...)
+}
+
+func (em outboxEmitter) Messages(e Event) ([]outbox.Message, error) {
+	subscriptions, err := em.subscriptions.List()
+	if err != nil {
+		return nil, err
+	}
+
+	now := em.clock().UTC().Truncate(time.Second)
+	e.ID = em.uuidProducer()
+	e.OccurredAt = now
+
+	var messages []outbox.Message
+	for _, s := range subscriptions {
+		if !s.Accepts(e.Type) {
+			continue
+		}
+
+		messages = append(messages, outbox.Message{
+			ID:            em.uuidProducer(),
+			Channel:       Channel,
+			Notification:  e.notification(s.ID),
+			Status:        outbox.StatusPending,
+			CreatedAt:     now,
+			NextAttemptAt: now,
+		})
+	}
+
+	return messages, nil
+}
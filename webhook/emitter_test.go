@@ -0,0 +1,290 @@
+package webhook
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/pascont/clock"
+	"github.com/hypnoglow/pascont/notifier"
+	"github.com/hypnoglow/pascont/outbox"
+)
+
+// recordingOutbox is an outbox.Repository which keeps the messages added.
+type recordingOutbox struct {
+	outbox.Repository
+
+	messages []outbox.Message
+	err      error
+}
+
+func (r *recordingOutbox) Add(messages ...outbox.Message) error {
+	r.messages = append(r.messages, messages...)
+	return r.err
+}
+
+func TestEmitter_Emit(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	uuidProducer := func() string {
+		return "12345678-90ab-cdef-0123-4567890abcde"
+	}
+	subscriptions := []*Subscription{
+		{ID: "all"},
+		{ID: "created", Events: []string{EventAccountCreated}},
+		{ID: "failed", Events: []string{EventLoginFailed}},
+	}
+
+	cases := []struct {
+		caseName string
+		// in
+		subscriptions Repository
+		outbox        *recordingOutbox
+		event         Event
+		// out
+		expectedMessages []outbox.Message
+		expectedError    bool
+	}{
+		{
+			caseName: "Event is written for each subscription to it",
+			subscriptions: NewFakeRepository(
+				nil,
+				nil,
+				[]FakeRepositoryListResult{
+					{
+						Subscriptions: subscriptions,
+					},
+				},
+				nil,
+			),
+			outbox: &recordingOutbox{},
+			event: Event{
+				Type:      EventAccountCreated,
+				TenantID:  1,
+				AccountID: 123,
+			},
+			expectedMessages: []outbox.Message{
+				{
+					ID:      uuidProducer(),
+					Channel: Channel,
+					Notification: notifier.Notification{
+						Kind:      EventAccountCreated,
+						TenantID:  1,
+						AccountID: 123,
+						Recipient: "all",
+						Params: map[string]string{
+							paramEventID:    uuidProducer(),
+							paramOccurredAt: now.Format(time.RFC3339),
+						},
+					},
+					Status:        outbox.StatusPending,
+					CreatedAt:     now,
+					NextAttemptAt: now,
+				},
+				{
+					ID:      uuidProducer(),
+					Channel: Channel,
+					Notification: notifier.Notification{
+						Kind:      EventAccountCreated,
+						TenantID:  1,
+						AccountID: 123,
+						Recipient: "created",
+						Params: map[string]string{
+							paramEventID:    uuidProducer(),
+							paramOccurredAt: now.Format(time.RFC3339),
+						},
+					},
+					Status:        outbox.StatusPending,
+					CreatedAt:     now,
+					NextAttemptAt: now,
+				},
+			},
+		},
+		{
+			caseName: "Event without subscriptions is not written",
+			subscriptions: NewFakeRepository(
+				nil,
+				nil,
+				[]FakeRepositoryListResult{
+					{
+						Subscriptions: subscriptions[2:],
+					},
+				},
+				nil,
+			),
+			outbox: &recordingOutbox{},
+			event: Event{
+				Type: EventAccountCreated,
+			},
+		},
+		{
+			caseName: "Error on List",
+			subscriptions: NewFakeRepository(
+				nil,
+				nil,
+				[]FakeRepositoryListResult{
+					{
+						Error: fmt.Errorf("List failed"),
+					},
+				},
+				nil,
+			),
+			outbox:        &recordingOutbox{},
+			event:         Event{Type: EventAccountCreated},
+			expectedError: true,
+		},
+		{
+			caseName: "Error on Add",
+			subscriptions: NewFakeRepository(
+				nil,
+				nil,
+				[]FakeRepositoryListResult{
+					{
+						Subscriptions: subscriptions[:1],
+					},
+				},
+				nil,
+			),
+			outbox: &recordingOutbox{err: fmt.Errorf("Add failed")},
+			event:  Event{Type: EventLoginFailed},
+			expectedMessages: []outbox.Message{
+				{
+					ID:      uuidProducer(),
+					Channel: Channel,
+					Notification: notifier.Notification{
+						Kind:      EventLoginFailed,
+						Recipient: "all",
+						Params: map[string]string{
+							paramEventID:    uuidProducer(),
+							paramOccurredAt: now.Format(time.RFC3339),
+						},
+					},
+					Status:        outbox.StatusPending,
+					CreatedAt:     now,
+					NextAttemptAt: now,
+				},
+			},
+			expectedError: true,
+		},
+	}
+
+	for i, c := range cases {
+		err := NewEmitter(c.subscriptions, c.outbox, uuidProducer, clock.Fixed(now)).Emit(c.event)
+
+		if (err != nil) != c.expectedError {
+			t.Errorf(
+				"testcase %d %s:\nExpected error to be %v, but got %v\n",
+				i,
+				c.caseName,
+				c.expectedError,
+				err,
+			)
+		}
+
+		if !reflect.DeepEqual(c.outbox.messages, c.expectedMessages) {
+			t.Errorf(
+				"testcase %d %s:\nExpected messages to be\n%#v\nbut got\n%#v\n",
+				i,
+				c.caseName,
+				c.expectedMessages,
+				c.outbox.messages,
+			)
+		}
+	}
+}
+
+func TestEmitter_Messages(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	uuidProducer := func() string {
+		return "12345678-90ab-cdef-0123-4567890abcde"
+	}
+
+	o := &recordingOutbox{}
+	em := NewEmitter(
+		NewFakeRepository(
+			nil,
+			nil,
+			[]FakeRepositoryListResult{
+				{
+					Subscriptions: []*Subscription{{ID: "all"}},
+				},
+			},
+			nil,
+		),
+		o,
+		uuidProducer,
+		clock.Fixed(now),
+	)
+
+	messages, err := em.Messages(SessionRevokedEvent(1, 123, ""))
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+
+	expectedMessages := []outbox.Message{
+		{
+			ID:      uuidProducer(),
+			Channel: Channel,
+			Notification: notifier.Notification{
+				Kind:      EventSessionRevoked,
+				TenantID:  1,
+				AccountID: 123,
+				Recipient: "all",
+				Params: map[string]string{
+					"all":           "true",
+					paramEventID:    uuidProducer(),
+					paramOccurredAt: now.Format(time.RFC3339),
+				},
+			},
+			Status:        outbox.StatusPending,
+			CreatedAt:     now,
+			NextAttemptAt: now,
+		},
+	}
+	if !reflect.DeepEqual(messages, expectedMessages) {
+		t.Errorf("Expected messages to be\n%#v\nbut got\n%#v\n", expectedMessages, messages)
+	}
+
+	// The messages are added by the caller, along with the change.
+	if len(o.messages) != 0 {
+		t.Errorf("Expected no messages to be added, but got %#v", o.messages)
+	}
+}
+
+func TestMessages(t *testing.T) {
+	fakeLogger := log.New(ioutil.Discard, "", log.LstdFlags)
+	m := outbox.Message{ID: "1"}
+
+	if messages := Messages(nil, fakeLogger, Event{}); messages != nil {
+		t.Errorf("Expected no messages without an emitter, but got %#v", messages)
+	}
+
+	em := NewFakeEmitter(
+		[]FakeEmitterMessagesResult{
+			{
+				Messages: []outbox.Message{m},
+			},
+			{
+				Messages: []outbox.Message{m},
+			},
+		},
+		nil,
+	)
+	if messages := Messages(em, fakeLogger, Event{}, Event{}); len(messages) != 2 {
+		t.Errorf("Expected messages of both events, but got %#v", messages)
+	}
+
+	em = NewFakeEmitter(
+		[]FakeEmitterMessagesResult{
+			{
+				Error: fmt.Errorf("Messages failed"),
+			},
+		},
+		nil,
+	)
+	if messages := Messages(em, fakeLogger, Event{}); messages != nil {
+		t.Errorf("Expected no messages on error, but got %#v", messages)
+	}
+}
@@ -0,0 +1,113 @@
+package webhook
+
+import "github.com/hypnoglow/pascont/outbox"
+
+type fakeEmitter struct {
+	messagesResults       []FakeEmitterMessagesResult
+	messagesResultCounter int
+	emitResults           []FakeEmitterEmitResult
+	emitResultCounter     int
+}
+
+type FakeEmitterMessagesResult struct {
+	Messages []outbox.Message
+	Error    error
+}
+
+type FakeEmitterEmitResult struct {
+	Error error
+}
+
+// NewFakeEmitter returns a new fake Emitter.
+func NewFakeEmitter(messagesResults []FakeEmitterMessagesResult, emitResults []FakeEmitterEmitResult) Emitter {
+	return &fakeEmitter{
+		messagesResults:       messagesResults,
+		messagesResultCounter: 0,
+		emitResults:           emitResults,
+		emitResultCounter:     0,
+	}
+}
+
+func (em *fakeEmitter) Messages(e Event) ([]outbox.Message, error) {
+	res := em.messagesResults[em.messagesResultCounter]
+	em.messagesResultCounter++
+	return res.Messages, res.Error
+}
+
+func (em *fakeEmitter) Emit(e Event) error {
+	res := em.emitResults[em.emitResultCounter]
+	em.emitResultCounter++
+	return res.Error
+}
+
+type fakeRepository struct {
+	saveResults           []FakeRepositorySaveResult
+	saveResultCounter     int
+	findByIDResults       []FakeRepositoryFindByIDResult
+	findByIDResultCounter int
+	listResults           []FakeRepositoryListResult
+	listResultCounter     int
+	deleteResults         []FakeRepositoryDeleteResult
+	deleteResultCounter   int
+}
+
+type FakeRepositorySaveResult struct {
+	Error error
+}
+
+type FakeRepositoryFindByIDResult struct {
+	Subscription *Subscription
+	Error        error
+}
+
+type FakeRepositoryListResult struct {
+	Subscriptions []*Subscription
+	Error         error
+}
+
+type FakeRepositoryDeleteResult struct {
+	Error error
+}
+
+// NewFakeRepository returns a new fake Repository.
+func NewFakeRepository(
+	saveResults []FakeRepositorySaveResult,
+	findByIDResults []FakeRepositoryFindByIDResult,
+	listResults []FakeRepositoryListResult,
+	deleteResults []FakeRepositoryDeleteResult,
+) Repository {
+	return &fakeRepository{
+		saveResults:           saveResults,
+		saveResultCounter:     0,
+		findByIDResults:       findByIDResults,
+		findByIDResultCounter: 0,
+		listResults:           listResults,
+		listResultCounter:     0,
+		deleteResults:         deleteResults,
+		deleteResultCounter:   0,
+	}
+}
+
+func (r *fakeRepository) Save(s Subscription) error {
+	res := r.saveResults[r.saveResultCounter]
+	r.saveResultCounter++
+	return res.Error
+}
+
+func (r *fakeRepository) FindByID(id string) (*Subscription, error) {
+	res := r.findByIDResults[r.findByIDResultCounter]
+	r.findByIDResultCounter++
+	return res.Subscription, res.Error
+}
+
+func (r *fakeRepository) List() ([]*Subscription, error) {
+	res := r.listResults[r.listResultCounter]
+	r.listResultCounter++
+	return res.Subscriptions, res.Error
+}
+
+func (r *fakeRepository) Delete(id string) error {
+	res := r.deleteResults[r.deleteResultCounter]
+	r.deleteResultCounter++
+	return res.Error
+}
@@ -0,0 +1,98 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	"github.com/hypnoglow/pascont/clock"
+	"github.com/hypnoglow/pascont/notary"
+	"github.com/hypnoglow/pascont/notifier"
+)
+
+// Headers of event requests.
+const (
+	HeaderEvent     = "X-Pascont-Event"
+	HeaderEventID   = "X-Pascont-Event-Id"
+	HeaderTimestamp = "X-Pascont-Timestamp"
+
+	// HeaderSignature is a hex-encoded signature of the timestamp and the body,
+	// made with the subscription secret. See SignedMessage.
+	HeaderSignature = "X-Pascont-Signature"
+)
+
+// SignedMessage returns the message of the event request signature:
+// the timestamp header and the request body, joined with a dot.
+// The timestamp makes it possible for subscribers to reject replayed requests.
+func SignedMessage(timestamp string, body []byte) []byte {
+	return append([]byte(timestamp+"."), body...)
+}
+
+// webhookNotifier is a Notifier which POSTs events to the URLs of subscriptions.
+// The recipient of notifications is a subscription ID.
+type webhookNotifier struct {
+	subscriptions Repository
+	notary        notary.Notary
+	client        *http.Client
+	clock         clock.Clock
+}
+
+// NewNotifier returns a new Notifier which delivers events written to the outbox by Emitter.
+// Requests are signed with the notary. If client is nil, http.DefaultClient is used.
+func NewNotifier(subscriptions Repository, n notary.Notary, client *http.Client, clk clock.Clock) notifier.Notifier {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return webhookNotifier{subscriptions, n, client, clk}
+}
+
+func (n webhookNotifier) Notify(notification notifier.Notification) error {
+	s, err := n.subscriptions.FindByID(notification.Recipient)
+	if err == ErrNotFound {
+		// Events of deleted subscriptions are dropped.
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	e, err := EventFromNotification(notification)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(e)
+	if err != nil {
+		return errors.Wrap(err, "Failed to encode an event")
+	}
+
+	req, err := http.NewRequest(http.MethodPost, s.URL, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "Failed to create an event request")
+	}
+
+	timestamp := strconv.FormatInt(n.clock().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, e.Type)
+	req.Header.Set(HeaderEventID, e.ID)
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderSignature, hex.EncodeToString(n.notary.Sign(SignedMessage(timestamp, body), s.Secret)))
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "Failed to post an event")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("Subscription %s responded with status %d", s.ID, resp.StatusCode)
+	}
+
+	return nil
+}
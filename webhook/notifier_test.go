@@ -0,0 +1,140 @@
+package webhook
+
+import (
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/pascont/clock"
+	"github.com/hypnoglow/pascont/notary"
+)
+
+func TestWebhookNotifier_Notify(t *testing.T) {
+	now := time.Date(2017, 7, 1, 21, 10, 29, 0, time.UTC)
+	secret := []byte("0123456789abcdef0123456789abcdef")
+	event := Event{
+		ID:         "event",
+		Type:       EventLoginSucceeded,
+		TenantID:   1,
+		AccountID:  123,
+		OccurredAt: now,
+		Data:       map[string]string{"sessionID": "session"},
+	}
+
+	var status int
+	var header http.Header
+	var body []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		header = req.Header
+		body, _ = ioutil.ReadAll(req.Body)
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	subscription := &Subscription{ID: "subscription", URL: srv.URL, Secret: secret}
+
+	cases := []struct {
+		caseName string
+		// in
+		subscriptions Repository
+		status        int
+		// out
+		expectedRequest bool
+		expectedError   bool
+	}{
+		{
+			caseName: "Delivered",
+			subscriptions: NewFakeRepository(
+				nil,
+				[]FakeRepositoryFindByIDResult{
+					{
+						Subscription: subscription,
+					},
+				},
+				nil,
+				nil,
+			),
+			status:          http.StatusOK,
+			expectedRequest: true,
+		},
+		{
+			caseName: "Rejected",
+			subscriptions: NewFakeRepository(
+				nil,
+				[]FakeRepositoryFindByIDResult{
+					{
+						Subscription: subscription,
+					},
+				},
+				nil,
+				nil,
+			),
+			status:          http.StatusServiceUnavailable,
+			expectedRequest: true,
+			expectedError:   true,
+		},
+		{
+			caseName: "Event of a deleted subscription is dropped",
+			subscriptions: NewFakeRepository(
+				nil,
+				[]FakeRepositoryFindByIDResult{
+					{
+						Error: ErrNotFound,
+					},
+				},
+				nil,
+				nil,
+			),
+		},
+	}
+
+	for i, c := range cases {
+		status, header, body = c.status, nil, nil
+		n := NewNotifier(c.subscriptions, notary.NewHMACNotary(), nil, clock.Fixed(now))
+
+		err := n.Notify(event.notification(subscription.ID))
+		if (err != nil) != c.expectedError {
+			t.Errorf(
+				"testcase %d %s:\nExpected error to be %v, but got %v\n",
+				i,
+				c.caseName,
+				c.expectedError,
+				err,
+			)
+		}
+
+		if (header != nil) != c.expectedRequest {
+			t.Errorf("testcase %d %s:\nExpected request to be %v\n", i, c.caseName, c.expectedRequest)
+		}
+		if header == nil {
+			continue
+		}
+
+		expectedHeader := map[string]string{
+			"Content-Type":  "application/json",
+			HeaderEvent:     EventLoginSucceeded,
+			HeaderEventID:   "event",
+			HeaderTimestamp: "1498943429",
+		}
+		for k, v := range expectedHeader {
+			if header.Get(k) != v {
+				t.Errorf("testcase %d %s:\nExpected header %s to be %s, but got %s\n", i, c.caseName, k, v, header.Get(k))
+			}
+		}
+
+		signature, _ := hex.DecodeString(header.Get(HeaderSignature))
+		if !notary.NewHMACNotary().Verify(SignedMessage(header.Get(HeaderTimestamp), body), signature, secret) {
+			t.Errorf("testcase %d %s:\nExpected valid signature, but got %s\n", i, c.caseName, header.Get(HeaderSignature))
+		}
+
+		expectedBody := `{"id":"event","type":"session.login_succeeded","tenantID":1,"accountID":123,` +
+			`"occurredAt":"2017-07-01T21:10:29Z","data":{"sessionID":"session"}}`
+		if !reflect.DeepEqual(string(body), expectedBody) {
+			t.Errorf("testcase %d %s:\nExpected body to be\n%s\nbut got\n%s\n", i, c.caseName, expectedBody, body)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package webhook
+
+import (
+	"sync"
+	"time"
+
+	"github.com/hypnoglow/pascont/clock"
+)
+
+// Repository is a repository for a Subscription.
+type Repository interface {
+	// Save saves a Subscription to the repository.
+	// If the subscription can not be changed, returns ErrReadOnly.
+	// Other errors may occur.
+	Save(s Subscription) error
+
+	// FindByID retrieves a Subscription for matching id.
+	// If subscription with such id not found, returns ErrNotFound.
+	// Other errors may occur.
+	FindByID(id string) (*Subscription, error)
+
+	// List retrieves all subscriptions ordered by creation time.
+	List() ([]*Subscription, error)
+
+	// Delete removes the subscription.
+	// If subscription with such id not found, returns ErrNotFound.
+	// If the subscription can not be changed, returns ErrReadOnly.
+	// Other errors may occur.
+	Delete(id string) error
+}
+
+// repositoryError is an error occurred in Repository.
+type repositoryError string
+
+func (e repositoryError) Error() string {
+	return string(e)
+}
+
+const (
+	// ErrNotFound occurs when subscription not found.
+	ErrNotFound = repositoryError("Subscription not found")
+
+	// ErrReadOnly occurs when subscription can not be changed,
+	// because it is registered in the config.
+	ErrReadOnly = repositoryError("Subscription is read-only")
+)
+
+// staticRepository is a Repository with subscriptions registered in the config
+// along with the ones in the underlying repository.
+type staticRepository struct {
+	static []Subscription
+	repo   Repository
+}
+
+// NewStaticRepository returns a new Repository, which has the static subscriptions
+// in addition to the ones in repo. Static subscriptions can not be changed.
+func NewStaticRepository(static []Subscription, repo Repository) Repository {
+	return staticRepository{static, repo}
+}
+
+func (r staticRepository) Save(s Subscription) error {
+	if r.isStatic(s.ID) {
+		return ErrReadOnly
+	}
+
+	return r.repo.Save(s)
+}
+
+func (r staticRepository) FindByID(id string) (*Subscription, error) {
+	for _, s := range r.static {
+		if s.ID == id {
+			s := s
+			return &s, nil
+		}
+	}
+
+	return r.repo.FindByID(id)
+}
+
+func (r staticRepository) List() ([]*Subscription, error) {
+	subscriptions, err := r.repo.List()
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*Subscription, 0, len(r.static)+len(subscriptions))
+	for _, s := range r.static {
+		s := s
+		list = append(list, &s)
+	}
+
+	return append(list, subscriptions...), nil
+}
+
+func (r staticRepository) Delete(id string) error {
+	if r.isStatic(id) {
+		return ErrReadOnly
+	}
+
+	return r.repo.Delete(id)
+}
+
+func (r staticRepository) isStatic(id string) bool {
+	for _, s := range r.static {
+		if s.ID == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+// cachedRepository is a Repository which keeps the list of subscriptions for a while,
+// so events are emitted without retrieving and decrypting all subscriptions each time.
+type cachedRepository struct {
+	repo  Repository
+	ttl   time.Duration
+	clock clock.Clock
+
+	mu            sync.Mutex
+	subscriptions []*Subscription
+	expiresAt     time.Time
+}
+
+// NewCachedRepository returns a new Repository, which lists the subscriptions of repo
+// at most once in ttl. The list is retrieved again after subscriptions are changed with it,
+// changes made elsewhere are seen when the ttl passes.
+func NewCachedRepository(repo Repository, ttl time.Duration, clk clock.Clock) Repository {
+	return &cachedRepository{repo: repo, ttl: ttl, clock: clk}
+}
+
+func (r *cachedRepository) Save(s Subscription) error {
+	defer r.invalidate()
+	return r.repo.Save(s)
+}
+
+func (r *cachedRepository) FindByID(id string) (*Subscription, error) {
+	return r.repo.FindByID(id)
+}
+
+func (r *cachedRepository) List() ([]*Subscription, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.clock()
+	if now.Before(r.expiresAt) {
+		return append([]*Subscription{}, r.subscriptions...), nil
+	}
+
+	subscriptions, err := r.repo.List()
+	if err != nil {
+		return nil, err
+	}
+	r.subscriptions = subscriptions
+	r.expiresAt = now.Add(r.ttl)
+
+	return append([]*Subscription{}, subscriptions...), nil
+}
+
+func (r *cachedRepository) Delete(id string) error {
+	defer r.invalidate()
+	return r.repo.Delete(id)
+}
+
+// invalidate makes the next List retrieve the subscriptions again.
+func (r *cachedRepository) invalidate() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.subscriptions = nil
+	r.expiresAt = time.Time{}
+}
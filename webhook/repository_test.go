@@ -0,0 +1,133 @@
+package webhook
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestStaticRepository(t *testing.T) {
+	static := []Subscription{
+		{ID: "static", URL: "https://example.com/static"},
+	}
+	stored := &Subscription{ID: "stored", URL: "https://example.com/stored"}
+
+	repo := NewStaticRepository(static, NewFakeRepository(
+		[]FakeRepositorySaveResult{
+			{
+				Error: nil,
+			},
+		},
+		[]FakeRepositoryFindByIDResult{
+			{
+				Subscription: stored,
+				Error:        nil,
+			},
+		},
+		[]FakeRepositoryListResult{
+			{
+				Subscriptions: []*Subscription{stored},
+				Error:         nil,
+			},
+			{
+				Subscriptions: nil,
+				Error:         fmt.Errorf("List failed"),
+			},
+		},
+		[]FakeRepositoryDeleteResult{
+			{
+				Error: nil,
+			},
+		},
+	))
+
+	if s, err := repo.FindByID("static"); err != nil || s.URL != "https://example.com/static" {
+		t.Errorf("Expected static subscription, but got %v, %v", s, err)
+	}
+	if s, err := repo.FindByID("stored"); err != nil || s != stored {
+		t.Errorf("Expected stored subscription, but got %v, %v", s, err)
+	}
+
+	list, err := repo.List()
+	if err != nil || len(list) != 2 || list[0].ID != "static" || list[1] != stored {
+		t.Errorf("Expected static and stored subscriptions, but got %v, %v", list, err)
+	}
+	if _, err := repo.List(); err == nil {
+		t.Errorf("Expected error on List, but got nil")
+	}
+
+	if err := repo.Save(Subscription{ID: "static"}); err != ErrReadOnly {
+		t.Errorf("Expected error to be %v, but got %v", ErrReadOnly, err)
+	}
+	if err := repo.Save(*stored); err != nil {
+		t.Errorf("Expected no error, but got %v", err)
+	}
+
+	if err := repo.Delete("static"); err != ErrReadOnly {
+		t.Errorf("Expected error to be %v, but got %v", ErrReadOnly, err)
+	}
+	if err := repo.Delete("stored"); err != nil {
+		t.Errorf("Expected no error, but got %v", err)
+	}
+}
+
+func TestCachedRepository(t *testing.T) {
+	now := time.Now()
+	first := &Subscription{ID: "first"}
+	second := &Subscription{ID: "second"}
+
+	repo := NewCachedRepository(NewFakeRepository(
+		[]FakeRepositorySaveResult{
+			{
+				Error: nil,
+			},
+		},
+		nil,
+		[]FakeRepositoryListResult{
+			{
+				Subscriptions: []*Subscription{first},
+				Error:         nil,
+			},
+			{
+				Subscriptions: []*Subscription{first, second},
+				Error:         nil,
+			},
+			{
+				Subscriptions: []*Subscription{second},
+				Error:         nil,
+			},
+			{
+				Subscriptions: nil,
+				Error:         fmt.Errorf("List failed"),
+			},
+		},
+		nil,
+	), time.Minute, func() time.Time { return now })
+
+	// The list is retrieved once in the ttl.
+	for i := 0; i < 2; i++ {
+		list, err := repo.List()
+		if err != nil || len(list) != 1 || list[0] != first {
+			t.Errorf("Expected the first subscription, but got %v, %v", list, err)
+		}
+	}
+
+	// Changes made with the repository are seen at once.
+	if err := repo.Save(*second); err != nil {
+		t.Errorf("Expected no error, but got %v", err)
+	}
+	if list, err := repo.List(); err != nil || len(list) != 2 {
+		t.Errorf("Expected both subscriptions, but got %v, %v", list, err)
+	}
+
+	// Other changes are seen when the ttl passes.
+	now = now.Add(time.Minute)
+	if list, err := repo.List(); err != nil || len(list) != 1 || list[0] != second {
+		t.Errorf("Expected the second subscription, but got %v, %v", list, err)
+	}
+
+	now = now.Add(time.Minute)
+	if _, err := repo.List(); err == nil {
+		t.Errorf("Expected error on List, but got nil")
+	}
+}
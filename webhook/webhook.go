@@ -0,0 +1,199 @@
+// Package webhook delivers account and session lifecycle events to other services,
+// which subscribe to them with webhooks.
+// Events are delivered through the outbox, so they are retried when a subscriber fails to accept them.
+package webhook
+
+import (
+	"crypto/rand"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/hypnoglow/pascont/identity"
+	"github.com/hypnoglow/pascont/notifier"
+)
+
+// Channel is the outbox channel events are delivered with.
+const Channel = "webhook_events"
+
+// Types of events.
+const (
+	// EventAccountCreated occurs when an account is created.
+	EventAccountCreated = "account.created"
+
+	// EventPasswordChanged occurs when an account password is changed.
+	EventPasswordChanged = "account.password_changed"
+
+	// EventLoginSucceeded occurs when a session is issued on log in.
+	// Data: sessionID, ip, userAgent.
+	EventLoginSucceeded = "session.login_succeeded"
+
+	// EventLoginFailed occurs when a log in to an existing account fails.
+	// Data: reason, ip, userAgent.
+	EventLoginFailed = "session.login_failed"
+
+	// EventSessionRevoked occurs when a session is deleted before it expires.
+	// Data: sessionID, or all=true when all sessions of the account are revoked.
+	EventSessionRevoked = "session.revoked"
+)
+
+// Reasons of failed log ins, in the data of EventLoginFailed.
+const (
+	LoginFailedPassword   = "password"
+	LoginFailedCode       = "code"
+	LoginFailedLocked     = "locked"
+	LoginFailedInactive   = "inactive"
+	LoginFailedUnverified = "unverified"
+)
+
+// EventTypes are all types of events, subscriptions may subscribe to.
+var EventTypes = []string{
+	EventAccountCreated,
+	EventPasswordChanged,
+	EventLoginSucceeded,
+	EventLoginFailed,
+	EventSessionRevoked,
+}
+
+// IsEventType reports whether t is a type of events.
+func IsEventType(t string) bool {
+	for _, eventType := range EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Event is something that happened to an account, which subscribers are informed about.
+type Event struct {
+	ID         string            `json:"id"`
+	Type       string            `json:"type"`
+	TenantID   int64             `json:"tenantID"`
+	AccountID  int64             `json:"accountID"`
+	OccurredAt time.Time         `json:"occurredAt"`
+	Data       map[string]string `json:"data"`
+}
+
+// SessionRevokedEvent returns the EventSessionRevoked of the session of the account.
+// Empty sessionID means all the sessions of the account.
+func SessionRevokedEvent(tenantID, accountID int64, sessionID string) Event {
+	data := map[string]string{"sessionID": sessionID}
+	if sessionID == "" {
+		data = map[string]string{"all": "true"}
+	}
+
+	return Event{
+		Type:      EventSessionRevoked,
+		TenantID:  tenantID,
+		AccountID: accountID,
+		Data:      data,
+	}
+}
+
+// Events travel through the outbox as notifications to subscriptions.
+// The event ID and time are among the notification params.
+const (
+	paramEventID    = "_eventID"
+	paramOccurredAt = "_occurredAt"
+)
+
+// notification returns the notification delivering the event to the subscription.
+func (e Event) notification(subscriptionID string) notifier.Notification {
+	params := make(map[string]string, len(e.Data)+2)
+	for k, v := range e.Data {
+		params[k] = v
+	}
+	params[paramEventID] = e.ID
+	params[paramOccurredAt] = e.OccurredAt.Format(time.RFC3339)
+
+	return notifier.Notification{
+		Kind:      e.Type,
+		TenantID:  e.TenantID,
+		AccountID: e.AccountID,
+		Recipient: subscriptionID,
+		Params:    params,
+	}
+}
+
+// EventFromNotification returns the event delivered with the notification written by Emitter.
+func EventFromNotification(n notifier.Notification) (Event, error) {
+	occurredAt, err := time.Parse(time.RFC3339, n.Params[paramOccurredAt])
+	if err != nil {
+		return Event{}, errors.Wrap(err, "Failed to decode an event")
+	}
+
+	data := make(map[string]string, len(n.Params))
+	for k, v := range n.Params {
+		if k != paramEventID && k != paramOccurredAt {
+			data[k] = v
+		}
+	}
+
+	return Event{
+		ID:         n.Params[paramEventID],
+		Type:       n.Kind,
+		TenantID:   n.TenantID,
+		AccountID:  n.AccountID,
+		OccurredAt: occurredAt,
+		Data:       data,
+	}, nil
+}
+
+// SecretLength is the length of a subscription secret in bytes.
+const SecretLength = 32
+
+// Subscription is a webhook of a service subscribed to events.
+type Subscription struct {
+	ID  string
+	URL string
+
+	// Events are the types of events the subscription receives.
+	// Empty Events means all the events.
+	Events []string
+
+	// Secret is a key events are signed with.
+	Secret []byte
+
+	CreatedAt time.Time
+}
+
+// Generate creates a new Subscription of the url to the events, with a random secret.
+// The Subscription ID is a UUID produced by identity.UUIDProducer func.
+func Generate(uuidProducer identity.UUIDProducer, url string, events []string, createdAt time.Time) (*Subscription, error) {
+	for _, e := range events {
+		if !IsEventType(e) {
+			return nil, fmt.Errorf("Unknown event type %s", e)
+		}
+	}
+
+	secret := make([]byte, SecretLength)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, errors.Wrap(err, "Failed to generate a subscription secret")
+	}
+
+	return &Subscription{
+		ID:        uuidProducer(),
+		URL:       url,
+		Events:    events,
+		Secret:    secret,
+		CreatedAt: createdAt.UTC().Truncate(time.Second),
+	}, nil
+}
+
+// Accepts reports whether the subscription receives events of the type.
+func (s Subscription) Accepts(eventType string) bool {
+	if len(s.Events) == 0 {
+		return true
+	}
+
+	for _, e := range s.Events {
+		if e == eventType {
+			return true
+		}
+	}
+
+	return false
+}
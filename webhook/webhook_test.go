@@ -0,0 +1,96 @@
+package webhook
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGenerate(t *testing.T) {
+	now := time.Now()
+	uuidProducer := func() string {
+		return "12345678-90ab-cdef-0123-4567890abcde"
+	}
+
+	s, err := Generate(uuidProducer, "https://example.com/hook", []string{EventAccountCreated}, now)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	if s.ID != uuidProducer() || s.URL != "https://example.com/hook" || !s.CreatedAt.Equal(now.UTC().Truncate(time.Second)) {
+		t.Errorf("Unexpected subscription %#v", *s)
+	}
+	if len(s.Secret) != SecretLength {
+		t.Errorf("Expected secret to be %d bytes long, but got %d", SecretLength, len(s.Secret))
+	}
+
+	if _, err := Generate(uuidProducer, "https://example.com/hook", []string{"unknown"}, now); err == nil {
+		t.Errorf("Expected error on unknown event type, but got nil")
+	}
+}
+
+func TestSubscription_Accepts(t *testing.T) {
+	cases := []struct {
+		caseName string
+		// in
+		events    []string
+		eventType string
+		// out
+		expected bool
+	}{
+		{
+			caseName:  "Subscription to all events",
+			events:    nil,
+			eventType: EventLoginFailed,
+			expected:  true,
+		},
+		{
+			caseName:  "Subscribed event",
+			events:    []string{EventAccountCreated, EventLoginFailed},
+			eventType: EventLoginFailed,
+			expected:  true,
+		},
+		{
+			caseName:  "Not subscribed event",
+			events:    []string{EventAccountCreated},
+			eventType: EventLoginFailed,
+			expected:  false,
+		},
+	}
+
+	for i, c := range cases {
+		s := Subscription{Events: c.events}
+		if actual := s.Accepts(c.eventType); actual != c.expected {
+			t.Errorf(
+				"testcase %d %s:\nExpected %v, but got %v\n",
+				i,
+				c.caseName,
+				c.expected,
+				actual,
+			)
+		}
+	}
+}
+
+func TestEvent_notification(t *testing.T) {
+	e := Event{
+		ID:         "event",
+		Type:       EventSessionRevoked,
+		TenantID:   1,
+		AccountID:  123,
+		OccurredAt: time.Date(2017, 7, 1, 21, 10, 29, 0, time.UTC),
+		Data:       map[string]string{"sessionID": "session"},
+	}
+
+	n := e.notification("subscription")
+	if n.Recipient != "subscription" || n.Kind != EventSessionRevoked {
+		t.Errorf("Unexpected notification %#v", n)
+	}
+
+	actual, err := EventFromNotification(n)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	if !reflect.DeepEqual(actual, e) {
+		t.Errorf("Expected event to be\n%#v\nbut got\n%#v\n", e, actual)
+	}
+}
@@ -0,0 +1,101 @@
+// Package webhooks provides a REST controller for operators to manage webhook subscriptions
+// and the deliveries of events to them.
+package webhooks
+
+import (
+	"log"
+	"time"
+
+	"github.com/hypnoglow/pascont/clock"
+	"github.com/hypnoglow/pascont/identity"
+	"github.com/hypnoglow/pascont/outbox"
+	"github.com/hypnoglow/pascont/webhook"
+)
+
+const (
+	PathWebhooks              = "/admin/webhooks"
+	PathWebhook               = "/admin/webhooks/:id"
+	PathWebhookDeliveries     = "/admin/webhooks/:id/deliveries"
+	PathWebhookDeliveryReplay = "/admin/webhooks/:id/deliveries/:deliveryID/replay"
+)
+
+// RestController is a REST controller for webhook subscriptions.
+type RestController struct {
+	logger           *log.Logger
+	subscriptionRepo webhook.Repository
+	outboxRepo       outbox.Repository
+	uuidProducer     identity.UUIDProducer
+	clock            clock.Clock
+}
+
+// NewRestController returns a new RestController.
+func NewRestController(
+	logger *log.Logger,
+	subscriptionRepo webhook.Repository,
+	outboxRepo outbox.Repository,
+	u identity.UUIDProducer,
+	clk clock.Clock,
+) RestController {
+	return RestController{
+		logger,
+		subscriptionRepo,
+		outboxRepo,
+		u,
+		clk,
+	}
+}
+
+type subscriptionSchema struct {
+	ID        string    `json:"id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func newSubscriptionSchema(s *webhook.Subscription) subscriptionSchema {
+	events := s.Events
+	if events == nil {
+		events = []string{}
+	}
+
+	return subscriptionSchema{
+		ID:        s.ID,
+		URL:       s.URL,
+		Events:    events,
+		CreatedAt: s.CreatedAt,
+	}
+}
+
+type deliverySchema struct {
+	ID            string        `json:"id"`
+	Event         webhook.Event `json:"event"`
+	Status        outbox.Status `json:"status"`
+	Attempts      int           `json:"attempts"`
+	NextAttemptAt time.Time     `json:"nextAttemptAt"`
+	LastError     string        `json:"lastError"`
+	CreatedAt     time.Time     `json:"createdAt"`
+	DeliveredAt   *time.Time    `json:"deliveredAt,omitempty"`
+}
+
+func newDeliverySchema(m outbox.Message) (deliverySchema, error) {
+	e, err := webhook.EventFromNotification(m.Notification)
+	if err != nil {
+		return deliverySchema{}, err
+	}
+
+	s := deliverySchema{
+		ID:            m.ID,
+		Event:         e,
+		Status:        m.Status,
+		Attempts:      m.Attempts,
+		NextAttemptAt: m.NextAttemptAt,
+		LastError:     m.LastError,
+		CreatedAt:     m.CreatedAt,
+	}
+	if !m.DeliveredAt.IsZero() {
+		deliveredAt := m.DeliveredAt
+		s.DeliveredAt = &deliveredAt
+	}
+
+	return s, nil
+}
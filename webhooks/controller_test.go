@@ -0,0 +1,21 @@
+package webhooks
+
+import (
+	"io/ioutil"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/pascont/outbox"
+	"github.com/hypnoglow/pascont/webhook"
+)
+
+func TestNewRestController(t *testing.T) {
+	NewRestController(
+		log.New(ioutil.Discard, "", log.LstdFlags),
+		webhook.NewFakeRepository(nil, nil, nil, nil),
+		outbox.NewFakeRepository(nil, nil, nil, nil, nil, nil, nil, nil, nil),
+		func() string { return "" },
+		time.Now,
+	)
+}
@@ -0,0 +1,39 @@
+package webhooks
+
+import (
+	"net/http"
+
+	"github.com/hypnoglow/pascont/kit"
+	"github.com/hypnoglow/pascont/kit/middleware"
+	"github.com/hypnoglow/pascont/kit/schema"
+	"github.com/hypnoglow/pascont/webhook"
+)
+
+// DeleteWebhook is a handler for:
+// DELETE /admin/webhooks/:id
+//
+// Pending deliveries to the subscription are dropped.
+func (c RestController) DeleteWebhook(w http.ResponseWriter, req *http.Request) {
+	id, _ := middleware.PathParam(req, "id")
+
+	err := c.subscriptionRepo.Delete(id)
+	if err == webhook.ErrNotFound {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if err == webhook.ErrReadOnly {
+		kit.RespondWithError(w, http.StatusConflict, schema.NewError(
+			"Subscription is registered in the config and can not be deleted",
+			"",
+			nil,
+		))
+		return
+	}
+	if err != nil {
+		c.logger.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,105 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hypnoglow/pascont/kit/middleware"
+	"github.com/hypnoglow/pascont/webhook"
+)
+
+func TestRestController_DeleteWebhook(t *testing.T) {
+	fakeLogger := log.New(ioutil.Discard, "", log.LstdFlags)
+
+	cases := []struct {
+		caseName string
+		// in
+		subscriptionRepo webhook.Repository
+		// out
+		expectedCode int
+	}{
+		{
+			caseName: "Non-existent subscription should result in 404",
+			subscriptionRepo: webhook.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				[]webhook.FakeRepositoryDeleteResult{
+					{
+						Error: webhook.ErrNotFound,
+					},
+				},
+			),
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			caseName: "Subscription from the config should result in 409",
+			subscriptionRepo: webhook.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				[]webhook.FakeRepositoryDeleteResult{
+					{
+						Error: webhook.ErrReadOnly,
+					},
+				},
+			),
+			expectedCode: http.StatusConflict,
+		},
+		{
+			caseName: "subscriptionRepo.Delete failed",
+			subscriptionRepo: webhook.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				[]webhook.FakeRepositoryDeleteResult{
+					{
+						Error: fmt.Errorf("Delete failed"),
+					},
+				},
+			),
+			expectedCode: http.StatusInternalServerError,
+		},
+		{
+			caseName: "Successful",
+			subscriptionRepo: webhook.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				[]webhook.FakeRepositoryDeleteResult{
+					{
+						Error: nil,
+					},
+				},
+			),
+			expectedCode: http.StatusNoContent,
+		},
+	}
+
+	for i, c := range cases {
+		ctrl := NewRestController(fakeLogger, c.subscriptionRepo, nil, nil, nil)
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodDelete, PathWebhooks+"/12345678-90ab-cdef-0123-4567890abcde", nil)
+		req = req.WithContext(context.WithValue(
+			req.Context(),
+			middleware.ContextKeyPathParams{},
+			map[string]string{"id": "12345678-90ab-cdef-0123-4567890abcde"},
+		))
+		ctrl.DeleteWebhook(w, req)
+
+		if w.Code != c.expectedCode {
+			t.Errorf(
+				"testcase %d %s:\nExpected status code to be %v, but got %v\n",
+				i,
+				c.caseName,
+				c.expectedCode,
+				w.Code,
+			)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package webhooks
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/pborman/uuid"
+
+	"github.com/hypnoglow/pascont/kit"
+	"github.com/hypnoglow/pascont/kit/form"
+	"github.com/hypnoglow/pascont/kit/middleware"
+	"github.com/hypnoglow/pascont/kit/schema"
+	"github.com/hypnoglow/pascont/outbox"
+	"github.com/hypnoglow/pascont/webhook"
+)
+
+// GetWebhookDeliveries is a handler for:
+// GET /admin/webhooks/:id/deliveries
+//
+// Deliveries can be filtered by status with the "status" parameter,
+// e.g. status=dead lists the events the subscription failed to accept.
+func (c RestController) GetWebhookDeliveries(w http.ResponseWriter, req *http.Request) {
+	id, _ := middleware.PathParam(req, "id")
+
+	var pageForm form.PaginationForm
+	form.PopulatePaginationFormFromQuery(req.URL.Query(), &pageForm)
+	status := outbox.Status(req.URL.Query().Get("status"))
+	switch status {
+	case "", outbox.StatusPending, outbox.StatusDelivered, outbox.StatusDead:
+	default:
+		pageForm.AddError(fmt.Sprintf("Status %s is unknown", status), "status", status)
+	}
+	if !pageForm.Validate() {
+		kit.RespondWithFormErrors(w, http.StatusBadRequest, pageForm.ValidationErrors())
+		return
+	}
+
+	if _, err := c.subscriptionRepo.FindByID(id); err != nil {
+		if err == webhook.ErrNotFound {
+			w.WriteHeader(http.StatusNotFound)
+		} else {
+			c.logger.Println(err)
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		return
+	}
+
+	messages, total, err := c.outboxRepo.ListByRecipient(webhook.Channel, id, status, pageForm.Offset, pageForm.Limit)
+	if err != nil {
+		c.logger.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	results := make([]deliverySchema, len(messages))
+	for i, m := range messages {
+		if results[i], err = newDeliverySchema(m); err != nil {
+			c.logger.Println(err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+	}
+
+	kit.RespondJSON(w, http.StatusOK, schema.NewResultsBody(
+		results,
+		schema.NewPaginationMeta(pageForm.Limit, pageForm.Offset, total),
+	))
+}
+
+// PostWebhookDeliveryReplay is a handler for:
+// POST /admin/webhooks/:id/deliveries/:deliveryID/replay
+//
+// The event is delivered again, with retries as a new one, whatever the delivery status is.
+func (c RestController) PostWebhookDeliveryReplay(w http.ResponseWriter, req *http.Request) {
+	id, _ := middleware.PathParam(req, "id")
+	deliveryID, _ := middleware.PathParam(req, "deliveryID")
+	if uuid.Parse(deliveryID) == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	m, err := c.outboxRepo.FindByID(deliveryID)
+	if err != nil {
+		if err == outbox.ErrNotFound {
+			w.WriteHeader(http.StatusNotFound)
+		} else {
+			c.logger.Println(err)
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		return
+	}
+
+	// Only deliveries to the subscription are visible.
+	if m.Channel != webhook.Channel || m.Notification.Recipient != id {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	if err := c.outboxRepo.Replay(m.ID, c.clock()); err != nil {
+		if err == outbox.ErrNotFound {
+			w.WriteHeader(http.StatusNotFound)
+		} else {
+			c.logger.Println(err)
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
@@ -0,0 +1,361 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/pascont/clock"
+	"github.com/hypnoglow/pascont/kit/middleware"
+	"github.com/hypnoglow/pascont/notifier"
+	"github.com/hypnoglow/pascont/outbox"
+	"github.com/hypnoglow/pascont/webhook"
+)
+
+func TestRestController_GetWebhookDeliveries(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	fakeLogger := log.New(ioutil.Discard, "", log.LstdFlags)
+	subscriptionID := "12345678-90ab-cdef-0123-4567890abcde"
+	found := []webhook.FakeRepositoryFindByIDResult{
+		{
+			Subscription: &webhook.Subscription{ID: subscriptionID},
+		},
+	}
+
+	cases := []struct {
+		caseName string
+		// in
+		subscriptionRepo webhook.Repository
+		outboxRepo       outbox.Repository
+		reqQuery         string
+		// out
+		expectedCode int
+		expectedBody *bytes.Buffer
+	}{
+		{
+			caseName: "Unknown status should result in 400",
+			reqQuery: "?status=lost",
+			// out
+			expectedCode: http.StatusBadRequest,
+			expectedBody: bytes.NewBufferString(`{
+				"errors":[
+					{
+						"message":"Status lost is unknown",
+						"field":"status",
+						"value":"lost"
+					}
+				]
+			}`),
+		},
+		{
+			caseName: "Non-existent subscription should result in 404",
+			subscriptionRepo: webhook.NewFakeRepository(
+				nil,
+				[]webhook.FakeRepositoryFindByIDResult{
+					{
+						Error: webhook.ErrNotFound,
+					},
+				},
+				nil,
+				nil,
+			),
+			// out
+			expectedCode: http.StatusNotFound,
+			expectedBody: bytes.NewBuffer(nil),
+		},
+		{
+			caseName:         "outboxRepo.ListByRecipient failed",
+			subscriptionRepo: webhook.NewFakeRepository(nil, found, nil, nil),
+			outboxRepo: outbox.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				[]outbox.FakeRepositoryListByRecipientResult{
+					{
+						Error: fmt.Errorf("ListByRecipient failed"),
+					},
+				},
+				nil,
+				nil,
+				nil,
+			),
+			// out
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: bytes.NewBuffer(nil),
+		},
+		{
+			caseName:         "Successful",
+			subscriptionRepo: webhook.NewFakeRepository(nil, found, nil, nil),
+			outboxRepo: outbox.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				[]outbox.FakeRepositoryListByRecipientResult{
+					{
+						Messages: []outbox.Message{
+							{
+								ID:      "87654321-90ab-cdef-0123-4567890abcde",
+								Channel: webhook.Channel,
+								Notification: notifier.Notification{
+									Kind:      webhook.EventAccountCreated,
+									TenantID:  1,
+									AccountID: 123,
+									Recipient: subscriptionID,
+									Params: map[string]string{
+										"_eventID":    "event",
+										"_occurredAt": now.Format(time.RFC3339),
+									},
+								},
+								Status:        outbox.StatusDead,
+								CreatedAt:     now,
+								Attempts:      10,
+								NextAttemptAt: now,
+								LastError:     "Subscription responded with status 500",
+							},
+						},
+						Total: 1,
+					},
+				},
+				nil,
+				nil,
+				nil,
+			),
+			reqQuery: "?status=dead",
+			// out
+			expectedCode: http.StatusOK,
+			expectedBody: bytes.NewBufferString(fmt.Sprintf(`{
+				"results":[
+					{
+						"id":"87654321-90ab-cdef-0123-4567890abcde",
+						"event":{
+							"id":"event",
+							"type":"account.created",
+							"tenantID":1,
+							"accountID":123,
+							"occurredAt":"%s",
+							"data":{}
+						},
+						"status":"dead",
+						"attempts":10,
+						"nextAttemptAt":"%s",
+						"lastError":"Subscription responded with status 500",
+						"createdAt":"%s"
+					}
+				],
+				"meta":{
+					"limit":20,
+					"offset":0,
+					"total":1
+				}
+			}`, now.Format(time.RFC3339), now.Format(time.RFC3339), now.Format(time.RFC3339))),
+		},
+	}
+
+	for i, c := range cases {
+		ctrl := NewRestController(fakeLogger, c.subscriptionRepo, c.outboxRepo, nil, nil)
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, PathWebhooks+"/"+subscriptionID+"/deliveries"+c.reqQuery, nil)
+		req = req.WithContext(context.WithValue(
+			req.Context(),
+			middleware.ContextKeyPathParams{},
+			map[string]string{"id": subscriptionID},
+		))
+		ctrl.GetWebhookDeliveries(w, req)
+
+		if w.Code != c.expectedCode {
+			t.Errorf(
+				"testcase %d %s:\nExpected status code to be %v, but got %v\n",
+				i,
+				c.caseName,
+				c.expectedCode,
+				w.Code,
+			)
+		}
+
+		// To make possible pretty formatting in test cases, we use json.Compact:
+		b := c.expectedBody.Bytes()
+		if b != nil {
+			c.expectedBody.Reset()
+			err := json.Compact(c.expectedBody, b)
+			if err != nil {
+				t.Errorf(
+					"testcase %d %s:\nFailed to compact JSON body: %s",
+					i,
+					c.caseName,
+					err,
+				)
+			}
+		}
+
+		if !bytes.Equal(w.Body.Bytes(), c.expectedBody.Bytes()) {
+			t.Errorf(
+				"testcase %d %s:\nExpected body to be\n%v\nbut got\n%v\n",
+				i,
+				c.caseName,
+				c.expectedBody,
+				w.Body,
+			)
+		}
+	}
+}
+
+func TestRestController_PostWebhookDeliveryReplay(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	fakeLogger := log.New(ioutil.Discard, "", log.LstdFlags)
+	subscriptionID := "12345678-90ab-cdef-0123-4567890abcde"
+	delivery := func(channel, recipient string) []outbox.FakeRepositoryFindByIDResult {
+		return []outbox.FakeRepositoryFindByIDResult{
+			{
+				Message: &outbox.Message{
+					ID:           "87654321-90ab-cdef-0123-4567890abcde",
+					Channel:      channel,
+					Notification: notifier.Notification{Recipient: recipient},
+					Status:       outbox.StatusDead,
+				},
+			},
+		}
+	}
+
+	cases := []struct {
+		caseName string
+		// in
+		deliveryID string
+		outboxRepo outbox.Repository
+		// out
+		expectedCode int
+	}{
+		{
+			caseName:     "Non-UUID delivery should result in 404",
+			deliveryID:   "latest",
+			outboxRepo:   outbox.NewFakeRepository(nil, nil, nil, nil, nil, nil, nil, nil, nil),
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			caseName: "Non-existent delivery should result in 404",
+			outboxRepo: outbox.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				nil,
+				[]outbox.FakeRepositoryFindByIDResult{
+					{
+						Error: outbox.ErrNotFound,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			caseName: "outboxRepo.FindByID failed",
+			outboxRepo: outbox.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				nil,
+				[]outbox.FakeRepositoryFindByIDResult{
+					{
+						Error: fmt.Errorf("FindByID failed"),
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			expectedCode: http.StatusInternalServerError,
+		},
+		{
+			caseName:     "Delivery to other subscription should result in 404",
+			outboxRepo:   outbox.NewFakeRepository(nil, nil, nil, nil, delivery(webhook.Channel, "other"), nil, nil, nil, nil),
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			caseName:     "Notification of other channel should result in 404",
+			outboxRepo:   outbox.NewFakeRepository(nil, nil, nil, nil, delivery("smtp", subscriptionID), nil, nil, nil, nil),
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			caseName: "outboxRepo.Replay failed",
+			outboxRepo: outbox.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				nil,
+				delivery(webhook.Channel, subscriptionID),
+				nil,
+				[]outbox.FakeRepositoryReplayResult{
+					{
+						Error: fmt.Errorf("Replay failed"),
+					},
+				},
+				nil,
+				nil,
+			),
+			expectedCode: http.StatusInternalServerError,
+		},
+		{
+			caseName: "Successful",
+			outboxRepo: outbox.NewFakeRepository(
+				nil,
+				nil,
+				nil,
+				nil,
+				delivery(webhook.Channel, subscriptionID),
+				nil,
+				[]outbox.FakeRepositoryReplayResult{
+					{
+						Error: nil,
+					},
+				},
+				nil,
+				nil,
+			),
+			expectedCode: http.StatusAccepted,
+		},
+	}
+
+	for i, c := range cases {
+		if c.deliveryID == "" {
+			c.deliveryID = "87654321-90ab-cdef-0123-4567890abcde"
+		}
+
+		ctrl := NewRestController(fakeLogger, nil, c.outboxRepo, nil, clock.Fixed(now))
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(
+			http.MethodPost,
+			PathWebhooks+"/"+subscriptionID+"/deliveries/"+c.deliveryID+"/replay",
+			nil,
+		)
+		req = req.WithContext(context.WithValue(
+			req.Context(),
+			middleware.ContextKeyPathParams{},
+			map[string]string{"id": subscriptionID, "deliveryID": c.deliveryID},
+		))
+		ctrl.PostWebhookDeliveryReplay(w, req)
+
+		if w.Code != c.expectedCode {
+			t.Errorf(
+				"testcase %d %s:\nExpected status code to be %v, but got %v\n",
+				i,
+				c.caseName,
+				c.expectedCode,
+				w.Code,
+			)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package webhooks
+
+import (
+	"net/http"
+
+	"github.com/hypnoglow/pascont/kit"
+	"github.com/hypnoglow/pascont/kit/middleware"
+	"github.com/hypnoglow/pascont/kit/schema"
+	"github.com/hypnoglow/pascont/webhook"
+)
+
+// GetWebhooks is a handler for:
+// GET /admin/webhooks
+//
+// Subscriptions registered in the config are listed too.
+func (c RestController) GetWebhooks(w http.ResponseWriter, req *http.Request) {
+	subscriptions, err := c.subscriptionRepo.List()
+	if err != nil {
+		c.logger.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	results := make([]subscriptionSchema, len(subscriptions))
+	for i, s := range subscriptions {
+		results[i] = newSubscriptionSchema(s)
+	}
+
+	kit.RespondJSON(w, http.StatusOK, schema.NewResultsBody(results, nil))
+}
+
+// GetWebhook is a handler for:
+// GET /admin/webhooks/:id
+func (c RestController) GetWebhook(w http.ResponseWriter, req *http.Request) {
+	id, _ := middleware.PathParam(req, "id")
+
+	s, err := c.subscriptionRepo.FindByID(id)
+	if err != nil {
+		if err == webhook.ErrNotFound {
+			w.WriteHeader(http.StatusNotFound)
+		} else {
+			c.logger.Println(err)
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		return
+	}
+
+	kit.RespondJSON(w, http.StatusOK, schema.NewResultBody(newSubscriptionSchema(s), nil))
+}
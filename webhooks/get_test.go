@@ -0,0 +1,258 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/pascont/kit/middleware"
+	"github.com/hypnoglow/pascont/webhook"
+)
+
+func TestRestController_GetWebhooks(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	fakeLogger := log.New(ioutil.Discard, "", log.LstdFlags)
+
+	cases := []struct {
+		caseName string
+		// in
+		subscriptionRepo webhook.Repository
+		// out
+		expectedCode int
+		expectedBody *bytes.Buffer
+	}{
+		{
+			caseName: "subscriptionRepo.List failed",
+			subscriptionRepo: webhook.NewFakeRepository(
+				nil,
+				nil,
+				[]webhook.FakeRepositoryListResult{
+					{
+						Error: fmt.Errorf("List failed"),
+					},
+				},
+				nil,
+			),
+			// out
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: bytes.NewBuffer(nil),
+		},
+		{
+			caseName: "Successful",
+			subscriptionRepo: webhook.NewFakeRepository(
+				nil,
+				nil,
+				[]webhook.FakeRepositoryListResult{
+					{
+						Subscriptions: []*webhook.Subscription{
+							{
+								ID:        "config",
+								URL:       "https://example.com/config",
+								Secret:    []byte("secret"),
+								CreatedAt: now,
+							},
+							{
+								ID:        "12345678-90ab-cdef-0123-4567890abcde",
+								URL:       "https://example.com/hook",
+								Events:    []string{webhook.EventAccountCreated},
+								Secret:    []byte("secret"),
+								CreatedAt: now,
+							},
+						},
+					},
+				},
+				nil,
+			),
+			// out
+			expectedCode: http.StatusOK,
+			expectedBody: bytes.NewBufferString(fmt.Sprintf(`{
+				"results":[
+					{
+						"id":"config",
+						"url":"https://example.com/config",
+						"events":[],
+						"createdAt":"%s"
+					},
+					{
+						"id":"12345678-90ab-cdef-0123-4567890abcde",
+						"url":"https://example.com/hook",
+						"events":["account.created"],
+						"createdAt":"%s"
+					}
+				]
+			}`, now.Format(time.RFC3339), now.Format(time.RFC3339))),
+		},
+	}
+
+	for i, c := range cases {
+		ctrl := NewRestController(fakeLogger, c.subscriptionRepo, nil, nil, nil)
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, PathWebhooks, nil)
+		ctrl.GetWebhooks(w, req)
+
+		if w.Code != c.expectedCode {
+			t.Errorf(
+				"testcase %d %s:\nExpected status code to be %v, but got %v\n",
+				i,
+				c.caseName,
+				c.expectedCode,
+				w.Code,
+			)
+		}
+
+		// To make possible pretty formatting in test cases, we use json.Compact:
+		b := c.expectedBody.Bytes()
+		if b != nil {
+			c.expectedBody.Reset()
+			err := json.Compact(c.expectedBody, b)
+			if err != nil {
+				t.Errorf(
+					"testcase %d %s:\nFailed to compact JSON body: %s",
+					i,
+					c.caseName,
+					err,
+				)
+			}
+		}
+
+		if !bytes.Equal(w.Body.Bytes(), c.expectedBody.Bytes()) {
+			t.Errorf(
+				"testcase %d %s:\nExpected body to be\n%v\nbut got\n%v\n",
+				i,
+				c.caseName,
+				c.expectedBody,
+				w.Body,
+			)
+		}
+	}
+}
+
+func TestRestController_GetWebhook(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	fakeLogger := log.New(ioutil.Discard, "", log.LstdFlags)
+
+	cases := []struct {
+		caseName string
+		// in
+		subscriptionRepo webhook.Repository
+		// out
+		expectedCode int
+		expectedBody *bytes.Buffer
+	}{
+		{
+			caseName: "Non-existent subscription should result in 404",
+			subscriptionRepo: webhook.NewFakeRepository(
+				nil,
+				[]webhook.FakeRepositoryFindByIDResult{
+					{
+						Error: webhook.ErrNotFound,
+					},
+				},
+				nil,
+				nil,
+			),
+			// out
+			expectedCode: http.StatusNotFound,
+			expectedBody: bytes.NewBuffer(nil),
+		},
+		{
+			caseName: "subscriptionRepo.FindByID failed",
+			subscriptionRepo: webhook.NewFakeRepository(
+				nil,
+				[]webhook.FakeRepositoryFindByIDResult{
+					{
+						Error: fmt.Errorf("FindByID failed"),
+					},
+				},
+				nil,
+				nil,
+			),
+			// out
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: bytes.NewBuffer(nil),
+		},
+		{
+			caseName: "Successful",
+			subscriptionRepo: webhook.NewFakeRepository(
+				nil,
+				[]webhook.FakeRepositoryFindByIDResult{
+					{
+						Subscription: &webhook.Subscription{
+							ID:        "12345678-90ab-cdef-0123-4567890abcde",
+							URL:       "https://example.com/hook",
+							Events:    []string{webhook.EventLoginFailed},
+							Secret:    []byte("secret"),
+							CreatedAt: now,
+						},
+					},
+				},
+				nil,
+				nil,
+			),
+			// out
+			expectedCode: http.StatusOK,
+			expectedBody: bytes.NewBufferString(fmt.Sprintf(`{
+				"result":{
+					"id":"12345678-90ab-cdef-0123-4567890abcde",
+					"url":"https://example.com/hook",
+					"events":["session.login_failed"],
+					"createdAt":"%s"
+				}
+			}`, now.Format(time.RFC3339))),
+		},
+	}
+
+	for i, c := range cases {
+		ctrl := NewRestController(fakeLogger, c.subscriptionRepo, nil, nil, nil)
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, PathWebhooks+"/12345678-90ab-cdef-0123-4567890abcde", nil)
+		req = req.WithContext(context.WithValue(
+			req.Context(),
+			middleware.ContextKeyPathParams{},
+			map[string]string{"id": "12345678-90ab-cdef-0123-4567890abcde"},
+		))
+		ctrl.GetWebhook(w, req)
+
+		if w.Code != c.expectedCode {
+			t.Errorf(
+				"testcase %d %s:\nExpected status code to be %v, but got %v\n",
+				i,
+				c.caseName,
+				c.expectedCode,
+				w.Code,
+			)
+		}
+
+		// To make possible pretty formatting in test cases, we use json.Compact:
+		b := c.expectedBody.Bytes()
+		if b != nil {
+			c.expectedBody.Reset()
+			err := json.Compact(c.expectedBody, b)
+			if err != nil {
+				t.Errorf(
+					"testcase %d %s:\nFailed to compact JSON body: %s",
+					i,
+					c.caseName,
+					err,
+				)
+			}
+		}
+
+		if !bytes.Equal(w.Body.Bytes(), c.expectedBody.Bytes()) {
+			t.Errorf(
+				"testcase %d %s:\nExpected body to be\n%v\nbut got\n%v\n",
+				i,
+				c.caseName,
+				c.expectedBody,
+				w.Body,
+			)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package webhooks
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/hypnoglow/pascont/kit"
+	"github.com/hypnoglow/pascont/kit/form"
+	"github.com/hypnoglow/pascont/kit/schema"
+	"github.com/hypnoglow/pascont/webhook"
+)
+
+// PostWebhooks is a handler for:
+// POST /admin/webhooks
+func (c RestController) PostWebhooks(w http.ResponseWriter, req *http.Request) {
+	var subscriptionForm postWebhookForm
+	form.PopulateFormFromJSON(req.Body, &subscriptionForm)
+	if !subscriptionForm.Validate() {
+		kit.RespondWithFormErrors(w, http.StatusBadRequest, subscriptionForm.ValidationErrors())
+		return
+	}
+
+	s, err := webhook.Generate(c.uuidProducer, subscriptionForm.URL, subscriptionForm.Events, c.clock())
+	if err != nil {
+		c.logger.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	if err := c.subscriptionRepo.Save(*s); err != nil {
+		c.logger.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	// The secret is revealed only once, it can not be retrieved later.
+	kit.RespondJSON(w, http.StatusCreated, schema.NewResultBody(
+		postWebhookSchema{
+			subscriptionSchema: newSubscriptionSchema(s),
+			Secret:             hex.EncodeToString(s.Secret),
+		},
+		nil,
+	))
+}
+
+type postWebhookForm struct {
+	form.BaseForm
+	URL    string   `json:"url"`
+	Events []string `json:"events"`
+}
+
+func (f *postWebhookForm) Validate() bool {
+	if u, err := url.Parse(f.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		f.AddError("URL must be an absolute http or https URL", "url", f.URL)
+	}
+
+	for _, e := range f.Events {
+		if !webhook.IsEventType(e) {
+			f.AddError(fmt.Sprintf("Event %s is unknown", e), "events", e)
+		}
+	}
+
+	return len(f.ValidationErrors()) == 0
+}
+
+type postWebhookSchema struct {
+	subscriptionSchema
+	Secret string `json:"secret"`
+}
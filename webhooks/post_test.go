@@ -0,0 +1,160 @@
+package webhooks
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hypnoglow/pascont/clock"
+	"github.com/hypnoglow/pascont/webhook"
+)
+
+func TestRestController_PostWebhooks(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	fakeLogger := log.New(ioutil.Discard, "", log.LstdFlags)
+	uuidProducer := func() string {
+		return "12345678-90ab-cdef-0123-4567890abcde"
+	}
+
+	cases := []struct {
+		caseName string
+		// in
+		subscriptionRepo webhook.Repository
+		reqBody          io.Reader
+		// out
+		expectedCode int
+		expectedBody *bytes.Buffer
+	}{
+		{
+			caseName: "Invalid URL and unknown event should result in 400",
+			reqBody: bytes.NewBufferString(`{
+				"url":"example.com/hook",
+				"events":["account.created","unknown"]
+			}`),
+			// out
+			expectedCode: http.StatusBadRequest,
+			expectedBody: bytes.NewBufferString(`{
+				"errors":[
+					{
+						"message":"URL must be an absolute http or https URL",
+						"field":"url",
+						"value":"example.com/hook"
+					},
+					{
+						"message":"Event unknown is unknown",
+						"field":"events",
+						"value":"unknown"
+					}
+				]
+			}`),
+		},
+		{
+			caseName: "subscriptionRepo.Save failed",
+			subscriptionRepo: webhook.NewFakeRepository(
+				[]webhook.FakeRepositorySaveResult{
+					{
+						Error: fmt.Errorf("Save failed"),
+					},
+				},
+				nil,
+				nil,
+				nil,
+			),
+			reqBody: bytes.NewBufferString(`{
+				"url":"https://example.com/hook"
+			}`),
+			// out
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: bytes.NewBuffer(nil),
+		},
+		{
+			caseName: "Successful",
+			subscriptionRepo: webhook.NewFakeRepository(
+				[]webhook.FakeRepositorySaveResult{
+					{
+						Error: nil,
+					},
+				},
+				nil,
+				nil,
+				nil,
+			),
+			reqBody: bytes.NewBufferString(`{
+				"url":"https://example.com/hook",
+				"events":["account.created"]
+			}`),
+			// out
+			expectedCode: http.StatusCreated,
+			// The secret is random, so the body is checked separately.
+			expectedBody: nil,
+		},
+	}
+
+	for i, c := range cases {
+		ctrl := NewRestController(fakeLogger, c.subscriptionRepo, nil, uuidProducer, clock.Fixed(now))
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, PathWebhooks, c.reqBody)
+		ctrl.PostWebhooks(w, req)
+
+		if w.Code != c.expectedCode {
+			t.Errorf(
+				"testcase %d %s:\nExpected status code to be %v, but got %v\n",
+				i,
+				c.caseName,
+				c.expectedCode,
+				w.Code,
+			)
+		}
+
+		if c.expectedBody == nil {
+			var body struct {
+				Result postWebhookSchema `json:"result"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("testcase %d %s:\nFailed to decode body: %s", i, c.caseName, err)
+			}
+
+			secret, err := hex.DecodeString(body.Result.Secret)
+			if err != nil || len(secret) != webhook.SecretLength {
+				t.Errorf("testcase %d %s:\nExpected a secret of %d bytes, but got %s\n", i, c.caseName, webhook.SecretLength, body.Result.Secret)
+			}
+			if body.Result.ID != uuidProducer() || body.Result.URL != "https://example.com/hook" || !body.Result.CreatedAt.Equal(now) {
+				t.Errorf("testcase %d %s:\nUnexpected subscription %s\n", i, c.caseName, w.Body)
+			}
+			continue
+		}
+
+		// To make possible pretty formatting in test cases, we use json.Compact:
+		b := c.expectedBody.Bytes()
+		if b != nil {
+			c.expectedBody.Reset()
+			err := json.Compact(c.expectedBody, b)
+			if err != nil {
+				t.Errorf(
+					"testcase %d %s:\nFailed to compact JSON body: %s",
+					i,
+					c.caseName,
+					err,
+				)
+			}
+		}
+
+		if !bytes.Equal(w.Body.Bytes(), c.expectedBody.Bytes()) {
+			t.Errorf(
+				"testcase %d %s:\nExpected body to be\n%v\nbut got\n%v\n",
+				i,
+				c.caseName,
+				c.expectedBody,
+				w.Body,
+			)
+		}
+	}
+}